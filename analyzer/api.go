@@ -1,7 +1,9 @@
 package analyzer
 
 import (
+	"context"
 	"errors"
+	"fmt"
 	"strings"
 	"time"
 
@@ -30,11 +32,42 @@ var (
 
 // Analyzer is a worker that analyzes a subset of the Oasis Network.
 type Analyzer interface {
-	// Start starts the analyzer.
-	Start()
+	// Start starts the analyzer. It returns once the analyzer has
+	// finished its range or the provided context is cancelled.
+	Start(ctx context.Context)
 
 	// Name returns the name of the analyzer.
 	Name() string
+
+	// CheckReady returns an error if the analyzer is not ready to
+	// analyze, such as when its source is unreachable or serves
+	// another network.
+	CheckReady(ctx context.Context) error
+}
+
+// ErrChainContextMismatch is returned if a source serves a network
+// other than the configured network.
+var ErrChainContextMismatch = errors.New("source chain context mismatch")
+
+// NetworkSource is a source of a network, such as a ConsensusSourceStorage
+// or a RuntimeSourceStorage.
+type NetworkSource interface {
+	// ChainContext gets the chain domain separation context of the
+	// network served by the source.
+	ChainContext(ctx context.Context) (string, error)
+}
+
+// CheckChainContext returns an error if a source is unreachable, or if
+// its chain context is not the expected chain context.
+func CheckChainContext(ctx context.Context, source NetworkSource, expected string) error {
+	chainContext, err := source.ChainContext(ctx)
+	if err != nil {
+		return fmt.Errorf("source unreachable: %w", err)
+	}
+	if chainContext != expected {
+		return fmt.Errorf("%w: %s, expected %s", ErrChainContextMismatch, chainContext, expected)
+	}
+	return nil
 }
 
 // ConsensusConfig specifies configuration parameters for
@@ -43,6 +76,10 @@ type ConsensusConfig struct {
 	// ChainID is the chain ID for the underlying network.
 	ChainID string
 
+	// ChainContext is the domain separation context of the network,
+	// which the source must serve.
+	ChainContext string
+
 	// Range is the range of blocks to process.
 	// If this is set, the analyzer analyzes blocks in the provided range.
 	Range BlockRange
@@ -54,6 +91,11 @@ type ConsensusConfig struct {
 	// Source is the storage source from which to fetch block data
 	// when processing blocks in this range.
 	Source storage.ConsensusSourceStorage
+
+	// CheckpointInterval is the number of epochs between periodic
+	// checkpoints of consensus state. If this is zero, no periodic
+	// checkpoints are taken.
+	CheckpointInterval uint64
 }
 
 // BlockRange is a range of blocks.
@@ -71,6 +113,10 @@ type RuntimeConfig struct {
 	// ChainID is the chain ID for the underlying network.
 	ChainID string
 
+	// ChainContext is the domain separation context of the network,
+	// which the source must serve.
+	ChainContext string
+
 	// Range is the range of rounds to process.
 	// If this is set, the analyzer analyzes rounds in the provided range.
 	Range RoundRange
@@ -12,9 +12,11 @@ import (
 	"github.com/iancoleman/strcase"
 	"github.com/jackc/pgx/v4"
 	registry "github.com/oasisprotocol/metadata-registry-tools"
+	beacon "github.com/oasisprotocol/oasis-core/go/beacon/api"
 	"github.com/oasisprotocol/oasis-core/go/common/cbor"
 	"github.com/oasisprotocol/oasis-core/go/consensus/api/transaction"
 	"github.com/oasisprotocol/oasis-core/go/consensus/api/transaction/results"
+	governance "github.com/oasisprotocol/oasis-core/go/governance/api"
 	staking "github.com/oasisprotocol/oasis-core/go/staking/api"
 	oasisConfig "github.com/oasisprotocol/oasis-sdk/client-sdk/go/config"
 	"golang.org/x/sync/errgroup"
@@ -24,13 +26,21 @@ import (
 	"github.com/oasisprotocol/oasis-indexer/config"
 	"github.com/oasisprotocol/oasis-indexer/log"
 	"github.com/oasisprotocol/oasis-indexer/metrics"
+	"github.com/oasisprotocol/oasis-indexer/notifier"
 	"github.com/oasisprotocol/oasis-indexer/storage"
 	source "github.com/oasisprotocol/oasis-indexer/storage/oasis"
+	"github.com/oasisprotocol/oasis-indexer/streaming"
 )
 
 const (
-	consensusMainDamaskName = "consensus_main_damask"
+	// MainDamaskName is the name of the main consensus analyzer, under
+	// which its progress is recorded in target storage.
+	MainDamaskName = "consensus_main_damask"
+
 	registryUpdateFrequency = 100 // once per n block
+	sendBatchTimeout        = 30 * time.Second
+	notifierCommitTimeout   = 10 * time.Second
+	checkpointTimeout       = 10 * time.Minute
 )
 
 // Main is the main Analyzer for the consensus layer.
@@ -40,10 +50,26 @@ type Main struct {
 	target  storage.TargetStorage
 	logger  *log.Logger
 	metrics metrics.DatabaseMetrics
+
+	analysisMetrics metrics.AnalysisMetrics
+
+	notifier *notifier.Notifier
+	bus      streaming.Bus
+
+	// lastHeight and lastEpoch are the height and epoch of the last
+	// processed block, by which epoch changes are detected.
+	lastHeight int64
+	lastEpoch  beacon.EpochTime
+
+	// checkpointDone is closed once the checkpoint being taken, if any,
+	// has been taken or has failed.
+	checkpointDone chan struct{}
 }
 
 // NewMain returns a new main analyzer for the consensus layer.
-func NewMain(cfg *config.AnalyzerConfig, target storage.TargetStorage, logger *log.Logger) (*Main, error) {
+// Events are staged with the provided notifier and committed heights are
+// published on the provided bus, either of which may be nil if disabled.
+func NewMain(cfg *config.AnalyzerConfig, target storage.TargetStorage, n *notifier.Notifier, b streaming.Bus, logger *log.Logger) (*Main, error) {
 	ctx := context.Background()
 
 	var ac analyzer.ConsensusConfig
@@ -74,8 +100,10 @@ func NewMain(cfg *config.AnalyzerConfig, target storage.TargetStorage, logger *l
 			To:   cfg.To,
 		}
 		ac = analyzer.ConsensusConfig{
-			Range:  blockRange,
-			Source: client,
+			ChainContext:       cfg.ChainContext,
+			Range:              blockRange,
+			Source:             client,
+			CheckpointInterval: cfg.CheckpointInterval,
 		}
 	} else {
 		interval, err := time.ParseDuration(cfg.Interval)
@@ -95,18 +123,42 @@ func NewMain(cfg *config.AnalyzerConfig, target storage.TargetStorage, logger *l
 		cfg:     ac,
 		qf:      analyzer.NewQueryFactory(strcase.ToSnake(cfg.ChainID), "" /* no runtime identifier for the consensus layer */),
 		target:  target,
-		logger:  logger.With("analyzer", consensusMainDamaskName),
-		metrics: metrics.NewDefaultDatabaseMetrics(consensusMainDamaskName),
+		logger:  logger.With("analyzer", MainDamaskName),
+		metrics: metrics.NewDefaultDatabaseMetrics(MainDamaskName),
+
+		analysisMetrics: metrics.NewDefaultAnalysisMetrics(MainDamaskName, cfg.ChainID),
+
+		notifier: n,
+		bus:      b,
 	}, nil
 }
 
 // Start starts the main consensus analyzer.
-func (m *Main) Start() {
-	ctx := context.Background()
-
+func (m *Main) Start(ctx context.Context) {
 	// Start aggregate worker.
 	go m.aggregateWorker(ctx)
 
+	// Start chain head worker.
+	go m.chainHeadWorker(ctx)
+
+	// Start webhook notifier. It is stopped along with the analyzer, which
+	// waits for it to store undelivered events before returning, so that
+	// target storage is not closed under it.
+	notifierCtx, stopNotifier := context.WithCancel(ctx)
+	notifierDone := make(chan struct{})
+	go func() {
+		defer close(notifierDone)
+		m.notifier.Start(notifierCtx)
+	}()
+	defer func() {
+		stopNotifier()
+		<-notifierDone
+	}()
+
+	// Checkpoints are taken under the analyzer context, so that they are
+	// cancelled on shutdown, but the analyzer still waits for them to end.
+	defer m.waitForCheckpoint()
+
 	// Get block to be indexed.
 	var height int64
 
@@ -138,6 +190,13 @@ func (m *Main) Start() {
 		return
 	}
 	for m.cfg.Range.To == 0 || height <= m.cfg.Range.To {
+		if ctx.Err() != nil {
+			m.logger.Info("shutting down analyzer",
+				"height", height,
+			)
+			return
+		}
+
 		if err := m.processBlock(ctx, height); err != nil {
 			if err == analyzer.ErrOutOfRange {
 				m.logger.Info("no data source available at this height",
@@ -146,13 +205,20 @@ func (m *Main) Start() {
 				return
 			}
 
+			if ctx.Err() != nil {
+				// The analyzer is shutting down.
+				continue
+			}
+
 			m.logger.Error("error processing block",
 				"err", err.Error(),
 			)
-			backoff.Wait()
+			m.analysisMetrics.RetryCounter().Inc()
+			_ = backoff.WaitContext(ctx)
 			continue
 		}
 
+		m.analysisMetrics.LatestHeight().Set(float64(height))
 		backoff.Reset()
 		height++
 	}
@@ -160,7 +226,17 @@ func (m *Main) Start() {
 
 // Name returns the name of the Main.
 func (m *Main) Name() string {
-	return consensusMainDamaskName
+	return MainDamaskName
+}
+
+// CheckReady checks that the source of the analyzer is reachable and
+// serves the configured network. Analyzers that run periodically have
+// no source, and are always ready.
+func (m *Main) CheckReady(ctx context.Context) error {
+	if m.cfg.Source == nil {
+		return nil
+	}
+	return analyzer.CheckChainContext(ctx, m.cfg.Source, m.cfg.ChainContext)
 }
 
 // source returns the source storage for the provided block height.
@@ -174,14 +250,15 @@ func (m *Main) source(height int64) (storage.ConsensusSourceStorage, error) {
 }
 
 // latestBlock returns the latest block processed by the consensus analyzer.
+// It is read from the primary, which has all blocks processed so far.
 func (m *Main) latestBlock(ctx context.Context) (int64, error) {
 	var latest int64
 	if err := m.target.QueryRow(
-		ctx,
+		storage.WithPrimary(ctx),
 		m.qf.LatestBlockQuery(),
 		// ^analyzers should only analyze for a single chain ID, and we anchor this
 		// at the starting block.
-		consensusMainDamaskName,
+		MainDamaskName,
 	).Scan(&latest); err != nil {
 		return 0, err
 	}
@@ -201,11 +278,29 @@ func (m *Main) processBlock(ctx context.Context, height int64) error {
 	// Prepare and perform updates.
 	batch := &storage.QueryBatch{}
 
+	// Registry data is only prepared once the block and staking data have
+	// been, since nodes are frozen when the epoch changes or entities are
+	// slashed.
+	var (
+		epoch        beacon.EpochTime
+		slashed      []staking.Address
+		registryData *storage.RegistryData
+	)
+	group.Go(func() (err error) {
+		epoch, err = m.prepareBlockData(groupCtx, height, batch)
+		return err
+	})
+	group.Go(func() (err error) {
+		slashed, err = m.prepareStakingData(groupCtx, height, batch)
+		return err
+	})
+	group.Go(func() (err error) {
+		registryData, err = m.fetchRegistryData(groupCtx, height)
+		return err
+	})
+
 	type prepareFunc = func(context.Context, int64, *storage.QueryBatch) error
 	for _, f := range []prepareFunc{
-		m.prepareBlockData,
-		m.prepareRegistryData,
-		m.prepareStakingData,
 		m.prepareSchedulerData,
 		m.prepareGovernanceData,
 	} {
@@ -224,37 +319,218 @@ func (m *Main) processBlock(ctx context.Context, height int64) error {
 		batch.Queue(
 			m.qf.IndexingProgressQuery(),
 			height,
-			consensusMainDamaskName,
+			MainDamaskName,
 		)
 		return nil
 	})
 
 	if err := group.Wait(); err != nil {
+		m.notifier.Discard(height)
 		return err
 	}
 
+	epochChanged, err := m.epochChanged(ctx, height, epoch)
+	if err != nil {
+		m.notifier.Discard(height)
+		return err
+	}
+	if err := m.prepareRegistryData(ctx, height, batch, registryData, slashed, epochChanged); err != nil {
+		m.notifier.Discard(height)
+		return err
+	}
+
+	m.analysisMetrics.BatchSize().Observe(float64(batch.Len()))
+
 	opName := "process_block_consensus"
 	timer := m.metrics.DatabaseTimer(m.target.Name(), opName)
 	defer timer.ObserveDuration()
 
-	if err := m.target.SendBatch(ctx, batch); err != nil {
+	// The batch is sent with a context detached from the analyzer so that
+	// an in-flight batch is committed even if shutdown has been requested.
+	sendCtx, cancel := context.WithTimeout(context.Background(), sendBatchTimeout)
+	defer cancel()
+
+	if err := m.target.SendBatch(sendCtx, batch); err != nil {
 		m.metrics.DatabaseCounter(m.target.Name(), opName, "failure").Inc()
+		m.notifier.Discard(height)
 		return err
 	}
 	m.metrics.DatabaseCounter(m.target.Name(), opName, "success").Inc()
+
+	m.lastHeight, m.lastEpoch = height, epoch
+
+	// Events of the committed block are delivered even if shutdown has
+	// been requested since, so their subscriptions are looked up with
+	// a detached context too.
+	commitCtx, cancelCommit := context.WithTimeout(context.Background(), notifierCommitTimeout)
+	defer cancelCommit()
+	m.notifier.Commit(commitCtx, height)
+	m.publish(ctx, height)
+
+	// State is checkpointed at the first block of each epoch that is a
+	// multiple of the checkpoint interval, or failing that, at the first
+	// block of a later epoch before the next multiple.
+	if m.cfg.CheckpointInterval != 0 && epochChanged {
+		m.maybeCheckpoint(ctx, height, epoch)
+	}
 	return nil
 }
 
-// prepareBlockData adds block data queries to the batch.
-func (m *Main) prepareBlockData(ctx context.Context, height int64, batch *storage.QueryBatch) error {
+// epochChanged returns whether the provided block, of the provided epoch,
+// is the first block of its epoch. The epoch of the previous block is known
+// if it was the last processed block; otherwise, the first block of the
+// epoch is read from target storage, where it is recorded once indexed.
+func (m *Main) epochChanged(ctx context.Context, height int64, epoch beacon.EpochTime) (bool, error) {
+	if m.lastHeight != 0 && m.lastHeight == height-1 {
+		return m.lastEpoch != epoch, nil
+	}
+
+	var start int64
+	if err := m.target.QueryRow(
+		storage.WithPrimary(ctx),
+		m.qf.ConsensusEpochStartQuery(),
+		epoch,
+	).Scan(&start); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return true, nil
+		}
+		return false, err
+	}
+	return start >= height, nil
+}
+
+// maybeCheckpoint starts checkpointing consensus state at the provided
+// height, the first of its epoch, unless state has been checkpointed since
+// the last epoch that is a multiple of the checkpoint interval, or the
+// previous checkpoint is still being taken. Failed checkpoints are not
+// recorded, so that they are retried at the next epoch, including after
+// a restart.
+func (m *Main) maybeCheckpoint(ctx context.Context, height int64, epoch beacon.EpochTime) {
+	if m.checkpointDone != nil {
+		select {
+		case <-m.checkpointDone:
+			m.checkpointDone = nil
+		default:
+			m.logger.Info("previous checkpoint still being taken, not checkpointing state",
+				"height", height,
+				"epoch", epoch,
+			)
+			return
+		}
+	}
+
+	due := uint64(epoch) - uint64(epoch)%m.cfg.CheckpointInterval
+	var taken bool
+	if err := m.target.QueryRow(
+		storage.WithPrimary(ctx),
+		m.qf.ConsensusCheckpointTakenQuery(),
+		due,
+		height,
+	).Scan(&taken); err != nil {
+		m.logger.Error("failed to look up checkpoints, not checkpointing state",
+			"height", height,
+			"epoch", epoch,
+			"error", err,
+		)
+		return
+	}
+	if taken {
+		return
+	}
+
+	done := make(chan struct{})
+	m.checkpointDone = done
+	go func() {
+		defer close(done)
+		m.checkpoint(ctx, height, epoch)
+	}()
+}
+
+// waitForCheckpoint waits for the checkpoint being taken, if any.
+func (m *Main) waitForCheckpoint() {
+	if m.checkpointDone != nil {
+		<-m.checkpointDone
+	}
+}
+
+// checkpoint checkpoints consensus state at the provided height, and
+// records the checkpoint. Checkpoints copy whole tables, so they are sent
+// in a batch of their own once the block is committed, with a timeout of
+// their own, while the following blocks are processed. They are taken in
+// a single snapshot of indexed state, and only recorded if the snapshot
+// is that of the height; since checkpointed rows reference their
+// checkpoint, they are not copied either if a following block has been
+// committed in the meantime.
+func (m *Main) checkpoint(ctx context.Context, height int64, epoch beacon.EpochTime) {
+	m.logger.Info("checkpointing state",
+		"height", height,
+		"epoch", epoch,
+	)
+
+	// A checkpoint replaces any previous one at the same height, such as
+	// one taken by the verify command.
+	batch := &storage.QueryBatch{}
+	batch.Queue(`SET TRANSACTION ISOLATION LEVEL REPEATABLE READ`)
+	batch.Queue(m.qf.ConsensusCheckpointDeleteQuery(),
+		height,
+	)
+	batch.Queue(m.qf.ConsensusLatestCheckpointInsertQuery(),
+		height,
+		epoch,
+		MainDamaskName,
+	)
+	for _, query := range m.qf.ConsensusCheckpointQueries() {
+		batch.Queue(query, height)
+	}
+
+	opName := "checkpoint_consensus"
+	timer := m.metrics.DatabaseTimer(m.target.Name(), opName)
+	defer timer.ObserveDuration()
+
+	ctx, cancel := context.WithTimeout(ctx, checkpointTimeout)
+	defer cancel()
+
+	if err := m.target.SendBatch(ctx, batch); err != nil {
+		m.metrics.DatabaseCounter(m.target.Name(), opName, "failure").Inc()
+		m.logger.Error("failed to checkpoint state, retrying at the next epoch",
+			"height", height,
+			"epoch", epoch,
+			"error", err,
+		)
+		return
+	}
+	m.metrics.DatabaseCounter(m.target.Name(), opName, "success").Inc()
+}
+
+// publish announces that the provided block has been committed.
+func (m *Main) publish(ctx context.Context, height int64) {
+	if m.bus == nil {
+		return
+	}
+	if err := m.bus.Publish(ctx, &streaming.Message{
+		Layer:  streaming.LayerConsensus,
+		Height: height,
+	}); err != nil {
+		m.logger.Error("failed to publish block",
+			"height", height,
+			"err", err.Error(),
+		)
+	}
+}
+
+// prepareBlockData adds block data queries to the batch, and returns
+// the epoch of the block.
+func (m *Main) prepareBlockData(ctx context.Context, height int64, batch *storage.QueryBatch) (beacon.EpochTime, error) {
 	source, err := m.source(height)
 	if err != nil {
-		return err
+		return 0, err
 	}
 
+	timer := m.analysisMetrics.SourceTimer("BlockData")
 	data, err := source.BlockData(ctx, height)
+	timer.ObserveDuration()
 	if err != nil {
-		return err
+		return 0, err
 	}
 
 	for _, f := range []func(*storage.QueryBatch, *storage.ConsensusBlockData) error{
@@ -264,11 +540,11 @@ func (m *Main) prepareBlockData(ctx context.Context, height int64, batch *storag
 		m.queueEventInserts,
 	} {
 		if err := f(batch, data); err != nil {
-			return err
+			return 0, err
 		}
 	}
 
-	return nil
+	return data.Epoch, nil
 }
 
 func (m *Main) queueBlockInserts(batch *storage.QueryBatch, data *storage.ConsensusBlockData) error {
@@ -312,6 +588,12 @@ func (m *Main) queueTransactionInserts(batch *storage.QueryBatch, data *storage.
 
 		var tx transaction.Transaction
 		if err := signedTx.Open(&tx); err != nil {
+			m.logger.Warn("skipping transaction that failed to open",
+				"height", data.BlockHeader.Height,
+				"tx_hash", signedTx.Hash().Hex(),
+				"err", err.Error(),
+			)
+			m.analysisMetrics.SkippedTransactionCounter("open_failed").Inc()
 			continue
 		}
 
@@ -385,18 +667,37 @@ func (m *Main) queueEventInserts(batch *storage.QueryBatch, data *storage.Consen
 	return nil
 }
 
-// prepareRegistryData adds registry data queries to the batch.
-func (m *Main) prepareRegistryData(ctx context.Context, height int64, batch *storage.QueryBatch) error {
+// fetchRegistryData fetches registry data at the provided height.
+func (m *Main) fetchRegistryData(ctx context.Context, height int64) (*storage.RegistryData, error) {
 	source, err := m.source(height)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
+	timer := m.analysisMetrics.SourceTimer("RegistryData")
 	data, err := source.RegistryData(ctx, height)
+	timer.ObserveDuration()
+	return data, err
+}
+
+// prepareRegistryData adds registry data queries to the batch, including
+// the freezes of nodes, which are only fetched if entities were slashed
+// or the epoch changed at the provided height.
+func (m *Main) prepareRegistryData(ctx context.Context, height int64, batch *storage.QueryBatch, data *storage.RegistryData, slashed []staking.Address, epochChanged bool) error {
+	source, err := m.source(height)
 	if err != nil {
 		return err
 	}
 
+	if len(slashed) > 0 || epochChanged {
+		timer := m.analysisMetrics.SourceTimer("NodeFreezes")
+		data.NodeFreezes, err = source.NodeFreezes(ctx, height, slashed, epochChanged)
+		timer.ObserveDuration()
+		if err != nil {
+			return err
+		}
+	}
+
 	for _, f := range []func(*storage.QueryBatch, *storage.RegistryData) error{
 		m.queueRuntimeRegistrations,
 		m.queueRuntimeStatusUpdates,
@@ -407,6 +708,7 @@ func (m *Main) prepareRegistryData(ctx context.Context, height int64, batch *sto
 			return err
 		}
 	}
+	m.stageRegistryNotifications(height, data)
 
 	if height%registryUpdateFrequency == 0 {
 		if err := m.queueMetadataRegistry(ctx, batch); err != nil {
@@ -419,6 +721,7 @@ func (m *Main) prepareRegistryData(ctx context.Context, height int64, batch *sto
 
 func (m *Main) queueRuntimeRegistrations(batch *storage.QueryBatch, data *storage.RegistryData) error {
 	runtimeUpsertQuery := m.qf.ConsensusRuntimeUpsertQuery()
+	runtimeHistoryInsertQuery := m.qf.ConsensusRuntimeRegistrationHistoryInsertQuery()
 
 	for _, runtimeEvent := range data.RuntimeEvents {
 		keyManager := "none"
@@ -427,12 +730,20 @@ func (m *Main) queueRuntimeRegistrations(batch *storage.QueryBatch, data *storag
 			keyManager = runtimeEvent.Runtime.KeyManager.String()
 		}
 
+		// Runtimes are registered again whenever their descriptor is
+		// updated, which is told apart before the runtime is upserted.
+		batch.Queue(runtimeHistoryInsertQuery,
+			runtimeEvent.Runtime.ID.String(),
+			data.Height,
+		)
 		batch.Queue(runtimeUpsertQuery,
 			runtimeEvent.Runtime.ID.String(),
 			false,
 			runtimeEvent.Runtime.Kind.String(),
 			runtimeEvent.Runtime.TEEHardware.String(),
 			keyManager,
+			runtimeEvent.Runtime.Genesis.Round,
+			runtimeEvent.Runtime.Genesis.StateRoot.String(),
 		)
 	}
 
@@ -442,12 +753,15 @@ func (m *Main) queueRuntimeRegistrations(batch *storage.QueryBatch, data *storag
 func (m *Main) queueRuntimeStatusUpdates(batch *storage.QueryBatch, data *storage.RegistryData) error {
 	runtimeSuspensionQuery := m.qf.ConsensusRuntimeSuspensionQuery()
 	runtimeUnsuspensionQuery := m.qf.ConsensusRuntimeUnsuspensionQuery()
+	runtimeHistoryInsertQuery := m.qf.ConsensusRuntimeHistoryInsertQuery()
 
 	for _, runtime := range data.RuntimeSuspensions {
 		batch.Queue(runtimeSuspensionQuery, runtime)
+		batch.Queue(runtimeHistoryInsertQuery, runtime, data.Height, "suspended")
 	}
 	for _, runtime := range data.RuntimeUnsuspensions {
 		batch.Queue(runtimeUnsuspensionQuery, runtime)
+		batch.Queue(runtimeHistoryInsertQuery, runtime, data.Height, "unsuspended")
 	}
 
 	return nil
@@ -478,6 +792,9 @@ func (m *Main) queueEntityEvents(batch *storage.QueryBatch, data *storage.Regist
 func (m *Main) queueNodeEvents(batch *storage.QueryBatch, data *storage.RegistryData) error {
 	nodeUpsertQuery := m.qf.ConsensusNodeUpsertQuery()
 	nodeDeleteQuery := m.qf.ConsensusNodeDeleteQuery()
+	nodeFreezeUpdateQuery := m.qf.ConsensusNodeFreezeUpdateQuery()
+	nodeHistoryInsertQuery := m.qf.ConsensusNodeHistoryInsertQuery()
+	nodeHistorySnapshotQuery := m.qf.ConsensusNodeHistorySnapshotQuery()
 
 	for _, nodeEvent := range data.NodeEvents {
 		vrfPubkey := ""
@@ -500,23 +817,31 @@ func (m *Main) queueNodeEvents(batch *storage.QueryBatch, data *storage.Registry
 			consensusAddresses = append(consensusAddresses, address.String())
 		}
 
+		runtimes, err := json.Marshal(util.NodeRuntimes(nodeEvent.Node))
+		if err != nil {
+			return err
+		}
+
+		event := "expired"
 		if nodeEvent.IsRegistration {
-			// A new node is registered.
+			// A new node is registered, or an existing node updates its
+			// descriptor. Its voting power and freeze are left as is.
+			event = "registered"
 			batch.Queue(nodeUpsertQuery,
 				nodeEvent.Node.ID.String(),
 				nodeEvent.Node.EntityID.String(),
 				nodeEvent.Node.Expiration,
 				nodeEvent.Node.TLS.PubKey.String(),
 				nodeEvent.Node.TLS.NextPubKey.String(),
-				fmt.Sprintf(`{'%s'}`, strings.Join(tlsAddresses, `','`)),
+				tlsAddresses,
 				nodeEvent.Node.P2P.ID.String(),
-				fmt.Sprintf(`{'%s'}`, strings.Join(p2pAddresses, `','`)),
+				p2pAddresses,
 				nodeEvent.Node.Consensus.ID.String(),
 				strings.Join(consensusAddresses, ","),
 				vrfPubkey,
-				nodeEvent.Node.Roles,
+				nodeEvent.Node.Roles.String(),
 				nodeEvent.Node.SoftwareVersion,
-				0,
+				string(runtimes),
 			)
 		} else {
 			// An existing node is expired.
@@ -524,6 +849,44 @@ func (m *Main) queueNodeEvents(batch *storage.QueryBatch, data *storage.Registry
 				nodeEvent.Node.ID.String(),
 			)
 		}
+		batch.Queue(nodeHistoryInsertQuery,
+			nodeEvent.Node.ID.String(),
+			nodeEvent.Node.EntityID.String(),
+			data.Height,
+			event,
+			nodeEvent.Node.Expiration,
+			nodeEvent.Node.SoftwareVersion,
+			nodeEvent.Node.Roles.String(),
+			tlsAddresses,
+			p2pAddresses,
+			strings.Join(consensusAddresses, ","),
+			nil,
+		)
+	}
+
+	// Freezes and unfreezes only change the status of nodes, so they are
+	// recorded with the descriptors of the nodes as they are stored.
+	for _, freeze := range data.NodeFreezes {
+		batch.Queue(nodeFreezeUpdateQuery,
+			freeze.NodeID.String(),
+			freeze.FreezeEndTime,
+		)
+		batch.Queue(nodeHistorySnapshotQuery,
+			freeze.NodeID.String(),
+			data.Height,
+			"frozen",
+		)
+	}
+	for _, unfrozenEvent := range data.NodeUnfrozenEvents {
+		batch.Queue(nodeFreezeUpdateQuery,
+			unfrozenEvent.NodeID.String(),
+			nil,
+		)
+		batch.Queue(nodeHistorySnapshotQuery,
+			unfrozenEvent.NodeID.String(),
+			data.Height,
+			"unfrozen",
+		)
 	}
 
 	return nil
@@ -554,15 +917,19 @@ func (m *Main) queueMetadataRegistry(ctx context.Context, batch *storage.QueryBa
 	return nil
 }
 
-func (m *Main) prepareStakingData(ctx context.Context, height int64, batch *storage.QueryBatch) error {
+// prepareStakingData adds staking data queries to the batch, and returns
+// the entities slashed at the provided height.
+func (m *Main) prepareStakingData(ctx context.Context, height int64, batch *storage.QueryBatch) ([]staking.Address, error) {
 	source, err := m.source(height)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
+	timer := m.analysisMetrics.SourceTimer("StakingData")
 	data, err := source.StakingData(ctx, height)
+	timer.ObserveDuration()
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	for _, f := range []func(*storage.QueryBatch, *storage.StakingData) error{
@@ -572,11 +939,20 @@ func (m *Main) prepareStakingData(ctx context.Context, height int64, batch *stor
 		m.queueAllowanceChanges,
 	} {
 		if err := f(batch, data); err != nil {
-			return err
+			return nil, err
 		}
 	}
+	m.stageStakingNotifications(height, data)
 
-	return nil
+	// Entities whose escrow was taken were slashed, which freezes
+	// their nodes.
+	var slashed []staking.Address
+	for _, escrow := range data.Escrows {
+		if escrow.Take != nil {
+			slashed = append(slashed, escrow.Take.Owner)
+		}
+	}
+	return slashed, nil
 }
 
 func (m *Main) queueTransfers(batch *storage.QueryBatch, data *storage.StakingData) error {
@@ -721,7 +1097,9 @@ func (m *Main) prepareSchedulerData(ctx context.Context, height int64, batch *st
 		return err
 	}
 
+	timer := m.analysisMetrics.SourceTimer("SchedulerData")
 	data, err := source.SchedulerData(ctx, height)
+	timer.ObserveDuration()
 	if err != nil {
 		return err
 	}
@@ -740,11 +1118,27 @@ func (m *Main) prepareSchedulerData(ctx context.Context, height int64, batch *st
 
 func (m *Main) queueValidatorUpdates(batch *storage.QueryBatch, data *storage.SchedulerData) error {
 	validatorNodeUpdateQuery := m.qf.ConsensusValidatorNodeUpdateQuery()
+	epochValidatorUpsertQuery := m.qf.ConsensusEpochValidatorUpsertQuery()
+
+	validators := make([]string, 0, len(data.Validators))
+	for _, validator := range data.Validators {
+		validators = append(validators, validator.ID.String())
+	}
+
+	// Nodes that left the validator set no longer have voting power, and
+	// the validator set of the epoch is the current one.
+	batch.Queue(m.qf.ConsensusValidatorNodesResetQuery(), validators)
+	batch.Queue(m.qf.ConsensusEpochValidatorsDeleteQuery(), data.Epoch, validators)
 	for _, validator := range data.Validators {
 		batch.Queue(validatorNodeUpdateQuery,
 			validator.ID,
 			validator.VotingPower,
 		)
+		batch.Queue(epochValidatorUpsertQuery,
+			data.Epoch,
+			validator.ID.String(),
+			validator.VotingPower,
+		)
 	}
 
 	return nil
@@ -752,12 +1146,13 @@ func (m *Main) queueValidatorUpdates(batch *storage.QueryBatch, data *storage.Sc
 
 func (m *Main) queueCommitteeUpdates(batch *storage.QueryBatch, data *storage.SchedulerData) error {
 	committeeMemberInsertQuery := m.qf.ConsensusCommitteeMemberInsertQuery()
+	epochCommitteeMemberInsertQuery := m.qf.ConsensusEpochCommitteeMemberInsertQuery()
 
 	batch.Queue(m.qf.ConsensusCommitteeMembersTruncateQuery())
 	for namespace, committees := range data.Committees {
 		runtime := namespace.String()
 		for _, committee := range committees {
-			kind := committee.String()
+			kind := committee.Kind.String()
 			validFor := int64(committee.ValidFor)
 			for _, member := range committee.Members {
 				batch.Queue(committeeMemberInsertQuery,
@@ -767,6 +1162,16 @@ func (m *Main) queueCommitteeUpdates(batch *storage.QueryBatch, data *storage.Sc
 					kind,
 					member.Role.String(),
 				)
+
+				// Committees are elected for a single epoch, so they are
+				// kept by the epoch they are valid for.
+				batch.Queue(epochCommitteeMemberInsertQuery,
+					validFor,
+					runtime,
+					kind,
+					member.PublicKey.String(),
+					member.Role.String(),
+				)
 			}
 		}
 	}
@@ -781,7 +1186,9 @@ func (m *Main) prepareGovernanceData(ctx context.Context, height int64, batch *s
 		return err
 	}
 
+	timer := m.analysisMetrics.SourceTimer("GovernanceData")
 	data, err := source.GovernanceData(ctx, height)
+	timer.ObserveDuration()
 	if err != nil {
 		return err
 	}
@@ -796,6 +1203,7 @@ func (m *Main) prepareGovernanceData(ctx context.Context, height int64, batch *s
 			return err
 		}
 	}
+	m.stageGovernanceNotifications(height, data)
 
 	return nil
 }
@@ -805,6 +1213,11 @@ func (m *Main) queueSubmissions(batch *storage.QueryBatch, data *storage.Governa
 	proposalSubmissionCancelInsertQuery := m.qf.ConsensusProposalSubmissionCancelInsertQuery()
 
 	for _, submission := range data.ProposalSubmissions {
+		content, err := json.Marshal(submission.Content)
+		if err != nil {
+			return err
+		}
+
 		if submission.Content.Upgrade != nil {
 			batch.Queue(proposalSubmissionInsertQuery,
 				submission.ID,
@@ -818,6 +1231,7 @@ func (m *Main) queueSubmissions(batch *storage.QueryBatch, data *storage.Governa
 				submission.Content.Upgrade.Epoch,
 				submission.CreatedAt,
 				submission.ClosesAt,
+				string(content),
 			)
 		} else if submission.Content.CancelUpgrade != nil {
 			batch.Queue(proposalSubmissionCancelInsertQuery,
@@ -828,6 +1242,7 @@ func (m *Main) queueSubmissions(batch *storage.QueryBatch, data *storage.Governa
 				submission.Content.CancelUpgrade.ProposalID,
 				submission.CreatedAt,
 				submission.ClosesAt,
+				string(content),
 			)
 		}
 	}
@@ -850,6 +1265,8 @@ func (m *Main) queueExecutions(batch *storage.QueryBatch, data *storage.Governan
 func (m *Main) queueFinalizations(batch *storage.QueryBatch, data *storage.GovernanceData) error {
 	proposalUpdateQuery := m.qf.ConsensusProposalUpdateQuery()
 	proposalInvalidVotesUpdateQuery := m.qf.ConsensusProposalInvalidVotesUpdateQuery()
+	proposalResultsUpdateQuery := m.qf.ConsensusProposalResultsUpdateQuery()
+	talliedVoteUpsertQuery := m.qf.ConsensusTalliedVoteUpsertQuery()
 
 	for _, finalization := range data.ProposalFinalizations {
 		batch.Queue(proposalUpdateQuery,
@@ -860,6 +1277,32 @@ func (m *Main) queueFinalizations(batch *storage.QueryBatch, data *storage.Gover
 			finalization.ID,
 			finalization.InvalidVotes,
 		)
+
+		// Choices that no one voted for have no results, and are recorded
+		// as a stake of zero.
+		yes := finalization.Results[governance.VoteYes]
+		no := finalization.Results[governance.VoteNo]
+		abstain := finalization.Results[governance.VoteAbstain]
+		batch.Queue(proposalResultsUpdateQuery,
+			finalization.ID,
+			yes.String(),
+			no.String(),
+			abstain.String(),
+		)
+
+		for _, vote := range data.TalliedVotes[finalization.ID] {
+			var weight *string
+			if vote.Weight != nil {
+				w := vote.Weight.String()
+				weight = &w
+			}
+			batch.Queue(talliedVoteUpsertQuery,
+				finalization.ID,
+				vote.Voter.String(),
+				vote.Vote.String(),
+				weight,
+			)
+		}
 	}
 
 	return nil
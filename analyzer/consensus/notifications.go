@@ -0,0 +1,104 @@
+package consensus
+
+import (
+	"github.com/oasisprotocol/oasis-indexer/notifier"
+	"github.com/oasisprotocol/oasis-indexer/storage"
+)
+
+// stageRegistryNotifications stages notifications for registry events.
+func (m *Main) stageRegistryNotifications(height int64, data *storage.RegistryData) {
+	var events []*notifier.Event
+	// Nodes are only removed from the registry once their registrations
+	// expire, which is signaled by a node event that is not a registration.
+	for _, nodeEvent := range data.NodeEvents {
+		if nodeEvent.IsRegistration {
+			continue
+		}
+		entityID := nodeEvent.Node.EntityID.String()
+		events = append(events, &notifier.Event{
+			Kind:     notifier.KindNodeExpired,
+			Height:   height,
+			EntityID: entityID,
+			Body: &notifier.NodeEvent{
+				NodeID:   nodeEvent.Node.ID.String(),
+				EntityID: entityID,
+			},
+		})
+	}
+	for _, freeze := range data.NodeFreezes {
+		entityID := freeze.EntityID.String()
+		events = append(events, &notifier.Event{
+			Kind:     notifier.KindNodeFrozen,
+			Height:   height,
+			EntityID: entityID,
+			Body: &notifier.NodeEvent{
+				NodeID:        freeze.NodeID.String(),
+				EntityID:      entityID,
+				FreezeEndTime: uint64(freeze.FreezeEndTime),
+			},
+		})
+	}
+	for _, unfrozenEvent := range data.NodeUnfrozenEvents {
+		entity, ok := data.UnfrozenNodeEntities[unfrozenEvent.NodeID]
+		if !ok {
+			m.logger.Warn("skipping notification of unfrozen node of unknown entity",
+				"height", height,
+				"node_id", unfrozenEvent.NodeID.String(),
+			)
+			continue
+		}
+		entityID := entity.String()
+		events = append(events, &notifier.Event{
+			Kind:     notifier.KindNodeUnfrozen,
+			Height:   height,
+			EntityID: entityID,
+			Body: &notifier.NodeEvent{
+				NodeID:   unfrozenEvent.NodeID.String(),
+				EntityID: entityID,
+			},
+		})
+	}
+	m.notifier.Stage(height, events...)
+}
+
+// stageStakingNotifications stages notifications for staking events.
+func (m *Main) stageStakingNotifications(height int64, data *storage.StakingData) {
+	events := make([]*notifier.Event, 0, len(data.Transfers))
+	for _, transfer := range data.Transfers {
+		from := transfer.From.String()
+		to := transfer.To.String()
+		events = append(events, &notifier.Event{
+			Kind:     notifier.KindTransfer,
+			Height:   height,
+			Accounts: []string{from, to},
+			Amount:   transfer.Amount.ToBigInt(),
+			Body: &notifier.TransferEvent{
+				From:   from,
+				To:     to,
+				Amount: transfer.Amount.String(),
+			},
+		})
+	}
+	m.notifier.Stage(height, events...)
+}
+
+// stageGovernanceNotifications stages notifications for governance events.
+func (m *Main) stageGovernanceNotifications(height int64, data *storage.GovernanceData) {
+	events := make([]*notifier.Event, 0, len(data.ProposalSubmissions))
+	for _, proposal := range data.ProposalSubmissions {
+		submitter := proposal.Submitter.String()
+		events = append(events, &notifier.Event{
+			Kind:     notifier.KindProposalSubmitted,
+			Height:   height,
+			Accounts: []string{submitter},
+			Amount:   proposal.Deposit.ToBigInt(),
+			Body: &notifier.ProposalSubmittedEvent{
+				ID:        proposal.ID,
+				Submitter: submitter,
+				Deposit:   proposal.Deposit.String(),
+				ClosesAt:  uint64(proposal.ClosesAt),
+			},
+		})
+	}
+	m.notifier.Stage(height, events...)
+}
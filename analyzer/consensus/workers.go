@@ -10,6 +10,9 @@ import (
 const (
 	aggregateWorkerInterval = 10 * time.Second
 	aggregateWorkerTimeout  = 60 * time.Second
+
+	chainHeadWorkerInterval = 6 * time.Second
+	chainHeadWorkerTimeout  = 10 * time.Second
 )
 
 // The aggregate worker refreshes aggregate statistics for the consensus layer.
@@ -46,3 +49,35 @@ func (m *Main) aggregateWorker(ctx context.Context) {
 		}
 	}
 }
+
+// The chain head worker tracks the latest height available from source storage.
+func (m *Main) chainHeadWorker(ctx context.Context) {
+	if m.cfg.Source == nil {
+		return
+	}
+
+	m.logger.Info("starting chain head worker")
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(chainHeadWorkerInterval):
+			func() {
+				cancelCtx, cancel := context.WithTimeout(ctx, chainHeadWorkerTimeout)
+				defer cancel()
+
+				timer := m.analysisMetrics.SourceTimer("LatestHeight")
+				height, err := m.cfg.Source.LatestHeight(cancelCtx)
+				timer.ObserveDuration()
+				if err != nil {
+					m.logger.Error("failed to fetch chain head",
+						"err", err,
+					)
+					return
+				}
+
+				m.analysisMetrics.ChainHead().Set(float64(height))
+			}()
+		}
+	}
+}
@@ -18,12 +18,14 @@ import (
 	"github.com/oasisprotocol/oasis-indexer/metrics"
 	"github.com/oasisprotocol/oasis-indexer/storage"
 	"github.com/oasisprotocol/oasis-indexer/storage/oasis"
+	"github.com/oasisprotocol/oasis-indexer/streaming"
 )
 
 const (
 	emerald = analyzer.RuntimeEmerald
 
 	emeraldMainDamaskName = "emerald_main_damask"
+	sendBatchTimeout      = 30 * time.Second
 )
 
 // Main is the main Analyzer for the Emerald Runtime.
@@ -34,11 +36,16 @@ type Main struct {
 	logger  *log.Logger
 	metrics metrics.DatabaseMetrics
 
+	analysisMetrics metrics.AnalysisMetrics
+
+	bus streaming.Bus
+
 	moduleHandlers []modules.ModuleHandler
 }
 
 // NewMain returns a new main analyzer for the Emerald Runtime.
-func NewMain(cfg *config.AnalyzerConfig, target storage.TargetStorage, logger *log.Logger) (*Main, error) {
+// Committed rounds are published on the provided bus, which may be nil if disabled.
+func NewMain(cfg *config.AnalyzerConfig, target storage.TargetStorage, b streaming.Bus, logger *log.Logger) (*Main, error) {
 	ctx := context.Background()
 
 	// Initialize source storage.
@@ -76,8 +83,9 @@ func NewMain(cfg *config.AnalyzerConfig, target storage.TargetStorage, logger *l
 		To:   uint64(cfg.To),
 	}
 	ac := analyzer.RuntimeConfig{
-		Range:  roundRange,
-		Source: client,
+		ChainContext: cfg.ChainContext,
+		Range:        roundRange,
+		Source:       client,
 	}
 
 	qf := analyzer.NewQueryFactory(strcase.ToSnake(cfg.ChainID), emerald.String())
@@ -89,6 +97,10 @@ func NewMain(cfg *config.AnalyzerConfig, target storage.TargetStorage, logger *l
 		logger:  logger,
 		metrics: metrics.NewDefaultDatabaseMetrics(emeraldMainDamaskName),
 
+		analysisMetrics: metrics.NewDefaultAnalysisMetrics(emeraldMainDamaskName, cfg.ChainID),
+
+		bus: b,
+
 		// module handlers
 		moduleHandlers: []modules.ModuleHandler{
 			modules.NewCoreHandler(client, &qf, logger),
@@ -98,8 +110,10 @@ func NewMain(cfg *config.AnalyzerConfig, target storage.TargetStorage, logger *l
 	}, nil
 }
 
-func (m *Main) Start() {
-	ctx := context.Background()
+// Start starts the main emerald analyzer.
+func (m *Main) Start(ctx context.Context) {
+	// Start chain head worker.
+	go m.chainHeadWorker(ctx)
 
 	// Get round to be indexed.
 	var round uint64
@@ -132,6 +146,13 @@ func (m *Main) Start() {
 		return
 	}
 	for m.cfg.Range.To == 0 || round <= m.cfg.Range.To {
+		if ctx.Err() != nil {
+			m.logger.Info("shutting down analyzer",
+				"round", round,
+			)
+			return
+		}
+
 		if err := m.processRound(ctx, round); err != nil {
 			if err == analyzer.ErrOutOfRange {
 				m.logger.Info("no data source available for this round",
@@ -140,13 +161,20 @@ func (m *Main) Start() {
 				return
 			}
 
+			if ctx.Err() != nil {
+				// The analyzer is shutting down.
+				continue
+			}
+
 			m.logger.Error("error processing round",
 				"err", err,
 			)
-			backoff.Wait()
+			m.analysisMetrics.RetryCounter().Inc()
+			_ = backoff.WaitContext(ctx)
 			continue
 		}
 
+		m.analysisMetrics.LatestHeight().Set(float64(round))
 		backoff.Reset()
 		round++
 	}
@@ -157,11 +185,18 @@ func (m *Main) Name() string {
 	return emeraldMainDamaskName
 }
 
+// CheckReady checks that the source of the analyzer is reachable and
+// serves the configured network.
+func (m *Main) CheckReady(ctx context.Context) error {
+	return analyzer.CheckChainContext(ctx, m.cfg.Source, m.cfg.ChainContext)
+}
+
 // latestRound returns the latest round processed by the consensus analyzer.
+// It is read from the primary, which has all rounds processed so far.
 func (m *Main) latestRound(ctx context.Context) (uint64, error) {
 	var latest uint64
 	if err := m.target.QueryRow(
-		ctx,
+		storage.WithPrimary(ctx),
 		m.qf.LatestBlockQuery(),
 		// ^analyzers should only analyze for a single chain ID, and we anchor this
 		// at the starting round.
@@ -192,16 +227,18 @@ func (m *Main) processRound(ctx context.Context, round uint64) error {
 		return nil
 	})
 
-	type prepareFunc = func(context.Context, uint64, *storage.QueryBatch) error
 	for _, h := range m.moduleHandlers {
-		func(f prepareFunc) {
+		func(h modules.ModuleHandler) {
 			group.Go(func() error {
-				if err := f(groupCtx, round, batch); err != nil {
+				timer := m.analysisMetrics.ModuleTimer(h.Name())
+				defer timer.ObserveDuration()
+
+				if err := h.PrepareData(groupCtx, round, batch); err != nil {
 					return err
 				}
 				return nil
 			})
-		}(h.PrepareData)
+		}(h)
 	}
 
 	// Update indexing progress.
@@ -218,21 +255,48 @@ func (m *Main) processRound(ctx context.Context, round uint64) error {
 		return err
 	}
 
+	m.analysisMetrics.BatchSize().Observe(float64(batch.Len()))
+
 	opName := fmt.Sprintf("process_round_%s", emerald.String())
 	timer := m.metrics.DatabaseTimer(m.target.Name(), opName)
 	defer timer.ObserveDuration()
 
-	if err := m.target.SendBatch(ctx, batch); err != nil {
+	// The batch is sent with a context detached from the analyzer so that
+	// an in-flight batch is committed even if shutdown has been requested.
+	sendCtx, cancel := context.WithTimeout(context.Background(), sendBatchTimeout)
+	defer cancel()
+
+	if err := m.target.SendBatch(sendCtx, batch); err != nil {
 		m.metrics.DatabaseCounter(m.target.Name(), opName, "failure").Inc()
 		return err
 	}
 	m.metrics.DatabaseCounter(m.target.Name(), opName, "success").Inc()
+
+	m.publish(ctx, round)
 	return nil
 }
 
+// publish announces that the provided round has been committed.
+func (m *Main) publish(ctx context.Context, round uint64) {
+	if m.bus == nil {
+		return
+	}
+	if err := m.bus.Publish(ctx, &streaming.Message{
+		Layer:  streaming.LayerEmerald,
+		Height: int64(round),
+	}); err != nil {
+		m.logger.Error("failed to publish round",
+			"round", round,
+			"err", err,
+		)
+	}
+}
+
 // prepareBlockData adds block data queries to the batch.
 func (m *Main) prepareBlockData(ctx context.Context, round uint64, batch *storage.QueryBatch) error {
+	timer := m.analysisMetrics.SourceTimer("BlockData")
 	data, err := m.cfg.Source.BlockData(ctx, round)
+	timer.ObserveDuration()
 	if err != nil {
 		return err
 	}
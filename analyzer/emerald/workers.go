@@ -0,0 +1,39 @@
+package emerald
+
+import (
+	"context"
+	"time"
+)
+
+const (
+	chainHeadWorkerInterval = 6 * time.Second
+	chainHeadWorkerTimeout  = 10 * time.Second
+)
+
+// The chain head worker tracks the latest round available from source storage.
+func (m *Main) chainHeadWorker(ctx context.Context) {
+	m.logger.Info("starting chain head worker")
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(chainHeadWorkerInterval):
+			func() {
+				cancelCtx, cancel := context.WithTimeout(ctx, chainHeadWorkerTimeout)
+				defer cancel()
+
+				timer := m.analysisMetrics.SourceTimer("LatestRound")
+				round, err := m.cfg.Source.LatestRound(cancelCtx)
+				timer.ObserveDuration()
+				if err != nil {
+					m.logger.Error("failed to fetch chain head",
+						"err", err,
+					)
+					return
+				}
+
+				m.analysisMetrics.ChainHead().Set(float64(round))
+			}()
+		}
+	}
+}
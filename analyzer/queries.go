@@ -35,6 +35,12 @@ func (qf QueryFactory) ConsensusBlockInsertQuery() string {
 			VALUES ($1, $2, $3, $4, $5, $6, $7)`, qf.chainID)
 }
 
+func (qf QueryFactory) ConsensusEpochStartQuery() string {
+	return fmt.Sprintf(`
+		SELECT start_height FROM %s.epochs
+			WHERE id = $1`, qf.chainID)
+}
+
 func (qf QueryFactory) ConsensusEpochInsertQuery() string {
 	return fmt.Sprintf(`
 		INSERT INTO %s.epochs (id, start_height)
@@ -80,8 +86,8 @@ func (qf QueryFactory) ConsensusEventInsertQuery() string {
 
 func (qf QueryFactory) ConsensusRuntimeUpsertQuery() string {
 	return fmt.Sprintf(`
-		INSERT INTO %s.runtimes (id, suspended, kind, tee_hardware, key_manager)
-			VALUES ($1, $2, $3, $4, $5)
+		INSERT INTO %s.runtimes (id, suspended, kind, tee_hardware, key_manager, genesis_round, genesis_state_root)
+			VALUES ($1, $2, $3, $4, $5, $6, $7)
 			ON CONFLICT (id) DO
 			UPDATE SET
 				suspended = excluded.suspended,
@@ -104,6 +110,21 @@ func (qf QueryFactory) ConsensusRuntimeUnsuspensionQuery() string {
 			WHERE id = $1`, qf.chainID)
 }
 
+func (qf QueryFactory) ConsensusRuntimeHistoryInsertQuery() string {
+	return fmt.Sprintf(`
+		INSERT INTO %s.runtime_history (runtime, height, event)
+			VALUES ($1, $2, $3)`, qf.chainID)
+}
+
+// ConsensusRuntimeRegistrationHistoryInsertQuery records the registration of
+// a runtime, or the update of its descriptor if it is already registered. It
+// is to be queued before the runtime is upserted.
+func (qf QueryFactory) ConsensusRuntimeRegistrationHistoryInsertQuery() string {
+	return fmt.Sprintf(`
+		INSERT INTO %[1]s.runtime_history (runtime, height, event)
+			SELECT $1, $2, CASE WHEN EXISTS (SELECT 1 FROM %[1]s.runtimes WHERE id = $1) THEN 'updated' ELSE 'registered' END`, qf.chainID)
+}
+
 func (qf QueryFactory) ConsensusClaimedNodeInsertQuery() string {
 	return fmt.Sprintf(`
 		INSERT INTO %s.claimed_nodes (entity_id, node_id) VALUES ($1, $2)
@@ -120,7 +141,7 @@ func (qf QueryFactory) ConsensusEntityUpsertQuery() string {
 
 func (qf QueryFactory) ConsensusNodeUpsertQuery() string {
 	return fmt.Sprintf(`
-		INSERT INTO %s.nodes (id, entity_id, expiration, tls_pubkey, tls_next_pubkey, tls_addresses, p2p_pubkey, p2p_addresses, consensus_pubkey, consensus_address, vrf_pubkey, roles, software_version, voting_power)
+		INSERT INTO %s.nodes (id, entity_id, expiration, tls_pubkey, tls_next_pubkey, tls_addresses, p2p_pubkey, p2p_addresses, consensus_pubkey, consensus_address, vrf_pubkey, roles, software_version, runtimes)
 			VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14)
 		ON CONFLICT (id) DO UPDATE
 		SET
@@ -136,7 +157,7 @@ func (qf QueryFactory) ConsensusNodeUpsertQuery() string {
 			vrf_pubkey = excluded.vrf_pubkey,
 			roles = excluded.roles,
 			software_version = excluded.software_version,
-			voting_power = excluded.voting_power`, qf.chainID)
+			runtimes = excluded.runtimes`, qf.chainID)
 }
 
 func (qf QueryFactory) ConsensusNodeDeleteQuery() string {
@@ -144,6 +165,26 @@ func (qf QueryFactory) ConsensusNodeDeleteQuery() string {
 		DELETE FROM %s.nodes WHERE id = $1`, qf.chainID)
 }
 
+func (qf QueryFactory) ConsensusNodeFreezeUpdateQuery() string {
+	return fmt.Sprintf(`
+		UPDATE %s.nodes SET freeze_end = $2
+			WHERE id = $1`, qf.chainID)
+}
+
+func (qf QueryFactory) ConsensusNodeHistoryInsertQuery() string {
+	return fmt.Sprintf(`
+		INSERT INTO %s.node_history (node_id, entity_id, height, event, expiration, software_version, roles, tls_addresses, p2p_addresses, consensus_address, freeze_end)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)`, qf.chainID)
+}
+
+func (qf QueryFactory) ConsensusNodeHistorySnapshotQuery() string {
+	return fmt.Sprintf(`
+		INSERT INTO %[1]s.node_history (node_id, entity_id, height, event, expiration, software_version, roles, tls_addresses, p2p_addresses, consensus_address, freeze_end)
+			SELECT id, entity_id, $2, $3, expiration, software_version, roles, tls_addresses, p2p_addresses, consensus_address, freeze_end
+			FROM %[1]s.nodes
+			WHERE id = $1`, qf.chainID)
+}
+
 func (qf QueryFactory) ConsensusEntityMetaUpsertQuery() string {
 	return fmt.Sprintf(`
 		UPDATE %s.entities
@@ -284,6 +325,26 @@ func (qf QueryFactory) ConsensusValidatorNodeUpdateQuery() string {
 			WHERE id = $1`, qf.chainID)
 }
 
+func (qf QueryFactory) ConsensusValidatorNodesResetQuery() string {
+	return fmt.Sprintf(`
+		UPDATE %s.nodes SET voting_power = 0
+			WHERE voting_power <> 0 AND NOT id = ANY($1)`, qf.chainID)
+}
+
+func (qf QueryFactory) ConsensusEpochValidatorsDeleteQuery() string {
+	return fmt.Sprintf(`
+		DELETE FROM %s.epoch_validators
+			WHERE epoch = $1 AND NOT node_id = ANY($2)`, qf.chainID)
+}
+
+func (qf QueryFactory) ConsensusEpochValidatorUpsertQuery() string {
+	return fmt.Sprintf(`
+		INSERT INTO %s.epoch_validators (epoch, node_id, voting_power)
+			VALUES ($1, $2, $3)
+		ON CONFLICT (epoch, node_id) DO
+			UPDATE SET voting_power = excluded.voting_power`, qf.chainID)
+}
+
 func (qf QueryFactory) ConsensusCommitteeMemberInsertQuery() string {
 	return fmt.Sprintf(`
 		INSERT INTO %s.committee_members (node, valid_for, runtime, kind, role)
@@ -295,16 +356,23 @@ func (qf QueryFactory) ConsensusCommitteeMembersTruncateQuery() string {
 		TRUNCATE %s.committee_members`, qf.chainID)
 }
 
+func (qf QueryFactory) ConsensusEpochCommitteeMemberInsertQuery() string {
+	return fmt.Sprintf(`
+		INSERT INTO %s.epoch_committee_members (epoch, runtime, kind, node, role)
+			VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT (epoch, runtime, kind, node, role) DO NOTHING`, qf.chainID)
+}
+
 func (qf QueryFactory) ConsensusProposalSubmissionInsertQuery() string {
 	return fmt.Sprintf(`
-		INSERT INTO %s.proposals (id, submitter, state, deposit, handler, cp_target_version, rhp_target_version, rcp_target_version, upgrade_epoch, created_at, closes_at)
-			VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)`, qf.chainID)
+		INSERT INTO %s.proposals (id, submitter, state, deposit, handler, cp_target_version, rhp_target_version, rcp_target_version, upgrade_epoch, created_at, closes_at, content)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)`, qf.chainID)
 }
 
 func (qf QueryFactory) ConsensusProposalSubmissionCancelInsertQuery() string {
 	return fmt.Sprintf(`
-		INSERT INTO %s.proposals (id, submitter, state, deposit, cancels, created_at, closes_at)
-			VALUES ($1, $2, $3, $4, $5, $6, $7)`, qf.chainID)
+		INSERT INTO %s.proposals (id, submitter, state, deposit, cancels, created_at, closes_at, content)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8)`, qf.chainID)
 }
 
 func (qf QueryFactory) ConsensusProposalExecutionsUpdateQuery() string {
@@ -328,10 +396,151 @@ func (qf QueryFactory) ConsensusProposalInvalidVotesUpdateQuery() string {
 			WHERE id = $1`, qf.chainID)
 }
 
+func (qf QueryFactory) ConsensusProposalResultsUpdateQuery() string {
+	return fmt.Sprintf(`
+		UPDATE %s.proposals
+		SET yes_stake = $2, no_stake = $3, abstain_stake = $4
+			WHERE id = $1`, qf.chainID)
+}
+
 func (qf QueryFactory) ConsensusVoteInsertQuery() string {
 	return fmt.Sprintf(`
 		INSERT INTO %s.votes (proposal, voter, vote)
-			VALUES ($1, $2, $3)`, qf.chainID)
+			VALUES ($1, $2, $3)
+		ON CONFLICT (proposal, voter) DO
+			UPDATE SET vote = excluded.vote`, qf.chainID)
+}
+
+func (qf QueryFactory) ConsensusTalliedVoteUpsertQuery() string {
+	return fmt.Sprintf(`
+		INSERT INTO %s.votes (proposal, voter, vote, weight)
+			VALUES ($1, $2, $3, $4)
+		ON CONFLICT (proposal, voter) DO
+			UPDATE SET vote = excluded.vote, weight = excluded.weight`, qf.chainID)
+}
+
+func (qf QueryFactory) ConsensusCheckpointDeleteQuery() string {
+	return fmt.Sprintf(`
+		DELETE FROM %s.checkpointed_heights
+			WHERE height = $1`, qf.chainID)
+}
+
+func (qf QueryFactory) ConsensusCheckpointInsertQuery() string {
+	return fmt.Sprintf(`
+		INSERT INTO %s.checkpointed_heights (height, epoch)
+			VALUES ($1, $2)`, qf.chainID)
+}
+
+// ConsensusLatestCheckpointInsertQuery records a checkpoint at a height
+// only if it is still the latest height processed by the analyzer.
+func (qf QueryFactory) ConsensusLatestCheckpointInsertQuery() string {
+	return fmt.Sprintf(`
+		INSERT INTO %[1]s.checkpointed_heights (height, epoch)
+			SELECT height, $2 FROM (
+				SELECT height FROM %[1]s.processed_blocks
+					WHERE analyzer = $3
+				ORDER BY height DESC
+				LIMIT 1
+			) AS latest
+			WHERE height = $1`, qf.chainID)
+}
+
+// ConsensusCheckpointTakenQuery returns whether there is a periodic
+// checkpoint of an epoch since the provided one, up to the provided height.
+func (qf QueryFactory) ConsensusCheckpointTakenQuery() string {
+	return fmt.Sprintf(`
+		SELECT EXISTS (
+			SELECT 1 FROM %s.checkpointed_heights
+				WHERE epoch >= $1 AND height <= $2
+		)`, qf.chainID)
+}
+
+// ConsensusCheckpointQueries returns the queries that copy consensus state
+// into the checkpoint tables, stamped with the height of the checkpoint,
+// which is their only argument.
+func (qf QueryFactory) ConsensusCheckpointQueries() []string {
+	return []string{
+		qf.ConsensusEntitiesCheckpointQuery(),
+		qf.ConsensusClaimedNodesCheckpointQuery(),
+		qf.ConsensusNodesCheckpointQuery(),
+		qf.ConsensusRuntimesCheckpointQuery(),
+		qf.ConsensusAccountsCheckpointQuery(),
+		qf.ConsensusAllowancesCheckpointQuery(),
+		qf.ConsensusDelegationsCheckpointQuery(),
+		qf.ConsensusDebondingDelegationsCheckpointQuery(),
+		qf.ConsensusProposalsCheckpointQuery(),
+		qf.ConsensusVotesCheckpointQuery(),
+	}
+}
+
+func (qf QueryFactory) ConsensusEntitiesCheckpointQuery() string {
+	return fmt.Sprintf(`
+		INSERT INTO %[1]s.entities_checkpoints (height, id, address)
+			SELECT $1, id, address
+			FROM %[1]s.entities`, qf.chainID)
+}
+
+func (qf QueryFactory) ConsensusClaimedNodesCheckpointQuery() string {
+	return fmt.Sprintf(`
+		INSERT INTO %[1]s.claimed_nodes_checkpoints (height, entity_id, node_id)
+			SELECT $1, entity_id, node_id
+			FROM %[1]s.claimed_nodes`, qf.chainID)
+}
+
+func (qf QueryFactory) ConsensusNodesCheckpointQuery() string {
+	return fmt.Sprintf(`
+		INSERT INTO %[1]s.nodes_checkpoints (height, id, entity_id, expiration, tls_pubkey, tls_next_pubkey, tls_addresses, p2p_pubkey, p2p_addresses, consensus_pubkey, consensus_address, vrf_pubkey, roles, software_version, voting_power, freeze_end, runtimes)
+			SELECT $1, id, entity_id, expiration, tls_pubkey, tls_next_pubkey, tls_addresses, p2p_pubkey, p2p_addresses, consensus_pubkey, consensus_address, vrf_pubkey, roles, software_version, voting_power, freeze_end, runtimes
+			FROM %[1]s.nodes`, qf.chainID)
+}
+
+func (qf QueryFactory) ConsensusRuntimesCheckpointQuery() string {
+	return fmt.Sprintf(`
+		INSERT INTO %[1]s.runtimes_checkpoints (height, id, suspended, kind, tee_hardware, key_manager)
+			SELECT $1, id, suspended, kind, tee_hardware, key_manager
+			FROM %[1]s.runtimes`, qf.chainID)
+}
+
+func (qf QueryFactory) ConsensusAccountsCheckpointQuery() string {
+	return fmt.Sprintf(`
+		INSERT INTO %[1]s.accounts_checkpoints (height, address, general_balance, nonce, escrow_balance_active, escrow_total_shares_active, escrow_balance_debonding, escrow_total_shares_debonding)
+			SELECT $1, address, general_balance, nonce, escrow_balance_active, escrow_total_shares_active, escrow_balance_debonding, escrow_total_shares_debonding
+			FROM %[1]s.accounts`, qf.chainID)
+}
+
+func (qf QueryFactory) ConsensusAllowancesCheckpointQuery() string {
+	return fmt.Sprintf(`
+		INSERT INTO %[1]s.allowances_checkpoints (height, owner, beneficiary, allowance)
+			SELECT $1, owner, beneficiary, allowance
+			FROM %[1]s.allowances`, qf.chainID)
+}
+
+func (qf QueryFactory) ConsensusDelegationsCheckpointQuery() string {
+	return fmt.Sprintf(`
+		INSERT INTO %[1]s.delegations_checkpoints (height, delegatee, delegator, shares)
+			SELECT $1, delegatee, delegator, shares
+			FROM %[1]s.delegations`, qf.chainID)
+}
+
+func (qf QueryFactory) ConsensusDebondingDelegationsCheckpointQuery() string {
+	return fmt.Sprintf(`
+		INSERT INTO %[1]s.debonding_delegations_checkpoints (height, id, delegatee, delegator, shares, debond_end)
+			SELECT $1, id, delegatee, delegator, shares, debond_end
+			FROM %[1]s.debonding_delegations`, qf.chainID)
+}
+
+func (qf QueryFactory) ConsensusProposalsCheckpointQuery() string {
+	return fmt.Sprintf(`
+		INSERT INTO %[1]s.proposals_checkpoints (height, id, submitter, state, executed, deposit, handler, cp_target_version, rhp_target_version, rcp_target_version, upgrade_epoch, cancels, created_at, closes_at, invalid_votes)
+			SELECT $1, id, submitter, state, executed, deposit, handler, cp_target_version, rhp_target_version, rcp_target_version, upgrade_epoch, cancels, created_at, closes_at, invalid_votes
+			FROM %[1]s.proposals`, qf.chainID)
+}
+
+func (qf QueryFactory) ConsensusVotesCheckpointQuery() string {
+	return fmt.Sprintf(`
+		INSERT INTO %[1]s.votes_checkpoints (height, proposal, voter, vote, weight)
+			SELECT $1, proposal, voter, vote, weight
+			FROM %[1]s.votes`, qf.chainID)
 }
 
 func (qf QueryFactory) RuntimeBlockInsertQuery() string {
@@ -2,10 +2,12 @@
 package util
 
 import (
+	"context"
 	"fmt"
 	"time"
 
 	beacon "github.com/oasisprotocol/oasis-core/go/beacon/api"
+	"github.com/oasisprotocol/oasis-core/go/common/node"
 	staking "github.com/oasisprotocol/oasis-core/go/staking/api"
 )
 
@@ -42,11 +44,26 @@ func NewBackoff(initialTimeout time.Duration, maximumTimeout time.Duration) (*Ba
 
 // Wait waits for the appropriate backoff interval.
 func (b *Backoff) Wait() {
-	time.Sleep(b.currentTimeout)
+	_ = b.WaitContext(context.Background())
+}
+
+// WaitContext waits for the appropriate backoff interval, returning
+// early with the context's error if it is cancelled.
+func (b *Backoff) WaitContext(ctx context.Context) error {
+	timer := time.NewTimer(b.currentTimeout)
+	defer timer.Stop()
+
 	b.currentTimeout *= 2
 	if b.currentTimeout > b.maximumTimeout {
 		b.currentTimeout = b.maximumTimeout
 	}
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
 }
 
 // Reset resets the backoff.
@@ -80,3 +97,21 @@ func CurrentBound(cs staking.CommissionSchedule, now beacon.EpochTime) (currentB
 
 	return latestStartedStep, uint64(cs.Bounds[i].Start - 1)
 }
+
+// NodeRuntime is a runtime that a node is registered for, as it is stored.
+type NodeRuntime struct {
+	ID      string `json:"id"`
+	Version string `json:"version"`
+}
+
+// NodeRuntimes returns the runtimes that a node is registered for.
+func NodeRuntimes(n *node.Node) []NodeRuntime {
+	runtimes := make([]NodeRuntime, 0, len(n.Runtimes))
+	for _, rt := range n.Runtimes {
+		runtimes = append(runtimes, NodeRuntime{
+			ID:      rt.ID.String(),
+			Version: rt.Version.String(),
+		})
+	}
+	return runtimes
+}
@@ -1,11 +1,15 @@
 package util
 
 import (
+	"context"
 	"testing"
 	"time"
 
 	beacon "github.com/oasisprotocol/oasis-core/go/beacon/api"
+	"github.com/oasisprotocol/oasis-core/go/common"
+	"github.com/oasisprotocol/oasis-core/go/common/node"
 	"github.com/oasisprotocol/oasis-core/go/common/quantity"
+	"github.com/oasisprotocol/oasis-core/go/common/version"
 	staking "github.com/oasisprotocol/oasis-core/go/staking/api"
 	"github.com/stretchr/testify/require"
 )
@@ -45,6 +49,26 @@ func TestBackoffMaximum(t *testing.T) {
 	require.Equal(t, backoff.Timeout(), 10*time.Millisecond)
 }
 
+// TestBackoffWaitContext tests if waiting on the backoff
+// returns early when the context is cancelled.
+func TestBackoffWaitContext(t *testing.T) {
+	backoff, err := NewBackoff(time.Millisecond, 10*time.Second)
+	require.Nil(t, err)
+
+	require.Nil(t, backoff.WaitContext(context.Background()))
+	require.Equal(t, backoff.Timeout(), 2*time.Millisecond)
+
+	backoff, err = NewBackoff(5*time.Second, 10*time.Second)
+	require.Nil(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	start := time.Now()
+	require.ErrorIs(t, backoff.WaitContext(ctx), context.Canceled)
+	require.Less(t, time.Since(start), time.Second)
+}
+
 // TestMaximumTimeoutUpperBound tests that the maximum timeout upper
 // bound is respected.
 func TestMaximumTimeoutUpperBound(t *testing.T) {
@@ -102,3 +126,19 @@ func TestCurrentBound(t *testing.T) {
 	})
 	require.Equal(t, epochEnd, uint64(0))
 }
+
+// TestNodeRuntimes tests that the runtimes of nodes are
+// stored by ID and version.
+func TestNodeRuntimes(t *testing.T) {
+	var id common.Namespace
+	require.Nil(t, id.UnmarshalHex("000000000000000000000000000000000000000000000000e2eaa99fc008f87f"))
+
+	require.Equal(t, []NodeRuntime{}, NodeRuntimes(&node.Node{}))
+	require.Equal(t, []NodeRuntime{
+		{ID: id.String(), Version: "1.2.3"},
+	}, NodeRuntimes(&node.Node{
+		Runtimes: []*node.Runtime{
+			{ID: id, Version: version.Version{Major: 1, Minor: 2, Patch: 3}},
+		},
+	}))
+}
@@ -7,9 +7,15 @@ import (
 	"github.com/go-chi/chi/v5"
 	"github.com/go-chi/chi/v5/middleware"
 
+	"github.com/oasisprotocol/oasis-indexer/api/auth"
+	"github.com/oasisprotocol/oasis-indexer/api/graphql"
 	v1 "github.com/oasisprotocol/oasis-indexer/api/v1"
+	"github.com/oasisprotocol/oasis-indexer/cache"
+	"github.com/oasisprotocol/oasis-indexer/config"
+	"github.com/oasisprotocol/oasis-indexer/health"
 	"github.com/oasisprotocol/oasis-indexer/log"
 	"github.com/oasisprotocol/oasis-indexer/storage"
+	"github.com/oasisprotocol/oasis-indexer/streaming"
 )
 
 const (
@@ -35,24 +41,40 @@ type IndexerAPI struct {
 	logger   *log.Logger
 }
 
-// NewIndexerAPI creates a new Indexer API.
-func NewIndexerAPI(db storage.TargetStorage, l *log.Logger) *IndexerAPI {
+// NewIndexerAPI creates a new Indexer API. Newly indexed data
+// is streamed from the provided bus, if any, and responses are
+// cached in the provided cache, if any. Requests are rate
+// limited if a rate limit configuration is provided. Health
+// probes are served by the provided prober, if any.
+func NewIndexerAPI(db storage.TargetStorage, b streaming.Bus, c cache.Cache, rateLimitCfg *config.RateLimitConfig, p *health.Prober, l *log.Logger) *IndexerAPI {
 	r := chi.NewRouter()
 
-	// Register handlers.
-	v1Handler := v1.NewHandler(db, l)
+	// Register probes outside of the middlewares of handlers, so
+	// that they are neither rate limited nor require an API key.
+	if p != nil {
+		p.Register(r)
+	}
+
+	// Register handlers. Middlewares are applied in order, so rate
+	// limits are applied once requests have been assigned an ID.
 	handlers := []Handler{
-		v1Handler,
+		v1.NewHandler(db, b, c, l),
 	}
-	for _, handler := range handlers {
-		handler.RegisterMiddlewares(r)
+	if rateLimitCfg != nil {
+		handlers = append(handlers, auth.NewHandler(db, rateLimitCfg, l))
 	}
-	r.Use(middleware.Recoverer)
+	handlers = append(handlers, graphql.NewHandler(db, l))
+	r.Group(func(r chi.Router) {
+		for _, handler := range handlers {
+			handler.RegisterMiddlewares(r)
+		}
+		r.Use(middleware.Recoverer)
 
-	// Register routes.
-	for _, handler := range handlers {
-		handler.RegisterRoutes(r)
-	}
+		// Register routes.
+		for _, handler := range handlers {
+			handler.RegisterRoutes(r)
+		}
+	})
 
 	return &IndexerAPI{
 		router:   r,
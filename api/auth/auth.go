@@ -0,0 +1,394 @@
+// Package auth implements API keys and rate limits for the Oasis Indexer API.
+package auth
+
+import (
+	"context"
+	"errors"
+	"math"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/chi/v5/middleware"
+
+	"github.com/oasisprotocol/oasis-indexer/api/common"
+	v1 "github.com/oasisprotocol/oasis-indexer/api/v1"
+	"github.com/oasisprotocol/oasis-indexer/config"
+	"github.com/oasisprotocol/oasis-indexer/log"
+	"github.com/oasisprotocol/oasis-indexer/metrics"
+	"github.com/oasisprotocol/oasis-indexer/storage"
+)
+
+const (
+	moduleName = "api_auth"
+
+	// KeyHeader is the header in which clients provide their API key.
+	KeyHeader = "X-API-Key"
+
+	// AnonymousTier is the tier of requests without an API key.
+	AnonymousTier = "anonymous"
+
+	// keyRefreshInterval is how often API keys are reloaded from
+	// target storage, which bounds how long revoked keys remain usable.
+	keyRefreshInterval = time.Minute
+
+	// usageSyncInterval is how often quota usage is synchronized with
+	// target storage, which bounds how long clients may exceed their
+	// quota across API servers.
+	usageSyncInterval = 10 * time.Second
+
+	// backgroundTimeout bounds how long reloading keys and synchronizing
+	// quota usage in the background may take.
+	backgroundTimeout = 10 * time.Second
+)
+
+var (
+	errInvalidKey      = &common.Error{Code: common.CodeUnauthorized, Status: http.StatusUnauthorized, Msg: "invalid api key"}
+	errTierUnavailable = &common.Error{Code: common.CodeForbidden, Status: http.StatusForbidden, Msg: "api key tier is not available"}
+	errKeysUnavailable = &common.Error{Code: common.CodeStorageError, Status: http.StatusServiceUnavailable, Msg: "unable to verify api key"}
+)
+
+// Handler authenticates and rate limits requests to the API.
+type Handler struct {
+	requireKey bool
+	trustProxy bool
+	anonymous  *limiter
+	tiers      map[string]*limiter
+
+	// loadKeys loads the active API keys, by key hash.
+	loadKeys func(ctx context.Context) (map[string]*Key, error)
+
+	keysMu      sync.RWMutex
+	keys        map[string]*Key
+	keysLoaded  time.Time
+	keysErr     error
+	keysLoading bool
+
+	// addUsage adds to the quota usage of clients on a day, returning
+	// their total usage, and deleteUsage deletes the usage of previous days.
+	addUsage    func(ctx context.Context, day int64, usage map[string]int64) (map[string]int64, error)
+	deleteUsage func(ctx context.Context, day int64) error
+
+	usageMu      sync.Mutex
+	usageSynced  time.Time
+	usageSyncing bool
+	usageDay     int64
+
+	now     func() time.Time
+	logger  *log.Logger
+	metrics metrics.RequestMetrics
+}
+
+// NewHandler creates a new handler that authenticates requests with
+// API keys from target storage, and rate limits them per key or per
+// client IP according to the provided configuration.
+func NewHandler(db storage.TargetStorage, cfg *config.RateLimitConfig, l *log.Logger) *Handler {
+	store := NewStore(db)
+	h := &Handler{
+		requireKey:  cfg.RequireKey,
+		trustProxy:  cfg.TrustProxy,
+		tiers:       make(map[string]*limiter, len(cfg.Tiers)),
+		loadKeys:    store.active,
+		addUsage:    store.addUsage,
+		deleteUsage: store.deleteUsage,
+		now:         time.Now,
+		logger:      l.WithModule(moduleName),
+		metrics:     metrics.NewDefaultRequestMetrics(moduleName),
+	}
+	if cfg.Anonymous != nil {
+		h.anonymous = newLimiter(cfg.Anonymous)
+	}
+	for name, tier := range cfg.Tiers {
+		h.tiers[name] = newLimiter(tier)
+	}
+	return h
+}
+
+// RegisterMiddlewares implements the APIHandler interface.
+func (h *Handler) RegisterMiddlewares(r chi.Router) {
+	if h.trustProxy {
+		r.Use(middleware.RealIP)
+	}
+	r.Use(h.limitMiddleware)
+}
+
+// RegisterRoutes implements the APIHandler interface.
+func (h *Handler) RegisterRoutes(r chi.Router) {}
+
+// Name implements the APIHandler interface.
+func (h *Handler) Name() string {
+	return "auth"
+}
+
+// limitMiddleware is a middleware that identifies clients by their
+// API key, or by their IP if none is provided, and rejects requests
+// from clients that exceeded the limits of their tier.
+func (h *Handler) limitMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+
+		var client, keyID, tier string
+		var l *limiter
+		if secret := r.Header.Get(KeyHeader); secret != "" {
+			key, err := h.lookup(ctx, secret)
+			switch err {
+			case nil:
+			case ErrKeyNotFound:
+				h.reply(w, r, errInvalidKey)
+				return
+			default:
+				h.logger.Error("failed to load api keys",
+					"request_id", ctx.Value(v1.RequestIDContextKey),
+					"err", err.Error(),
+				)
+				h.reply(w, r, errKeysUnavailable)
+				return
+			}
+
+			if l = h.tiers[key.Tier]; l == nil {
+				h.logger.Error("api key has unknown tier",
+					"request_id", ctx.Value(v1.RequestIDContextKey),
+					"key_id", key.ID,
+					"tier", key.Tier,
+				)
+				h.reply(w, r, errTierUnavailable)
+				return
+			}
+			keyID = strconv.FormatInt(key.ID, 10)
+			client, tier = "key:"+keyID, key.Tier
+			r = r.WithContext(context.WithValue(ctx, v1.APIKeyIDContextKey, key.ID))
+		} else {
+			if h.requireKey || h.anonymous == nil {
+				h.reply(w, r, common.ErrKeyRequired)
+				return
+			}
+			l, client, tier = h.anonymous, "ip:"+clientIP(r), AnonymousTier
+		}
+
+		now := h.now()
+		wait, err := l.take(client, requestCost(r), now)
+		h.syncUsageInBackground(now)
+		if err != nil {
+			w.Header().Set("Retry-After", strconv.FormatInt(int64(math.Ceil(wait.Seconds())), 10))
+			h.reply(w, r, err)
+			h.metrics.KeyRequestCounter(keyID, tier, common.ErrorCause(err)).Inc()
+			return
+		}
+		h.metrics.KeyRequestCounter(keyID, tier, "allowed").Inc()
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+// lookup returns the active API key with the provided secret. Keys are
+// cached, so that requests do not hit target storage. Requests only wait
+// for keys to be loaded until they first are, and fail with the last error
+// until the refresh interval has passed after a failed load; afterwards,
+// keys are reloaded in the background, and previously loaded keys are used.
+func (h *Handler) lookup(ctx context.Context, secret string) (*Key, error) {
+	h.keysMu.RLock()
+	keys, loaded, keysErr := h.keys, h.keysLoaded, h.keysErr
+	h.keysMu.RUnlock()
+
+	switch now := h.now(); {
+	case keys == nil && now.Sub(loaded) >= keyRefreshInterval:
+		var err error
+		if keys, err = h.reloadKeys(ctx, now); err != nil {
+			return nil, err
+		}
+	case keys == nil:
+		if keysErr == nil {
+			keysErr = common.ErrStorageError
+		}
+		return nil, keysErr
+	case now.Sub(loaded) >= keyRefreshInterval:
+		h.reloadKeysInBackground(now)
+	}
+
+	key, ok := keys[hashKey(secret)]
+	if !ok {
+		return nil, ErrKeyNotFound
+	}
+	return key, nil
+}
+
+// reloadKeys reloads the active API keys from target storage.
+func (h *Handler) reloadKeys(ctx context.Context, now time.Time) (map[string]*Key, error) {
+	keys, err := h.loadKeys(ctx)
+
+	h.keysMu.Lock()
+	defer h.keysMu.Unlock()
+	if err != nil {
+		// Retry once the refresh interval has passed again, rather
+		// than on every request while target storage is unavailable.
+		h.keysLoaded, h.keysErr = now, err
+		return nil, err
+	}
+	h.keys, h.keysLoaded, h.keysErr = keys, now, nil
+	return keys, nil
+}
+
+// reloadKeysInBackground reloads the active API keys from target storage,
+// unless they are already being reloaded.
+func (h *Handler) reloadKeysInBackground(now time.Time) {
+	h.keysMu.Lock()
+	defer h.keysMu.Unlock()
+	if h.keysLoading {
+		return
+	}
+	h.keysLoading = true
+
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), backgroundTimeout)
+		defer cancel()
+
+		if _, err := h.reloadKeys(ctx, now); err != nil {
+			h.logger.Error("failed to reload api keys, using cached keys",
+				"err", err.Error(),
+			)
+		}
+
+		h.keysMu.Lock()
+		h.keysLoading = false
+		h.keysMu.Unlock()
+	}()
+}
+
+// syncUsage adds the quota usage of all clients since the last
+// synchronization to target storage, and learns their total usage,
+// including usage through other API servers.
+func (h *Handler) syncUsage(ctx context.Context, now time.Time) error {
+	limiters := make([]*limiter, 0, len(h.tiers)+1)
+	if h.anonymous != nil {
+		limiters = append(limiters, h.anonymous)
+	}
+	for _, l := range h.tiers {
+		limiters = append(limiters, l)
+	}
+
+	day := unixDay(now)
+	usage := make(map[string]int64)
+	for _, l := range limiters {
+		for client, used := range l.takeUsage(day) {
+			usage[client] = used
+		}
+	}
+	if len(usage) == 0 {
+		return nil
+	}
+
+	totals, err := h.addUsage(ctx, day, usage)
+	if err != nil {
+		for _, l := range limiters {
+			l.restoreUsage(day, usage)
+		}
+		return err
+	}
+	for _, l := range limiters {
+		l.setUsage(day, totals)
+	}
+	return nil
+}
+
+// syncUsageInBackground synchronizes quota usage with target storage once
+// the synchronization interval has passed, unless it is being synchronized
+// already. The usage of previous days is deleted once a day has passed.
+func (h *Handler) syncUsageInBackground(now time.Time) {
+	h.usageMu.Lock()
+	defer h.usageMu.Unlock()
+	if h.usageSyncing || now.Sub(h.usageSynced) < usageSyncInterval {
+		return
+	}
+	h.usageSyncing, h.usageSynced = true, now
+	day := unixDay(now)
+	newDay := day != h.usageDay
+	h.usageDay = day
+
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), backgroundTimeout)
+		defer cancel()
+
+		if err := h.syncUsage(ctx, now); err != nil {
+			h.logger.Error("failed to synchronize quota usage",
+				"err", err.Error(),
+			)
+		}
+		if newDay {
+			if err := h.deleteUsage(ctx, day); err != nil {
+				h.logger.Error("failed to delete past quota usage",
+					"err", err.Error(),
+				)
+			}
+		}
+
+		h.usageMu.Lock()
+		h.usageSyncing = false
+		h.usageMu.Unlock()
+	}()
+}
+
+// reply replies to a rejected request with an error as JSON, and
+// counts the request by the cause of the error. Errors other than
+// those of the request are logged, since they are not replied with.
+func (h *Handler) reply(w http.ResponseWriter, r *http.Request, err error) {
+	var e *common.Error
+	if !errors.As(err, &e) {
+		h.logger.Error("failed to authorize request",
+			"request_id", r.Context().Value(v1.RequestIDContextKey),
+			"error", err,
+		)
+	}
+	if err := common.ReplyWithError(w, v1.RequestID(r.Context()), err); err != nil {
+		h.logger.Error("failed to write response",
+			"request_id", r.Context().Value(v1.RequestIDContextKey),
+			"error", err,
+		)
+	}
+	h.metrics.RequestCounter(r.URL.Path, "failure", common.ErrorCause(err)).Inc()
+}
+
+// endpointCosts are the costs of endpoints that are more expensive to
+// serve than a page, by path prefix.
+var endpointCosts = []struct {
+	prefix string
+	cost   int
+}{
+	// Exports stream all matching rows, which are many pages.
+	{"/v1/consensus/export/", 100},
+	// Streams are long-lived, and query storage for every block.
+	{"/v1/stream", 10},
+}
+
+// requestCost returns how many requests a request counts as. Requests
+// for pages larger than the default count as several requests, and
+// requests to some endpoints as many, since they are more expensive
+// to serve.
+func requestCost(r *http.Request) int {
+	for _, e := range endpointCosts {
+		if strings.HasPrefix(r.URL.Path, e.prefix) {
+			return e.cost
+		}
+	}
+
+	limit, err := strconv.ParseUint(r.URL.Query().Get(common.LimitKey), 10, 64)
+	if err != nil || limit <= common.DefaultLimit {
+		return 1
+	}
+	if limit > common.MaximumLimit {
+		limit = common.MaximumLimit
+	}
+	return int((limit + common.DefaultLimit - 1) / common.DefaultLimit)
+}
+
+// clientIP returns the IP of the client that made the request.
+func clientIP(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
@@ -0,0 +1,302 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	v1 "github.com/oasisprotocol/oasis-indexer/api/v1"
+	"github.com/oasisprotocol/oasis-indexer/config"
+	"github.com/oasisprotocol/oasis-indexer/log"
+)
+
+func TestLimiter(t *testing.T) {
+	l := newLimiter(&config.TierConfig{Rate: 2, Burst: 4, DailyQuota: 20})
+	now := time.Date(2022, 4, 11, 23, 59, 0, 0, time.UTC)
+
+	// The burst may be spent at once, after which tokens accrue at the rate.
+	for i := 0; i < 4; i++ {
+		_, err := l.take("a", 1, now)
+		require.Nil(t, err)
+	}
+	wait, err := l.take("a", 1, now)
+	require.ErrorIs(t, err, errRateLimited)
+	require.Equal(t, 500*time.Millisecond, wait)
+
+	// Clients are limited independently.
+	_, err = l.take("b", 1, now)
+	require.Nil(t, err)
+
+	// Costs are capped to the burst, but not towards the quota.
+	now = now.Add(2 * time.Second)
+	_, err = l.take("a", 10, now)
+	require.Nil(t, err)
+
+	// Quotas are spent by cost, and reset at midnight UTC.
+	now = now.Add(2 * time.Second)
+	wait, err = l.take("a", 7, now)
+	require.ErrorIs(t, err, errQuotaExceeded)
+	require.Equal(t, 56*time.Second, wait)
+
+	now = now.Add(time.Minute)
+	_, err = l.take("a", 3, now)
+	require.Nil(t, err)
+
+	// Idle clients are forgotten once their bucket is full
+	// and their quota has been reset.
+	now = now.Add(time.Hour)
+	l.sweep(now)
+	require.Len(t, l.clients, 1)
+	now = now.Add(24 * time.Hour)
+	l.sweep(now)
+	require.Empty(t, l.clients)
+}
+
+func TestRequestCost(t *testing.T) {
+	for _, tc := range []struct {
+		query string
+		cost  int
+	}{
+		{"", 1},
+		{"?limit=10", 1},
+		{"?limit=100", 1},
+		{"?limit=101", 2},
+		{"?limit=1000", 10},
+		{"?limit=100000", 10},
+		{"?limit=invalid", 1},
+	} {
+		r := httptest.NewRequest(http.MethodGet, "/v1/consensus/transactions"+tc.query, nil)
+		require.Equal(t, tc.cost, requestCost(r), tc.query)
+	}
+
+	// Some endpoints cost more regardless of their limit.
+	r := httptest.NewRequest(http.MethodGet, "/v1/consensus/export/account_activity?limit=10", nil)
+	require.Equal(t, 100, requestCost(r))
+	r = httptest.NewRequest(http.MethodGet, "/v1/stream?topic=consensus_blocks", nil)
+	require.Equal(t, 10, requestCost(r))
+}
+
+// usageStore is quota usage in target storage, shared by API servers.
+type usageStore struct {
+	mu    sync.Mutex
+	used  map[string]int64
+	err   error
+	added int
+}
+
+func (s *usageStore) add(ctx context.Context, day int64, usage map[string]int64) (map[string]int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.err != nil {
+		return nil, s.err
+	}
+	s.added++
+
+	totals := make(map[string]int64, len(usage))
+	for client, used := range usage {
+		s.used[client] += used
+		totals[client] = s.used[client]
+	}
+	return totals, nil
+}
+
+func (s *usageStore) delete(ctx context.Context, day int64) error {
+	return nil
+}
+
+func TestSyncUsage(t *testing.T) {
+	store := &usageStore{used: make(map[string]int64)}
+	newServer := func() *Handler {
+		return &Handler{
+			tiers: map[string]*limiter{
+				"default": newLimiter(&config.TierConfig{Rate: 10, Burst: 10, DailyQuota: 5}),
+			},
+			addUsage:    store.add,
+			deleteUsage: store.delete,
+		}
+	}
+	a, b := newServer(), newServer()
+	now := time.Date(2022, 4, 11, 0, 0, 0, 0, time.UTC)
+	ctx := context.Background()
+
+	// Usage through each server is added to storage.
+	for i := 0; i < 3; i++ {
+		_, err := a.tiers["default"].take("key:1", 1, now)
+		require.Nil(t, err)
+	}
+	_, err := b.tiers["default"].take("key:1", 1, now)
+	require.Nil(t, err)
+	require.Nil(t, a.syncUsage(ctx, now))
+	require.Nil(t, b.syncUsage(ctx, now))
+	require.Equal(t, int64(4), store.used["key:1"])
+
+	// Servers learn the usage through other servers.
+	_, err = b.tiers["default"].take("key:1", 2, now)
+	require.ErrorIs(t, err, errQuotaExceeded)
+	_, err = b.tiers["default"].take("key:1", 1, now)
+	require.Nil(t, err)
+
+	// Usage that could not be added is added by the next synchronization.
+	store.err = errors.New("connection refused")
+	require.NotNil(t, b.syncUsage(ctx, now))
+	store.err = nil
+	require.Nil(t, b.syncUsage(ctx, now))
+	require.Equal(t, int64(5), store.used["key:1"])
+	require.Nil(t, a.syncUsage(ctx, now))
+	_, err = a.tiers["default"].take("key:1", 1, now)
+	require.ErrorIs(t, err, errQuotaExceeded)
+
+	// Tiers without quotas are not synchronized.
+	added := store.added
+	h := &Handler{
+		anonymous: newLimiter(&config.TierConfig{Rate: 1, Burst: 1}),
+		addUsage:  store.add,
+	}
+	_, err = h.anonymous.take("ip:192.0.2.1", 1, now)
+	require.Nil(t, err)
+	require.Nil(t, h.syncUsage(ctx, now))
+	require.Equal(t, added, store.added)
+}
+
+func TestMiddleware(t *testing.T) {
+	h := NewHandler(nil, &config.RateLimitConfig{
+		Anonymous: &config.TierConfig{Rate: 1, Burst: 1},
+		Tiers: map[string]*config.TierConfig{
+			"default": {Rate: 1, Burst: 2, DailyQuota: 3},
+		},
+	}, log.NewDefaultLogger("auth"))
+
+	now := time.Date(2022, 4, 11, 0, 0, 0, 0, time.UTC)
+	h.now = func() time.Time { return now }
+
+	keys := map[string]*Key{
+		hashKey("oik_default"): {ID: 1, Name: "default", Tier: "default"},
+		hashKey("oik_premium"): {ID: 2, Name: "premium", Tier: "premium"},
+	}
+	var keysMu sync.Mutex
+	var loadErr error
+	h.loadKeys = func(ctx context.Context) (map[string]*Key, error) {
+		keysMu.Lock()
+		defer keysMu.Unlock()
+		if loadErr != nil {
+			return nil, loadErr
+		}
+		loaded := make(map[string]*Key, len(keys))
+		for hash, k := range keys {
+			loaded[hash] = k
+		}
+		return loaded, nil
+	}
+	store := &usageStore{used: make(map[string]int64)}
+	h.addUsage, h.deleteUsage = store.add, store.delete
+
+	var keyID interface{}
+	handler := h.limitMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		keyID = r.Context().Value(v1.APIKeyIDContextKey)
+		w.WriteHeader(http.StatusOK)
+	}))
+	do := func(key string, addr string) *httptest.ResponseRecorder {
+		r := httptest.NewRequest(http.MethodGet, "/v1/", nil)
+		r.RemoteAddr = addr
+		if key != "" {
+			r.Header.Set(KeyHeader, key)
+		}
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, r)
+		return w
+	}
+
+	// Anonymous requests are limited per IP.
+	require.Equal(t, http.StatusOK, do("", "192.0.2.1:1234").Code)
+	resp := do("", "192.0.2.1:1235")
+	require.Equal(t, http.StatusTooManyRequests, resp.Code)
+	require.Equal(t, "1", resp.Header().Get("Retry-After"))
+	require.Equal(t, http.StatusOK, do("", "192.0.2.2:1234").Code)
+	require.Nil(t, keyID)
+
+	// Requests with a key are limited per key, independently of IP,
+	// and are served with the ID of the key.
+	require.Equal(t, http.StatusOK, do("oik_default", "192.0.2.1:1234").Code)
+	require.Equal(t, int64(1), keyID)
+	require.Equal(t, http.StatusOK, do("oik_default", "192.0.2.1:1234").Code)
+	require.Equal(t, http.StatusTooManyRequests, do("oik_default", "192.0.2.3:1234").Code)
+
+	// Quotas hold until the next day.
+	now = now.Add(time.Hour)
+	require.Equal(t, http.StatusOK, do("oik_default", "192.0.2.1:1234").Code)
+	resp = do("oik_default", "192.0.2.1:1234")
+	require.Equal(t, http.StatusTooManyRequests, resp.Code)
+	require.Equal(t, "82800", resp.Header().Get("Retry-After"))
+
+	// Unknown keys and tiers are rejected.
+	require.Equal(t, http.StatusUnauthorized, do("oik_unknown", "192.0.2.1:1234").Code)
+	require.Equal(t, http.StatusForbidden, do("oik_premium", "192.0.2.1:1234").Code)
+
+	// Keys are reloaded periodically in the background, and cached keys
+	// are used until they are reloaded, or if they cannot be.
+	keysMu.Lock()
+	delete(keys, hashKey("oik_default"))
+	loadErr = errors.New("connection refused")
+	keysMu.Unlock()
+	now = now.Add(keyRefreshInterval)
+	require.Equal(t, http.StatusTooManyRequests, do("oik_default", "192.0.2.1:1234").Code)
+	waitForKeys := func() {
+		require.Eventually(t, func() bool {
+			h.keysMu.RLock()
+			defer h.keysMu.RUnlock()
+			return !h.keysLoading
+		}, time.Second, time.Millisecond)
+	}
+	waitForKeys()
+	require.Equal(t, http.StatusTooManyRequests, do("oik_default", "192.0.2.1:1234").Code)
+
+	keysMu.Lock()
+	loadErr = nil
+	keysMu.Unlock()
+	now = now.Add(keyRefreshInterval)
+	require.Equal(t, http.StatusTooManyRequests, do("oik_default", "192.0.2.1:1234").Code)
+	waitForKeys()
+	require.Equal(t, http.StatusUnauthorized, do("oik_default", "192.0.2.1:1234").Code)
+
+	// Keys may be required.
+	h.requireKey = true
+	require.Equal(t, http.StatusUnauthorized, do("", "192.0.2.4:1234").Code)
+}
+
+func TestLookupUnavailable(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2022, 4, 11, 0, 0, 0, 0, time.UTC)
+	loads := 0
+	loadErr := errors.New("connection refused")
+	h := &Handler{
+		loadKeys: func(ctx context.Context) (map[string]*Key, error) {
+			loads++
+			if loadErr != nil {
+				return nil, loadErr
+			}
+			return map[string]*Key{hashKey("oik_default"): {ID: 1}}, nil
+		},
+		now: func() time.Time { return now },
+	}
+
+	// Until keys are first loaded, a failed load is only retried once
+	// the refresh interval has passed.
+	_, err := h.lookup(ctx, "oik_default")
+	require.ErrorIs(t, err, loadErr)
+	_, err = h.lookup(ctx, "oik_default")
+	require.ErrorIs(t, err, loadErr)
+	require.Equal(t, 1, loads)
+
+	loadErr = nil
+	now = now.Add(keyRefreshInterval)
+	key, err := h.lookup(ctx, "oik_default")
+	require.Nil(t, err)
+	require.Equal(t, int64(1), key.ID)
+	require.Equal(t, 2, loads)
+}
@@ -0,0 +1,165 @@
+package auth
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"time"
+
+	"github.com/jackc/pgx/v4"
+
+	"github.com/oasisprotocol/oasis-indexer/storage"
+)
+
+const (
+	// KeyPrefix is the prefix of all API keys, so that they are easy
+	// to recognize, e.g. by secret scanners.
+	KeyPrefix = "oik_"
+
+	// keySize is the number of random bytes in an API key.
+	keySize = 24
+
+	// displayPrefixLength is the number of characters of an API key
+	// that are stored, to help identify it.
+	displayPrefixLength = len(KeyPrefix) + 8
+)
+
+// ErrKeyNotFound is returned if an API key does not exist
+// or has been revoked.
+var ErrKeyNotFound = errors.New("api key not found")
+
+// Key is an API key issued to a client.
+type Key struct {
+	ID        int64
+	Name      string
+	Tier      string
+	Prefix    string
+	CreatedAt time.Time
+	RevokedAt *time.Time
+}
+
+// Store manages API keys in target storage.
+type Store struct {
+	db storage.TargetStorage
+}
+
+// NewStore creates a new API key store.
+func NewStore(db storage.TargetStorage) *Store {
+	return &Store{db}
+}
+
+// Create issues a new API key with the provided name and tier. The key
+// itself is only returned here, since only its hash is stored.
+func (s *Store) Create(ctx context.Context, name string, tier string) (*Key, string, error) {
+	b := make([]byte, keySize)
+	if _, err := rand.Read(b); err != nil {
+		return nil, "", err
+	}
+	secret := KeyPrefix + hex.EncodeToString(b)
+
+	k := Key{
+		Name:   name,
+		Tier:   tier,
+		Prefix: secret[:displayPrefixLength],
+	}
+	if err := s.db.QueryRow(storage.WithPrimary(ctx), createKeyQuery, name, tier, hashKey(secret), k.Prefix).Scan(&k.ID, &k.CreatedAt); err != nil {
+		return nil, "", err
+	}
+	return &k, secret, nil
+}
+
+// List returns all API keys, including revoked keys.
+func (s *Store) List(ctx context.Context) ([]*Key, error) {
+	rows, err := s.db.Query(ctx, keysQuery)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var keys []*Key
+	for rows.Next() {
+		var k Key
+		if err := rows.Scan(&k.ID, &k.Name, &k.Tier, &k.Prefix, &k.CreatedAt, &k.RevokedAt); err != nil {
+			return nil, err
+		}
+		keys = append(keys, &k)
+	}
+	return keys, rows.Err()
+}
+
+// Revoke revokes the API key with the provided ID.
+func (s *Store) Revoke(ctx context.Context, id int64) error {
+	if err := s.db.QueryRow(storage.WithPrimary(ctx), revokeKeyQuery, id).Scan(&id); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return ErrKeyNotFound
+		}
+		return err
+	}
+	return nil
+}
+
+// active returns all API keys that have not been revoked, by key hash.
+// They are read from the primary, so that revocations apply at once.
+func (s *Store) active(ctx context.Context) (map[string]*Key, error) {
+	rows, err := s.db.Query(storage.WithPrimary(ctx), activeKeysQuery)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	keys := make(map[string]*Key)
+	for rows.Next() {
+		var k Key
+		var hash string
+		if err := rows.Scan(&k.ID, &k.Name, &k.Tier, &k.Prefix, &k.CreatedAt, &hash); err != nil {
+			return nil, err
+		}
+		keys[hash] = &k
+	}
+	return keys, rows.Err()
+}
+
+// hashKey returns the hash of an API key, as stored.
+func hashKey(secret string) string {
+	h := sha256.Sum256([]byte(secret))
+	return hex.EncodeToString(h[:])
+}
+
+// addUsage adds to the quota usage of clients on a UTC day, in days since
+// the Unix epoch, and returns their total usage on the day.
+func (s *Store) addUsage(ctx context.Context, day int64, usage map[string]int64) (map[string]int64, error) {
+	ctx = storage.WithPrimary(ctx)
+
+	clients := make([]string, 0, len(usage))
+	used := make([]int64, 0, len(usage))
+	for client, u := range usage {
+		clients = append(clients, client)
+		used = append(used, u)
+	}
+
+	rows, err := s.db.Query(ctx, addUsageQuery, day, clients, used)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	totals := make(map[string]int64, len(usage))
+	for rows.Next() {
+		var client string
+		var total int64
+		if err := rows.Scan(&client, &total); err != nil {
+			return nil, err
+		}
+		totals[client] = total
+	}
+	return totals, rows.Err()
+}
+
+// deleteUsage deletes the quota usage of days before the provided day.
+func (s *Store) deleteUsage(ctx context.Context, day int64) error {
+	batch := &storage.QueryBatch{}
+	batch.Queue(deleteUsageQuery, day)
+	return s.db.SendBatch(storage.WithPrimary(ctx), batch)
+}
@@ -0,0 +1,187 @@
+package auth
+
+import (
+	"math"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/oasisprotocol/oasis-indexer/api/common"
+	"github.com/oasisprotocol/oasis-indexer/config"
+)
+
+// sweepInterval is how often idle clients are forgotten.
+const sweepInterval = time.Minute
+
+var (
+	// errRateLimited is returned if a client made too many requests
+	// in a short period of time.
+	errRateLimited = &common.Error{Code: common.CodeRateLimited, Status: http.StatusTooManyRequests, Msg: "rate limit exceeded"}
+
+	// errQuotaExceeded is returned if a client exhausted its daily quota.
+	errQuotaExceeded = &common.Error{Code: common.CodeQuotaExceeded, Status: http.StatusTooManyRequests, Msg: "daily quota exceeded"}
+)
+
+// limiter limits the requests of the clients of a tier, using
+// a token bucket and a daily quota per client.
+//
+// Token buckets bound the load of each API server, so they are kept in
+// memory. Quota usage is shared by API servers through target storage,
+// and is synchronized with it periodically.
+type limiter struct {
+	rate  float64
+	burst float64
+	quota int64
+
+	mu        sync.Mutex
+	clients   map[string]*bucket
+	lastSweep time.Time
+}
+
+// bucket is the state of a single client.
+type bucket struct {
+	tokens float64
+	last   time.Time
+
+	// day is the UTC day, in days since the Unix epoch, that used
+	// counts the requests of.
+	day  int64
+	used int64
+
+	// pending is the part of used that has not been added to the
+	// usage in target storage yet.
+	pending int64
+}
+
+func newLimiter(cfg *config.TierConfig) *limiter {
+	return &limiter{
+		rate:    cfg.Rate,
+		burst:   float64(cfg.Burst),
+		quota:   cfg.DailyQuota,
+		clients: make(map[string]*bucket),
+	}
+}
+
+// take takes cost tokens from the bucket of the client. If the client is
+// limited, it returns the reason and how long the client should wait before
+// retrying. Costs above the burst are capped to the burst when taken from
+// the bucket, so that any request may eventually succeed, but are counted
+// in full towards the quota.
+func (l *limiter) take(client string, cost int, now time.Time) (time.Duration, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.sweep(now)
+
+	c := math.Min(float64(cost), l.burst)
+	day := unixDay(now)
+
+	b, ok := l.clients[client]
+	if !ok {
+		b = &bucket{tokens: l.burst, last: now, day: day}
+		l.clients[client] = b
+	}
+	b.refill(l.rate, l.burst, now)
+	if b.day != day {
+		b.day = day
+		b.used = 0
+		b.pending = 0
+	}
+
+	if l.quota > 0 && b.used+int64(cost) > l.quota {
+		midnight := time.Unix((day+1)*secondsPerDay, 0)
+		return midnight.Sub(now), errQuotaExceeded
+	}
+	if b.tokens < c {
+		wait := time.Duration((c - b.tokens) / l.rate * float64(time.Second))
+		return wait, errRateLimited
+	}
+
+	b.tokens -= c
+	b.used += int64(cost)
+	if l.quota > 0 {
+		b.pending += int64(cost)
+	}
+	return 0, nil
+}
+
+// takeUsage returns the usage of all clients on the day that has not been
+// added to target storage yet, and resets it. Clients without such usage
+// are included as well, so that their usage through other API servers is
+// learned. It returns nothing if the tier has no quota.
+func (l *limiter) takeUsage(day int64) map[string]int64 {
+	if l.quota == 0 {
+		return nil
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	usage := make(map[string]int64)
+	for client, b := range l.clients {
+		if b.day != day {
+			continue
+		}
+		usage[client] = b.pending
+		b.pending = 0
+	}
+	return usage
+}
+
+// restoreUsage restores usage returned by takeUsage that could not be
+// added to target storage, so that it is added by the next synchronization.
+func (l *limiter) restoreUsage(day int64, usage map[string]int64) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	for client, used := range usage {
+		if b, ok := l.clients[client]; ok && b.day == day {
+			b.pending += used
+		}
+	}
+}
+
+// setUsage sets the usage of clients on the day to their total usage, as
+// stored in target storage, and the usage since it was taken.
+func (l *limiter) setUsage(day int64, totals map[string]int64) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	for client, total := range totals {
+		if b, ok := l.clients[client]; ok && b.day == day {
+			b.used = total + b.pending
+		}
+	}
+}
+
+// sweep forgets clients whose buckets are full and whose quota has been
+// reset, since they are indistinguishable from new clients.
+func (l *limiter) sweep(now time.Time) {
+	if now.Sub(l.lastSweep) < sweepInterval {
+		return
+	}
+	l.lastSweep = now
+
+	day := unixDay(now)
+	for client, b := range l.clients {
+		b.refill(l.rate, l.burst, now)
+		if b.tokens >= l.burst && (l.quota == 0 || b.day != day) {
+			delete(l.clients, client)
+		}
+	}
+}
+
+// refill adds the tokens accrued since the bucket was last refilled.
+func (b *bucket) refill(rate float64, burst float64, now time.Time) {
+	if elapsed := now.Sub(b.last).Seconds(); elapsed > 0 {
+		b.tokens = math.Min(burst, b.tokens+elapsed*rate)
+		b.last = now
+	}
+}
+
+const secondsPerDay = 24 * 60 * 60
+
+// unixDay returns the number of UTC days since the Unix epoch.
+func unixDay(t time.Time) int64 {
+	return t.Unix() / secondsPerDay
+}
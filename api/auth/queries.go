@@ -0,0 +1,38 @@
+package auth
+
+// API keys are not tied to a chain, so unlike other queries these
+// are not created by a chain-specific query factory.
+const (
+	createKeyQuery = `
+		INSERT INTO api_keys (name, tier, key_hash, key_prefix)
+			VALUES ($1, $2, $3, $4)
+			RETURNING id, created_at`
+
+	keysQuery = `
+		SELECT id, name, tier, key_prefix, created_at, revoked_at
+			FROM api_keys
+		ORDER BY id`
+
+	activeKeysQuery = `
+		SELECT id, name, tier, key_prefix, created_at, key_hash
+			FROM api_keys
+			WHERE revoked_at IS NULL`
+
+	revokeKeyQuery = `
+		UPDATE api_keys
+			SET revoked_at = CURRENT_TIMESTAMP
+			WHERE id = $1 AND revoked_at IS NULL
+			RETURNING id`
+
+	addUsageQuery = `
+		INSERT INTO api_quota_usage (client, day, used)
+			SELECT client, $1, used
+				FROM UNNEST($2::TEXT[], $3::BIGINT[]) AS usage (client, used)
+		ON CONFLICT (client, day) DO UPDATE
+			SET used = api_quota_usage.used + excluded.used
+		RETURNING client, used`
+
+	deleteUsageQuery = `
+		DELETE FROM api_quota_usage
+			WHERE day < $1`
+)
@@ -0,0 +1,28 @@
+package common
+
+import (
+	"context"
+	"net"
+	"time"
+)
+
+type connContextKey struct{}
+
+// WithConn returns a context carrying the connection a request is served
+// on. It is used as the ConnContext of the API server, so that responses
+// that outlive the write timeout of the server may extend it.
+func WithConn(ctx context.Context, c net.Conn) context.Context {
+	return context.WithValue(ctx, connContextKey{}, c)
+}
+
+// SetWriteDeadline sets the write deadline of the connection a request
+// is served on, replacing the deadline set by the write timeout of the
+// server. A zero time means writes do not time out. It does nothing if
+// the connection is not known, e.g. in tests.
+func SetWriteDeadline(ctx context.Context, t time.Time) error {
+	c, ok := ctx.Value(connContextKey{}).(net.Conn)
+	if !ok {
+		return nil
+	}
+	return c.SetWriteDeadline(t)
+}
@@ -0,0 +1,34 @@
+package common
+
+import (
+	"context"
+	"errors"
+	"net"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSetWriteDeadline(t *testing.T) {
+	// Without a connection, deadlines are ignored.
+	require.Nil(t, SetWriteDeadline(context.Background(), time.Now()))
+
+	c, peer := net.Pipe()
+	defer c.Close()
+	defer peer.Close()
+	ctx := WithConn(context.Background(), c)
+
+	require.Nil(t, SetWriteDeadline(ctx, time.Now().Add(-time.Second)))
+	_, err := c.Write([]byte("data"))
+	require.True(t, errors.Is(err, os.ErrDeadlineExceeded))
+
+	// A zero deadline lifts it.
+	require.Nil(t, SetWriteDeadline(ctx, time.Time{}))
+	go func() {
+		_, _ = peer.Read(make([]byte, 4))
+	}()
+	_, err = c.Write([]byte("data"))
+	require.Nil(t, err)
+}
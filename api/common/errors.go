@@ -3,49 +3,135 @@ package common
 import (
 	"encoding/json"
 	"errors"
+	"fmt"
 	"net/http"
 )
 
+// ErrorCode is a machine-readable code of an error response.
+type ErrorCode string
+
+const (
+	// CodeBadRequest is the code of malformed requests.
+	CodeBadRequest ErrorCode = "bad_request"
+	// CodeInvalidParameter is the code of requests with a parameter
+	// of an invalid format, which is named in the details.
+	CodeInvalidParameter ErrorCode = "invalid_parameter"
+	// CodeUnauthorized is the code of requests without a valid API key.
+	CodeUnauthorized ErrorCode = "unauthorized"
+	// CodeForbidden is the code of requests whose API key may not be used.
+	CodeForbidden ErrorCode = "forbidden"
+	// CodeNotFound is the code of requests for data that does not exist.
+	CodeNotFound ErrorCode = "not_found"
+	// CodeUnknownChain is the code of requests for an unknown chain.
+	CodeUnknownChain ErrorCode = "unknown_chain"
+	// CodeRateLimited is the code of requests over a rate limit.
+	CodeRateLimited ErrorCode = "rate_limited"
+	// CodeQuotaExceeded is the code of requests over a daily quota.
+	CodeQuotaExceeded ErrorCode = "quota_exceeded"
+	// CodeNotIndexed is the code of requests for heights that are
+	// not yet indexed.
+	CodeNotIndexed ErrorCode = "not_indexed"
+	// CodeStorageError is the code of requests that failed in storage.
+	CodeStorageError ErrorCode = "storage_error"
+	// CodeInternalError is the code of requests that failed otherwise.
+	CodeInternalError ErrorCode = "internal_error"
+)
+
+// Error is an error of a request, which is replied with as an error
+// response of its status and code.
+type Error struct {
+	Code    ErrorCode
+	Status  int
+	Msg     string
+	Details map[string]interface{}
+}
+
+func (e *Error) Error() string {
+	return e.Msg
+}
+
 var (
 	// ErrBadRequest is returned when the provided HTTP request
 	// is malformed.
-	ErrBadRequest = errors.New("invalid request parameters")
+	ErrBadRequest = &Error{Code: CodeBadRequest, Status: http.StatusBadRequest, Msg: "invalid request parameters"}
 	// ErrBadChainID is returned when a malformed or missing chain ID
 	// is provided.
-	ErrBadChainID = errors.New("unable to resolve chain ID")
+	ErrBadChainID = &Error{Code: CodeUnknownChain, Status: http.StatusNotFound, Msg: "unable to resolve chain ID"}
 	// ErrStorageError is returned when the underlying storage suffers
 	// from an internal error.
-	ErrStorageError = errors.New("internal storage error")
+	ErrStorageError = &Error{Code: CodeStorageError, Status: http.StatusInternalServerError, Msg: "internal storage error"}
+	// ErrNotFound is returned when the requested data does not exist.
+	ErrNotFound = &Error{Code: CodeNotFound, Status: http.StatusNotFound, Msg: "not found"}
+	// ErrNotIndexed is returned when the requested height is not
+	// yet indexed.
+	ErrNotIndexed = &Error{Code: CodeNotIndexed, Status: http.StatusServiceUnavailable, Msg: "not yet indexed"}
+	// ErrKeyRequired is returned when a request that must be made with
+	// an API key is made without one.
+	ErrKeyRequired = &Error{Code: CodeUnauthorized, Status: http.StatusUnauthorized, Msg: "api key required"}
+	// ErrInternal is replied with for errors that are not an *Error,
+	// whose messages may reveal internals of the server.
+	ErrInternal = &Error{Code: CodeInternalError, Status: http.StatusInternalServerError, Msg: "internal error"}
 )
 
+// NewNotFoundError returns an error of requested data that does not
+// exist, which is described by what.
+func NewNotFoundError(what string) *Error {
+	return &Error{Code: CodeNotFound, Status: http.StatusNotFound, Msg: what + " not found"}
+}
+
+// NewInvalidParameterError returns an error of a request parameter of
+// an invalid format, which is named in the details of the error.
+func NewInvalidParameterError(name string, reason string) *Error {
+	return &Error{
+		Code:    CodeInvalidParameter,
+		Status:  http.StatusBadRequest,
+		Msg:     fmt.Sprintf("invalid %s: %s", name, reason),
+		Details: map[string]interface{}{"parameter": name},
+	}
+}
+
 // ErrorResponse is a JSON error.
 type ErrorResponse struct {
-	Msg string `json:"msg"`
+	Code      ErrorCode              `json:"code"`
+	Status    int                    `json:"status"`
+	Msg       string                 `json:"msg"`
+	RequestID string                 `json:"request_id,omitempty"`
+	Details   map[string]interface{} `json:"details,omitempty"`
+}
+
+// ErrorCause returns the cause of an error, by which failed requests
+// are counted. It is the code of the error.
+func ErrorCause(err error) string {
+	return string(asError(err).Code)
+}
+
+// asError returns the *Error of an error. Other errors are internal
+// errors, which are replied with as ErrInternal.
+func asError(err error) *Error {
+	var e *Error
+	if errors.As(err, &e) {
+		return e
+	}
+	return ErrInternal
 }
 
 // ReplyWithError replies to an HTTP request with an error
-// as JSON.
-func ReplyWithError(w http.ResponseWriter, err error) error {
-	var response ErrorResponse
-	var code int
-	switch err {
-	case ErrBadRequest:
-		response = ErrorResponse{err.Error()}
-		code = http.StatusBadRequest
-	case ErrBadChainID:
-		response = ErrorResponse{err.Error()}
-		code = http.StatusNotFound
-	case ErrStorageError:
-		response = ErrorResponse{err.Error()}
-		code = http.StatusInternalServerError
-	default:
-		response = ErrorResponse{err.Error()}
-		code = http.StatusInternalServerError
+// as JSON, identifying the request by its ID if it is not empty.
+// Errors that are not an *Error are replied with as ErrInternal, so
+// callers should log them.
+func ReplyWithError(w http.ResponseWriter, requestID string, err error) error {
+	e := asError(err)
+	response := ErrorResponse{
+		Code:      e.Code,
+		Status:    e.Status,
+		Msg:       e.Msg,
+		RequestID: requestID,
+		Details:   e.Details,
 	}
 
 	w.Header().Set("content-type", "application/json; charset=utf-8")
 	w.Header().Set("x-content-type-options", "nosniff")
-	w.WriteHeader(code)
+	w.WriteHeader(e.Status)
 	if err := json.NewEncoder(w).Encode(response); err != nil {
 		return err
 	}
@@ -0,0 +1,59 @@
+package common
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// TestReplyWithError tests that errors are replied with as JSON
+// of their status and code.
+func TestReplyWithError(t *testing.T) {
+	for _, tc := range []struct {
+		err      error
+		response ErrorResponse
+	}{
+		{
+			err:      ErrNotIndexed,
+			response: ErrorResponse{Code: CodeNotIndexed, Status: http.StatusServiceUnavailable, Msg: "not yet indexed", RequestID: "request"},
+		},
+		{
+			err:      fmt.Errorf("failed to find block: %w", NewNotFoundError("block")),
+			response: ErrorResponse{Code: CodeNotFound, Status: http.StatusNotFound, Msg: "block not found", RequestID: "request"},
+		},
+		{
+			err: NewInvalidParameterError("height", "must be an integer"),
+			response: ErrorResponse{
+				Code:      CodeInvalidParameter,
+				Status:    http.StatusBadRequest,
+				Msg:       "invalid height: must be an integer",
+				RequestID: "request",
+				Details:   map[string]interface{}{"parameter": "height"},
+			},
+		},
+		{
+			err:      errors.New("connection reset"),
+			response: ErrorResponse{Code: CodeInternalError, Status: http.StatusInternalServerError, Msg: "internal error", RequestID: "request"},
+		},
+	} {
+		w := httptest.NewRecorder()
+		require.Nil(t, ReplyWithError(w, "request", tc.err))
+		require.Equal(t, tc.response.Status, w.Code)
+		require.Equal(t, "application/json; charset=utf-8", w.Header().Get("content-type"))
+
+		var response ErrorResponse
+		require.Nil(t, json.Unmarshal(w.Body.Bytes(), &response))
+		require.Equal(t, tc.response, response)
+		require.Equal(t, string(tc.response.Code), ErrorCause(tc.err))
+	}
+
+	// Requests without an ID are not identified.
+	w := httptest.NewRecorder()
+	require.Nil(t, ReplyWithError(w, "", ErrBadRequest))
+	require.JSONEq(t, `{"code": "bad_request", "status": 400, "msg": "invalid request parameters"}`, w.Body.String())
+}
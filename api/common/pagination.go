@@ -9,6 +9,7 @@ import (
 const (
 	LimitKey  = "limit"
 	OffsetKey = "offset"
+	OrderKey  = "order_by"
 
 	// By default, just order by the first returned column so
 	// we always have a deterministic ordering.
@@ -43,7 +44,12 @@ func NewPagination(r *http.Request) (p Pagination, err error) {
 		offset, err = strconv.ParseUint(v, 10, 64)
 	}
 
+	// The order is validated by endpoints, which know what
+	// their results may be ordered by.
 	order := DefaultOrder
+	if v := values.Get(OrderKey); v != "" {
+		order = v
+	}
 
 	p = Pagination{
 		Limit:  limit,
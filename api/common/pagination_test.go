@@ -84,3 +84,18 @@ func TestPaginationWithTooHighLimit(t *testing.T) {
 	require.Equal(t, p.Limit, MaximumLimit)
 	require.Equal(t, p.Offset, offset)
 }
+
+// TestPaginationWithOrder tests if the requested order is set.
+func TestPaginationWithOrder(t *testing.T) {
+	ctx := context.Background()
+
+	r, err := http.NewRequestWithContext(ctx, "GET", "https://fake-api.com/get-resource?order_by=-escrow,name", nil)
+	require.Nil(t, err)
+
+	p, err := NewPagination(r)
+	require.Nil(t, err)
+
+	require.Equal(t, *p.Order, "-escrow,name")
+	require.Equal(t, p.Limit, DefaultLimit)
+	require.Equal(t, p.Offset, DefaultOffset)
+}
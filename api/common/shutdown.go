@@ -0,0 +1,85 @@
+package common
+
+import (
+	"context"
+	"sync"
+)
+
+type shutdownContextKey struct{}
+
+// Shutdown signals long-lived responses, such as streams and exports, that
+// the server they are served by is shutting down, and waits for them to
+// end. Graceful shutdown of the server would otherwise wait for them until
+// they time out, and does not wait for hijacked connections at all.
+type Shutdown struct {
+	mu           sync.Mutex
+	shuttingDown bool
+	done         chan struct{}
+	responses    sync.WaitGroup
+}
+
+// NewShutdown returns a new Shutdown.
+func NewShutdown() *Shutdown {
+	return &Shutdown{done: make(chan struct{})}
+}
+
+// Context returns a context carrying the Shutdown. It is used as the
+// BaseContext of the API server, so that responses can end on shutdown.
+func (s *Shutdown) Context(ctx context.Context) context.Context {
+	return context.WithValue(ctx, shutdownContextKey{}, s)
+}
+
+// Start signals long-lived responses that the server is shutting down.
+// It is registered to be called on shutdown of the API server.
+func (s *Shutdown) Start() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if !s.shuttingDown {
+		s.shuttingDown = true
+		close(s.done)
+	}
+}
+
+// Wait waits for long-lived responses to end once shutdown has started.
+func (s *Shutdown) Wait() {
+	s.responses.Wait()
+}
+
+// UntilShutdown returns a context that is cancelled along with the provided
+// one, or once the server a request is served by shuts down. Long-lived
+// responses are served under it, and call the returned function once they
+// end. If the Shutdown is not known, e.g. in tests, the context is only
+// cancelled along with the provided one.
+func UntilShutdown(ctx context.Context) (context.Context, func()) {
+	ctx, cancel := context.WithCancel(ctx)
+	s, ok := ctx.Value(shutdownContextKey{}).(*Shutdown)
+	if !ok {
+		return ctx, cancel
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.shuttingDown {
+		cancel()
+		return ctx, cancel
+	}
+	s.responses.Add(1)
+
+	ended := make(chan struct{})
+	go func() {
+		select {
+		case <-s.done:
+			cancel()
+		case <-ended:
+		}
+	}()
+
+	var once sync.Once
+	return ctx, func() {
+		once.Do(func() {
+			close(ended)
+			cancel()
+			s.responses.Done()
+		})
+	}
+}
@@ -0,0 +1,45 @@
+package common
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestUntilShutdown(t *testing.T) {
+	// Without a Shutdown, contexts are only cancelled along with their parent.
+	ctx, end := UntilShutdown(context.Background())
+	require.Nil(t, ctx.Err())
+	end()
+	require.NotNil(t, ctx.Err())
+
+	s := NewShutdown()
+	base := s.Context(context.Background())
+	ctx, end = UntilShutdown(base)
+	require.Nil(t, ctx.Err())
+
+	// Responses are cancelled on shutdown, which waits for them to end.
+	waited := make(chan struct{})
+	go func() {
+		s.Wait()
+		close(waited)
+	}()
+	s.Start()
+	<-ctx.Done()
+	select {
+	case <-waited:
+		t.Fatal("shutdown did not wait for the response to end")
+	case <-time.After(10 * time.Millisecond):
+	}
+	end()
+	end()
+	<-waited
+
+	// Responses started after shutdown are cancelled straight away.
+	ctx, end = UntilShutdown(base)
+	defer end()
+	require.NotNil(t, ctx.Err())
+	s.Wait()
+}
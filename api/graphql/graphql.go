@@ -0,0 +1,223 @@
+// Package graphql implements the GraphQL API of the Oasis Indexer.
+package graphql
+
+import (
+	"context"
+	// Used to embed the schema.
+	_ "embed"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/chi/v5/middleware"
+	gql "github.com/graph-gophers/graphql-go"
+	gqlErrors "github.com/graph-gophers/graphql-go/errors"
+
+	"github.com/oasisprotocol/oasis-indexer/api/common"
+	v1 "github.com/oasisprotocol/oasis-indexer/api/v1"
+	"github.com/oasisprotocol/oasis-indexer/log"
+	"github.com/oasisprotocol/oasis-indexer/metrics"
+	"github.com/oasisprotocol/oasis-indexer/storage"
+)
+
+const (
+	moduleName = "api_graphql"
+
+	// maxRequestBodySize is the maximum size of a request body in bytes.
+	maxRequestBodySize = 1 << 16
+
+	// requestTimeout bounds how long executing a query may take.
+	requestTimeout = 10 * time.Second
+)
+
+// schema is the schema of the GraphQL API, in schema definition language.
+//
+//go:embed schema.graphql
+var schema string
+
+// Limits are limits of the queries that are executed.
+type Limits struct {
+	// MaxDepth is the maximum depth of nested fields, or 0 for no limit.
+	MaxDepth int
+	// MaxComplexity is the maximum number of objects a query may load,
+	// counting lists as their limit or their expected size, or 0 for
+	// no limit.
+	MaxComplexity int64
+}
+
+// DefaultLimits are the limits applied to GraphQL queries.
+var DefaultLimits = Limits{
+	MaxDepth:      8,
+	MaxComplexity: 10000,
+}
+
+// Request is a GraphQL request.
+type Request struct {
+	Query         string                 `json:"query"`
+	OperationName string                 `json:"operationName"`
+	Variables     map[string]interface{} `json:"variables"`
+}
+
+// Handler is the Oasis Indexer GraphQL API handler.
+type Handler struct {
+	schema  *gql.Schema
+	limits  Limits
+	logger  *log.Logger
+	metrics metrics.RequestMetrics
+}
+
+// NewHandler creates a new GraphQL API handler.
+func NewHandler(db storage.TargetStorage, l *log.Logger) *Handler {
+	logger := l.WithModule(moduleName)
+	return &Handler{
+		schema:  newSchema(&resolver{db, logger}, DefaultLimits),
+		limits:  DefaultLimits,
+		logger:  logger,
+		metrics: metrics.NewDefaultRequestMetrics(moduleName),
+	}
+}
+
+// newSchema parses the schema of the GraphQL API, whose root query
+// fields are resolved by r.
+func newSchema(r *resolver, limits Limits) *gql.Schema {
+	return gql.MustParseSchema(schema, r,
+		gql.UseStringDescriptions(),
+		gql.UseFieldResolvers(),
+		gql.MaxDepth(limits.MaxDepth),
+	)
+}
+
+// RegisterMiddlewares implements the APIHandler interface.
+func (h *Handler) RegisterMiddlewares(r chi.Router) {}
+
+// RegisterRoutes implements the APIHandler interface.
+func (h *Handler) RegisterRoutes(r chi.Router) {
+	r.Route("/graphql", func(r chi.Router) {
+		r.Use(middleware.Timeout(requestTimeout))
+
+		r.Get("/", h.Query)
+		r.Post("/", h.Query)
+		r.Get("/schema", h.GetSchema)
+	})
+}
+
+// Name implements the APIHandler interface.
+func (h *Handler) Name() string {
+	return "graphql"
+}
+
+// Query executes a GraphQL query, provided as JSON in the body of POST
+// requests or as URL parameters of GET requests.
+func (h *Handler) Query(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	req, err := parseRequest(w, r)
+	if err != nil {
+		h.reply(ctx, w, r, http.StatusBadRequest, &gql.Response{Errors: []*gqlErrors.QueryError{{Message: err.Error()}}})
+		h.metrics.RequestCounter(r.URL.Path, "failure", "bad_request").Inc()
+		return
+	}
+
+	resp := h.schema.Exec(withComplexity(ctx, h.limits.MaxComplexity), req.Query, req.OperationName, req.Variables)
+	if len(resp.Errors) > 0 && resp.Data == nil {
+		// The query was not executed, as it is invalid.
+		h.logger.Info("invalid query",
+			"request_id", ctx.Value(v1.RequestIDContextKey),
+			"err", resp.Errors[0].Error(),
+		)
+		h.reply(ctx, w, r, http.StatusBadRequest, &gql.Response{Errors: resp.Errors})
+		h.metrics.RequestCounter(r.URL.Path, "failure", "bad_request").Inc()
+		return
+	}
+	if len(resp.Errors) > 0 {
+		err := resp.Errors[0]
+		h.logger.Error("failed to execute query",
+			"request_id", ctx.Value(v1.RequestIDContextKey),
+			"err", err.Error(),
+		)
+		// Only errors of the request are replied with as they are.
+		e := common.ErrInternal
+		errors.As(err, &e)
+		h.reply(ctx, w, r, e.Status, &gql.Response{Errors: []*gqlErrors.QueryError{{Message: e.Error()}}})
+		h.metrics.RequestCounter(r.URL.Path, "failure", common.ErrorCause(err)).Inc()
+		return
+	}
+
+	if h.reply(ctx, w, r, http.StatusOK, &gql.Response{Data: resp.Data}) {
+		h.metrics.RequestCounter(r.URL.Path, "success").Inc()
+	}
+}
+
+// GetSchema returns the schema in schema definition language.
+func (h *Handler) GetSchema(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	w.Header().Set("content-type", "text/plain; charset=utf-8")
+	if _, err := w.Write([]byte(schema)); err != nil {
+		h.logger.Error("failed to write response",
+			"request_id", ctx.Value(v1.RequestIDContextKey),
+			"error", err,
+		)
+		h.metrics.RequestCounter(r.URL.Path, "failure", "http_error").Inc()
+	} else {
+		h.metrics.RequestCounter(r.URL.Path, "success").Inc()
+	}
+}
+
+// parseRequest parses a GraphQL request.
+func parseRequest(w http.ResponseWriter, r *http.Request) (*Request, error) {
+	var req Request
+	if r.Method == http.MethodGet {
+		params := r.URL.Query()
+		req.Query = params.Get("query")
+		req.OperationName = params.Get("operationName")
+		if v := params.Get("variables"); v != "" {
+			if err := json.NewDecoder(strings.NewReader(v)).Decode(&req.Variables); err != nil {
+				return nil, errors.New("variables must be a JSON object")
+			}
+		}
+	} else {
+		if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxRequestBodySize)).Decode(&req); err != nil {
+			return nil, errors.New("request body must be a JSON object")
+		}
+	}
+
+	if req.Query == "" {
+		return nil, errors.New("query is required")
+	}
+	return &req, nil
+}
+
+// reply writes a GraphQL response, returning true on success.
+func (h *Handler) reply(ctx context.Context, w http.ResponseWriter, r *http.Request, status int, response *gql.Response) bool {
+	resp, err := json.Marshal(response)
+	if err != nil {
+		h.logger.Error("failed to marshal response",
+			"request_id", ctx.Value(v1.RequestIDContextKey),
+			"error", err,
+		)
+		if err := common.ReplyWithError(w, v1.RequestID(ctx), err); err != nil {
+			h.logger.Error("failed to reply with error",
+				"request_id", ctx.Value(v1.RequestIDContextKey),
+				"error", err,
+			)
+		}
+		h.metrics.RequestCounter(r.URL.Path, "failure", "serde_error").Inc()
+		return false
+	}
+
+	w.Header().Set("content-type", "application/json")
+	w.WriteHeader(status)
+	if _, err := w.Write(resp); err != nil {
+		h.logger.Error("failed to write response",
+			"request_id", ctx.Value(v1.RequestIDContextKey),
+			"error", err,
+		)
+		h.metrics.RequestCounter(r.URL.Path, "failure", "http_error").Inc()
+		return false
+	}
+	return true
+}
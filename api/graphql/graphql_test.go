@@ -0,0 +1,172 @@
+package graphql
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v4"
+	"github.com/stretchr/testify/require"
+
+	v1 "github.com/oasisprotocol/oasis-indexer/api/v1"
+	"github.com/oasisprotocol/oasis-indexer/log"
+	"github.com/oasisprotocol/oasis-indexer/metrics"
+	"github.com/oasisprotocol/oasis-indexer/storage"
+)
+
+const testChainID = "oasis_3"
+
+var testMetrics = metrics.NewDefaultRequestMetrics("test_graphql")
+
+// tableStorage is a target storage that answers queries with fixed rows,
+// counting how many times each query was made.
+type tableStorage struct {
+	storage.TargetStorage
+
+	mu      sync.Mutex
+	rows    map[string][][]interface{}
+	queries map[string]int
+}
+
+func (s *tableStorage) Query(ctx context.Context, sql string, args ...interface{}) (storage.QueryResults, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	rows, ok := s.rows[sql]
+	if !ok {
+		return nil, errors.New("unexpected query")
+	}
+	s.queries[sql]++
+	return &tableRows{rows: rows, next: -1}, nil
+}
+
+type tableRows struct {
+	pgx.Rows
+	rows [][]interface{}
+	next int
+}
+
+func (r *tableRows) Next() bool {
+	r.next++
+	return r.next < len(r.rows)
+}
+
+func (r *tableRows) Scan(dest ...interface{}) error {
+	for i, v := range r.rows[r.next] {
+		if v != nil {
+			reflect.ValueOf(dest[i]).Elem().Set(reflect.ValueOf(v))
+		}
+	}
+	return nil
+}
+
+func (r *tableRows) Err() error {
+	return nil
+}
+
+func (r *tableRows) Close() {}
+
+// testStorage returns a storage of two blocks with a transaction each.
+func testStorage() *tableStorage {
+	qf := NewQueryFactory(testChainID)
+	blocks := [][]interface{}{
+		{int32(2), "b2", time.Unix(1649669400, 0)},
+		{int32(1), "b1", time.Unix(1649669394, 0)},
+	}
+	fee := BigInt("1000")
+	code := uint64(1)
+	transactions := [][]interface{}{
+		{int32(1), "t1", nil, "alice", int32(0), &fee, "staking.Transfer", []byte{0xa0}, nil},
+		{int32(2), "t2", nil, "bob", int32(3), nil, "staking.AddEscrow", nil, &code},
+	}
+	return &tableStorage{
+		rows: map[string][][]interface{}{
+			qf.BlocksQuery():               blocks,
+			qf.BlocksByHeightsQuery():      blocks,
+			qf.TransactionsByBlocksQuery(): transactions,
+		},
+		queries: map[string]int{},
+	}
+}
+
+func query(t *testing.T, db storage.TargetStorage, limits Limits, q string) (int, string) {
+	h := &Handler{
+		schema:  newSchema(&resolver{db, log.NewDefaultLogger("graphql")}, limits),
+		limits:  limits,
+		logger:  log.NewDefaultLogger("graphql"),
+		metrics: testMetrics,
+	}
+
+	body, err := json.Marshal(Request{Query: q})
+	require.Nil(t, err)
+	r := httptest.NewRequest(http.MethodPost, "/graphql", strings.NewReader(string(body)))
+	r = r.WithContext(context.WithValue(r.Context(), v1.ChainIDContextKey, testChainID))
+	w := httptest.NewRecorder()
+	h.Query(w, r)
+	return w.Code, w.Body.String()
+}
+
+func TestQuery(t *testing.T) {
+	db := testStorage()
+
+	status, resp := query(t, db, DefaultLimits, `{
+		blocks(limit: 2) {
+			height
+			timestamp
+			transactions { hash fee body success block { hash } }
+		}
+	}`)
+	require.Equal(t, http.StatusOK, status, resp)
+	require.JSONEq(t, `{"data": {"blocks": [
+		{"height": 2, "timestamp": "2022-04-11T09:30:00Z", "transactions": [
+			{"hash": "t2", "fee": null, "body": null, "success": false, "block": {"hash": "b2"}}
+		]},
+		{"height": 1, "timestamp": "2022-04-11T09:29:54Z", "transactions": [
+			{"hash": "t1", "fee": "1000", "body": "oA==", "success": true, "block": {"hash": "b1"}}
+		]}
+	]}}`, resp)
+
+	// Related objects are loaded once for all of their parents.
+	qf := NewQueryFactory(testChainID)
+	require.Equal(t, 1, db.queries[qf.BlocksQuery()])
+	require.Equal(t, 1, db.queries[qf.TransactionsByBlocksQuery()])
+	require.Equal(t, 1, db.queries[qf.BlocksByHeightsQuery()])
+}
+
+func TestQueryErrors(t *testing.T) {
+	for _, tc := range []struct {
+		limits Limits
+		query  string
+		status int
+		err    string
+	}{
+		{DefaultLimits, ``, http.StatusBadRequest, "query is required"},
+		{DefaultLimits, `{ blocks { unknown } }`, http.StatusBadRequest, `Cannot query field \"unknown\" on type \"Block\"`},
+		{DefaultLimits, `{ blocks(limit: -1) { height } }`, http.StatusBadRequest, "invalid request parameters"},
+		{DefaultLimits, `{ accounts { address } }`, http.StatusInternalServerError, "internal storage error"},
+		{
+			Limits{MaxDepth: 3},
+			`{ blocks { transactions { block { height } } } }`,
+			http.StatusBadRequest,
+			`Field \"height\" has depth 4 that exceeds max depth 3`,
+		},
+		{
+			// Up to 10 blocks are loaded, and the transactions of each
+			// of the 2 loaded blocks count as 50 objects.
+			Limits{MaxComplexity: 100},
+			`{ blocks(limit: 10) { transactions { hash } } }`,
+			http.StatusBadRequest,
+			"query exceeds maximum complexity of 100",
+		},
+	} {
+		status, resp := query(t, testStorage(), tc.limits, tc.query)
+		require.Equal(t, tc.status, status, tc.query)
+		require.Contains(t, resp, tc.err, tc.query)
+	}
+}
@@ -0,0 +1,225 @@
+package graphql
+
+import (
+	"fmt"
+)
+
+// QueryFactory is a convenience type for creating GraphQL API queries.
+//
+// Queries loading related objects take an array of keys, so that they
+// can be loaded for a batch of parent objects at once.
+type QueryFactory struct {
+	chainID string
+}
+
+func NewQueryFactory(chainID string) QueryFactory {
+	return QueryFactory{chainID}
+}
+
+func (qf QueryFactory) BlocksQuery() string {
+	return fmt.Sprintf(`
+		SELECT height, block_hash, time
+			FROM %s.blocks
+			WHERE ($1::bigint IS NULL OR height >= $1::bigint) AND
+						($2::bigint IS NULL OR height <= $2::bigint)
+		ORDER BY height DESC
+		LIMIT $3::bigint
+		OFFSET $4::bigint`, qf.chainID)
+}
+
+func (qf QueryFactory) BlocksByHeightsQuery() string {
+	return fmt.Sprintf(`
+		SELECT height, block_hash, time
+			FROM %s.blocks
+			WHERE height = ANY($1::bigint[])`, qf.chainID)
+}
+
+func (qf QueryFactory) TransactionsQuery() string {
+	return fmt.Sprintf(`
+		SELECT block, txn_hash, txn_index, sender, nonce, fee_amount::text, method, body, code
+			FROM %s.transactions
+			WHERE ($1::bigint IS NULL OR block = $1::bigint) AND
+						($2::text IS NULL OR method = $2::text) AND
+						($3::text IS NULL OR sender = $3::text)
+		ORDER BY block DESC, txn_index
+		LIMIT $4::bigint
+		OFFSET $5::bigint`, qf.chainID)
+}
+
+func (qf QueryFactory) TransactionsByHashesQuery() string {
+	return fmt.Sprintf(`
+		SELECT block, txn_hash, txn_index, sender, nonce, fee_amount::text, method, body, code
+			FROM %s.transactions
+			WHERE txn_hash = ANY($1::text[])`, qf.chainID)
+}
+
+func (qf QueryFactory) TransactionsByBlocksQuery() string {
+	return fmt.Sprintf(`
+		SELECT block, txn_hash, txn_index, sender, nonce, fee_amount::text, method, body, code
+			FROM %s.transactions
+			WHERE block = ANY($1::bigint[])
+		ORDER BY block, txn_index`, qf.chainID)
+}
+
+func (qf QueryFactory) EventsQuery() string {
+	return fmt.Sprintf(`
+		SELECT txn_block, txn_hash, txn_index, backend, type, body
+			FROM %s.events
+			WHERE ($1::bigint IS NULL OR txn_block = $1::bigint) AND
+						($2::text IS NULL OR backend = $2::text) AND
+						($3::text IS NULL OR type = $3::text)
+		ORDER BY txn_block DESC, txn_index
+		LIMIT $4::bigint
+		OFFSET $5::bigint`, qf.chainID)
+}
+
+func (qf QueryFactory) EventsByBlocksQuery() string {
+	return fmt.Sprintf(`
+		SELECT txn_block, txn_hash, txn_index, backend, type, body
+			FROM %s.events
+			WHERE txn_block = ANY($1::bigint[])
+		ORDER BY txn_block, txn_index`, qf.chainID)
+}
+
+func (qf QueryFactory) EventsByTransactionsQuery() string {
+	return fmt.Sprintf(`
+		SELECT txn_block, txn_hash, txn_index, backend, type, body
+			FROM %s.events
+			WHERE txn_hash = ANY($1::text[])
+		ORDER BY txn_block, txn_index`, qf.chainID)
+}
+
+func (qf QueryFactory) AccountsQuery() string {
+	return fmt.Sprintf(`
+		SELECT address, nonce, general_balance::text, escrow_balance_active::text, escrow_balance_debonding::text
+			FROM %s.accounts
+		LIMIT $1::bigint
+		OFFSET $2::bigint`, qf.chainID)
+}
+
+func (qf QueryFactory) AccountsByAddressesQuery() string {
+	return fmt.Sprintf(`
+		SELECT address, nonce, general_balance::text, escrow_balance_active::text, escrow_balance_debonding::text
+			FROM %s.accounts
+			WHERE address = ANY($1::text[])`, qf.chainID)
+}
+
+func (qf QueryFactory) DelegationsByDelegatorsQuery() string {
+	return fmt.Sprintf(`
+		SELECT delegator, delegatee, shares::text,
+				COALESCE(ROUND(shares * escrow_balance_active / NULLIF(escrow_total_shares_active, 0)), 0)::text
+			FROM %[1]s.delegations
+			JOIN %[1]s.accounts ON %[1]s.delegations.delegatee = %[1]s.accounts.address
+			WHERE delegator = ANY($1::text[])
+		ORDER BY delegator, shares DESC`, qf.chainID)
+}
+
+func (qf QueryFactory) EntitiesQuery() string {
+	return fmt.Sprintf(`
+		SELECT id, address
+			FROM %s.entities
+		LIMIT $1::bigint
+		OFFSET $2::bigint`, qf.chainID)
+}
+
+func (qf QueryFactory) EntitiesByIDsQuery() string {
+	return fmt.Sprintf(`
+		SELECT id, address
+			FROM %s.entities
+			WHERE id = ANY($1::text[])`, qf.chainID)
+}
+
+func (qf QueryFactory) NodesByIDsQuery() string {
+	return fmt.Sprintf(`
+		SELECT id, entity_id, expiration, tls_pubkey, tls_next_pubkey, p2p_pubkey, consensus_pubkey, roles,
+				software_version, COALESCE(voting_power, 0)
+			FROM %s.nodes
+			WHERE id = ANY($1::text[])`, qf.chainID)
+}
+
+func (qf QueryFactory) NodesByEntitiesQuery() string {
+	return fmt.Sprintf(`
+		SELECT id, entity_id, expiration, tls_pubkey, tls_next_pubkey, p2p_pubkey, consensus_pubkey, roles,
+				software_version, COALESCE(voting_power, 0)
+			FROM %s.nodes
+			WHERE entity_id = ANY($1::text[])
+		ORDER BY entity_id, id`, qf.chainID)
+}
+
+func (qf QueryFactory) ValidatorsQuery() string {
+	return fmt.Sprintf(`
+		SELECT
+				%[1]s.entities.id AS entity_id,
+				%[1]s.entities.address AS entity_address,
+				%[1]s.nodes.id AS node_id,
+				%[1]s.accounts.escrow_balance_active::text AS escrow,
+				CASE WHEN EXISTS(SELECT NULL FROM %[1]s.nodes WHERE %[1]s.entities.id = %[1]s.nodes.entity_id AND voting_power > 0) THEN true ELSE false END AS active,
+				CASE WHEN EXISTS(SELECT NULL FROM %[1]s.nodes WHERE %[1]s.entities.id = %[1]s.nodes.entity_id AND %[1]s.nodes.roles like '%%validator%%') THEN true ELSE false END AS status,
+				%[1]s.entities.meta->>'name' AS name
+			FROM %[1]s.entities
+			JOIN %[1]s.accounts ON %[1]s.entities.address = %[1]s.accounts.address
+			JOIN %[1]s.nodes ON %[1]s.entities.id = %[1]s.nodes.entity_id
+				AND %[1]s.nodes.roles like '%%validator%%'
+				AND %[1]s.nodes.voting_power = (
+					SELECT max(voting_power)
+					FROM %[1]s.nodes
+					WHERE %[1]s.entities.id = %[1]s.nodes.entity_id
+						AND %[1]s.nodes.roles like '%%validator%%'
+				)
+			WHERE ($1::text IS NULL OR %[1]s.entities.id = $1::text)
+		ORDER BY escrow_balance_active DESC
+		LIMIT $2::bigint
+		OFFSET $3::bigint`, qf.chainID)
+}
+
+func (qf QueryFactory) ProposalsQuery() string {
+	return fmt.Sprintf(`
+		SELECT id, submitter, state, deposit::text, handler, cp_target_version, rhp_target_version, rcp_target_version,
+				upgrade_epoch, cancels, created_at, closes_at, invalid_votes::text
+			FROM %s.proposals
+			WHERE ($1::text IS NULL OR submitter = $1::text) AND
+						($2::text IS NULL OR state = $2::text)
+		ORDER BY id DESC
+		LIMIT $3::bigint
+		OFFSET $4::bigint`, qf.chainID)
+}
+
+func (qf QueryFactory) ProposalsByIDsQuery() string {
+	return fmt.Sprintf(`
+		SELECT id, submitter, state, deposit::text, handler, cp_target_version, rhp_target_version, rcp_target_version,
+				upgrade_epoch, cancels, created_at, closes_at, invalid_votes::text
+			FROM %s.proposals
+			WHERE id = ANY($1::bigint[])`, qf.chainID)
+}
+
+func (qf QueryFactory) VotesByProposalsQuery() string {
+	return fmt.Sprintf(`
+		SELECT proposal, voter, vote
+			FROM %s.votes
+			WHERE proposal = ANY($1::bigint[])
+		ORDER BY proposal, voter`, qf.chainID)
+}
+
+func (qf QueryFactory) EmeraldRoundsQuery() string {
+	return fmt.Sprintf(`
+		SELECT height::bigint, version, timestamp::bigint, block_hash, prev_block_hash, io_root, state_root, messages_hash, in_messages_hash
+			FROM %s.emerald_rounds
+		ORDER BY height DESC
+		LIMIT $1::bigint
+		OFFSET $2::bigint`, qf.chainID)
+}
+
+func (qf QueryFactory) EmeraldRoundsByRoundsQuery() string {
+	return fmt.Sprintf(`
+		SELECT height::bigint, version, timestamp::bigint, block_hash, prev_block_hash, io_root, state_root, messages_hash, in_messages_hash
+			FROM %s.emerald_rounds
+			WHERE height = ANY($1::bigint[])`, qf.chainID)
+}
+
+func (qf QueryFactory) EmeraldTransfersByRoundsQuery() string {
+	return fmt.Sprintf(`
+		SELECT height::bigint, sender, receiver, amount
+			FROM %s.emerald_transfers
+			WHERE height = ANY($1::bigint[])
+		ORDER BY height`, qf.chainID)
+}
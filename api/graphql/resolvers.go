@@ -0,0 +1,737 @@
+package graphql
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"reflect"
+	"sync"
+	"sync/atomic"
+
+	"github.com/oasisprotocol/oasis-indexer/api/common"
+	v1 "github.com/oasisprotocol/oasis-indexer/api/v1"
+	"github.com/oasisprotocol/oasis-indexer/log"
+	"github.com/oasisprotocol/oasis-indexer/storage"
+)
+
+// Expected sizes of nested lists, used to estimate query complexity.
+const (
+	transactionsPerBlock  = 50
+	eventsPerBlock        = 100
+	eventsPerTransaction  = 10
+	delegationsPerAccount = 20
+	nodesPerEntity        = 10
+	votesPerProposal      = 100
+	transfersPerRound     = 20
+)
+
+type contextKey string
+
+// complexityContextKey is the key of the complexity of the query of a request.
+const complexityContextKey contextKey = "complexity"
+
+// complexity is the complexity of a query, which is the number of objects
+// it may load, counting lists as their limit or their expected size.
+type complexity struct {
+	max   int64
+	total int64
+}
+
+// withComplexity returns a context in which loaded objects count towards
+// a maximum complexity. A maximum of 0 is no limit.
+func withComplexity(ctx context.Context, max int64) context.Context {
+	return context.WithValue(ctx, complexityContextKey, &complexity{max: max})
+}
+
+// addComplexity counts n objects that are about to be loaded towards the
+// complexity of the query, failing if it then exceeds its maximum.
+func addComplexity(ctx context.Context, n int64) error {
+	c, ok := ctx.Value(complexityContextKey).(*complexity)
+	if !ok || c.max == 0 {
+		return nil
+	}
+	if atomic.AddInt64(&c.total, n) > c.max {
+		return &common.Error{
+			Code:   common.CodeBadRequest,
+			Status: http.StatusBadRequest,
+			Msg:    fmt.Sprintf("query exceeds maximum complexity of %d", c.max),
+		}
+	}
+	return nil
+}
+
+// scanFunc scans an object from a row.
+type scanFunc func(storage.QueryResults) (interface{}, error)
+
+// resolver loads objects from target storage. It resolves the fields
+// of the root query type.
+type resolver struct {
+	db     storage.TargetStorage
+	logger *log.Logger
+}
+
+// queryFactory returns the query factory for the chain of the request.
+func (r *resolver) queryFactory(ctx context.Context) (QueryFactory, error) {
+	cid, ok := ctx.Value(v1.ChainIDContextKey).(string)
+	if !ok {
+		return QueryFactory{}, common.ErrBadChainID
+	}
+	return NewQueryFactory(cid), nil
+}
+
+// load runs a query and scans all resulting objects.
+func (r *resolver) load(ctx context.Context, scan scanFunc, sql string, args ...interface{}) ([]interface{}, error) {
+	rows, err := r.db.Query(ctx, sql, args...)
+	if err != nil {
+		r.logger.Info("query failed",
+			"request_id", ctx.Value(v1.RequestIDContextKey),
+			"err", err.Error(),
+		)
+		return nil, common.ErrStorageError
+	}
+	defer rows.Close()
+
+	objects := []interface{}{}
+	for rows.Next() {
+		o, err := scan(rows)
+		if err != nil {
+			r.logger.Info("row scan failed",
+				"request_id", ctx.Value(v1.RequestIDContextKey),
+				"err", err.Error(),
+			)
+			return nil, common.ErrStorageError
+		}
+		objects = append(objects, o)
+	}
+	if err := rows.Err(); err != nil {
+		r.logger.Info("query failed",
+			"request_id", ctx.Value(v1.RequestIDContextKey),
+			"err", err.Error(),
+		)
+		return nil, common.ErrStorageError
+	}
+	return objects, nil
+}
+
+// list loads the objects of a root list field of at most limit objects
+// into out, which points to a slice.
+func (r *resolver) list(ctx context.Context, out interface{}, limit int64, scan scanFunc, query func(QueryFactory) string, args ...interface{}) error {
+	if err := addComplexity(ctx, limit); err != nil {
+		return err
+	}
+	qf, err := r.queryFactory(ctx)
+	if err != nil {
+		return err
+	}
+	objects, err := r.load(ctx, scan, query(qf), args...)
+	if err != nil {
+		return err
+	}
+	fill(out, r.newBatch(objects).objects)
+	return nil
+}
+
+// lookup loads the object of the provided key into out, using a query
+// for a batch of keys.
+func (r *resolver) lookup(ctx context.Context, out interface{}, scan scanFunc, query func(QueryFactory) string, key interface{}) error {
+	if err := addComplexity(ctx, 1); err != nil {
+		return err
+	}
+	qf, err := r.queryFactory(ctx)
+	if err != nil {
+		return err
+	}
+	objects, err := r.load(ctx, scan, query(qf), keyArray([]interface{}{key}))
+	if err != nil {
+		return err
+	}
+	fill(out, r.newBatch(objects).objects)
+	return nil
+}
+
+// relation relates objects to the objects of another type, which are
+// loaded by a query taking an array of keys.
+type relation struct {
+	scan  scanFunc
+	query func(QueryFactory) string
+	// parentKey returns the key of a parent, or nil if it has none.
+	parentKey func(interface{}) interface{}
+	// objectKey returns the key of a related object.
+	objectKey func(interface{}) interface{}
+	// size is the expected number of related objects of a parent,
+	// for relations to lists of objects, or 0.
+	size int64
+}
+
+// batch is a batch of objects that were loaded together. The objects
+// related to any object of a batch are loaded for all of its objects
+// when they are first resolved, with a single query per relation.
+type batch struct {
+	r       *resolver
+	objects []interface{}
+
+	mu    sync.Mutex
+	loads map[*relation]*batchLoad
+}
+
+// batchLoad is a load of the objects related to a batch, grouped by
+// the keys of their parents.
+type batchLoad struct {
+	done   chan struct{}
+	groups map[interface{}][]interface{}
+	err    error
+}
+
+// batched is embedded in objects to record the batch they were loaded in.
+type batched struct {
+	batch *batch
+}
+
+func (b *batched) setBatch(batch *batch) {
+	b.batch = batch
+}
+
+// newBatch creates a batch of objects that were loaded together.
+func (r *resolver) newBatch(objects []interface{}) *batch {
+	b := &batch{
+		r:       r,
+		objects: objects,
+		loads:   make(map[*relation]*batchLoad),
+	}
+	for _, o := range objects {
+		o.(interface{ setBatch(*batch) }).setBatch(b)
+	}
+	return b
+}
+
+// related loads the objects related to an object of the batch into out,
+// which points to a slice for relations to lists of objects.
+func (b *batch) related(ctx context.Context, rel *relation, object interface{}, out interface{}) error {
+	b.mu.Lock()
+	l, loading := b.loads[rel]
+	if !loading {
+		l = &batchLoad{done: make(chan struct{})}
+		b.loads[rel] = l
+	}
+	b.mu.Unlock()
+
+	if loading {
+		<-l.done
+	} else {
+		l.groups, l.err = b.load(ctx, rel)
+		close(l.done)
+	}
+	if l.err != nil {
+		return l.err
+	}
+	if key := rel.parentKey(object); key != nil {
+		fill(out, l.groups[key])
+	}
+	return nil
+}
+
+// load loads the objects related to all objects of the batch, taking
+// their distinct keys.
+func (b *batch) load(ctx context.Context, rel *relation) (map[interface{}][]interface{}, error) {
+	keys := make([]interface{}, 0, len(b.objects))
+	seen := make(map[interface{}]struct{}, len(b.objects))
+	for _, o := range b.objects {
+		k := rel.parentKey(o)
+		if k == nil {
+			continue
+		}
+		if _, ok := seen[k]; !ok {
+			seen[k] = struct{}{}
+			keys = append(keys, k)
+		}
+	}
+
+	groups := make(map[interface{}][]interface{})
+	if len(keys) == 0 {
+		return groups, nil
+	}
+	size := rel.size
+	if size == 0 {
+		size = 1
+	}
+	if err := addComplexity(ctx, int64(len(keys))*size); err != nil {
+		return nil, err
+	}
+	qf, err := b.r.queryFactory(ctx)
+	if err != nil {
+		return nil, err
+	}
+	objects, err := b.r.load(ctx, rel.scan, rel.query(qf), keyArray(keys))
+	if err != nil {
+		return nil, err
+	}
+	for _, o := range b.r.newBatch(objects).objects {
+		k := rel.objectKey(o)
+		groups[k] = append(groups[k], o)
+	}
+	return groups, nil
+}
+
+// fill sets the value out points to to the loaded objects if it is
+// a slice, or else to the first of them.
+func fill(out interface{}, objects []interface{}) {
+	v := reflect.ValueOf(out).Elem()
+	if v.Kind() != reflect.Slice {
+		if len(objects) > 0 {
+			v.Set(reflect.ValueOf(objects[0]))
+		}
+		return
+	}
+	for _, o := range objects {
+		v.Set(reflect.Append(v, reflect.ValueOf(o)))
+	}
+}
+
+// keyArray converts keys to an array query parameter.
+func keyArray(keys []interface{}) interface{} {
+	if len(keys) == 0 {
+		return []string{}
+	}
+	switch keys[0].(type) {
+	case int64:
+		ints := make([]int64, len(keys))
+		for i, k := range keys {
+			ints[i] = k.(int64)
+		}
+		return ints
+	default:
+		strs := make([]string, len(keys))
+		for i, k := range keys {
+			strs[i] = k.(string)
+		}
+		return strs
+	}
+}
+
+// stringKey returns the key of an optional string.
+func stringKey(s *string) interface{} {
+	if s == nil {
+		return nil
+	}
+	return *s
+}
+
+// paginationArgs are the arguments of paginated fields.
+type paginationArgs struct {
+	Limit  int32
+	Offset int32
+}
+
+// pagination returns the limit and offset of a paginated field.
+func (a paginationArgs) pagination() (int64, int64, error) {
+	limit, offset := int64(a.Limit), int64(a.Offset)
+	if limit < 0 || offset < 0 {
+		return 0, 0, common.ErrBadRequest
+	}
+	if limit > int64(common.MaximumLimit) {
+		limit = int64(common.MaximumLimit)
+	}
+	return limit, offset, nil
+}
+
+// Block resolves the block at the provided height.
+func (r *resolver) Block(ctx context.Context, args struct{ Height int32 }) (*Block, error) {
+	var b *Block
+	err := r.lookup(ctx, &b, scanBlock, QueryFactory.BlocksByHeightsQuery, int64(args.Height))
+	return b, err
+}
+
+// Blocks resolves blocks, starting with the most recent.
+func (r *resolver) Blocks(ctx context.Context, args struct {
+	paginationArgs
+	From *int32
+	To   *int32
+}) ([]*Block, error) {
+	limit, offset, err := args.pagination()
+	if err != nil {
+		return nil, err
+	}
+	var blocks []*Block
+	err = r.list(ctx, &blocks, limit, scanBlock, QueryFactory.BlocksQuery, args.From, args.To, limit, offset)
+	return blocks, err
+}
+
+// Transaction resolves the transaction with the provided hash.
+func (r *resolver) Transaction(ctx context.Context, args struct{ Hash string }) (*Transaction, error) {
+	var t *Transaction
+	err := r.lookup(ctx, &t, scanTransaction, QueryFactory.TransactionsByHashesQuery, args.Hash)
+	return t, err
+}
+
+// Transactions resolves transactions, starting with the most recent.
+func (r *resolver) Transactions(ctx context.Context, args struct {
+	paginationArgs
+	Block  *int32
+	Method *string
+	Sender *string
+}) ([]*Transaction, error) {
+	limit, offset, err := args.pagination()
+	if err != nil {
+		return nil, err
+	}
+	var txs []*Transaction
+	err = r.list(ctx, &txs, limit, scanTransaction, QueryFactory.TransactionsQuery, args.Block, args.Method, args.Sender, limit, offset)
+	return txs, err
+}
+
+// Events resolves events, starting with the most recent.
+func (r *resolver) Events(ctx context.Context, args struct {
+	paginationArgs
+	Block   *int32
+	Backend *string
+	Type    *string
+}) ([]*Event, error) {
+	limit, offset, err := args.pagination()
+	if err != nil {
+		return nil, err
+	}
+	var events []*Event
+	err = r.list(ctx, &events, limit, scanEvent, QueryFactory.EventsQuery, args.Block, args.Backend, args.Type, limit, offset)
+	return events, err
+}
+
+// Account resolves the account with the provided address.
+func (r *resolver) Account(ctx context.Context, args struct{ Address string }) (*Account, error) {
+	var a *Account
+	err := r.lookup(ctx, &a, scanAccount, QueryFactory.AccountsByAddressesQuery, args.Address)
+	return a, err
+}
+
+// Accounts resolves accounts.
+func (r *resolver) Accounts(ctx context.Context, args paginationArgs) ([]*Account, error) {
+	limit, offset, err := args.pagination()
+	if err != nil {
+		return nil, err
+	}
+	var accounts []*Account
+	err = r.list(ctx, &accounts, limit, scanAccount, QueryFactory.AccountsQuery, limit, offset)
+	return accounts, err
+}
+
+// Entity resolves the entity with the provided ID.
+func (r *resolver) Entity(ctx context.Context, args struct{ ID string }) (*Entity, error) {
+	var e *Entity
+	err := r.lookup(ctx, &e, scanEntity, QueryFactory.EntitiesByIDsQuery, args.ID)
+	return e, err
+}
+
+// Entities resolves entities.
+func (r *resolver) Entities(ctx context.Context, args paginationArgs) ([]*Entity, error) {
+	limit, offset, err := args.pagination()
+	if err != nil {
+		return nil, err
+	}
+	var entities []*Entity
+	err = r.list(ctx, &entities, limit, scanEntity, QueryFactory.EntitiesQuery, limit, offset)
+	return entities, err
+}
+
+// Node resolves the node with the provided ID.
+func (r *resolver) Node(ctx context.Context, args struct{ ID string }) (*Node, error) {
+	var n *Node
+	err := r.lookup(ctx, &n, scanNode, QueryFactory.NodesByIDsQuery, args.ID)
+	return n, err
+}
+
+// Validator resolves the validator of the entity with the provided ID.
+func (r *resolver) Validator(ctx context.Context, args struct{ EntityID string }) (*Validator, error) {
+	var v *Validator
+	err := r.list(ctx, &v, 1, scanValidator, QueryFactory.ValidatorsQuery, args.EntityID, 1, 0)
+	return v, err
+}
+
+// Validators resolves validators, ordered by escrow.
+func (r *resolver) Validators(ctx context.Context, args paginationArgs) ([]*Validator, error) {
+	limit, offset, err := args.pagination()
+	if err != nil {
+		return nil, err
+	}
+	var validators []*Validator
+	err = r.list(ctx, &validators, limit, scanValidator, QueryFactory.ValidatorsQuery, nil, limit, offset)
+	return validators, err
+}
+
+// Proposal resolves the proposal with the provided ID.
+func (r *resolver) Proposal(ctx context.Context, args struct{ ID int32 }) (*Proposal, error) {
+	var p *Proposal
+	err := r.lookup(ctx, &p, scanProposal, QueryFactory.ProposalsByIDsQuery, int64(args.ID))
+	return p, err
+}
+
+// Proposals resolves proposals, starting with the most recent.
+func (r *resolver) Proposals(ctx context.Context, args struct {
+	paginationArgs
+	Submitter *string
+	State     *string
+}) ([]*Proposal, error) {
+	limit, offset, err := args.pagination()
+	if err != nil {
+		return nil, err
+	}
+	var proposals []*Proposal
+	err = r.list(ctx, &proposals, limit, scanProposal, QueryFactory.ProposalsQuery, args.Submitter, args.State, limit, offset)
+	return proposals, err
+}
+
+// EmeraldRound resolves the Emerald round with the provided number.
+func (r *resolver) EmeraldRound(ctx context.Context, args struct{ Round int32 }) (*EmeraldRound, error) {
+	var round *EmeraldRound
+	err := r.lookup(ctx, &round, scanEmeraldRound, QueryFactory.EmeraldRoundsByRoundsQuery, int64(args.Round))
+	return round, err
+}
+
+// EmeraldRounds resolves Emerald rounds, starting with the most recent.
+func (r *resolver) EmeraldRounds(ctx context.Context, args paginationArgs) ([]*EmeraldRound, error) {
+	limit, offset, err := args.pagination()
+	if err != nil {
+		return nil, err
+	}
+	var rounds []*EmeraldRound
+	err = r.list(ctx, &rounds, limit, scanEmeraldRound, QueryFactory.EmeraldRoundsQuery, limit, offset)
+	return rounds, err
+}
+
+// Relations between objects.
+var (
+	blockTransactions = &relation{
+		scan:      scanTransaction,
+		query:     QueryFactory.TransactionsByBlocksQuery,
+		parentKey: func(p interface{}) interface{} { return int64(p.(*Block).Height) },
+		objectKey: func(o interface{}) interface{} { return int64(o.(*Transaction).Height) },
+		size:      transactionsPerBlock,
+	}
+	blockEvents = &relation{
+		scan:      scanEvent,
+		query:     QueryFactory.EventsByBlocksQuery,
+		parentKey: func(p interface{}) interface{} { return int64(p.(*Block).Height) },
+		objectKey: func(o interface{}) interface{} { return int64(o.(*Event).Height) },
+		size:      eventsPerBlock,
+	}
+	transactionBlock = &relation{
+		scan:      scanBlock,
+		query:     QueryFactory.BlocksByHeightsQuery,
+		parentKey: func(p interface{}) interface{} { return int64(p.(*Transaction).Height) },
+		objectKey: func(o interface{}) interface{} { return int64(o.(*Block).Height) },
+	}
+	transactionSenderAccount = &relation{
+		scan:      scanAccount,
+		query:     QueryFactory.AccountsByAddressesQuery,
+		parentKey: func(p interface{}) interface{} { return p.(*Transaction).Sender },
+		objectKey: accountKey,
+	}
+	transactionEvents = &relation{
+		scan:      scanEvent,
+		query:     QueryFactory.EventsByTransactionsQuery,
+		parentKey: func(p interface{}) interface{} { return p.(*Transaction).Hash },
+		objectKey: func(o interface{}) interface{} { return o.(*Event).TxHash },
+		size:      eventsPerTransaction,
+	}
+	eventTransaction = &relation{
+		scan:      scanTransaction,
+		query:     QueryFactory.TransactionsByHashesQuery,
+		parentKey: func(p interface{}) interface{} { return p.(*Event).TxHash },
+		objectKey: func(o interface{}) interface{} { return o.(*Transaction).Hash },
+	}
+	accountDelegations = &relation{
+		scan:      scanDelegation,
+		query:     QueryFactory.DelegationsByDelegatorsQuery,
+		parentKey: func(p interface{}) interface{} { return p.(*Account).Address },
+		objectKey: func(o interface{}) interface{} { return o.(*Delegation).Delegator },
+		size:      delegationsPerAccount,
+	}
+	delegationValidatorAccount = &relation{
+		scan:      scanAccount,
+		query:     QueryFactory.AccountsByAddressesQuery,
+		parentKey: func(p interface{}) interface{} { return p.(*Delegation).ValidatorAddress },
+		objectKey: accountKey,
+	}
+	entityAccount = &relation{
+		scan:      scanAccount,
+		query:     QueryFactory.AccountsByAddressesQuery,
+		parentKey: func(p interface{}) interface{} { return stringKey(p.(*Entity).Address) },
+		objectKey: accountKey,
+	}
+	entityNodes = &relation{
+		scan:      scanNode,
+		query:     QueryFactory.NodesByEntitiesQuery,
+		parentKey: func(p interface{}) interface{} { return p.(*Entity).ID },
+		objectKey: func(o interface{}) interface{} { return o.(*Node).EntityID },
+		size:      nodesPerEntity,
+	}
+	nodeEntity = &relation{
+		scan:      scanEntity,
+		query:     QueryFactory.EntitiesByIDsQuery,
+		parentKey: func(p interface{}) interface{} { return p.(*Node).EntityID },
+		objectKey: entityKey,
+	}
+	validatorEntity = &relation{
+		scan:      scanEntity,
+		query:     QueryFactory.EntitiesByIDsQuery,
+		parentKey: func(p interface{}) interface{} { return p.(*Validator).EntityID },
+		objectKey: entityKey,
+	}
+	validatorNode = &relation{
+		scan:      scanNode,
+		query:     QueryFactory.NodesByIDsQuery,
+		parentKey: func(p interface{}) interface{} { return p.(*Validator).NodeID },
+		objectKey: func(o interface{}) interface{} { return o.(*Node).ID },
+	}
+	proposalSubmitterAccount = &relation{
+		scan:      scanAccount,
+		query:     QueryFactory.AccountsByAddressesQuery,
+		parentKey: func(p interface{}) interface{} { return p.(*Proposal).Submitter },
+		objectKey: accountKey,
+	}
+	proposalVotes = &relation{
+		scan:      scanVote,
+		query:     QueryFactory.VotesByProposalsQuery,
+		parentKey: func(p interface{}) interface{} { return int64(p.(*Proposal).ID) },
+		objectKey: func(o interface{}) interface{} { return int64(o.(*Vote).ProposalID) },
+		size:      votesPerProposal,
+	}
+	voteVoterAccount = &relation{
+		scan:      scanAccount,
+		query:     QueryFactory.AccountsByAddressesQuery,
+		parentKey: func(p interface{}) interface{} { return p.(*Vote).Voter },
+		objectKey: accountKey,
+	}
+	emeraldRoundTransfers = &relation{
+		scan:      scanEmeraldTransfer,
+		query:     QueryFactory.EmeraldTransfersByRoundsQuery,
+		parentKey: func(p interface{}) interface{} { return int64(p.(*EmeraldRound).Round) },
+		objectKey: func(o interface{}) interface{} { return int64(o.(*EmeraldTransfer).Round) },
+		size:      transfersPerRound,
+	}
+)
+
+func accountKey(o interface{}) interface{} {
+	return o.(*Account).Address
+}
+
+func entityKey(o interface{}) interface{} {
+	return o.(*Entity).ID
+}
+
+// Transactions resolves the transactions in the block.
+func (b *Block) Transactions(ctx context.Context) ([]*Transaction, error) {
+	var txs []*Transaction
+	err := b.batch.related(ctx, blockTransactions, b, &txs)
+	return txs, err
+}
+
+// Events resolves the events emitted in the block.
+func (b *Block) Events(ctx context.Context) ([]*Event, error) {
+	var events []*Event
+	err := b.batch.related(ctx, blockEvents, b, &events)
+	return events, err
+}
+
+// Block resolves the block containing the transaction.
+func (t *Transaction) Block(ctx context.Context) (*Block, error) {
+	var b *Block
+	err := t.batch.related(ctx, transactionBlock, t, &b)
+	return b, err
+}
+
+// SenderAccount resolves the account of the sender.
+func (t *Transaction) SenderAccount(ctx context.Context) (*Account, error) {
+	var a *Account
+	err := t.batch.related(ctx, transactionSenderAccount, t, &a)
+	return a, err
+}
+
+// Events resolves the events emitted by the transaction.
+func (t *Transaction) Events(ctx context.Context) ([]*Event, error) {
+	var events []*Event
+	err := t.batch.related(ctx, transactionEvents, t, &events)
+	return events, err
+}
+
+// Transaction resolves the transaction that emitted the event.
+func (e *Event) Transaction(ctx context.Context) (*Transaction, error) {
+	var t *Transaction
+	err := e.batch.related(ctx, eventTransaction, e, &t)
+	return t, err
+}
+
+// Delegations resolves the active delegations of the account.
+func (a *Account) Delegations(ctx context.Context) ([]*Delegation, error) {
+	var delegations []*Delegation
+	err := a.batch.related(ctx, accountDelegations, a, &delegations)
+	return delegations, err
+}
+
+// ValidatorAccount resolves the account of the validator.
+func (d *Delegation) ValidatorAccount(ctx context.Context) (*Account, error) {
+	var a *Account
+	err := d.batch.related(ctx, delegationValidatorAccount, d, &a)
+	return a, err
+}
+
+// Account resolves the account of the entity.
+func (e *Entity) Account(ctx context.Context) (*Account, error) {
+	var a *Account
+	err := e.batch.related(ctx, entityAccount, e, &a)
+	return a, err
+}
+
+// Nodes resolves the nodes of the entity.
+func (e *Entity) Nodes(ctx context.Context) ([]*Node, error) {
+	var nodes []*Node
+	err := e.batch.related(ctx, entityNodes, e, &nodes)
+	return nodes, err
+}
+
+// Entity resolves the entity of the node.
+func (n *Node) Entity(ctx context.Context) (*Entity, error) {
+	var e *Entity
+	err := n.batch.related(ctx, nodeEntity, n, &e)
+	return e, err
+}
+
+// Entity resolves the entity of the validator.
+func (v *Validator) Entity(ctx context.Context) (*Entity, error) {
+	var e *Entity
+	err := v.batch.related(ctx, validatorEntity, v, &e)
+	return e, err
+}
+
+// Node resolves the validator node.
+func (v *Validator) Node(ctx context.Context) (*Node, error) {
+	var n *Node
+	err := v.batch.related(ctx, validatorNode, v, &n)
+	return n, err
+}
+
+// SubmitterAccount resolves the account of the submitter.
+func (p *Proposal) SubmitterAccount(ctx context.Context) (*Account, error) {
+	var a *Account
+	err := p.batch.related(ctx, proposalSubmitterAccount, p, &a)
+	return a, err
+}
+
+// Votes resolves the votes on the proposal.
+func (p *Proposal) Votes(ctx context.Context) ([]*Vote, error) {
+	var votes []*Vote
+	err := p.batch.related(ctx, proposalVotes, p, &votes)
+	return votes, err
+}
+
+// VoterAccount resolves the account of the voter.
+func (v *Vote) VoterAccount(ctx context.Context) (*Account, error) {
+	var a *Account
+	err := v.batch.related(ctx, voteVoterAccount, v, &a)
+	return a, err
+}
+
+// Transfers resolves the transfers, burns and mints in the round.
+func (r *EmeraldRound) Transfers(ctx context.Context) ([]*EmeraldTransfer, error) {
+	var transfers []*EmeraldTransfer
+	err := r.batch.related(ctx, emeraldRoundTransfers, r, &transfers)
+	return transfers, err
+}
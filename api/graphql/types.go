@@ -0,0 +1,375 @@
+// Types of objects loaded from target storage.
+package graphql
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	gql "github.com/graph-gophers/graphql-go"
+	oasisErrors "github.com/oasisprotocol/oasis-core/go/common/errors"
+
+	"github.com/oasisprotocol/oasis-indexer/storage"
+)
+
+// BigInt is an arbitrary precision integer, encoded as a string.
+type BigInt string
+
+// ImplementsGraphQLType implements the graphql-go Unmarshaler interface.
+func (BigInt) ImplementsGraphQLType(name string) bool {
+	return name == "BigInt"
+}
+
+// UnmarshalGraphQL implements the graphql-go Unmarshaler interface.
+func (b *BigInt) UnmarshalGraphQL(input interface{}) error {
+	s, ok := input.(string)
+	if !ok {
+		return fmt.Errorf("wrong type for BigInt: %T", input)
+	}
+	*b = BigInt(s)
+	return nil
+}
+
+// JSON is an arbitrary JSON value.
+type JSON json.RawMessage
+
+// ImplementsGraphQLType implements the graphql-go Unmarshaler interface.
+func (JSON) ImplementsGraphQLType(name string) bool {
+	return name == "JSON"
+}
+
+// UnmarshalGraphQL implements the graphql-go Unmarshaler interface.
+func (j *JSON) UnmarshalGraphQL(input interface{}) error {
+	raw, err := json.Marshal(input)
+	if err != nil {
+		return err
+	}
+	*j = raw
+	return nil
+}
+
+// MarshalJSON implements the json.Marshaler interface.
+func (j JSON) MarshalJSON() ([]byte, error) {
+	if j == nil {
+		return []byte("null"), nil
+	}
+	return j, nil
+}
+
+// Block is a consensus block.
+type Block struct {
+	batched
+	Height    int32
+	Hash      string
+	Timestamp gql.Time
+}
+
+func scanBlock(rows storage.QueryResults) (interface{}, error) {
+	var b Block
+	var timestamp time.Time
+	if err := rows.Scan(&b.Height, &b.Hash, &timestamp); err != nil {
+		return nil, err
+	}
+	b.Timestamp = gql.Time{Time: timestamp.UTC()}
+	return &b, nil
+}
+
+// Transaction is a consensus transaction.
+type Transaction struct {
+	batched
+	Height int32
+	Hash   string
+	Index  *int32
+	Sender string
+	Nonce  int32
+	Fee    *BigInt
+	Method string
+	// Body is the base64 encoded CBOR transaction body.
+	Body    *string
+	Success bool
+}
+
+func scanTransaction(rows storage.QueryResults) (interface{}, error) {
+	var t Transaction
+	var body []byte
+	var code *uint64
+	if err := rows.Scan(
+		&t.Height,
+		&t.Hash,
+		&t.Index,
+		&t.Sender,
+		&t.Nonce,
+		&t.Fee,
+		&t.Method,
+		&body,
+		&code,
+	); err != nil {
+		return nil, err
+	}
+	if body != nil {
+		encoded := base64.StdEncoding.EncodeToString(body)
+		t.Body = &encoded
+	}
+	t.Success = code == nil || *code == oasisErrors.CodeNoError
+	return &t, nil
+}
+
+// Event is a consensus event.
+type Event struct {
+	batched
+	Height  int32
+	TxHash  string
+	TxIndex *int32
+	Backend string
+	Type    string
+	Body    *JSON
+}
+
+func scanEvent(rows storage.QueryResults) (interface{}, error) {
+	var e Event
+	var body *string
+	if err := rows.Scan(
+		&e.Height,
+		&e.TxHash,
+		&e.TxIndex,
+		&e.Backend,
+		&e.Type,
+		&body,
+	); err != nil {
+		return nil, err
+	}
+	if body != nil {
+		raw := JSON(*body)
+		e.Body = &raw
+	}
+	return &e, nil
+}
+
+// Account is a consensus account.
+type Account struct {
+	batched
+	Address   string
+	Nonce     int32
+	Available BigInt
+	Escrow    BigInt
+	Debonding BigInt
+}
+
+func scanAccount(rows storage.QueryResults) (interface{}, error) {
+	var a Account
+	if err := rows.Scan(
+		&a.Address,
+		&a.Nonce,
+		&a.Available,
+		&a.Escrow,
+		&a.Debonding,
+	); err != nil {
+		return nil, err
+	}
+	return &a, nil
+}
+
+// Delegation is an active delegation.
+type Delegation struct {
+	batched
+	Delegator        string
+	ValidatorAddress string
+	Shares           BigInt
+	Amount           BigInt
+}
+
+func scanDelegation(rows storage.QueryResults) (interface{}, error) {
+	var d Delegation
+	if err := rows.Scan(
+		&d.Delegator,
+		&d.ValidatorAddress,
+		&d.Shares,
+		&d.Amount,
+	); err != nil {
+		return nil, err
+	}
+	return &d, nil
+}
+
+// Entity is a registered entity.
+type Entity struct {
+	batched
+	ID      string
+	Address *string
+}
+
+func scanEntity(rows storage.QueryResults) (interface{}, error) {
+	var e Entity
+	if err := rows.Scan(&e.ID, &e.Address); err != nil {
+		return nil, err
+	}
+	return &e, nil
+}
+
+// Node is a registered node.
+type Node struct {
+	batched
+	ID              string
+	EntityID        string
+	Expiration      int32
+	TLSPubkey       string
+	TLSNextPubkey   *string
+	P2PPubkey       string
+	ConsensusPubkey string
+	Roles           *string
+	SoftwareVersion *string
+	VotingPower     int32
+}
+
+func scanNode(rows storage.QueryResults) (interface{}, error) {
+	var n Node
+	if err := rows.Scan(
+		&n.ID,
+		&n.EntityID,
+		&n.Expiration,
+		&n.TLSPubkey,
+		&n.TLSNextPubkey,
+		&n.P2PPubkey,
+		&n.ConsensusPubkey,
+		&n.Roles,
+		&n.SoftwareVersion,
+		&n.VotingPower,
+	); err != nil {
+		return nil, err
+	}
+	return &n, nil
+}
+
+// Validator is an entity with a validator node.
+type Validator struct {
+	batched
+	EntityID      string
+	EntityAddress string
+	NodeID        string
+	Escrow        BigInt
+	Active        bool
+	Status        bool
+	Name          *string
+}
+
+func scanValidator(rows storage.QueryResults) (interface{}, error) {
+	var v Validator
+	if err := rows.Scan(
+		&v.EntityID,
+		&v.EntityAddress,
+		&v.NodeID,
+		&v.Escrow,
+		&v.Active,
+		&v.Status,
+		&v.Name,
+	); err != nil {
+		return nil, err
+	}
+	return &v, nil
+}
+
+// Proposal is a governance proposal.
+type Proposal struct {
+	batched
+	ID                             int32
+	Submitter                      string
+	State                          string
+	Deposit                        BigInt
+	Handler                        *string
+	ConsensusProtocolTarget        *string
+	RuntimeHostProtocolTarget      *string
+	RuntimeCommitteeProtocolTarget *string
+	UpgradeEpoch                   *int32
+	Cancels                        *int32
+	CreatedAt                      int32
+	ClosesAt                       int32
+	InvalidVotes                   BigInt
+}
+
+func scanProposal(rows storage.QueryResults) (interface{}, error) {
+	var p Proposal
+	if err := rows.Scan(
+		&p.ID,
+		&p.Submitter,
+		&p.State,
+		&p.Deposit,
+		&p.Handler,
+		&p.ConsensusProtocolTarget,
+		&p.RuntimeHostProtocolTarget,
+		&p.RuntimeCommitteeProtocolTarget,
+		&p.UpgradeEpoch,
+		&p.Cancels,
+		&p.CreatedAt,
+		&p.ClosesAt,
+		&p.InvalidVotes,
+	); err != nil {
+		return nil, err
+	}
+	return &p, nil
+}
+
+// Vote is a vote on a governance proposal.
+type Vote struct {
+	batched
+	ProposalID int32
+	Voter      string
+	Vote       *string
+}
+
+func scanVote(rows storage.QueryResults) (interface{}, error) {
+	var v Vote
+	if err := rows.Scan(&v.ProposalID, &v.Voter, &v.Vote); err != nil {
+		return nil, err
+	}
+	return &v, nil
+}
+
+// EmeraldRound is an Emerald ParaTime round.
+type EmeraldRound struct {
+	batched
+	Round          int32
+	Version        *int32
+	Timestamp      int32
+	Hash           string
+	PrevHash       string
+	IORoot         string
+	StateRoot      string
+	MessagesHash   string
+	InMessagesHash string
+}
+
+func scanEmeraldRound(rows storage.QueryResults) (interface{}, error) {
+	var r EmeraldRound
+	if err := rows.Scan(
+		&r.Round,
+		&r.Version,
+		&r.Timestamp,
+		&r.Hash,
+		&r.PrevHash,
+		&r.IORoot,
+		&r.StateRoot,
+		&r.MessagesHash,
+		&r.InMessagesHash,
+	); err != nil {
+		return nil, err
+	}
+	return &r, nil
+}
+
+// EmeraldTransfer is a transfer, burn or mint within the Emerald ParaTime.
+type EmeraldTransfer struct {
+	batched
+	Round    int32
+	Sender   string
+	Receiver string
+	Amount   string
+}
+
+func scanEmeraldTransfer(rows storage.QueryResults) (interface{}, error) {
+	var t EmeraldTransfer
+	if err := rows.Scan(&t.Round, &t.Sender, &t.Receiver, &t.Amount); err != nil {
+		return nil, err
+	}
+	return &t, nil
+}
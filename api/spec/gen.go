@@ -0,0 +1,260 @@
+package spec
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"path"
+	"strings"
+	"unicode"
+)
+
+// commentWidth is the width that generated comments are wrapped at.
+const commentWidth = 76
+
+// initialisms are words of names that are capitalized in Go names.
+var initialisms = map[string]string{
+	"id":  "ID",
+	"io":  "IO",
+	"p2p": "P2P",
+	"tls": "TLS",
+	"url": "URL",
+}
+
+// GenerateGo generates a Go file of the package, containing a type for each
+// schema of the document and an interface of servers of its operations.
+//
+// Schemas with an x-go-type are not generated. Properties that are not
+// required are omitted from JSON if empty, and are pointers unless they
+// are slices, raw JSON or have x-go-type-skip-optional-pointer set.
+func GenerateGo(doc *Document, pkg string) ([]byte, error) {
+	g := generator{doc: doc, imports: map[string]bool{}}
+
+	var body bytes.Buffer
+	if err := g.serverInterface(&body); err != nil {
+		return nil, err
+	}
+	for _, ns := range doc.Components.Schemas {
+		if ns.Schema.GoType != "" {
+			continue
+		}
+		if err := g.schemaType(&body, ns.Name, ns.Schema); err != nil {
+			return nil, fmt.Errorf("schema %s: %w", ns.Name, err)
+		}
+	}
+
+	var buf bytes.Buffer
+	writeHeader(&buf, pkg, g.imports)
+	buf.Write(body.Bytes())
+
+	return format.Source(buf.Bytes())
+}
+
+type generator struct {
+	doc     *Document
+	imports map[string]bool
+
+	// types is the import path of the package of the types of schemas,
+	// if it is not the generated package.
+	types string
+}
+
+// serverInterface generates the interface of servers, with a method
+// for each operation.
+func (g *generator) serverInterface(w *bytes.Buffer) error {
+	g.imports["net/http"] = true
+
+	fmt.Fprintf(w, "\n// ServerInterface is implemented by servers of the API, which serve\n")
+	fmt.Fprintf(w, "// each operation of the specification with a method.\n")
+	fmt.Fprintf(w, "type ServerInterface interface {\n")
+	var n int
+	for _, item := range g.doc.Paths {
+		for _, method := range Methods {
+			op, ok := item.Operations[method]
+			if !ok {
+				continue
+			}
+			if op.OperationID == "" {
+				return fmt.Errorf("%s %s has no operationId", strings.ToUpper(method), item.Path)
+			}
+			if n++; n > 1 {
+				fmt.Fprintf(w, "\n")
+			}
+			writeComment(w, "\t", op.OperationID+" "+lowerFirst(firstSentence(op.Summary)))
+			fmt.Fprintf(w, "\t//\n\t// %s %s\n", strings.ToUpper(method), item.Path)
+			fmt.Fprintf(w, "\t%s(w http.ResponseWriter, r *http.Request)\n", op.OperationID)
+		}
+	}
+	fmt.Fprintf(w, "}\n")
+	return nil
+}
+
+// schemaType generates the struct type of an object schema.
+func (g *generator) schemaType(w *bytes.Buffer, name string, s *Schema) error {
+	if s.Type != "object" || len(s.Properties) == 0 {
+		return fmt.Errorf("only objects with properties are supported")
+	}
+	goName := typeName(name, s)
+
+	fmt.Fprintf(w, "\n")
+	if s.Description != "" {
+		writeComment(w, "", goName+" is "+lowerFirst(s.Description))
+	}
+	fmt.Fprintf(w, "type %s struct {\n", goName)
+	for _, p := range s.Properties {
+		typ, err := g.goType(p.Schema)
+		if err != nil {
+			return fmt.Errorf("property %s: %w", p.Name, err)
+		}
+
+		tag := p.Name
+		required := s.IsRequired(p.Name)
+		if !required {
+			tag += ",omitempty"
+		}
+		if (p.Schema.Nullable || !required && !p.Schema.GoSkipOptionalPointer) && !isNillable(typ) {
+			typ = "*" + typ
+		}
+
+		if p.Schema.Description != "" {
+			writeComment(w, "\t", p.Schema.Description)
+		}
+		fmt.Fprintf(w, "\t%s %s `json:%q`\n", fieldName(p.Name, p.Schema), typ, tag)
+	}
+	fmt.Fprintf(w, "}\n")
+	return nil
+}
+
+// goType returns the Go type of values of a schema.
+func (g *generator) goType(s *Schema) (string, error) {
+	if s.Ref != "" {
+		target := s.Resolve()
+		if target.GoType != "" {
+			return target.GoType, nil
+		}
+		name := typeName(strings.TrimPrefix(s.Ref, schemaRefPrefix), target)
+		if g.types != "" {
+			g.imports[g.types] = true
+			name = path.Base(g.types) + "." + name
+		}
+		return name, nil
+	}
+	if len(s.OneOf) > 0 {
+		return "interface{}", nil
+	}
+
+	switch s.Type {
+	case "integer":
+		switch s.Format {
+		case "":
+			return "int", nil
+		case "int32", "int64", "uint64":
+			return s.Format, nil
+		}
+	case "number":
+		return "float64", nil
+	case "boolean":
+		return "bool", nil
+	case "string":
+		switch s.Format {
+		case "date-time":
+			g.imports["time"] = true
+			return "time.Time", nil
+		case "byte":
+			return "[]byte", nil
+		default:
+			return "string", nil
+		}
+	case "array":
+		if s.Items == nil {
+			return "", fmt.Errorf("array has no items")
+		}
+		typ, err := g.goType(s.Items)
+		if err != nil {
+			return "", err
+		}
+		return "[]" + typ, nil
+	case "object":
+		if len(s.Properties) > 0 {
+			return "", fmt.Errorf("objects with properties must be referenced")
+		}
+		g.imports["encoding/json"] = true
+		return "json.RawMessage", nil
+	}
+	return "", fmt.Errorf("unsupported type %q of format %q", s.Type, s.Format)
+}
+
+// typeName returns the name of the Go type of a named schema.
+func typeName(name string, s *Schema) string {
+	if s.GoName != "" {
+		return s.GoName
+	}
+	return name
+}
+
+// fieldName returns the name of the Go field of a property.
+func fieldName(name string, s *Schema) string {
+	if s.GoName != "" {
+		return s.GoName
+	}
+	var b strings.Builder
+	for _, word := range strings.Split(name, "_") {
+		if initialism, ok := initialisms[word]; ok {
+			b.WriteString(initialism)
+		} else {
+			b.WriteString(upperFirst(word))
+		}
+	}
+	return b.String()
+}
+
+// isNillable returns true if values of the Go type may already be nil.
+func isNillable(typ string) bool {
+	return strings.HasPrefix(typ, "[]") || typ == "json.RawMessage" || typ == "interface{}"
+}
+
+// writeComment writes text as a comment, wrapped at commentWidth.
+func writeComment(w *bytes.Buffer, indent string, text string) {
+	line := ""
+	for _, word := range strings.Fields(text) {
+		if line != "" && len(line)+1+len(word) > commentWidth {
+			fmt.Fprintf(w, "%s// %s\n", indent, line)
+			line = ""
+		}
+		if line != "" {
+			line += " "
+		}
+		line += word
+	}
+	if line != "" {
+		fmt.Fprintf(w, "%s// %s\n", indent, line)
+	}
+}
+
+// firstSentence returns the first sentence of text.
+func firstSentence(text string) string {
+	text = strings.Join(strings.Fields(text), " ")
+	if i := strings.Index(text, ". "); i >= 0 {
+		return text[:i+1]
+	}
+	return text
+}
+
+func lowerFirst(s string) string {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return s
+	}
+	r := []rune(s)
+	r[0] = unicode.ToLower(r[0])
+	return string(r)
+}
+
+func upperFirst(s string) string {
+	if s == "" {
+		return s
+	}
+	r := []rune(s)
+	r[0] = unicode.ToUpper(r[0])
+	return string(r)
+}
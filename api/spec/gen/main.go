@@ -0,0 +1,42 @@
+// Command gen generates Go code of the V1 API from its OpenAPI specification,
+// which is either the types and server interface of the API, or a client.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"os"
+
+	"github.com/oasisprotocol/oasis-indexer/api/spec"
+)
+
+func main() {
+	pkg := flag.String("package", "v1", "package of the generated file")
+	out := flag.String("o", "api.gen.go", "file to write")
+	client := flag.Bool("client", false, "generate a client instead of types")
+	types := flag.String("types", "github.com/oasisprotocol/oasis-indexer/api/v1", "import path of the types used by clients")
+	flag.Parse()
+
+	if err := generate(*pkg, *out, *client, *types); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
+
+func generate(pkg string, out string, client bool, types string) error {
+	doc, err := spec.V1()
+	if err != nil {
+		return fmt.Errorf("failed to load spec: %w", err)
+	}
+	var src []byte
+	if client {
+		src, err = spec.GenerateClient(doc, pkg, types)
+	} else {
+		src, err = spec.GenerateGo(doc, pkg)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to generate: %w", err)
+	}
+	return ioutil.WriteFile(out, src, 0o644) //nolint:gosec // Generated source is not secret.
+}
@@ -0,0 +1,338 @@
+package spec
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"path"
+	"sort"
+	"strings"
+)
+
+// GenerateClient generates a Go file of the package, containing a client
+// method for each operation of the document. Responses are decoded into
+// the types generated by GenerateGo into the types package, which is an
+// import path.
+//
+// The generated methods are implemented with the unexported helpers of the
+// client package, which are written by hand.
+func GenerateClient(doc *Document, pkg string, types string) ([]byte, error) {
+	g := generator{doc: doc, imports: map[string]bool{}, types: types}
+
+	var body bytes.Buffer
+	for _, item := range doc.Paths {
+		for _, method := range Methods {
+			op, ok := item.Operations[method]
+			if !ok {
+				continue
+			}
+			if err := g.clientOperation(&body, method, item.Path, op); err != nil {
+				return nil, fmt.Errorf("%s %s: %w", strings.ToUpper(method), item.Path, err)
+			}
+		}
+	}
+
+	var buf bytes.Buffer
+	writeHeader(&buf, pkg, g.imports)
+	buf.Write(body.Bytes())
+
+	return format.Source(buf.Bytes())
+}
+
+// clientOperation generates the parameters, method and, for paginated
+// operations, the pager of an operation.
+func (g *generator) clientOperation(w *bytes.Buffer, method string, template string, op *Operation) error {
+	if op.OperationID == "" {
+		return fmt.Errorf("operation has no operationId")
+	}
+	g.imports["context"] = true
+	g.imports["net/http"] = true
+
+	var pathArgs, queryParams []*Parameter
+	for _, p := range op.Parameters {
+		switch p.In {
+		case "path":
+			pathArgs = append(pathArgs, p)
+		case "query":
+			queryParams = append(queryParams, p)
+		}
+	}
+	paramsType := op.OperationID + "Params"
+	if len(queryParams) > 0 {
+		if err := g.clientParams(w, paramsType, op.OperationID, queryParams); err != nil {
+			return err
+		}
+	}
+
+	// The arguments of the method, and the expressions of the path
+	// and query of its request.
+	var pathDecls []string
+	pathExpr := fmt.Sprintf("%q", template)
+	for _, p := range pathArgs {
+		typ, err := g.goType(p.Schema)
+		if err != nil {
+			return fmt.Errorf("parameter %s: %w", p.Name, err)
+		}
+		pathDecls = append(pathDecls, argName(p.Name)+" "+typ)
+		pathExpr = strings.Replace(pathExpr, "{"+p.Name+"}", `" + pathParam(`+argName(p.Name)+`) + "`, 1)
+	}
+	pathExpr = strings.TrimSuffix(pathExpr, ` + ""`)
+	args := append([]string{"ctx context.Context"}, pathDecls...)
+	queryExpr := "nil"
+	if len(queryParams) > 0 {
+		args = append(args, "params *"+paramsType)
+		queryExpr = "params.query()"
+	}
+	bodyExpr := "nil"
+	if op.RequestBody != nil {
+		mt, ok := op.RequestBody.Content["application/json"]
+		if !ok {
+			return fmt.Errorf("only JSON request bodies are supported")
+		}
+		typ, err := g.goType(mt.Schema)
+		if err != nil {
+			return fmt.Errorf("request body: %w", err)
+		}
+		args = append(args, "body *"+typ)
+		bodyExpr = "body"
+	}
+	httpMethod := "http.Method" + upperFirst(method)
+
+	code, resp := successResponse(op)
+	if code == "" {
+		return fmt.Errorf("operation has no successful response")
+	}
+	media := make([]string, 0, len(resp.Content))
+	for name := range resp.Content {
+		media = append(media, name)
+	}
+	sort.Strings(media)
+
+	fmt.Fprintf(w, "\n")
+	writeComment(w, "", op.OperationID+" "+lowerFirst(firstSentence(op.Summary)))
+	switch {
+	case len(media) == 0:
+		fmt.Fprintf(w, "func (c *Client) %s(%s) error {\n", op.OperationID, strings.Join(args, ", "))
+		fmt.Fprintf(w, "\treturn c.doJSON(ctx, %s, %s, %s, %s, nil)\n", httpMethod, pathExpr, queryExpr, bodyExpr)
+		fmt.Fprintf(w, "}\n")
+	case resp.Content["application/json"] != nil:
+		typ, err := g.goType(resp.Content["application/json"].Schema)
+		if err != nil {
+			return fmt.Errorf("response: %w", err)
+		}
+		fmt.Fprintf(w, "func (c *Client) %s(%s) (*%s, error) {\n", op.OperationID, strings.Join(args, ", "), typ)
+		fmt.Fprintf(w, "\tvar out %s\n", typ)
+		fmt.Fprintf(w, "\tif err := c.doJSON(ctx, %s, %s, %s, %s, &out); err != nil {\n", httpMethod, pathExpr, queryExpr, bodyExpr)
+		fmt.Fprintf(w, "\t\treturn nil, err\n\t}\n")
+		fmt.Fprintf(w, "\treturn &out, nil\n")
+		fmt.Fprintf(w, "}\n")
+		if op.hasParameter("limit") && op.hasParameter("offset") {
+			return g.clientPager(w, op, pathDecls, paramsType, resp.Content["application/json"].Schema, typ)
+		}
+	default:
+		// Other media types are returned as is, so that they can be
+		// read as they are streamed.
+		g.imports["io"] = true
+		accept := fmt.Sprintf("%q", media[0])
+		if len(media) > 1 {
+			args = append(args, "accept string")
+			accept = "accept"
+			fmt.Fprintf(w, "//\n")
+			writeComment(w, "", "The response is of the accept media type, which is one of "+strings.Join(media, ", ")+".")
+		}
+		fmt.Fprintf(w, "func (c *Client) %s(%s) (io.ReadCloser, error) {\n", op.OperationID, strings.Join(args, ", "))
+		fmt.Fprintf(w, "\treturn c.doStream(ctx, %s, %s, %s, %s)\n", httpMethod, pathExpr, queryExpr, accept)
+		fmt.Fprintf(w, "}\n")
+	}
+	return nil
+}
+
+// clientParams generates the type of the query parameters of an operation.
+// Parameters that are not required are pointers, so that they are only
+// sent if set.
+func (g *generator) clientParams(w *bytes.Buffer, name string, opName string, params []*Parameter) error {
+	g.imports["net/url"] = true
+
+	fmt.Fprintf(w, "\n// %s are the query parameters of %s.\n", name, opName)
+	fmt.Fprintf(w, "type %s struct {\n", name)
+	for i, p := range params {
+		typ, err := g.paramType(p)
+		if err != nil {
+			return fmt.Errorf("parameter %s: %w", p.Name, err)
+		}
+		if i > 0 && p.Description != "" {
+			fmt.Fprintf(w, "\n")
+		}
+		writeComment(w, "\t", p.Description)
+		fmt.Fprintf(w, "\t%s %s\n", fieldName(p.Name, p.Schema), typ)
+	}
+	fmt.Fprintf(w, "}\n")
+
+	fmt.Fprintf(w, "\nfunc (p *%s) query() url.Values {\n", name)
+	fmt.Fprintf(w, "\tq := url.Values{}\n")
+	fmt.Fprintf(w, "\tif p == nil {\n\t\treturn q\n\t}\n")
+	for _, p := range params {
+		fmt.Fprintf(w, "\taddParam(q, %q, p.%s)\n", p.Name, fieldName(p.Name, p.Schema))
+	}
+	fmt.Fprintf(w, "\treturn q\n")
+	fmt.Fprintf(w, "}\n")
+	return nil
+}
+
+// paramType returns the Go type of a query parameter.
+func (g *generator) paramType(p *Parameter) (string, error) {
+	if p.Style == "deepObject" {
+		return "Filter", nil
+	}
+	typ, err := g.goType(p.Schema)
+	if err != nil {
+		return "", err
+	}
+	if !p.Required && !isNillable(typ) {
+		typ = "*" + typ
+	}
+	return typ, nil
+}
+
+// clientPager generates a pager of the results of a paginated operation,
+// which are the items of the only array of its response. The arguments of
+// the path of the operation are fixed for all pages.
+func (g *generator) clientPager(w *bytes.Buffer, op *Operation, pathArgs []string, paramsType string, s *Schema, typ string) error {
+	var items string
+	for _, p := range s.Resolve().Properties {
+		if p.Schema.Resolve().Type != "array" {
+			continue
+		}
+		if items != "" {
+			return fmt.Errorf("paginated response has several arrays")
+		}
+		items = fieldName(p.Name, p.Schema)
+	}
+	if items == "" {
+		return fmt.Errorf("paginated response has no array")
+	}
+
+	argNames := make([]string, 0, len(pathArgs))
+	callArgs := []string{"ctx"}
+	for _, arg := range pathArgs {
+		field := strings.Fields(arg)[0]
+		argNames = append(argNames, field)
+		callArgs = append(callArgs, "p."+field)
+	}
+	callArgs = append(callArgs, "&params")
+
+	name := op.OperationID + "Pager"
+	fmt.Fprintf(w, "\n// %s pages through the results of %s.\n", name, op.OperationID)
+	fmt.Fprintf(w, "type %s struct {\n", name)
+	fmt.Fprintf(w, "\tpager\n\tc *Client\n")
+	for _, arg := range pathArgs {
+		fmt.Fprintf(w, "\t%s\n", arg)
+	}
+	fmt.Fprintf(w, "\tparams %s\n\tpage *%s\n", paramsType, typ)
+	fmt.Fprintf(w, "}\n")
+
+	fmt.Fprintf(w, "\n")
+	writeComment(w, "", name+" returns a pager of the results of "+op.OperationID+", starting at the offset and with pages of the limit of the parameters.")
+	fmt.Fprintf(w, "func (c *Client) %s(%s) *%s {\n", name, strings.Join(append(pathArgs, "params *"+paramsType), ", "), name)
+	fmt.Fprintf(w, "\tp := &%s{c: c", name)
+	for _, arg := range argNames {
+		fmt.Fprintf(w, ", %s: %s", arg, arg)
+	}
+	fmt.Fprintf(w, "}\n")
+	fmt.Fprintf(w, "\tif params != nil {\n\t\tp.params = *params\n\t}\n")
+	fmt.Fprintf(w, "\tp.pager = newPager(p.params.Offset, p.params.Limit)\n")
+	fmt.Fprintf(w, "\treturn p\n")
+	fmt.Fprintf(w, "}\n")
+
+	fmt.Fprintf(w, "\n// Next fetches the next page, which is then returned by Page. It returns\n")
+	fmt.Fprintf(w, "// false once there are no more results, or if fetching the page failed.\n")
+	fmt.Fprintf(w, "func (p *%s) Next(ctx context.Context) bool {\n", name)
+	fmt.Fprintf(w, "\tif p.done {\n\t\treturn false\n\t}\n")
+	fmt.Fprintf(w, "\tparams := p.params\n")
+	fmt.Fprintf(w, "\tparams.Offset, params.Limit = p.next()\n")
+	fmt.Fprintf(w, "\tpage, err := p.c.%s(%s)\n", op.OperationID, strings.Join(callArgs, ", "))
+	fmt.Fprintf(w, "\tif err != nil {\n\t\tp.fail(err)\n\t\treturn false\n\t}\n")
+	fmt.Fprintf(w, "\tif !p.advance(len(page.%s)) {\n\t\treturn false\n\t}\n", items)
+	fmt.Fprintf(w, "\tp.page = page\n")
+	fmt.Fprintf(w, "\treturn true\n")
+	fmt.Fprintf(w, "}\n")
+
+	fmt.Fprintf(w, "\n// Page returns the page fetched by the last call to Next.\n")
+	fmt.Fprintf(w, "func (p *%s) Page() *%s {\n", name, typ)
+	fmt.Fprintf(w, "\treturn p.page\n")
+	fmt.Fprintf(w, "}\n")
+	return nil
+}
+
+// hasParameter returns true if the operation has a parameter of the name.
+func (op *Operation) hasParameter(name string) bool {
+	for _, p := range op.Parameters {
+		if p.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// successResponse returns the lowest successful status code of an
+// operation and its response, or an empty code if it has none.
+func successResponse(op *Operation) (string, *Response) {
+	var codes []string
+	for code := range op.Responses {
+		if strings.HasPrefix(code, "2") {
+			codes = append(codes, code)
+		}
+	}
+	if len(codes) == 0 {
+		return "", nil
+	}
+	sort.Strings(codes)
+	code := codes[0]
+	return code, op.Responses[code].Resolve()
+}
+
+// argName returns the name of the Go argument of a parameter.
+func argName(name string) string {
+	words := strings.Split(name, "_")
+	first := fieldName(words[0], &Schema{})
+	if _, ok := initialisms[words[0]]; ok {
+		first = words[0]
+	} else {
+		first = lowerFirst(first)
+	}
+	return first + fieldName(strings.Join(words[1:], "_"), &Schema{})
+}
+
+// writeHeader writes the header of a generated file of the package, with
+// imports of the standard library grouped before others.
+func writeHeader(w *bytes.Buffer, pkg string, imports map[string]bool) {
+	fmt.Fprintf(w, "// Code generated by github.com/oasisprotocol/oasis-indexer/api/spec/gen. DO NOT EDIT.\n\n")
+	fmt.Fprintf(w, "package %s\n\n", pkg)
+
+	sorted := make([]string, 0, len(imports))
+	for imp := range imports {
+		sorted = append(sorted, imp)
+	}
+	sort.Strings(sorted)
+
+	// Imports of the module are named, since their paths end in versions.
+	var std, other []string
+	for _, imp := range sorted {
+		if strings.Contains(strings.Split(imp, "/")[0], ".") {
+			other = append(other, imp)
+		} else {
+			std = append(std, imp)
+		}
+	}
+	fmt.Fprintf(w, "import (\n")
+	for _, imp := range std {
+		fmt.Fprintf(w, "\t%q\n", imp)
+	}
+	if len(std) > 0 && len(other) > 0 {
+		fmt.Fprintf(w, "\n")
+	}
+	for _, imp := range other {
+		fmt.Fprintf(w, "\t%s %q\n", path.Base(imp), imp)
+	}
+	fmt.Fprintf(w, ")\n")
+}
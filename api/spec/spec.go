@@ -0,0 +1,328 @@
+// Package spec provides the OpenAPI specification of the Oasis Indexer API,
+// from which API types are generated and against which requests are validated.
+package spec
+
+import (
+	// Used to embed the specification.
+	_ "embed"
+	"fmt"
+	"strings"
+	"sync"
+
+	"gopkg.in/yaml.v2"
+)
+
+// v1 is the specification of the V1 API.
+//
+//go:embed v1.yaml
+var v1 []byte
+
+var (
+	v1Once sync.Once
+	v1Doc  *Document
+	v1Err  error
+)
+
+// V1 returns the specification of the V1 API.
+func V1() (*Document, error) {
+	v1Once.Do(func() {
+		v1Doc, v1Err = Parse(v1)
+	})
+	return v1Doc, v1Err
+}
+
+// Methods are the HTTP methods of operations, in the order
+// they are listed.
+var Methods = []string{"get", "post", "put", "patch", "delete"}
+
+// Document is an OpenAPI document. Only the parts of the specification
+// used by the indexer are supported.
+type Document struct {
+	Paths      Paths      `yaml:"paths"`
+	Components Components `yaml:"components"`
+}
+
+// Components are the reusable objects of a document.
+type Components struct {
+	Schemas   Schemas              `yaml:"schemas"`
+	Responses map[string]*Response `yaml:"responses"`
+}
+
+// Paths are the paths of a document, in the order they are specified.
+type Paths []*PathItem
+
+// PathItem is a path of a document and its operations.
+type PathItem struct {
+	Path       string
+	Operations map[string]*Operation
+}
+
+// UnmarshalYAML implements yaml.Unmarshaler.
+func (p *Paths) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	return unmarshalOrdered(unmarshal, func(key string, value interface{}) error {
+		item := PathItem{Path: key}
+		if err := remarshal(value, &item.Operations); err != nil {
+			return fmt.Errorf("path %s: %w", key, err)
+		}
+		*p = append(*p, &item)
+		return nil
+	})
+}
+
+// Operation is an operation on a path.
+type Operation struct {
+	OperationID string               `yaml:"operationId"`
+	Summary     string               `yaml:"summary"`
+	Parameters  []*Parameter         `yaml:"parameters"`
+	RequestBody *RequestBody         `yaml:"requestBody"`
+	Responses   map[string]*Response `yaml:"responses"`
+}
+
+// Parameter is a parameter of an operation.
+type Parameter struct {
+	Name        string      `yaml:"name"`
+	In          string      `yaml:"in"`
+	Required    bool        `yaml:"required"`
+	Style       string      `yaml:"style"`
+	Explode     *bool       `yaml:"explode"`
+	Schema      *Schema     `yaml:"schema"`
+	Description string      `yaml:"description"`
+	Example     interface{} `yaml:"example"`
+}
+
+// RequestBody is the request body of an operation.
+type RequestBody struct {
+	Required bool                  `yaml:"required"`
+	Content  map[string]*MediaType `yaml:"content"`
+}
+
+// Response is a response of an operation.
+type Response struct {
+	Ref         string                `yaml:"$ref"`
+	Description string                `yaml:"description"`
+	Content     map[string]*MediaType `yaml:"content"`
+
+	resolved *Response
+}
+
+// MediaType is the content of a request or response in a media type.
+type MediaType struct {
+	Schema *Schema `yaml:"schema"`
+}
+
+// Schema is a JSON schema.
+type Schema struct {
+	Ref         string        `yaml:"$ref"`
+	Type        string        `yaml:"type"`
+	Format      string        `yaml:"format"`
+	Description string        `yaml:"description"`
+	Properties  Schemas       `yaml:"properties"`
+	Required    []string      `yaml:"required"`
+	Items       *Schema       `yaml:"items"`
+	OneOf       []*Schema     `yaml:"oneOf"`
+	Enum        []interface{} `yaml:"enum"`
+	Nullable    bool          `yaml:"nullable"`
+	MaxLength   *int          `yaml:"maxLength"`
+	Example     interface{}   `yaml:"example"`
+
+	// GoName is the name of the Go type of schemas, or the Go field
+	// of properties, if it is not derived from their name.
+	GoName string `yaml:"x-go-name"`
+	// GoType is the Go type of the schema, if it is not generated.
+	GoType string `yaml:"x-go-type"`
+	// GoSkipOptionalPointer is true if the Go field of an optional
+	// property is not a pointer, in which case it is omitted if it
+	// has the zero value.
+	GoSkipOptionalPointer bool `yaml:"x-go-type-skip-optional-pointer"`
+
+	// resolved is the schema referenced by Ref.
+	resolved *Schema
+}
+
+// Schemas are named schemas, in the order they are specified.
+type Schemas []*NamedSchema
+
+// NamedSchema is a schema and its name.
+type NamedSchema struct {
+	Name   string
+	Schema *Schema
+}
+
+// UnmarshalYAML implements yaml.Unmarshaler.
+func (s *Schemas) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	return unmarshalOrdered(unmarshal, func(key string, value interface{}) error {
+		var schema Schema
+		if err := remarshal(value, &schema); err != nil {
+			return fmt.Errorf("schema %s: %w", key, err)
+		}
+		*s = append(*s, &NamedSchema{key, &schema})
+		return nil
+	})
+}
+
+// Get returns the schema with the name, or nil if there is none.
+func (s Schemas) Get(name string) *Schema {
+	for _, ns := range s {
+		if ns.Name == name {
+			return ns.Schema
+		}
+	}
+	return nil
+}
+
+// Resolve returns the schema referenced by the schema, if any.
+func (s *Schema) Resolve() *Schema {
+	if s.resolved != nil {
+		return s.resolved
+	}
+	return s
+}
+
+// IsRequired returns true if the property with the name is required.
+func (s *Schema) IsRequired(name string) bool {
+	for _, r := range s.Required {
+		if r == name {
+			return true
+		}
+	}
+	return false
+}
+
+// Resolve returns the response referenced by the response, if any.
+func (r *Response) Resolve() *Response {
+	if r.resolved != nil {
+		return r.resolved
+	}
+	return r
+}
+
+// Parse parses an OpenAPI document, and resolves its references.
+func Parse(b []byte) (*Document, error) {
+	var doc Document
+	if err := yaml.Unmarshal(b, &doc); err != nil {
+		return nil, err
+	}
+	if err := doc.resolve(); err != nil {
+		return nil, err
+	}
+	return &doc, nil
+}
+
+const (
+	schemaRefPrefix   = "#/components/schemas/"
+	responseRefPrefix = "#/components/responses/"
+)
+
+// resolve resolves the references of the document.
+func (d *Document) resolve() error {
+	for _, ns := range d.Components.Schemas {
+		if err := d.resolveSchema(ns.Schema); err != nil {
+			return fmt.Errorf("schema %s: %w", ns.Name, err)
+		}
+	}
+	for name, r := range d.Components.Responses {
+		if err := d.resolveResponse(r); err != nil {
+			return fmt.Errorf("response %s: %w", name, err)
+		}
+	}
+	for _, item := range d.Paths {
+		for method, op := range item.Operations {
+			if err := d.resolveOperation(op); err != nil {
+				return fmt.Errorf("%s %s: %w", strings.ToUpper(method), item.Path, err)
+			}
+		}
+	}
+	return nil
+}
+
+func (d *Document) resolveOperation(op *Operation) error {
+	for _, p := range op.Parameters {
+		if p.Schema == nil {
+			return fmt.Errorf("parameter %s has no schema", p.Name)
+		}
+		if err := d.resolveSchema(p.Schema); err != nil {
+			return fmt.Errorf("parameter %s: %w", p.Name, err)
+		}
+	}
+	if op.RequestBody != nil {
+		for _, mt := range op.RequestBody.Content {
+			if err := d.resolveSchema(mt.Schema); err != nil {
+				return fmt.Errorf("request body: %w", err)
+			}
+		}
+	}
+	for code, r := range op.Responses {
+		if err := d.resolveResponse(r); err != nil {
+			return fmt.Errorf("response %s: %w", code, err)
+		}
+	}
+	return nil
+}
+
+func (d *Document) resolveResponse(r *Response) error {
+	if r.Ref != "" {
+		r.resolved = d.Components.Responses[strings.TrimPrefix(r.Ref, responseRefPrefix)]
+		if !strings.HasPrefix(r.Ref, responseRefPrefix) || r.resolved == nil {
+			return fmt.Errorf("unresolved reference %s", r.Ref)
+		}
+		return nil
+	}
+	for _, mt := range r.Content {
+		if err := d.resolveSchema(mt.Schema); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (d *Document) resolveSchema(s *Schema) error {
+	if s == nil {
+		return nil
+	}
+	if s.Ref != "" {
+		s.resolved = d.Components.Schemas.Get(strings.TrimPrefix(s.Ref, schemaRefPrefix))
+		if !strings.HasPrefix(s.Ref, schemaRefPrefix) || s.resolved == nil {
+			return fmt.Errorf("unresolved reference %s", s.Ref)
+		}
+		return nil
+	}
+	for _, p := range s.Properties {
+		if err := d.resolveSchema(p.Schema); err != nil {
+			return fmt.Errorf("property %s: %w", p.Name, err)
+		}
+	}
+	for _, o := range s.OneOf {
+		if err := d.resolveSchema(o); err != nil {
+			return err
+		}
+	}
+	return d.resolveSchema(s.Items)
+}
+
+// unmarshalOrdered unmarshals a YAML mapping, passing its items to f
+// in the order they are specified.
+func unmarshalOrdered(unmarshal func(interface{}) error, f func(key string, value interface{}) error) error {
+	var m yaml.MapSlice
+	if err := unmarshal(&m); err != nil {
+		return err
+	}
+	for _, item := range m {
+		key, ok := item.Key.(string)
+		if !ok {
+			return fmt.Errorf("unexpected key %v", item.Key)
+		}
+		if err := f(key, item.Value); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// remarshal unmarshals a generic YAML value into out.
+func remarshal(value interface{}, out interface{}) error {
+	b, err := yaml.Marshal(value)
+	if err != nil {
+		return err
+	}
+	return yaml.Unmarshal(b, out)
+}
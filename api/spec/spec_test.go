@@ -0,0 +1,199 @@
+package spec
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestV1(t *testing.T) {
+	doc, err := V1()
+	require.Nil(t, err)
+
+	ids := map[string]bool{}
+	for _, item := range doc.Paths {
+		for method, op := range item.Operations {
+			require.NotEmpty(t, op.OperationID, "%s %s", method, item.Path)
+			require.False(t, ids[op.OperationID], "duplicate operation %s", op.OperationID)
+			ids[op.OperationID] = true
+		}
+	}
+}
+
+func decode(t *testing.T, s string) interface{} {
+	dec := json.NewDecoder(strings.NewReader(s))
+	dec.UseNumber()
+	var v interface{}
+	require.Nil(t, dec.Decode(&v))
+	return v
+}
+
+func TestValidate(t *testing.T) {
+	doc, err := Parse([]byte(`
+components:
+  schemas:
+    List:
+      type: object
+      required: [items]
+      properties:
+        items:
+          type: array
+          items:
+            $ref: '#/components/schemas/Item'
+    Item:
+      type: object
+      required: [height, kind]
+      properties:
+        height:
+          type: integer
+          format: int64
+        amount:
+          type: integer
+          format: uint64
+        kind:
+          type: string
+          enum: [a, b]
+        time:
+          type: string
+          format: date-time
+        name:
+          type: string
+          nullable: true
+          maxLength: 4
+        body:
+          type: object
+`))
+	require.Nil(t, err)
+	list := doc.Components.Schemas.Get("List")
+
+	for _, tc := range []struct {
+		value  string
+		strict bool
+		err    string
+	}{
+		{value: `{"items": []}`},
+		{value: `{"items": [{"height": 1, "kind": "a", "name": null, "body": {"x": 1}}]}`},
+		{value: `{"items": [{"height": 1, "kind": "b", "time": "2022-04-11T09:30:00Z", "extra": 1}]}`},
+		{value: `{"items": [{"height": 1, "kind": "b", "extra": 1}]}`, strict: true, err: "items[0].extra: is not specified"},
+		{value: `{}`, err: "items: is required"},
+		{value: `{"items": null}`, err: "items: must not be null"},
+		{value: `{"items": [{"kind": "a"}]}`, err: "items[0].height: is required"},
+		{value: `{"items": [{"height": "1", "kind": "a"}]}`, err: "items[0].height: must be an integer"},
+		{value: `{"items": [{"height": 1.5, "kind": "a"}]}`, err: "items[0].height: must be an integer"},
+		{value: `{"items": [{"height": 9223372036854775808, "kind": "a"}]}`, err: "items[0].height: must be within the range of int64"},
+		{value: `{"items": [{"height": 1, "amount": -1, "kind": "a"}]}`, err: "items[0].amount: must be within the range of uint64"},
+		{value: `{"items": [{"height": 1, "kind": "c"}]}`, err: "items[0].kind: must be one of [a b]"},
+		{value: `{"items": [{"height": 1, "kind": "a", "time": "yesterday"}]}`, err: "items[0].time: must be an RFC 3339 formatted time"},
+		{value: `{"items": [{"height": 1, "kind": "a", "name": "abcde"}]}`, err: "items[0].name: must be at most 4 characters long"},
+	} {
+		validate := list.Validate
+		if tc.strict {
+			validate = list.ValidateStrict
+		}
+		err := validate(decode(t, tc.value))
+		if tc.err == "" {
+			require.Nil(t, err, tc.value)
+		} else {
+			require.EqualError(t, err, tc.err, tc.value)
+		}
+	}
+}
+
+func TestStringFormats(t *testing.T) {
+	DefineStringFormat("test-even", "of even length", func(s string) error {
+		if len(s)%2 != 0 {
+			return fmt.Errorf("odd length")
+		}
+		return nil
+	})
+	doc, err := Parse([]byte(`
+components:
+  schemas:
+    Pair:
+      type: string
+      format: test-even
+`))
+	require.Nil(t, err)
+	pair := doc.Components.Schemas.Get("Pair")
+
+	require.Nil(t, pair.Validate("ab"))
+	require.EqualError(t, pair.Validate("abc"), "must be of even length")
+}
+
+func TestFindRoute(t *testing.T) {
+	doc, err := V1()
+	require.Nil(t, err)
+
+	route := doc.FindRoute(http.MethodGet, "/consensus/entities/abc/nodes")
+	require.NotNil(t, route)
+	require.Equal(t, "ListEntityNodes", route.Operation.OperationID)
+	require.Equal(t, map[string]string{"entity_id": "abc"}, route.PathParams)
+
+	route = doc.FindRoute(http.MethodGet, "/consensus/entities/a%2Fb%3D/nodes")
+	require.NotNil(t, route)
+	require.Equal(t, map[string]string{"entity_id": "a/b="}, route.PathParams)
+
+	route = doc.FindRoute(http.MethodGet, "/consensus/blocks/")
+	require.NotNil(t, route)
+	require.Equal(t, "ListBlocks", route.Operation.OperationID)
+
+	route = doc.FindRoute(http.MethodGet, "/")
+	require.NotNil(t, route)
+	require.Equal(t, "GetStatus", route.Operation.OperationID)
+
+	require.Nil(t, doc.FindRoute(http.MethodGet, "/consensus/unknown"))
+	require.Nil(t, doc.FindRoute(http.MethodPut, "/consensus/blocks"))
+}
+
+func TestValidateRequest(t *testing.T) {
+	doc, err := V1()
+	require.Nil(t, err)
+
+	for _, tc := range []struct {
+		method string
+		target string
+		body   string
+		err    string
+	}{
+		{method: http.MethodGet, target: "/consensus/blocks?from=1&after=2022-04-11T09:30:00Z&unknown=x"},
+		{method: http.MethodGet, target: "/consensus/blocks?from=&to="},
+		{method: http.MethodGet, target: "/consensus/blocks?from=one", err: "from: must be an integer"},
+		{method: http.MethodGet, target: "/consensus/blocks?before=yesterday", err: "before: must be an RFC 3339 formatted time"},
+		{method: http.MethodGet, target: "/consensus/blocks/one", err: "height: must be an integer"},
+		{method: http.MethodGet, target: "/search", err: "q: is required"},
+		{method: http.MethodGet, target: "/search?q=" + strings.Repeat("a", 129), err: "q: must be at most 128 characters long"},
+		{method: http.MethodGet, target: "/consensus/transactions?method=staking.Transfer"},
+		{method: http.MethodGet, target: "/stream?topic=consensus_votes", err: "topic: must be one of [consensus_blocks consensus_transactions consensus_events emerald_rounds]"},
+		{method: http.MethodGet, target: "/consensus/accounts?order_by=-escrow,address&available[gte]=100000000000000000000"},
+		{method: http.MethodGet, target: "/consensus/accounts?order_by=-escrow,height", err: "order_by[1]: must be one of [address -address nonce -nonce available -available escrow -escrow debonding -debonding total_balance -total_balance]"},
+		{method: http.MethodGet, target: "/consensus/accounts?available[gte]=lots", err: "available[gte]: must be an integer"},
+		{method: http.MethodGet, target: "/consensus/accounts?available[like]=1", err: "available[like]: is not supported"},
+		{method: http.MethodGet, target: "/consensus/validators?active[eq]=yes", err: "active[eq]: must be a boolean"},
+		{method: http.MethodPost, target: "/consensus/webhooks", body: `{"kind": "transfer", "url": "https://example.com"}`},
+		{method: http.MethodPost, target: "/consensus/webhooks", err: "body: is required"},
+		{method: http.MethodPost, target: "/consensus/webhooks", body: `{"kind": "transfer"`, err: "body: must be JSON"},
+		{method: http.MethodPost, target: "/consensus/webhooks", body: `{"kind": "burn", "url": "https://example.com"}`, err: "body.kind: must be one of [transfer proposal_submitted node_expired node_frozen node_unfrozen]"},
+	} {
+		r := httptest.NewRequest(tc.method, tc.target, strings.NewReader(tc.body))
+		route := doc.FindRoute(r.Method, r.URL.Path)
+		require.NotNil(t, route, tc.target)
+
+		err := route.ValidateRequest(r)
+		if tc.err != "" {
+			require.EqualError(t, err, tc.err, tc.target)
+			continue
+		}
+		require.Nil(t, err, tc.target)
+
+		// The body can be read again by handlers.
+		body, err := io.ReadAll(r.Body)
+		require.Nil(t, err)
+		require.Equal(t, tc.body, string(body))
+	}
+}
@@ -0,0 +1,429 @@
+package spec
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"math/big"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// ValidationError is an error of a value that does not match its schema.
+type ValidationError struct {
+	// Path is the location of the value, which is the parameter it was
+	// read from followed by the properties and items it is nested in.
+	Path string
+	Msg  string
+}
+
+func (e *ValidationError) Error() string {
+	if e.Path == "" {
+		return e.Msg
+	}
+	return fmt.Sprintf("%s: %s", e.Path, e.Msg)
+}
+
+func errorf(path string, format string, args ...interface{}) error {
+	return &ValidationError{Path: path, Msg: fmt.Sprintf(format, args...)}
+}
+
+// Bounds of integer formats.
+var (
+	minInt64  = big.NewInt(math.MinInt64)
+	maxInt64  = big.NewInt(math.MaxInt64)
+	maxUint64 = new(big.Int).SetUint64(math.MaxUint64)
+	minInt32  = big.NewInt(math.MinInt32)
+	maxInt32  = big.NewInt(math.MaxInt32)
+)
+
+// Validate validates a JSON value against the schema. Values are as
+// decoded by encoding/json into an interface{}, with numbers decoded
+// as json.Number.
+func (s *Schema) Validate(v interface{}) error {
+	return s.validate(v, "", false)
+}
+
+// ValidateStrict validates a JSON value against the schema like Validate,
+// but additionally rejects properties that objects are not specified to
+// have. Objects without any specified properties may have any.
+func (s *Schema) ValidateStrict(v interface{}) error {
+	return s.validate(v, "", true)
+}
+
+func (s *Schema) validate(v interface{}, path string, strict bool) error {
+	s = s.Resolve()
+	if v == nil {
+		if s.Nullable {
+			return nil
+		}
+		return errorf(path, "must not be null")
+	}
+
+	if len(s.OneOf) > 0 {
+		var n int
+		for _, o := range s.OneOf {
+			if o.validate(v, path, strict) == nil {
+				n++
+			}
+		}
+		if n != 1 {
+			return errorf(path, "must match exactly one schema, but matches %d", n)
+		}
+		return nil
+	}
+
+	var err error
+	switch s.Type {
+	case "object":
+		err = s.validateObject(v, path, strict)
+	case "array":
+		items, ok := v.([]interface{})
+		if !ok {
+			return errorf(path, "must be an array")
+		}
+		for i, item := range items {
+			if err = s.Items.validate(item, fmt.Sprintf("%s[%d]", path, i), strict); err != nil {
+				return err
+			}
+		}
+	case "string":
+		err = s.validateString(v, path)
+	case "integer":
+		err = s.validateInteger(v, path)
+	case "number":
+		n, ok := v.(json.Number)
+		if !ok {
+			return errorf(path, "must be a number")
+		}
+		if _, err = strconv.ParseFloat(string(n), 64); err != nil {
+			return errorf(path, "must be a number")
+		}
+	case "boolean":
+		if _, ok := v.(bool); !ok {
+			return errorf(path, "must be a boolean")
+		}
+	}
+	if err != nil {
+		return err
+	}
+
+	if len(s.Enum) > 0 {
+		for _, e := range s.Enum {
+			if fmt.Sprint(e) == fmt.Sprint(v) {
+				return nil
+			}
+		}
+		return errorf(path, "must be one of %v", s.Enum)
+	}
+	return nil
+}
+
+func (s *Schema) validateObject(v interface{}, path string, strict bool) error {
+	obj, ok := v.(map[string]interface{})
+	if !ok {
+		return errorf(path, "must be an object")
+	}
+	for _, name := range s.Required {
+		if _, ok := obj[name]; !ok {
+			return errorf(joinPath(path, name), "is required")
+		}
+	}
+
+	// Properties are validated in order, so that errors are deterministic.
+	names := make([]string, 0, len(obj))
+	for name := range obj {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		p := s.Properties.Get(name)
+		if p == nil {
+			if strict && len(s.Properties) > 0 {
+				return errorf(joinPath(path, name), "is not specified")
+			}
+			continue
+		}
+		if err := p.validate(obj[name], joinPath(path, name), strict); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *Schema) validateString(v interface{}, path string) error {
+	str, ok := v.(string)
+	if !ok {
+		return errorf(path, "must be a string")
+	}
+	if s.MaxLength != nil && utf8.RuneCountInString(str) > *s.MaxLength {
+		return errorf(path, "must be at most %d characters long", *s.MaxLength)
+	}
+	switch s.Format {
+	case "date-time":
+		if _, err := time.Parse(time.RFC3339Nano, str); err != nil {
+			return errorf(path, "must be an RFC 3339 formatted time")
+		}
+	case "byte":
+		if _, err := base64.StdEncoding.DecodeString(str); err != nil {
+			return errorf(path, "must be base64 encoded")
+		}
+	default:
+		if f, ok := stringFormats[s.Format]; ok && f.validate(str) != nil {
+			return errorf(path, "must be %s", f.description)
+		}
+	}
+	return nil
+}
+
+// stringFormat is a custom format of strings.
+type stringFormat struct {
+	description string
+	validate    func(string) error
+}
+
+// stringFormats are the custom formats of strings, by name.
+var stringFormats = map[string]stringFormat{}
+
+// DefineStringFormat defines a custom format of strings, against which
+// strings of the format are validated. Values that are not of the format
+// are described as not being of the description, e.g. "a staking address".
+//
+// Formats must be defined before documents are used, e.g. in init.
+func DefineStringFormat(name string, description string, validate func(string) error) {
+	stringFormats[name] = stringFormat{description, validate}
+}
+
+func (s *Schema) validateInteger(v interface{}, path string) error {
+	n, ok := v.(json.Number)
+	if !ok {
+		return errorf(path, "must be an integer")
+	}
+	i, ok := new(big.Int).SetString(string(n), 10)
+	if !ok {
+		return errorf(path, "must be an integer")
+	}
+	var min, max *big.Int
+	switch s.Format {
+	case "int64":
+		min, max = minInt64, maxInt64
+	case "uint64":
+		min, max = new(big.Int), maxUint64
+	case "int32":
+		min, max = minInt32, maxInt32
+	}
+	if min != nil && (i.Cmp(min) < 0 || i.Cmp(max) > 0) {
+		return errorf(path, "must be within the range of %s", s.Format)
+	}
+	return nil
+}
+
+func joinPath(path string, name string) string {
+	if path == "" {
+		return name
+	}
+	return path + "." + name
+}
+
+// Route is an operation of a document, and the values of the path
+// parameters of a request for it.
+type Route struct {
+	// Path is the path template of the operation.
+	Path       string
+	Method     string
+	Operation  *Operation
+	PathParams map[string]string
+}
+
+// FindRoute returns the route of a request with the method and path,
+// which is relative to the server URL, or nil if the document specifies
+// no such operation. Literal path segments take precedence over path
+// parameters, whose values are unescaped.
+func (d *Document) FindRoute(method string, path string) *Route {
+	segments := splitPath(path)
+
+	var best *Route
+	bestLiterals := -1
+	for _, item := range d.Paths {
+		op, ok := item.Operations[strings.ToLower(method)]
+		if !ok {
+			continue
+		}
+		template := splitPath(item.Path)
+		if len(template) != len(segments) {
+			continue
+		}
+
+		params := map[string]string{}
+		literals := 0
+		for i, t := range template {
+			switch {
+			case strings.HasPrefix(t, "{") && strings.HasSuffix(t, "}"):
+				value, err := url.PathUnescape(segments[i])
+				if err != nil || value == "" {
+					literals = -1
+				}
+				params[t[1:len(t)-1]] = value
+			case t == segments[i]:
+				literals++
+			default:
+				literals = -1
+			}
+			if literals < 0 {
+				break
+			}
+		}
+		if literals > bestLiterals {
+			best = &Route{Path: item.Path, Method: method, Operation: op, PathParams: params}
+			bestLiterals = literals
+		}
+	}
+	return best
+}
+
+func splitPath(path string) []string {
+	path = strings.Trim(path, "/")
+	if path == "" {
+		return nil
+	}
+	return strings.Split(path, "/")
+}
+
+// ValidateRequest validates the parameters and the JSON body of a request
+// against the operation of the route. Query parameters that are not
+// specified are ignored. The body is restored so that it can be read
+// again.
+func (rt *Route) ValidateRequest(r *http.Request) error {
+	query := r.URL.Query()
+	for _, p := range rt.Operation.Parameters {
+		var err error
+		switch p.In {
+		case "path":
+			err = p.validateValues([]string{rt.PathParams[p.Name]})
+		case "query":
+			if p.Style == "deepObject" {
+				err = p.validateDeepObject(query)
+			} else {
+				err = p.validateValues(query[p.Name])
+			}
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	if rt.Operation.RequestBody == nil {
+		return nil
+	}
+	mt, ok := rt.Operation.RequestBody.Content["application/json"]
+	if !ok {
+		return nil
+	}
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		return err
+	}
+	r.Body = io.NopCloser(bytes.NewReader(body))
+	if len(bytes.TrimSpace(body)) == 0 {
+		if rt.Operation.RequestBody.Required {
+			return errorf("body", "is required")
+		}
+		return nil
+	}
+	dec := json.NewDecoder(bytes.NewReader(body))
+	dec.UseNumber()
+	var v interface{}
+	if err := dec.Decode(&v); err != nil {
+		return errorf("body", "must be JSON")
+	}
+	return mt.Schema.validate(v, "body", false)
+}
+
+// validateValues validates the values of a parameter. Empty values
+// are treated as missing.
+func (p *Parameter) validateValues(values []string) error {
+	var n int
+	for _, raw := range values {
+		if raw == "" {
+			continue
+		}
+		n++
+		if err := p.Schema.validate(p.value(raw), p.Name, false); err != nil {
+			return err
+		}
+	}
+	if n == 0 && p.Required {
+		return errorf(p.Name, "is required")
+	}
+	return nil
+}
+
+// validateDeepObject validates the values of an object parameter, which
+// are query parameters of the form name[property]=value.
+func (p *Parameter) validateDeepObject(query map[string][]string) error {
+	schema := p.Schema.Resolve()
+	var keys []string
+	for key := range query {
+		if strings.HasPrefix(key, p.Name+"[") && strings.HasSuffix(key, "]") {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		prop := schema.Properties.Get(key[len(p.Name)+1 : len(key)-1])
+		if prop == nil {
+			return errorf(key, "is not supported")
+		}
+		for _, raw := range query[key] {
+			if err := prop.validate(paramValue(prop, raw), key, false); err != nil {
+				return err
+			}
+		}
+	}
+	if len(keys) == 0 && p.Required {
+		return errorf(p.Name, "is required")
+	}
+	return nil
+}
+
+// value converts a raw value of the parameter to the JSON value its
+// schema describes. Arrays are comma-separated, unless exploded.
+func (p *Parameter) value(raw string) interface{} {
+	schema := p.Schema.Resolve()
+	if schema.Type != "array" {
+		return paramValue(schema, raw)
+	}
+	parts := []string{raw}
+	if p.Explode != nil && !*p.Explode {
+		parts = strings.Split(raw, ",")
+	}
+	items := make([]interface{}, len(parts))
+	for i, part := range parts {
+		items[i] = paramValue(schema.Items, part)
+	}
+	return items
+}
+
+// paramValue converts a raw parameter value to the JSON value of its
+// schema. Values that are not of the type of the schema are returned
+// as strings, so that they fail validation.
+func paramValue(s *Schema, raw string) interface{} {
+	switch s.Resolve().Type {
+	case "integer", "number":
+		return json.Number(raw)
+	case "boolean":
+		if b, err := strconv.ParseBool(raw); err == nil {
+			return b
+		}
+	}
+	return raw
+}
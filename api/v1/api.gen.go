@@ -0,0 +1,1010 @@
+// Code generated by github.com/oasisprotocol/oasis-indexer/api/spec/gen. DO NOT EDIT.
+
+package v1
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+)
+
+// ServerInterface is implemented by servers of the API, which serve
+// each operation of the specification with a method.
+type ServerInterface interface {
+	// GetStatus returns the indexer status.
+	//
+	// GET /
+	GetStatus(w http.ResponseWriter, r *http.Request)
+
+	// Search searches for blocks, transactions, entities, nodes, accounts, Emerald
+	// rounds and validators.
+	//
+	// GET /search
+	Search(w http.ResponseWriter, r *http.Request)
+
+	// ListBlocks returns a list of consensus blocks.
+	//
+	// GET /consensus/blocks
+	ListBlocks(w http.ResponseWriter, r *http.Request)
+
+	// GetBlock returns a consensus block.
+	//
+	// GET /consensus/blocks/{height}
+	GetBlock(w http.ResponseWriter, r *http.Request)
+
+	// ListTransactions returns a list of consensus transactions.
+	//
+	// GET /consensus/transactions
+	ListTransactions(w http.ResponseWriter, r *http.Request)
+
+	// GetTransaction returns a consensus transaction.
+	//
+	// GET /consensus/transactions/{tx_hash}
+	GetTransaction(w http.ResponseWriter, r *http.Request)
+
+	// ListEntities returns a list of entities registered at the consensus layer.
+	//
+	// GET /consensus/entities
+	ListEntities(w http.ResponseWriter, r *http.Request)
+
+	// GetEntity returns an entity registered at the consensus layer.
+	//
+	// GET /consensus/entities/{entity_id}
+	GetEntity(w http.ResponseWriter, r *http.Request)
+
+	// ListEntityNodes returns a list of nodes registered at the consensus layer.
+	//
+	// GET /consensus/entities/{entity_id}/nodes
+	ListEntityNodes(w http.ResponseWriter, r *http.Request)
+
+	// GetEntityNode returns a node registered at the consensus layer.
+	//
+	// GET /consensus/entities/{entity_id}/nodes/{node_id}
+	GetEntityNode(w http.ResponseWriter, r *http.Request)
+
+	// GetEntityNodeHistory returns the registrations, expiries, freezes and
+	// unfreezes of a node, from the most recent.
+	//
+	// GET /consensus/entities/{entity_id}/nodes/{node_id}/history
+	GetEntityNodeHistory(w http.ResponseWriter, r *http.Request)
+
+	// ListRuntimes returns a list of runtimes registered at the consensus layer.
+	//
+	// GET /consensus/runtimes
+	ListRuntimes(w http.ResponseWriter, r *http.Request)
+
+	// GetRuntime returns a runtime registered at the consensus layer, along with
+	// its current committees and its history.
+	//
+	// GET /consensus/runtimes/{runtime_id}
+	GetRuntime(w http.ResponseWriter, r *http.Request)
+
+	// GetRuntimeHistory returns the registrations, updates, suspensions and
+	// unsuspensions of a runtime, from the most recent.
+	//
+	// GET /consensus/runtimes/{runtime_id}/history
+	GetRuntimeHistory(w http.ResponseWriter, r *http.Request)
+
+	// ListValidators returns a list of validators registered at the consensus
+	// layer.
+	//
+	// GET /consensus/validators
+	ListValidators(w http.ResponseWriter, r *http.Request)
+
+	// GetValidator returns a validator registered at the consensus layer.
+	//
+	// GET /consensus/validators/{entity_id}
+	GetValidator(w http.ResponseWriter, r *http.Request)
+
+	// GetValidatorDelegators returns the accounts delegating to a validator, along
+	// with the total of its delegations.
+	//
+	// GET /consensus/validators/{entity_id}/delegators
+	GetValidatorDelegators(w http.ResponseWriter, r *http.Request)
+
+	// GetValidatorDebondingDelegators returns the debonding delegations to a
+	// validator, along with the total of its debonding delegations.
+	//
+	// GET /consensus/validators/{entity_id}/debonding_delegators
+	GetValidatorDebondingDelegators(w http.ResponseWriter, r *http.Request)
+
+	// ListAccounts returns a list of consensus layer accounts.
+	//
+	// GET /consensus/accounts
+	ListAccounts(w http.ResponseWriter, r *http.Request)
+
+	// GetAccount returns a consensus layer account.
+	//
+	// GET /consensus/accounts/{address}
+	GetAccount(w http.ResponseWriter, r *http.Request)
+
+	// GetDelegations returns an account's delegations.
+	//
+	// GET /consensus/accounts/{address}/delegations
+	GetDelegations(w http.ResponseWriter, r *http.Request)
+
+	// GetDebondingDelegations returns an account's debonding delegations.
+	//
+	// GET /consensus/accounts/{address}/debonding_delegations
+	GetDebondingDelegations(w http.ResponseWriter, r *http.Request)
+
+	// ListEpochs returns a list of consensus epochs.
+	//
+	// GET /consensus/epochs
+	ListEpochs(w http.ResponseWriter, r *http.Request)
+
+	// GetEpoch returns a consensus epoch.
+	//
+	// GET /consensus/epochs/{epoch}
+	GetEpoch(w http.ResponseWriter, r *http.Request)
+
+	// GetEpochCommittees returns the members of the runtime committees of an
+	// epoch.
+	//
+	// GET /consensus/epochs/{epoch}/committees
+	GetEpochCommittees(w http.ResponseWriter, r *http.Request)
+
+	// GetEpochValidators returns the consensus validators of an epoch, by
+	// descending voting power.
+	//
+	// GET /consensus/epochs/{epoch}/validators
+	GetEpochValidators(w http.ResponseWriter, r *http.Request)
+
+	// ListProposals returns a list of governance proposals.
+	//
+	// GET /consensus/proposals
+	ListProposals(w http.ResponseWriter, r *http.Request)
+
+	// GetProposal returns a governance proposal.
+	//
+	// GET /consensus/proposals/{proposal_id}
+	GetProposal(w http.ResponseWriter, r *http.Request)
+
+	// GetProposalVotes returns a list of votes for a governance proposal.
+	//
+	// GET /consensus/proposals/{proposal_id}/votes
+	GetProposalVotes(w http.ResponseWriter, r *http.Request)
+
+	// ListTransactionsPerSecond returns the consensus layer TPS for each 5 minute
+	// interval.
+	//
+	// GET /consensus/stats/tps
+	ListTransactionsPerSecond(w http.ResponseWriter, r *http.Request)
+
+	// ListDailyVolume returns the consensus layer daily transaction volume for
+	// each day.
+	//
+	// GET /consensus/stats/daily_volume
+	ListDailyVolume(w http.ResponseWriter, r *http.Request)
+
+	// ListWebhookSubscriptions returns a list of the webhook subscriptions of the
+	// API key of the request.
+	//
+	// GET /consensus/webhooks
+	ListWebhookSubscriptions(w http.ResponseWriter, r *http.Request)
+
+	// CreateWebhookSubscription creates a webhook subscription.
+	//
+	// POST /consensus/webhooks
+	CreateWebhookSubscription(w http.ResponseWriter, r *http.Request)
+
+	// GetWebhookSubscription returns a webhook subscription.
+	//
+	// GET /consensus/webhooks/{subscription_id}
+	GetWebhookSubscription(w http.ResponseWriter, r *http.Request)
+
+	// DeleteWebhookSubscription deletes a webhook subscription and its dead
+	// letters.
+	//
+	// DELETE /consensus/webhooks/{subscription_id}
+	DeleteWebhookSubscription(w http.ResponseWriter, r *http.Request)
+
+	// ListWebhookDeadLetters returns a list of failed deliveries for a webhook
+	// subscription.
+	//
+	// GET /consensus/webhooks/{subscription_id}/dead_letters
+	ListWebhookDeadLetters(w http.ResponseWriter, r *http.Request)
+
+	// ExportAccountActivity streams the activity of a consensus account, which are
+	// the transactions it sent and the staking events involving it, in order.
+	//
+	// GET /consensus/export/account_activity
+	ExportAccountActivity(w http.ResponseWriter, r *http.Request)
+
+	// ExportStakingEvents streams consensus staking events in order, as
+	// newline-delimited JSON or, if requested with an `Accept` header, as CSV.
+	//
+	// GET /consensus/export/staking_events
+	ExportStakingEvents(w http.ResponseWriter, r *http.Request)
+
+	// Stream streams newly indexed data as Server-Sent Events or, if the request
+	// is a WebSocket upgrade, as WebSocket text messages.
+	//
+	// GET /stream
+	Stream(w http.ResponseWriter, r *http.Request)
+}
+
+// Status is the status of the indexer.
+type Status struct {
+	// The latest chain ID at the head of indexing.
+	LatestChainID string `json:"latest_chain_id"`
+	// The latest indexed block at the head of indexing.
+	LatestBlock int64 `json:"latest_block"`
+	// The RFC 3339 formatted time of latest indexing update.
+	LatestUpdate time.Time `json:"latest_update"`
+}
+
+// SearchResults is a list of matches of a search query.
+type SearchResults struct {
+	Results []SearchResult `json:"results"`
+}
+
+// SearchResult is a match of a search query. Properties other than the kind
+// and ID are only set for some kinds of matches.
+type SearchResult struct {
+	// The kind of the match.
+	Kind string `json:"kind"`
+	// The height, hash, public key or address that identifies the match within its
+	// kind.
+	ID string `json:"id"`
+	// The block height of transactions.
+	Height *int64 `json:"height,omitempty"`
+	// The entity that controls nodes.
+	EntityID *string `json:"entity_id,omitempty"`
+	// The staking address of entities and validators.
+	Address *string `json:"address,omitempty"`
+	// The name of entities and validators in the metadata registry.
+	Name *string `json:"name,omitempty"`
+}
+
+// BlockList is a list of consensus blocks.
+type BlockList struct {
+	Blocks []Block `json:"blocks"`
+}
+
+// Block is a consensus block.
+type Block struct {
+	// The block height.
+	Height int64 `json:"height"`
+	// The block header hash.
+	Hash string `json:"hash"`
+	// The second-granular consensus time.
+	Timestamp time.Time `json:"timestamp"`
+}
+
+// TransactionList is a list of consensus transactions.
+type TransactionList struct {
+	Transactions []Transaction `json:"transactions"`
+}
+
+// Transaction is a consensus transaction.
+type Transaction struct {
+	// The block height at which this transaction was executed.
+	Height int64 `json:"height"`
+	// The cryptographic hash of this transaction's encoding.
+	Hash string `json:"hash"`
+	// The staking address of the sender of this transaction.
+	Sender string `json:"sender"`
+	// The nonce used with this transaction, to prevent replay.
+	Nonce uint64 `json:"nonce"`
+	// The fee that this transaction's sender committed to pay to execute it.
+	Fee uint64 `json:"fee"`
+	// The method that was called.
+	Method string `json:"method"`
+	// The CBOR-encoded method call body.
+	Body []byte `json:"body"`
+	// Whether this transaction successfully executed.
+	Success bool `json:"success"`
+}
+
+// EntityList is a list of entities registered at the consensus layer.
+type EntityList struct {
+	Entities []Entity `json:"entities"`
+}
+
+// Entity is an entity registered at the consensus layer.
+type Entity struct {
+	// The public key identifying this entity.
+	ID string `json:"id"`
+	// The staking address of this entity.
+	Address string `json:"address,omitempty"`
+	// The vector of nodes owned by this entity. It is null in lists of entities.
+	Nodes []string `json:"nodes"`
+	// The name of this entity in the metadata registry.
+	Name  string          `json:"name,omitempty"`
+	Media *ValidatorMedia `json:"media,omitempty"`
+	// The active escrow of this entity, in base units.
+	Escrow uint64 `json:"escrow"`
+	// The number of accounts delegating to this entity.
+	Delegators uint64 `json:"delegators"`
+}
+
+// NodeList is a list of nodes registered at the consensus layer.
+type NodeList struct {
+	// The public key identifying the entity controlling the nodes.
+	EntityID string `json:"entity_id"`
+	Nodes    []Node `json:"nodes"`
+}
+
+// Node is a node registered at the consensus layer.
+type Node struct {
+	// The public key identifying this node.
+	ID string `json:"id"`
+	// The public key identifying the entity controlling this node.
+	EntityID string `json:"entity_id"`
+	// The epoch in which this node's commitment expires.
+	Expiration uint64 `json:"expiration"`
+	// The public key used for establishing TLS connections.
+	TLSPubkey string `json:"tls_pubkey"`
+	// The public key that will be used for establishing TLS connections upon
+	// rotation.
+	TLSNextPubkey string `json:"tls_next_pubkey,omitempty"`
+	// The unique identifier of this node on the P2P transport.
+	P2PPubkey string `json:"p2p_pubkey"`
+	// The unique identifier of this node as a consensus member.
+	ConsensusPubkey string `json:"consensus_pubkey"`
+	// A bitmask representing this node's roles.
+	Roles string `json:"roles"`
+	// The addresses at which this node accepts TLS connections.
+	TLSAddresses []string `json:"tls_addresses"`
+	// The addresses of this node on the P2P transport.
+	P2PAddresses []string `json:"p2p_addresses"`
+	// The addresses of this node as a consensus member.
+	ConsensusAddresses []string `json:"consensus_addresses"`
+	// The public key used for VRF-based elections.
+	VRFPubkey string `json:"vrf_pubkey,omitempty"`
+	// The version of the software that the node runs.
+	SoftwareVersion string `json:"software_version,omitempty"`
+	// The runtimes that this node is registered for.
+	Runtimes []NodeRuntime `json:"runtimes"`
+	// The epoch in which the freeze of this node ends, if it is frozen.
+	FreezeEnd *uint64 `json:"freeze_end,omitempty"`
+}
+
+// NodeRuntime is a runtime that a node is registered for.
+type NodeRuntime struct {
+	// The runtime ID.
+	ID string `json:"id"`
+	// The version of the runtime that the node runs.
+	Version string `json:"version"`
+}
+
+// NodeHistory is the history of a node registered at the consensus layer.
+type NodeHistory struct {
+	// The public key identifying the node.
+	NodeID string `json:"node_id"`
+	// The public key identifying the entity controlling the node.
+	EntityID string `json:"entity_id"`
+	// The events of the node, from the most recent.
+	Events []NodeHistoryEvent `json:"events"`
+}
+
+// NodeHistoryEvent is a registration, expiry, freeze or unfreeze of a node,
+// along with the node descriptor as of the event.
+type NodeHistoryEvent struct {
+	// The block height at which the event occurred.
+	Height int64 `json:"height"`
+	// The kind of event. Nodes are registered again whenever they update their
+	// descriptor.
+	Event string `json:"event"`
+	// The epoch in which the node's commitment expires.
+	Expiration uint64 `json:"expiration"`
+	// The software version of the node.
+	SoftwareVersion string `json:"software_version,omitempty"`
+	// The roles of the node.
+	Roles string `json:"roles,omitempty"`
+	// The addresses at which the node accepts TLS connections.
+	TLSAddresses []string `json:"tls_addresses"`
+	// The addresses of the node on the P2P transport.
+	P2PAddresses []string `json:"p2p_addresses"`
+	// The comma separated addresses of the node as a consensus member.
+	ConsensusAddress string `json:"consensus_address,omitempty"`
+	// The epoch after which the node may be unfrozen, if the node was frozen as of
+	// the event.
+	FreezeEnd *uint64 `json:"freeze_end,omitempty"`
+}
+
+// AccountList is a list of consensus layer accounts.
+type AccountList struct {
+	Accounts []Account `json:"accounts"`
+}
+
+// Account is a consensus layer account.
+type Account struct {
+	// The staking address for this account.
+	Address string `json:"address"`
+	// A nonce used to prevent replay.
+	Nonce uint64 `json:"nonce"`
+	// The available balance, in base units.
+	Available uint64 `json:"available"`
+	// The active escrow balance, in base units.
+	Escrow uint64 `json:"escrow"`
+	// The debonding escrow balance, in base units.
+	Debonding uint64 `json:"debonding"`
+	// The delegations balance, in base units.
+	DelegationsBalance uint64 `json:"delegations_balance,omitempty"`
+	// The debonding delegations balance, in base units.
+	DebondingDelegationsBalance uint64 `json:"debonding_delegations_balance,omitempty"`
+	// The allowances made by this account. It is null in lists of accounts.
+	Allowances []Allowance `json:"allowances"`
+}
+
+// DebondingDelegationList is a list of debonding delegations.
+type DebondingDelegationList struct {
+	DebondingDelegations []DebondingDelegation `json:"debonding_delegations"`
+}
+
+// DebondingDelegation is a debonding delegation.
+type DebondingDelegation struct {
+	// The amount of tokens delegated in base units.
+	Amount uint64 `json:"amount"`
+	// The shares of tokens delegated.
+	Shares uint64 `json:"shares"`
+	// The delegatee address.
+	ValidatorAddress string `json:"address"`
+	// The epoch at which the debonding ends.
+	DebondEnd uint64 `json:"debond_end"`
+}
+
+// DelegationList is a list of delegations.
+type DelegationList struct {
+	Delegations []Delegation `json:"delegations"`
+}
+
+// Delegation is a delegation.
+type Delegation struct {
+	// The amount of tokens delegated in base units.
+	Amount uint64 `json:"amount"`
+	// The shares of tokens delegated.
+	Shares uint64 `json:"shares"`
+	// The delegatee address.
+	ValidatorAddress string `json:"address"`
+}
+
+// Allowance is an allowance made by an account.
+type Allowance struct {
+	// The allowed account.
+	Address string `json:"address"`
+	// The amount allowed for the allowed account.
+	Amount uint64 `json:"amount"`
+}
+
+// EpochList is a list of consensus epochs.
+type EpochList struct {
+	Epochs []Epoch `json:"epochs"`
+}
+
+// Epoch is a consensus epoch.
+type Epoch struct {
+	// The epoch number.
+	ID uint64 `json:"id"`
+	// The (inclusive) height at which this epoch started.
+	StartHeight uint64 `json:"start_height"`
+	// The (inclusive) height at which this epoch ended, if it has ended.
+	EndHeight uint64 `json:"end_height,omitempty"`
+}
+
+// EpochCommittees is the members of the runtime committees of an epoch.
+type EpochCommittees struct {
+	// The epoch number.
+	Epoch uint64 `json:"epoch"`
+	// The members of the committees elected for the epoch.
+	Members []CommitteeMember `json:"members"`
+}
+
+// RuntimeList is a list of runtimes registered at the consensus layer.
+type RuntimeList struct {
+	Runtimes []Runtime `json:"runtimes"`
+}
+
+// Runtime is a runtime registered at the consensus layer.
+type Runtime struct {
+	// The hex-encoded ID of the runtime.
+	ID string `json:"id"`
+	// The name of the runtime, if it is a known ParaTime.
+	Name string `json:"name,omitempty"`
+	// The kind of the runtime.
+	Kind string `json:"kind"`
+	// The TEE hardware that the runtime requires, if any.
+	TEEHardware string `json:"tee_hardware"`
+	// The hex-encoded ID of the key manager runtime of the runtime, if it uses
+	// one.
+	KeyManager string `json:"key_manager,omitempty"`
+	// Whether the runtime is suspended, since its owner does not have enough
+	// stake.
+	Suspended bool `json:"suspended"`
+	// The round of the runtime genesis block.
+	GenesisRound *uint64 `json:"genesis_round,omitempty"`
+	// The state root of the runtime genesis block.
+	GenesisStateRoot string `json:"genesis_state_root,omitempty"`
+	// The latest round of the runtime indexed by the runtime analyzers, if the
+	// runtime is indexed.
+	LatestRound *int64 `json:"latest_round,omitempty"`
+	// The members of the current committees of the runtime. Only runtimes that are
+	// returned on their own include their committees.
+	Committees []CommitteeMember `json:"committees,omitempty"`
+	// The latest registrations, updates, suspensions and unsuspensions of the
+	// runtime, at most 10, from the most recent. Only runtimes that are returned
+	// on their own include their history, which is paginated in full at
+	// `/consensus/runtimes/{runtime_id}/history`.
+	History []RuntimeHistoryEvent `json:"history,omitempty"`
+}
+
+// RuntimeHistory is the history of a runtime registered at the consensus
+// layer.
+type RuntimeHistory struct {
+	// The hex-encoded ID of the runtime.
+	RuntimeID string `json:"runtime_id"`
+	// The events of the runtime, from the most recent.
+	Events []RuntimeHistoryEvent `json:"events"`
+}
+
+// RuntimeHistoryEvent is a registration, update, suspension or unsuspension of
+// a runtime.
+type RuntimeHistoryEvent struct {
+	// The block height at which the event occurred.
+	Height int64 `json:"height"`
+	// The kind of event. A runtime is registered once, and its descriptor is
+	// updated whenever it is registered again.
+	Event string `json:"event"`
+}
+
+// CommitteeMember is a member of a runtime committee.
+type CommitteeMember struct {
+	// The hex-encoded ID of the runtime of the committee.
+	Runtime string `json:"runtime"`
+	// The kind of the committee.
+	Kind string `json:"kind"`
+	// The public key identifying the member node.
+	NodeID string `json:"node_id"`
+	// The public key identifying the entity controlling the member node, if the
+	// node is known.
+	EntityID string `json:"entity_id,omitempty"`
+	// The role of the node in the committee.
+	Role string `json:"role"`
+}
+
+// EpochValidators is the consensus validators of an epoch.
+type EpochValidators struct {
+	// The epoch number.
+	Epoch uint64 `json:"epoch"`
+	// The validators of the epoch, as of its last block, or of the latest block if
+	// the epoch has not ended.
+	Validators []EpochValidator `json:"validators"`
+}
+
+// EpochValidator is a consensus validator of an epoch.
+type EpochValidator struct {
+	// The public key identifying the validator node.
+	NodeID string `json:"node_id"`
+	// The public key identifying the entity controlling the validator node, if the
+	// node is known.
+	EntityID string `json:"entity_id,omitempty"`
+	// The consensus voting power of the validator.
+	VotingPower int64 `json:"voting_power"`
+}
+
+// ProposalList is a list of governance proposals.
+type ProposalList struct {
+	Proposals []Proposal `json:"proposals"`
+}
+
+// Proposal is a governance proposal.
+type Proposal struct {
+	// The unique identifier of the proposal.
+	ID uint64 `json:"id"`
+	// The staking address of the proposal submitter.
+	Submitter string `json:"submitter"`
+	// The state of the proposal.
+	State string `json:"state"`
+	// The deposit attached to this proposal.
+	Deposit uint64 `json:"deposit"`
+	// The name of the upgrade handler.
+	Handler *string `json:"handler,omitempty"`
+	Target  Target  `json:"target,omitempty"`
+	// The epoch at which the proposed upgrade will happen.
+	Epoch *uint64 `json:"epoch,omitempty"`
+	// The proposal to cancel, if this proposal proposes cancelling an existing
+	// proposal.
+	Cancels *int64 `json:"cancels,omitempty"`
+	// The epoch at which this proposal was created.
+	CreatedAt uint64 `json:"created_at"`
+	// The epoch at which voting for this proposal will close.
+	ClosesAt uint64 `json:"closes_at"`
+	// The number of invalid votes for this proposal, after tallying.
+	InvalidVotes uint64           `json:"invalid_votes"`
+	Content      *ProposalContent `json:"content,omitempty"`
+	Results      *ProposalResults `json:"results,omitempty"`
+}
+
+// ProposalContent is the content of a governance proposal, which either
+// proposes an upgrade or cancelling a pending upgrade.
+type ProposalContent struct {
+	Upgrade       *UpgradeDescriptor     `json:"upgrade,omitempty"`
+	CancelUpgrade *CancelUpgradeProposal `json:"cancel_upgrade,omitempty"`
+}
+
+// UpgradeDescriptor is the descriptor of a proposed upgrade.
+type UpgradeDescriptor struct {
+	// The version of the upgrade descriptor.
+	Version uint64 `json:"v"`
+	// The name of the upgrade handler.
+	Handler string `json:"handler"`
+	Target  Target `json:"target"`
+	// The epoch at which the upgrade will happen.
+	Epoch uint64 `json:"epoch"`
+}
+
+// CancelUpgradeProposal is the cancellation of a pending upgrade.
+type CancelUpgradeProposal struct {
+	// The unique identifier of the upgrade proposal to cancel.
+	ProposalID uint64 `json:"proposal_id"`
+}
+
+// ProposalResults is the tallied votes of a closed governance proposal for
+// each choice.
+type ProposalResults struct {
+	Yes     VoteTally `json:"yes"`
+	No      VoteTally `json:"no"`
+	Abstain VoteTally `json:"abstain"`
+}
+
+// VoteTally is the tallied votes of a governance proposal for a choice. Only
+// votes of validators are tallied, weighted by their escrow.
+type VoteTally struct {
+	// The number of tallied votes for the choice.
+	Votes uint64 `json:"votes"`
+	// The escrow of the voters of the choice when the proposal closed.
+	Stake uint64 `json:"stake"`
+}
+
+// Target is the target protocol versions of an upgrade proposal.
+type Target struct {
+	ConsensusProtocol        *string `json:"consensus_protocol"`
+	RuntimeHostProtocol      *string `json:"runtime_host_protocol"`
+	RuntimeCommitteeProtocol *string `json:"runtime_committee_protocol"`
+}
+
+// ProposalVotes is a list of votes for a governance proposal.
+type ProposalVotes struct {
+	// The unique identifier of the proposal.
+	ProposalID uint64 `json:"proposal_id"`
+	// The list of votes for the proposal.
+	Votes []ProposalVote `json:"votes"`
+}
+
+// ProposalVote is a vote for a governance proposal.
+type ProposalVote struct {
+	// The staking address casting this vote.
+	Address string `json:"address"`
+	// The vote cast.
+	Vote string `json:"vote"`
+	// The escrow of the voter when the proposal closed, if the vote was tallied.
+	// Votes are tallied when proposals close, and only if their voter is a
+	// validator.
+	Weight *uint64 `json:"weight,omitempty"`
+}
+
+// ValidatorList is a list of validators registered at the consensus layer.
+type ValidatorList struct {
+	Validators []Validator `json:"validators"`
+}
+
+// Validator is a validator registered at the consensus layer.
+type Validator struct {
+	// The name of this Validator.
+	Name string `json:"name"`
+	// The staking address identifying this Validator.
+	EntityAddress string `json:"entity_address"`
+	// The public key identifying this Validator.
+	EntityID string `json:"entity_id"`
+	// The public key identifying this Validator's node.
+	NodeID string `json:"node_id"`
+	// The amount staked.
+	Escrow uint64 `json:"escrow"`
+	// Whether the entity is part of the validator set, which are the top
+	// <scheduler.params.max_validators> entities by stake.
+	Active bool `json:"active"`
+	// Whether the entity has a node that is registered for being a validator, is
+	// up to date, and has successfully registered itself. The entity may or may
+	// not be part of the validator set.
+	Status bool           `json:"status"`
+	Media  ValidatorMedia `json:"media"`
+	// Commission rate.
+	CurrentRate            uint64                   `json:"current_rate"`
+	CurrentCommissionBound ValidatorCommissionBound `json:"current_commission_bound"`
+	Participation          ValidatorParticipation   `json:"participation"`
+	// The number of accounts delegating to this Validator.
+	Delegators             uint64                          `json:"delegators"`
+	DelegatorConcentration ValidatorDelegatorConcentration `json:"delegator_concentration"`
+}
+
+// ValidatorDelegatorConcentration is the concentration of the stake delegated
+// to a validator, as the share of it that the largest delegators account for,
+// between 0 and 1.
+type ValidatorDelegatorConcentration struct {
+	// The share of the stake delegated by the largest delegator.
+	Top1 float64 `json:"top_1"`
+	// The share of the stake delegated by the 10 largest delegators.
+	Top10 float64 `json:"top_10"`
+	// The share of the stake delegated by the 100 largest delegators.
+	Top100 float64 `json:"top_100"`
+}
+
+// ValidatorDelegatorList is a list of accounts delegating to a validator.
+type ValidatorDelegatorList struct {
+	// The number of accounts delegating to the validator.
+	TotalCount uint64 `json:"total_count"`
+	// The amount delegated to the validator, in base units.
+	TotalAmount uint64 `json:"total_amount"`
+	// The shares of the escrow pool of the validator.
+	TotalShares uint64               `json:"total_shares"`
+	Delegators  []ValidatorDelegator `json:"delegators"`
+}
+
+// ValidatorDelegator is an account delegating to a validator.
+type ValidatorDelegator struct {
+	// The address of the delegator.
+	Address string `json:"address"`
+	// The amount delegated in base units, as of the current escrow pool of the
+	// validator.
+	Amount uint64 `json:"amount"`
+	// The shares of the delegation.
+	Shares uint64 `json:"shares"`
+}
+
+// ValidatorDebondingDelegatorList is a list of debonding delegations to a
+// validator.
+type ValidatorDebondingDelegatorList struct {
+	// The number of debonding delegations to the validator.
+	TotalCount uint64 `json:"total_count"`
+	// The amount debonding from the validator, in base units.
+	TotalAmount uint64 `json:"total_amount"`
+	// The shares of the debonding escrow pool of the validator.
+	TotalShares         uint64                        `json:"total_shares"`
+	DebondingDelegators []ValidatorDebondingDelegator `json:"debonding_delegators"`
+}
+
+// ValidatorDebondingDelegator is a debonding delegation to a validator.
+type ValidatorDebondingDelegator struct {
+	// The address of the delegator.
+	Address string `json:"address"`
+	// The amount debonding in base units, as of the current debonding escrow pool
+	// of the validator.
+	Amount uint64 `json:"amount"`
+	// The shares of the debonding delegation.
+	Shares uint64 `json:"shares"`
+	// The epoch at which the debonding ends.
+	DebondEnd uint64 `json:"debond_end"`
+}
+
+// ValidatorParticipation is the participation of a validator in governance,
+// across closed proposals.
+type ValidatorParticipation struct {
+	// The number of closed governance proposals.
+	Proposals uint64 `json:"proposals"`
+	// The number of closed governance proposals whose tally counted a vote of the
+	// validator.
+	Votes uint64 `json:"votes"`
+}
+
+// ValidatorMedia is the metadata of a validator.
+type ValidatorMedia struct {
+	// An URL associated with the entity.
+	WebsiteLink string `json:"url"`
+	// An email address for the validator.
+	EmailAddress string `json:"email"`
+	// A Twitter handle.
+	TwitterAcc string `json:"twitter"`
+	// A Telegram handle.
+	TgChat string `json:"tg"`
+	// A logo type.
+	Logotype string `json:"logotype"`
+	// The name of the validator.
+	Name string `json:"name"`
+}
+
+// ValidatorCommissionBound is the current commission bound of a validator.
+type ValidatorCommissionBound struct {
+	Lower      uint64 `json:"lower"`
+	Upper      uint64 `json:"upper"`
+	EpochStart uint64 `json:"epoch_start"`
+	EpochEnd   uint64 `json:"epoch_end"`
+}
+
+// TpsCheckpointList is a list of TPS checkpoint windows.
+type TpsCheckpointList struct {
+	// The length, in minutes, of each TPS measurement window.
+	IntervalMinutes int `json:"interval_minutes"`
+	// The list of TPS checkpoint windows.
+	TpsCheckpoints []TpsCheckpoint `json:"tps_checkpoints"`
+}
+
+// TpsCheckpoint is the live TPS value at a marker timestamp.
+type TpsCheckpoint struct {
+	// The timestamp anchoring this TPS measurement window.
+	Timestamp time.Time `json:"timestamp"`
+	// The transaction volume in this measurement window.
+	TxVolume uint64 `json:"tx_volume"`
+}
+
+// VolumeList is a list of daily transaction volumes.
+type VolumeList struct {
+	// The list of daily transaction volumes.
+	Volumes []Volume `json:"volumes"`
+}
+
+// Volume is the daily transaction volume on a day.
+type Volume struct {
+	// The date for this daily transaction volume measurement.
+	Date time.Time `json:"date"`
+	// The transaction volume on this day.
+	TxVolume uint64 `json:"tx_volume"`
+}
+
+// WebhookSubscriptionList is a list of webhook subscriptions.
+type WebhookSubscriptionList struct {
+	// The list of webhook subscriptions.
+	Subscriptions []WebhookSubscription `json:"subscriptions"`
+}
+
+// WebhookSubscription is a webhook subscription.
+type WebhookSubscription struct {
+	// The unique identifier of the webhook subscription.
+	ID int64 `json:"id"`
+	// The kind of event to be notified of. `node_expired` is delivered when a
+	// node's registration expires, which removes it from the registry, and
+	// `node_frozen` and `node_unfrozen` when a node is frozen, e.g. as its entity
+	// is slashed, or unfrozen.
+	Kind string `json:"kind"`
+	// The URL to which events are delivered.
+	URL string `json:"url"`
+	// The account filter of the subscription, if any.
+	Account *string `json:"account,omitempty"`
+	// The entity filter of the subscription, if any.
+	EntityID *string `json:"entity_id,omitempty"`
+	// The minimum amount filter of the subscription, if any.
+	MinAmount *string `json:"min_amount,omitempty"`
+	// The RFC 3339 formatted time the subscription was created.
+	CreatedAt time.Time `json:"created_at"`
+	// The secret used to sign deliveries. It is only returned when the
+	// subscription is created.
+	Secret string `json:"secret,omitempty"`
+}
+
+// WebhookSubscriptionRequest is a request to create a webhook subscription.
+type WebhookSubscriptionRequest struct {
+	// The kind of event to be notified of.
+	Kind string `json:"kind"`
+	// The https URL to which events are delivered. Its host must only resolve to
+	// public addresses.
+	URL string `json:"url"`
+	// If set, only deliver events involving this account, i.e. the sender or
+	// receiver of a transfer, or the submitter of a proposal.
+	Account *string `json:"account,omitempty"`
+	// If set, only deliver events involving this entity, e.g. the deregistration
+	// of one of its nodes.
+	EntityID *string `json:"entity_id,omitempty"`
+	// If set, only deliver events moving at least this amount, i.e. the amount of
+	// a transfer or the deposit of a proposal.
+	MinAmount *string `json:"min_amount,omitempty"`
+}
+
+// WebhookDeadLetterList is a list of failed deliveries for a webhook
+// subscription.
+type WebhookDeadLetterList struct {
+	// The unique identifier of the webhook subscription.
+	SubscriptionID int64 `json:"subscription_id"`
+	// The list of failed deliveries.
+	DeadLetters []WebhookDeadLetter `json:"dead_letters"`
+}
+
+// WebhookDeadLetter is a webhook delivery that failed after all retries.
+type WebhookDeadLetter struct {
+	// The unique identifier of the dead letter.
+	ID int64 `json:"id"`
+	// The block height of the undelivered event.
+	Height int64 `json:"height"`
+	// The undelivered payload.
+	Payload json.RawMessage `json:"payload"`
+	// The number of delivery attempts made.
+	Attempts int `json:"attempts"`
+	// The error of the last delivery attempt.
+	LastError string `json:"last_error"`
+	// The RFC 3339 formatted time the delivery was given up on.
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// Event is a consensus event.
+type Event struct {
+	// The block height at which the event was emitted.
+	Height int64 `json:"height"`
+	// The hash of the transaction that emitted the event.
+	TxHash string `json:"tx_hash"`
+	// The index of the transaction within the block.
+	TxIndex int `json:"tx_index"`
+	// The consensus backend that emitted the event.
+	Backend string `json:"backend"`
+	// The type of the event.
+	Type string `json:"type"`
+	// The event, as emitted by the backend.
+	Body json.RawMessage `json:"body"`
+}
+
+// EmeraldRound is an Emerald ParaTime round.
+type EmeraldRound struct {
+	// The round number.
+	Round int64 `json:"round"`
+	// The block header version.
+	Version uint64 `json:"version"`
+	// The second-granular consensus time.
+	Timestamp int64 `json:"timestamp"`
+	// The block header hash.
+	Hash string `json:"hash"`
+	// The previous block header hash.
+	PrevHash string `json:"prev_hash"`
+	// The I/O merkle root.
+	IORoot string `json:"io_root"`
+	// The state merkle root.
+	StateRoot string `json:"state_root"`
+	// The hash of emitted runtime messages.
+	MessagesHash string `json:"messages_hash"`
+	// The hash of processed incoming messages.
+	InMessagesHash string `json:"in_messages_hash"`
+}
+
+// AccountActivity is a row of the activity of a consensus account, which is
+// either a transaction sent by the account or a staking event involving the
+// account.
+type AccountActivity struct {
+	// The block height of the activity.
+	Height int64 `json:"height"`
+	// The RFC 3339 formatted time of the block.
+	Timestamp time.Time `json:"timestamp"`
+	// The hash of the transaction.
+	TxHash string `json:"tx_hash"`
+	// Whether the activity is a transaction or a staking event.
+	Kind string `json:"kind"`
+	// The method of the transaction, or the type of the event.
+	Type string `json:"type"`
+	// The fee paid for the transaction, in base units.
+	Fee *string `json:"fee,omitempty"`
+	// The amount of the event, in base units.
+	Amount *string `json:"amount,omitempty"`
+	// Whether the transaction succeeded.
+	Success *bool `json:"success,omitempty"`
+	// The event, as emitted by the staking backend.
+	Body json.RawMessage `json:"body,omitempty"`
+}
+
+// StakingEvent is a consensus staking event.
+type StakingEvent struct {
+	// The block height at which the event was emitted.
+	Height int64 `json:"height"`
+	// The RFC 3339 formatted time of the block.
+	Timestamp time.Time `json:"timestamp"`
+	// The hash of the transaction that emitted the event.
+	TxHash string `json:"tx_hash"`
+	// The type of the event.
+	Type string `json:"type"`
+	// The account the event originates from, e.g. the sender of a transfer or the
+	// delegator of an escrow.
+	Owner *string `json:"owner,omitempty"`
+	// The account the event targets, e.g. the receiver of a transfer or the
+	// delegatee of an escrow.
+	Counterparty *string `json:"counterparty,omitempty"`
+	// The amount of the event, in base units.
+	Amount *string `json:"amount,omitempty"`
+	// The event, as emitted by the staking backend.
+	Body json.RawMessage `json:"body"`
+}
+
+// StreamMessage is a message sent on the streaming API.
+type StreamMessage struct {
+	// The topic the message was sent on.
+	Topic string `json:"topic"`
+	// The height or round of the data.
+	Height int64 `json:"height"`
+	// The streamed data, depending on the topic.
+	Data interface{} `json:"data"`
+}
@@ -0,0 +1,294 @@
+package v1
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/oasisprotocol/oasis-indexer/cache"
+	"github.com/oasisprotocol/oasis-indexer/log"
+)
+
+const (
+	// immutableCacheTTL is how long the server caches immutable responses.
+	immutableCacheTTL = 24 * time.Hour
+
+	// latestCacheTTL is how long the server caches other responses at most.
+	// They are no longer served once indexing progresses, so this only
+	// bounds how long they take up space.
+	latestCacheTTL = time.Minute
+
+	// generationRefreshInterval is how often indexing progress is checked.
+	generationRefreshInterval = time.Second
+
+	// immutableCacheControl lets clients cache responses indefinitely.
+	immutableCacheControl = "public, max-age=31536000, immutable"
+
+	// latestCacheControl requires clients to revalidate responses,
+	// which is cheap since responses have ETags.
+	latestCacheControl = "no-cache"
+)
+
+// cachedResponse is a successful response, as cached by the server.
+type cachedResponse struct {
+	ContentType  string `json:"content_type"`
+	CacheControl string `json:"cache_control"`
+	ETag         string `json:"etag"`
+	Body         []byte `json:"body"`
+}
+
+// responseCache caches responses. Immutable responses are cached until they
+// expire, while other responses are cached for a generation, which changes
+// whenever indexing progresses.
+type responseCache struct {
+	cache  cache.Cache
+	client *storageClient
+	logger *log.Logger
+
+	mu          sync.Mutex
+	generations map[string]*generation
+}
+
+// generation is the generation of responses for a chain.
+type generation struct {
+	value   string
+	checked time.Time
+}
+
+func newResponseCache(c cache.Cache, client *storageClient, logger *log.Logger) *responseCache {
+	return &responseCache{
+		cache:       c,
+		client:      client,
+		logger:      logger,
+		generations: make(map[string]*generation),
+	}
+}
+
+// lookup returns the response cached for the key, if any, and the current
+// generation. The generation is empty if it could not be determined.
+func (c *responseCache) lookup(ctx context.Context, chainID string, key string) (*cachedResponse, string) {
+	if resp := c.get(ctx, immutableCacheKey(key)); resp != nil {
+		return resp, ""
+	}
+
+	gen, err := c.generation(ctx, chainID)
+	if err != nil {
+		c.logger.Error("failed to determine cache generation",
+			"request_id", ctx.Value(RequestIDContextKey),
+			"err", err.Error(),
+		)
+		return nil, ""
+	}
+	return c.get(ctx, latestCacheKey(gen, key)), gen
+}
+
+// store caches a response for the key. Responses that are not immutable
+// are only cached if the generation they belong to is known.
+func (c *responseCache) store(ctx context.Context, gen string, key string, resp *cachedResponse) {
+	ttl := latestCacheTTL
+	switch {
+	case resp.CacheControl == immutableCacheControl:
+		key, ttl = immutableCacheKey(key), immutableCacheTTL
+	case gen != "":
+		key = latestCacheKey(gen, key)
+	default:
+		return
+	}
+
+	value, err := json.Marshal(resp)
+	if err != nil {
+		c.logger.Error("failed to marshal cached response",
+			"request_id", ctx.Value(RequestIDContextKey),
+			"err", err.Error(),
+		)
+		return
+	}
+	if err := c.cache.Set(ctx, key, value, ttl); err != nil {
+		c.logger.Error("failed to cache response",
+			"request_id", ctx.Value(RequestIDContextKey),
+			"err", err.Error(),
+		)
+	}
+}
+
+func (c *responseCache) get(ctx context.Context, key string) *cachedResponse {
+	value, ok, err := c.cache.Get(ctx, key)
+	if err != nil {
+		c.logger.Error("failed to get cached response",
+			"request_id", ctx.Value(RequestIDContextKey),
+			"err", err.Error(),
+		)
+		return nil
+	}
+	if !ok {
+		return nil
+	}
+
+	var resp cachedResponse
+	if err := json.Unmarshal(value, &resp); err != nil {
+		c.logger.Error("failed to unmarshal cached response",
+			"request_id", ctx.Value(RequestIDContextKey),
+			"err", err.Error(),
+		)
+		return nil
+	}
+	return &resp
+}
+
+// generation returns the current generation of responses for the chain,
+// which is the time the latest block was processed.
+func (c *responseCache) generation(ctx context.Context, chainID string) (string, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := time.Now()
+	if g, ok := c.generations[chainID]; ok && now.Sub(g.checked) < generationRefreshInterval {
+		return g.value, nil
+	}
+
+	t, err := c.client.LatestProcessedTime(ctx)
+	if err != nil {
+		return "", err
+	}
+	g := &generation{strconv.FormatInt(t.UnixNano(), 10), now}
+	c.generations[chainID] = g
+	return g.value, nil
+}
+
+func immutableCacheKey(key string) string {
+	return "v1:immutable:" + key
+}
+
+func latestCacheKey(gen string, key string) string {
+	return "v1:" + gen + ":" + key
+}
+
+// cacheMiddleware is a middleware that sets ETag and Cache-Control headers
+// on successful responses, and replies to requests with a matching
+// If-None-Match header with 304 Not Modified. If the server has a response
+// cache, responses are served from it.
+func (h *Handler) cacheMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			next.ServeHTTP(w, r)
+			return
+		}
+		ctx := r.Context()
+
+		// Lists may be requested in several formats.
+		chainID, _ := ctx.Value(ChainIDContextKey).(string)
+		f := negotiateFormat(r.Header.Get("accept"), formatJSON, formatCSV, formatNDJSON)
+		key := chainID + ":" + string(f) + ":" + r.URL.Path + "?" + r.URL.Query().Encode()
+
+		var gen string
+		if h.cache != nil {
+			var resp *cachedResponse
+			if resp, gen = h.cache.lookup(ctx, chainID, key); resp != nil {
+				h.writeResponse(w, r, resp)
+				h.metrics.RequestCounter(r.URL.Path, "success", "cache_hit").Inc()
+				return
+			}
+		}
+
+		buf := &bufferedResponseWriter{header: make(http.Header), status: http.StatusOK}
+		next.ServeHTTP(buf, r)
+
+		if buf.status != http.StatusOK {
+			for k, v := range buf.header {
+				w.Header()[k] = v
+			}
+			w.WriteHeader(buf.status)
+			if _, err := w.Write(buf.body.Bytes()); err != nil {
+				h.logger.Error("failed to write response",
+					"request_id", ctx.Value(RequestIDContextKey),
+					"error", err,
+				)
+			}
+			return
+		}
+
+		resp := &cachedResponse{
+			ContentType:  buf.header.Get("content-type"),
+			CacheControl: buf.header.Get("cache-control"),
+			ETag:         etag(buf.body.Bytes()),
+			Body:         buf.body.Bytes(),
+		}
+		if resp.CacheControl != immutableCacheControl {
+			resp.CacheControl = latestCacheControl
+		}
+		if h.cache != nil {
+			h.cache.store(ctx, gen, key, resp)
+		}
+		h.writeResponse(w, r, resp)
+	})
+}
+
+// writeResponse writes a successful response, or 304 Not Modified if the
+// client already has it.
+func (h *Handler) writeResponse(w http.ResponseWriter, r *http.Request, resp *cachedResponse) {
+	w.Header().Set("content-type", resp.ContentType)
+	w.Header().Set("cache-control", resp.CacheControl)
+	w.Header().Set("etag", resp.ETag)
+	w.Header().Set("vary", "accept")
+	if etagMatches(r.Header.Get("if-none-match"), resp.ETag) {
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+	if _, err := w.Write(resp.Body); err != nil {
+		h.logger.Error("failed to write response",
+			"request_id", r.Context().Value(RequestIDContextKey),
+			"error", err,
+		)
+	}
+}
+
+// setImmutable marks a response as immutable, so that it is cached
+// by clients and the server for long.
+func setImmutable(w http.ResponseWriter) {
+	w.Header().Set("cache-control", immutableCacheControl)
+}
+
+// etag returns the entity tag of a response body.
+func etag(body []byte) string {
+	h := sha256.Sum256(body)
+	return `"` + hex.EncodeToString(h[:16]) + `"`
+}
+
+// etagMatches returns true if the If-None-Match header matches the entity tag,
+// using weak comparison.
+func etagMatches(ifNoneMatch string, tag string) bool {
+	for _, t := range strings.Split(ifNoneMatch, ",") {
+		t = strings.TrimPrefix(strings.TrimSpace(t), "W/")
+		if t == "*" || t == tag {
+			return true
+		}
+	}
+	return false
+}
+
+// bufferedResponseWriter is a http.ResponseWriter that buffers a response,
+// so that it can be inspected before it is written.
+type bufferedResponseWriter struct {
+	header http.Header
+	status int
+	body   bytes.Buffer
+}
+
+func (w *bufferedResponseWriter) Header() http.Header {
+	return w.header
+}
+
+func (w *bufferedResponseWriter) WriteHeader(status int) {
+	w.status = status
+}
+
+func (w *bufferedResponseWriter) Write(b []byte) (int, error) {
+	return w.body.Write(b)
+}
@@ -0,0 +1,108 @@
+package v1
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/oasisprotocol/oasis-indexer/cache"
+	"github.com/oasisprotocol/oasis-indexer/log"
+	"github.com/oasisprotocol/oasis-indexer/metrics"
+	"github.com/oasisprotocol/oasis-indexer/storage"
+)
+
+// processedTimeStorage is a target storage that only answers
+// the latest processed time.
+type processedTimeStorage struct {
+	storage.TargetStorage
+	processed time.Time
+}
+
+func (s *processedTimeStorage) QueryRow(ctx context.Context, sql string, args ...interface{}) storage.QueryResult {
+	return processedTimeRow{s.processed}
+}
+
+type processedTimeRow struct {
+	processed time.Time
+}
+
+func (r processedTimeRow) Scan(dest ...interface{}) error {
+	*dest[0].(*time.Time) = r.processed
+	return nil
+}
+
+func TestCacheMiddleware(t *testing.T) {
+	db := &processedTimeStorage{processed: time.Unix(1649635200, 0)}
+	logger := log.NewDefaultLogger("v1")
+	client := newStorageClient(db, logger)
+	h := &Handler{
+		client:  client,
+		cache:   newResponseCache(cache.NewMemoryCache(16), client, logger),
+		logger:  logger,
+		metrics: metrics.NewDefaultRequestMetrics("test_v1"),
+	}
+
+	calls := map[string]int{}
+	handler := h.cacheMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls[r.URL.Path]++
+		switch r.URL.Path {
+		case "/v1/consensus/blocks/1":
+			setImmutable(w)
+		case "/v1/consensus/blocks/2":
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Header().Set("content-type", "application/json")
+		_, _ = w.Write([]byte(`{"path": "` + r.URL.Path + `"}`))
+	}))
+	do := func(path string, ifNoneMatch string) *httptest.ResponseRecorder {
+		r := httptest.NewRequest(http.MethodGet, path, nil)
+		r = r.WithContext(context.WithValue(r.Context(), ChainIDContextKey, "oasis_3"))
+		if ifNoneMatch != "" {
+			r.Header.Set("If-None-Match", ifNoneMatch)
+		}
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, r)
+		return w
+	}
+
+	// Responses are cached, and have entity tags.
+	resp := do("/v1/consensus/blocks", "")
+	require.Equal(t, http.StatusOK, resp.Code)
+	require.Equal(t, `{"path": "/v1/consensus/blocks"}`, resp.Body.String())
+	require.Equal(t, latestCacheControl, resp.Header().Get("cache-control"))
+	tag := resp.Header().Get("etag")
+	require.NotEmpty(t, tag)
+
+	resp = do("/v1/consensus/blocks", "")
+	require.Equal(t, http.StatusOK, resp.Code)
+	require.Equal(t, tag, resp.Header().Get("etag"))
+	require.Equal(t, 1, calls["/v1/consensus/blocks"])
+
+	// Clients that have the response are not sent it again.
+	resp = do("/v1/consensus/blocks", `"other", W/`+tag)
+	require.Equal(t, http.StatusNotModified, resp.Code)
+	require.Empty(t, resp.Body.String())
+
+	// Immutable responses are cached for long by clients.
+	resp = do("/v1/consensus/blocks/1", "")
+	require.Equal(t, immutableCacheControl, resp.Header().Get("cache-control"))
+
+	// Failed responses are not cached.
+	require.Equal(t, http.StatusNotFound, do("/v1/consensus/blocks/2", "").Code)
+	require.Equal(t, http.StatusNotFound, do("/v1/consensus/blocks/2", "").Code)
+	require.Equal(t, 2, calls["/v1/consensus/blocks/2"])
+
+	// Responses are no longer served once indexing progresses,
+	// unless they are immutable.
+	db.processed = db.processed.Add(time.Second)
+	h.cache.generations["oasis_3"].checked = time.Time{}
+	do("/v1/consensus/blocks", "")
+	do("/v1/consensus/blocks/1", "")
+	require.Equal(t, 2, calls["/v1/consensus/blocks"])
+	require.Equal(t, 1, calls["/v1/consensus/blocks/1"])
+}
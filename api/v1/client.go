@@ -2,6 +2,13 @@ package v1
 
 import (
 	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
 	"net/http"
 	"net/url"
 	"strconv"
@@ -9,19 +16,33 @@ import (
 
 	"github.com/go-chi/chi/v5"
 	"github.com/iancoleman/strcase"
+	"github.com/jackc/pgx/v4"
 	oasisErrors "github.com/oasisprotocol/oasis-core/go/common/errors"
 
 	beacon "github.com/oasisprotocol/oasis-core/go/beacon/api"
+	"github.com/oasisprotocol/oasis-core/go/common/crypto/signature"
+	governance "github.com/oasisprotocol/oasis-core/go/governance/api"
 	staking "github.com/oasisprotocol/oasis-core/go/staking/api"
+	oasisConfig "github.com/oasisprotocol/oasis-sdk/client-sdk/go/config"
 
+	"github.com/oasisprotocol/oasis-indexer/analyzer"
 	"github.com/oasisprotocol/oasis-indexer/analyzer/util"
 	"github.com/oasisprotocol/oasis-indexer/api/common"
 	"github.com/oasisprotocol/oasis-indexer/log"
+	"github.com/oasisprotocol/oasis-indexer/notifier"
 	"github.com/oasisprotocol/oasis-indexer/storage"
+	"github.com/oasisprotocol/oasis-indexer/streaming"
 )
 
 const (
 	tpsWindowSizeMinutes = 5
+
+	// maxRequestBodySize is the maximum size of a request body in bytes.
+	maxRequestBodySize = 1 << 16
+
+	// runtimeHistoryLimit is the number of the latest events of a runtime
+	// that are included with it.
+	runtimeHistoryLimit = 10
 )
 
 // storageClient is a wrapper around a storage.TargetStorage
@@ -36,6 +57,36 @@ func newStorageClient(db storage.TargetStorage, l *log.Logger) *storageClient {
 	return &storageClient{db, l}
 }
 
+// lookupError returns the error of a failed lookup of a single row of
+// the data described by what. Rows that do not exist are not found.
+func lookupError(err error, what string) error {
+	if errors.Is(err, pgx.ErrNoRows) {
+		return common.NewNotFoundError(what)
+	}
+	return common.ErrStorageError
+}
+
+// blockLookupError returns the error of a failed lookup of the block
+// at the height of a request. Blocks above the latest indexed block
+// may exist, but are not yet indexed.
+func (c *storageClient) blockLookupError(ctx context.Context, err error, r *http.Request) error {
+	if !errors.Is(err, pgx.ErrNoRows) {
+		return common.ErrStorageError
+	}
+	height, err := strconv.ParseInt(chi.URLParam(r, "height"), 10, 64)
+	if err != nil {
+		return common.NewNotFoundError("block")
+	}
+	status, err := c.Status(ctx)
+	if err != nil {
+		return err
+	}
+	if height > status.LatestBlock {
+		return common.ErrNotIndexed
+	}
+	return common.NewNotFoundError("block")
+}
+
 // Status returns status information for the Oasis Indexer.
 func (c *storageClient) Status(ctx context.Context) (*Status, error) {
 	qf := NewQueryFactory(strcase.ToSnake(LatestChainID))
@@ -56,6 +107,29 @@ func (c *storageClient) Status(ctx context.Context) (*Status, error) {
 	return &s, nil
 }
 
+// LatestProcessedTime returns the time the latest block was processed,
+// by any analyzer.
+func (c *storageClient) LatestProcessedTime(ctx context.Context) (time.Time, error) {
+	cid, ok := ctx.Value(ChainIDContextKey).(string)
+	if !ok {
+		return time.Time{}, common.ErrBadChainID
+	}
+	qf := NewQueryFactory(cid)
+
+	var t time.Time
+	if err := c.db.QueryRow(
+		ctx,
+		qf.LatestProcessedTimeQuery(),
+	).Scan(&t); err != nil {
+		c.logger.Info("row scan failed",
+			"request_id", ctx.Value(RequestIDContextKey),
+			"err", err.Error(),
+		)
+		return time.Time{}, common.ErrStorageError
+	}
+	return t, nil
+}
+
 // Blocks returns a list of consensus blocks.
 func (c *storageClient) Blocks(ctx context.Context, r *http.Request) (*BlockList, error) {
 	cid, ok := ctx.Value(ChainIDContextKey).(string)
@@ -149,7 +223,7 @@ func (c *storageClient) Block(ctx context.Context, r *http.Request) (*Block, err
 			"request_id", ctx.Value(RequestIDContextKey),
 			"err", err.Error(),
 		)
-		return nil, common.ErrStorageError
+		return nil, c.blockLookupError(ctx, err, r)
 	}
 	b.Timestamp = b.Timestamp.UTC()
 
@@ -266,7 +340,7 @@ func (c *storageClient) Transaction(ctx context.Context, r *http.Request) (*Tran
 	if err := c.db.QueryRow(
 		ctx,
 		qf.TransactionQuery(),
-		chi.URLParam(r, "txn_hash"),
+		chi.URLParam(r, "tx_hash"),
 	).Scan(
 		&t.Height,
 		&t.Hash,
@@ -281,7 +355,7 @@ func (c *storageClient) Transaction(ctx context.Context, r *http.Request) (*Tran
 			"request_id", ctx.Value(RequestIDContextKey),
 			"err", err.Error(),
 		)
-		return nil, common.ErrStorageError
+		return nil, lookupError(err, "transaction")
 	}
 	if code == oasisErrors.CodeNoError {
 		t.Success = true
@@ -307,11 +381,19 @@ func (c *storageClient) Entities(ctx context.Context, r *http.Request) (*EntityL
 		return nil, common.ErrBadRequest
 	}
 
+	clauses, err := entitiesListSpec.parse(r.URL.Query(), pagination, 1)
+	if err != nil {
+		c.logger.Info("malformed list parameters",
+			"request_id", ctx.Value(RequestIDContextKey),
+			"err", err.Error(),
+		)
+		return nil, common.ErrBadRequest
+	}
+
 	rows, err := c.db.Query(
 		ctx,
-		qf.EntitiesQuery(),
-		pagination.Limit,
-		pagination.Offset,
+		qf.EntitiesListQuery(clauses),
+		append(clauses.args, pagination.Limit, pagination.Offset)...,
 	)
 	if err != nil {
 		c.logger.Info("query failed",
@@ -327,7 +409,7 @@ func (c *storageClient) Entities(ctx context.Context, r *http.Request) (*EntityL
 	}
 	for rows.Next() {
 		var e Entity
-		if err := rows.Scan(&e.ID, &e.Address); err != nil {
+		if err := scanEntity(rows, &e); err != nil {
 			c.logger.Info("query failed",
 				"err", err.Error(),
 			)
@@ -354,16 +436,16 @@ func (c *storageClient) Entity(ctx context.Context, r *http.Request) (*Entity, e
 	}
 
 	var e Entity
-	if err = c.db.QueryRow(
+	if err = scanEntity(c.db.QueryRow(
 		ctx,
 		qf.EntityQuery(),
 		entityID,
-	).Scan(&e.ID, &e.Address); err != nil {
+	), &e); err != nil {
 		c.logger.Info("row scan failed",
 			"request_id", ctx.Value(RequestIDContextKey),
 			"err", err.Error(),
 		)
-		return nil, common.ErrStorageError
+		return nil, lookupError(err, "entity")
 	}
 
 	entityID, err = url.PathUnescape(chi.URLParam(r, "entity_id"))
@@ -418,16 +500,27 @@ func (c *storageClient) EntityNodes(ctx context.Context, r *http.Request) (*Node
 		return nil, common.ErrBadRequest
 	}
 
+	clauses, err := entityNodesListSpec.parse(r.URL.Query(), pagination, 3)
+	if err != nil {
+		c.logger.Info("malformed list parameters",
+			"request_id", ctx.Value(RequestIDContextKey),
+			"err", err.Error(),
+		)
+		return nil, common.ErrBadRequest
+	}
+
 	id, err := url.PathUnescape(chi.URLParam(r, "entity_id"))
 	if err != nil {
 		return nil, common.ErrBadRequest
 	}
+	var role *string
+	if v := r.URL.Query().Get("role"); v != "" {
+		role = &v
+	}
 	rows, err := c.db.Query(
 		ctx,
-		qf.EntityNodesQuery(),
-		id,
-		pagination.Limit,
-		pagination.Offset,
+		qf.EntityNodesListQuery(clauses),
+		append([]interface{}{id, role}, append(clauses.args, pagination.Limit, pagination.Offset)...)...,
 	)
 	if err != nil {
 		c.logger.Info("query failed",
@@ -443,16 +536,7 @@ func (c *storageClient) EntityNodes(ctx context.Context, r *http.Request) (*Node
 	}
 	for rows.Next() {
 		var n Node
-		if err := rows.Scan(
-			&n.ID,
-			&n.EntityID,
-			&n.Expiration,
-			&n.TLSPubkey,
-			&n.TLSNextPubkey,
-			&n.P2PPubkey,
-			&n.ConsensusPubkey,
-			&n.Roles,
-		); err != nil {
+		if err := scanNode(rows, &n); err != nil {
 			c.logger.Info("row scan failed",
 				"request_id", ctx.Value(RequestIDContextKey),
 				"err", err.Error(),
@@ -484,74 +568,30 @@ func (c *storageClient) EntityNode(ctx context.Context, r *http.Request) (*Node,
 		return nil, common.ErrBadRequest
 	}
 	var n Node
-	if err := c.db.QueryRow(
+	if err := scanNode(c.db.QueryRow(
 		ctx,
 		qf.EntityNodeQuery(),
 		entityID,
 		nodeID,
-	).Scan(
-		&n.ID,
-		&n.EntityID,
-		&n.Expiration,
-		&n.TLSPubkey,
-		&n.TLSNextPubkey,
-		&n.P2PPubkey,
-		&n.ConsensusPubkey,
-		&n.Roles,
-	); err != nil {
+	), &n); err != nil {
 		c.logger.Info("row scan failed",
 			"request_id", ctx.Value(RequestIDContextKey),
 			"err", err.Error(),
 		)
-		return nil, common.ErrStorageError
+		return nil, lookupError(err, "node")
 	}
 
 	return &n, nil
 }
 
-// Accounts returns a list of consensus accounts.
-func (c *storageClient) Accounts(ctx context.Context, r *http.Request) (*AccountList, error) {
+// EntityNodeHistory returns the history of a node controlled by an entity.
+func (c *storageClient) EntityNodeHistory(ctx context.Context, r *http.Request) (*NodeHistory, error) {
 	cid, ok := ctx.Value(ChainIDContextKey).(string)
 	if !ok {
 		return nil, common.ErrBadChainID
 	}
 	qf := NewQueryFactory(cid)
 
-	params := r.URL.Query()
-
-	var minAvailable *string
-	if v := params.Get("minAvailable"); v != "" {
-		minAvailable = &v
-	}
-	var maxAvailable *string
-	if v := params.Get("maxAvailable"); v != "" {
-		maxAvailable = &v
-	}
-	var minEscrow *string
-	if v := params.Get("minEscrow"); v != "" {
-		minEscrow = &v
-	}
-	var maxEscrow *string
-	if v := params.Get("maxEscrow"); v != "" {
-		maxEscrow = &v
-	}
-	var minDebonding *string
-	if v := params.Get("minDebonding"); v != "" {
-		minDebonding = &v
-	}
-	var maxDebonding *string
-	if v := params.Get("maxDebonding"); v != "" {
-		maxDebonding = &v
-	}
-	var minTotalBalance *string
-	if v := params.Get("minTotalBalance"); v != "" {
-		minTotalBalance = &v
-	}
-	var maxTotalBalance *string
-	if v := params.Get("maxTotalBalance"); v != "" {
-		maxTotalBalance = &v
-	}
-
 	pagination, err := common.NewPagination(r)
 	if err != nil {
 		c.logger.Info("pagination failed",
@@ -561,17 +601,38 @@ func (c *storageClient) Accounts(ctx context.Context, r *http.Request) (*Account
 		return nil, common.ErrBadRequest
 	}
 
+	entityID, err := url.PathUnescape(chi.URLParam(r, "entity_id"))
+	if err != nil {
+		return nil, common.ErrBadRequest
+	}
+	nodeID, err := url.PathUnescape(chi.URLParam(r, "node_id"))
+	if err != nil {
+		return nil, common.ErrBadRequest
+	}
+
+	// Expired nodes are no longer stored, but their history is.
+	h := NodeHistory{
+		EntityID: entityID,
+		Events:   []NodeHistoryEvent{},
+	}
+	if err := c.db.QueryRow(
+		ctx,
+		qf.EntityNodeHistoryLookupQuery(),
+		entityID,
+		nodeID,
+	).Scan(&h.NodeID); err != nil {
+		c.logger.Info("row scan failed",
+			"request_id", ctx.Value(RequestIDContextKey),
+			"err", err.Error(),
+		)
+		return nil, lookupError(err, "node")
+	}
+
 	rows, err := c.db.Query(
 		ctx,
-		qf.AccountsQuery(),
-		minAvailable,
-		maxAvailable,
-		minEscrow,
-		maxEscrow,
-		minDebonding,
-		maxDebonding,
-		minTotalBalance,
-		maxTotalBalance,
+		qf.EntityNodeHistoryQuery(),
+		entityID,
+		nodeID,
 		pagination.Limit,
 		pagination.Offset,
 	)
@@ -584,17 +645,18 @@ func (c *storageClient) Accounts(ctx context.Context, r *http.Request) (*Account
 	}
 	defer rows.Close()
 
-	as := AccountList{
-		Accounts: []Account{},
-	}
 	for rows.Next() {
-		var a Account
+		var e NodeHistoryEvent
 		if err := rows.Scan(
-			&a.Address,
-			&a.Nonce,
-			&a.Available,
-			&a.Escrow,
-			&a.Debonding,
+			&e.Height,
+			&e.Event,
+			&e.Expiration,
+			&e.SoftwareVersion,
+			&e.Roles,
+			&e.TLSAddresses,
+			&e.P2PAddresses,
+			&e.ConsensusAddress,
+			&e.FreezeEnd,
 		); err != nil {
 			c.logger.Info("row scan failed",
 				"request_id", ctx.Value(RequestIDContextKey),
@@ -603,78 +665,84 @@ func (c *storageClient) Accounts(ctx context.Context, r *http.Request) (*Account
 			return nil, common.ErrStorageError
 		}
 
-		as.Accounts = append(as.Accounts, a)
+		h.Events = append(h.Events, e)
 	}
 
-	return &as, nil
+	return &h, nil
 }
 
-// Account returns a consensus account.
-func (c *storageClient) Account(ctx context.Context, r *http.Request) (*Account, error) {
-	cid, ok := ctx.Value(ChainIDContextKey).(string)
-	if !ok {
-		return nil, common.ErrBadChainID
+// runtimeName returns the name of the ParaTime with the provided ID, if it
+// is known on any network.
+func runtimeName(id string) string {
+	for _, network := range oasisConfig.DefaultNetworks.All {
+		for name, paratime := range network.ParaTimes.All {
+			if paratime.ID == id {
+				return name
+			}
+		}
 	}
-	qf := NewQueryFactory(cid)
+	return ""
+}
 
-	a := Account{
-		Allowances: []Allowance{},
-	}
-	if err := c.db.QueryRow(
-		ctx,
-		qf.AccountQuery(),
-		chi.URLParam(r, "address"),
-	).Scan(
-		&a.Address,
-		&a.Nonce,
-		&a.Available,
-		&a.Escrow,
-		&a.Debonding,
-		&a.DelegationsBalance,
-		&a.DebondingDelegationsBalance,
+// scanEntity scans a row of entity columns.
+func scanEntity(row storage.QueryResult, e *Entity) error {
+	if err := row.Scan(
+		&e.ID,
+		&e.Address,
+		&e.Media,
+		&e.Escrow,
+		&e.Delegators,
 	); err != nil {
-		c.logger.Info("row scan failed",
-			"request_id", ctx.Value(RequestIDContextKey),
-			"err", err.Error(),
-		)
-		return nil, common.ErrStorageError
+		return err
 	}
-
-	allowanceRows, err := c.db.Query(
-		ctx,
-		qf.AccountAllowancesQuery(),
-		chi.URLParam(r, "address"),
-	)
-	if err != nil {
-		c.logger.Info("query failed",
-			"request_id", ctx.Value(RequestIDContextKey),
-			"err", err.Error(),
-		)
-		return nil, common.ErrStorageError
+	if e.Media != nil {
+		e.Name = e.Media.Name
 	}
-	defer allowanceRows.Close()
 
-	for allowanceRows.Next() {
-		var al Allowance
-		if err := allowanceRows.Scan(
-			&al.Address,
-			&al.Amount,
-		); err != nil {
-			c.logger.Info("row scan failed",
-				"request_id", ctx.Value(RequestIDContextKey),
-				"err", err.Error(),
-			)
-			return nil, common.ErrStorageError
-		}
+	return nil
+}
 
-		a.Allowances = append(a.Allowances, al)
+// scanNode scans a row of node columns.
+func scanNode(row storage.QueryResult, n *Node) error {
+	return row.Scan(
+		&n.ID,
+		&n.EntityID,
+		&n.Expiration,
+		&n.TLSPubkey,
+		&n.TLSNextPubkey,
+		&n.P2PPubkey,
+		&n.ConsensusPubkey,
+		&n.Roles,
+		&n.TLSAddresses,
+		&n.P2PAddresses,
+		&n.ConsensusAddresses,
+		&n.VRFPubkey,
+		&n.SoftwareVersion,
+		&n.Runtimes,
+		&n.FreezeEnd,
+	)
+}
+
+// scanRuntime scans a row of runtime columns.
+func scanRuntime(row storage.QueryResult, rt *Runtime) error {
+	if err := row.Scan(
+		&rt.ID,
+		&rt.Kind,
+		&rt.TEEHardware,
+		&rt.KeyManager,
+		&rt.Suspended,
+		&rt.GenesisRound,
+		&rt.GenesisStateRoot,
+	); err != nil {
+		return err
 	}
+	rt.Name = runtimeName(rt.ID)
 
-	return &a, nil
+	return nil
 }
 
-// Delegations returns a list of delegations.
-func (c *storageClient) Delegations(ctx context.Context, r *http.Request) (*DelegationList, error) {
+// Runtimes returns a list of registered runtimes.
+func (c *storageClient) Runtimes(ctx context.Context, r *http.Request) (*RuntimeList, error) {
 	cid, ok := ctx.Value(ChainIDContextKey).(string)
 	if !ok {
 		return nil, common.ErrBadChainID
@@ -692,8 +760,7 @@ func (c *storageClient) Delegations(ctx context.Context, r *http.Request) (*Dele
 
 	rows, err := c.db.Query(
 		ctx,
-		qf.DelegationsQuery(),
-		chi.URLParam(r, "address"),
+		qf.RuntimesQuery(),
 		pagination.Limit,
 		pagination.Offset,
 	)
@@ -706,19 +773,12 @@ func (c *storageClient) Delegations(ctx context.Context, r *http.Request) (*Dele
 	}
 	defer rows.Close()
 
-	ds := DelegationList{
-		Delegations: []Delegation{},
+	rs := RuntimeList{
+		Runtimes: []Runtime{},
 	}
 	for rows.Next() {
-		var d Delegation
-		var escrowBalanceActive uint64
-		var escrowTotalSharesActive uint64
-		if err := rows.Scan(
-			&d.ValidatorAddress,
-			&d.Shares,
-			&escrowBalanceActive,
-			&escrowTotalSharesActive,
-		); err != nil {
+		var rt Runtime
+		if err := scanRuntime(rows, &rt); err != nil {
 			c.logger.Info("row scan failed",
 				"request_id", ctx.Value(RequestIDContextKey),
 				"err", err.Error(),
@@ -726,38 +786,50 @@ func (c *storageClient) Delegations(ctx context.Context, r *http.Request) (*Dele
 			return nil, common.ErrStorageError
 		}
 
-		d.Amount = uint64(float64(d.Shares) * float64(escrowBalanceActive) / float64(escrowTotalSharesActive))
-
-		ds.Delegations = append(ds.Delegations, d)
+		rs.Runtimes = append(rs.Runtimes, rt)
 	}
 
-	return &ds, nil
+	return &rs, nil
 }
 
-// DebondingDelegations returns a list of debonding delegations.
-func (c *storageClient) DebondingDelegations(ctx context.Context, r *http.Request) (*DebondingDelegationList, error) {
+// Runtime returns a registered runtime, along with its current committees
+// and its history.
+func (c *storageClient) Runtime(ctx context.Context, r *http.Request) (*Runtime, error) {
 	cid, ok := ctx.Value(ChainIDContextKey).(string)
 	if !ok {
 		return nil, common.ErrBadChainID
 	}
 	qf := NewQueryFactory(cid)
 
-	pagination, err := common.NewPagination(r)
-	if err != nil {
-		c.logger.Info("pagination failed",
+	var rt Runtime
+	if err := scanRuntime(c.db.QueryRow(
+		ctx,
+		qf.RuntimeQuery(),
+		chi.URLParam(r, "runtime_id"),
+	), &rt); err != nil {
+		c.logger.Info("row scan failed",
 			"request_id", ctx.Value(RequestIDContextKey),
 			"err", err.Error(),
 		)
-		return nil, common.ErrBadRequest
+		return nil, lookupError(err, "runtime")
 	}
 
-	rows, err := c.db.Query(
-		ctx,
-		qf.DebondingDelegationsQuery(),
-		chi.URLParam(r, "address"),
-		pagination.Limit,
-		pagination.Offset,
-	)
+	// Runtimes indexed by a runtime analyzer have their latest round.
+	if rt.Name == analyzer.RuntimeEmerald.String() {
+		var round int64
+		if err := c.db.QueryRow(ctx, qf.LatestEmeraldRoundQuery()).Scan(&round); err != nil {
+			c.logger.Info("row scan failed",
+				"request_id", ctx.Value(RequestIDContextKey),
+				"err", err.Error(),
+			)
+			return nil, common.ErrStorageError
+		}
+		if round != 0 {
+			rt.LatestRound = &round
+		}
+	}
+
+	committeeRows, err := c.db.Query(ctx, qf.RuntimeCommitteesQuery(), rt.ID)
 	if err != nil {
 		c.logger.Info("query failed",
 			"request_id", ctx.Value(RequestIDContextKey),
@@ -765,21 +837,17 @@ func (c *storageClient) DebondingDelegations(ctx context.Context, r *http.Reques
 		)
 		return nil, common.ErrStorageError
 	}
-	defer rows.Close()
-
-	ds := DebondingDelegationList{
-		DebondingDelegations: []DebondingDelegation{},
-	}
-	for rows.Next() {
-		var d DebondingDelegation
-		var escrowBalanceDebonding uint64
-		var escrowTotalSharesDebonding uint64
-		if err := rows.Scan(
-			&d.ValidatorAddress,
-			&d.Shares,
-			&d.DebondEnd,
-			&escrowBalanceDebonding,
-			&escrowTotalSharesDebonding,
+	defer committeeRows.Close()
+
+	rt.Committees = []CommitteeMember{}
+	for committeeRows.Next() {
+		var m CommitteeMember
+		if err := committeeRows.Scan(
+			&m.Runtime,
+			&m.Kind,
+			&m.NodeID,
+			&m.EntityID,
+			&m.Role,
 		); err != nil {
 			c.logger.Info("row scan failed",
 				"request_id", ctx.Value(RequestIDContextKey),
@@ -787,15 +855,21 @@ func (c *storageClient) DebondingDelegations(ctx context.Context, r *http.Reques
 			)
 			return nil, common.ErrStorageError
 		}
-		d.Amount = uint64(float64(d.Shares) * float64(escrowBalanceDebonding) / float64(escrowTotalSharesDebonding))
-		ds.DebondingDelegations = append(ds.DebondingDelegations, d)
+
+		rt.Committees = append(rt.Committees, m)
 	}
 
-	return &ds, nil
+	// Only the latest events are included, the rest are paginated on
+	// their own.
+	if rt.History, err = c.runtimeHistory(ctx, qf, rt.ID, runtimeHistoryLimit, 0); err != nil {
+		return nil, err
+	}
+
+	return &rt, nil
 }
 
-// Epochs returns a list of consensus epochs.
-func (c *storageClient) Epochs(ctx context.Context, r *http.Request) (*EpochList, error) {
+// RuntimeHistory returns the history of a runtime.
+func (c *storageClient) RuntimeHistory(ctx context.Context, r *http.Request) (*RuntimeHistory, error) {
 	cid, ok := ctx.Value(ChainIDContextKey).(string)
 	if !ok {
 		return nil, common.ErrBadChainID
@@ -811,12 +885,30 @@ func (c *storageClient) Epochs(ctx context.Context, r *http.Request) (*EpochList
 		return nil, common.ErrBadRequest
 	}
 
-	rows, err := c.db.Query(
+	var h RuntimeHistory
+	if err := c.db.QueryRow(
 		ctx,
-		qf.EpochsQuery(),
-		pagination.Limit,
-		pagination.Offset,
-	)
+		qf.RuntimeHistoryLookupQuery(),
+		chi.URLParam(r, "runtime_id"),
+	).Scan(&h.RuntimeID); err != nil {
+		c.logger.Info("row scan failed",
+			"request_id", ctx.Value(RequestIDContextKey),
+			"err", err.Error(),
+		)
+		return nil, lookupError(err, "runtime")
+	}
+
+	if h.Events, err = c.runtimeHistory(ctx, qf, h.RuntimeID, pagination.Limit, pagination.Offset); err != nil {
+		return nil, err
+	}
+
+	return &h, nil
+}
+
+// runtimeHistory returns a page of the events of a runtime, from the most
+// recent.
+func (c *storageClient) runtimeHistory(ctx context.Context, qf QueryFactory, id string, limit uint64, offset uint64) ([]RuntimeHistoryEvent, error) {
+	rows, err := c.db.Query(ctx, qf.RuntimeHistoryQuery(), id, limit, offset)
 	if err != nil {
 		c.logger.Info("query failed",
 			"request_id", ctx.Value(RequestIDContextKey),
@@ -824,56 +916,30 @@ func (c *storageClient) Epochs(ctx context.Context, r *http.Request) (*EpochList
 		)
 		return nil, common.ErrStorageError
 	}
+	defer rows.Close()
 
-	es := EpochList{
-		Epochs: []Epoch{},
-	}
+	events := []RuntimeHistoryEvent{}
 	for rows.Next() {
-		var e Epoch
-		var endHeight *uint64
-		if err := rows.Scan(&e.ID, &e.StartHeight, &endHeight); err != nil {
+		var e RuntimeHistoryEvent
+		if err := rows.Scan(
+			&e.Height,
+			&e.Event,
+		); err != nil {
 			c.logger.Info("row scan failed",
 				"request_id", ctx.Value(RequestIDContextKey),
 				"err", err.Error(),
 			)
 			return nil, common.ErrStorageError
 		}
-		if endHeight != nil {
-			e.EndHeight = *endHeight
-		}
-
-		es.Epochs = append(es.Epochs, e)
-	}
-
-	return &es, nil
-}
-
-// Epoch returns a consensus epoch.
-func (c *storageClient) Epoch(ctx context.Context, r *http.Request) (*Epoch, error) {
-	cid, ok := ctx.Value(ChainIDContextKey).(string)
-	if !ok {
-		return nil, common.ErrBadChainID
-	}
-	qf := NewQueryFactory(cid)
 
-	var e Epoch
-	if err := c.db.QueryRow(
-		ctx,
-		qf.EpochQuery(),
-		chi.URLParam(r, "epoch"),
-	).Scan(&e.ID, &e.StartHeight, &e.EndHeight); err != nil {
-		c.logger.Info("row scan failed",
-			"request_id", ctx.Value(RequestIDContextKey),
-			"err", err.Error(),
-		)
-		return nil, common.ErrStorageError
+		events = append(events, e)
 	}
 
-	return &e, nil
+	return events, nil
 }
 
-// Proposals returns a list of governance proposals.
-func (c *storageClient) Proposals(ctx context.Context, r *http.Request) (*ProposalList, error) {
+// Accounts returns a list of consensus accounts.
+func (c *storageClient) Accounts(ctx context.Context, r *http.Request) (*AccountList, error) {
 	cid, ok := ctx.Value(ChainIDContextKey).(string)
 	if !ok {
 		return nil, common.ErrBadChainID
@@ -882,13 +948,37 @@ func (c *storageClient) Proposals(ctx context.Context, r *http.Request) (*Propos
 
 	params := r.URL.Query()
 
-	var submitter *string
-	if v := params.Get("submitter"); v != "" {
-		submitter = &v
+	var minAvailable *string
+	if v := params.Get("minAvailable"); v != "" {
+		minAvailable = &v
 	}
-	var state *string
-	if v := params.Get("state"); v != "" {
-		state = &v
+	var maxAvailable *string
+	if v := params.Get("maxAvailable"); v != "" {
+		maxAvailable = &v
+	}
+	var minEscrow *string
+	if v := params.Get("minEscrow"); v != "" {
+		minEscrow = &v
+	}
+	var maxEscrow *string
+	if v := params.Get("maxEscrow"); v != "" {
+		maxEscrow = &v
+	}
+	var minDebonding *string
+	if v := params.Get("minDebonding"); v != "" {
+		minDebonding = &v
+	}
+	var maxDebonding *string
+	if v := params.Get("maxDebonding"); v != "" {
+		maxDebonding = &v
+	}
+	var minTotalBalance *string
+	if v := params.Get("minTotalBalance"); v != "" {
+		minTotalBalance = &v
+	}
+	var maxTotalBalance *string
+	if v := params.Get("maxTotalBalance"); v != "" {
+		maxTotalBalance = &v
 	}
 
 	pagination, err := common.NewPagination(r)
@@ -900,13 +990,32 @@ func (c *storageClient) Proposals(ctx context.Context, r *http.Request) (*Propos
 		return nil, common.ErrBadRequest
 	}
 
+	clauses, err := accountsListSpec.parse(params, pagination, 9)
+	if err != nil {
+		c.logger.Info("malformed list parameters",
+			"request_id", ctx.Value(RequestIDContextKey),
+			"err", err.Error(),
+		)
+		return nil, common.ErrBadRequest
+	}
+
+	args := []interface{}{
+		minAvailable,
+		maxAvailable,
+		minEscrow,
+		maxEscrow,
+		minDebonding,
+		maxDebonding,
+		minTotalBalance,
+		maxTotalBalance,
+	}
+	args = append(args, clauses.args...)
+	args = append(args, pagination.Limit, pagination.Offset)
+
 	rows, err := c.db.Query(
 		ctx,
-		qf.ProposalsQuery(),
-		submitter,
-		state,
-		pagination.Limit,
-		pagination.Offset,
+		qf.AccountsListQuery(clauses),
+		args...,
 	)
 	if err != nil {
 		c.logger.Info("query failed",
@@ -917,25 +1026,17 @@ func (c *storageClient) Proposals(ctx context.Context, r *http.Request) (*Propos
 	}
 	defer rows.Close()
 
-	ps := ProposalList{
-		Proposals: []Proposal{},
+	as := AccountList{
+		Accounts: []Account{},
 	}
 	for rows.Next() {
-		var p Proposal
+		var a Account
 		if err := rows.Scan(
-			&p.ID,
-			&p.Submitter,
-			&p.State,
-			&p.Deposit,
-			&p.Handler,
-			&p.Target.ConsensusProtocol,
-			&p.Target.RuntimeHostProtocol,
-			&p.Target.RuntimeCommitteeProtocol,
-			&p.Epoch,
-			&p.Cancels,
-			&p.CreatedAt,
-			&p.ClosesAt,
-			&p.InvalidVotes,
+			&a.Address,
+			&a.Nonce,
+			&a.Available,
+			&a.Escrow,
+			&a.Debonding,
 		); err != nil {
 			c.logger.Info("row scan failed",
 				"request_id", ctx.Value(RequestIDContextKey),
@@ -944,52 +1045,78 @@ func (c *storageClient) Proposals(ctx context.Context, r *http.Request) (*Propos
 			return nil, common.ErrStorageError
 		}
 
-		ps.Proposals = append(ps.Proposals, p)
+		as.Accounts = append(as.Accounts, a)
 	}
 
-	return &ps, nil
+	return &as, nil
 }
 
-// Proposal returns a governance proposal.
-func (c *storageClient) Proposal(ctx context.Context, r *http.Request) (*Proposal, error) {
+// Account returns a consensus account.
+func (c *storageClient) Account(ctx context.Context, r *http.Request) (*Account, error) {
 	cid, ok := ctx.Value(ChainIDContextKey).(string)
 	if !ok {
 		return nil, common.ErrBadChainID
 	}
 	qf := NewQueryFactory(cid)
 
-	var p Proposal
+	a := Account{
+		Allowances: []Allowance{},
+	}
 	if err := c.db.QueryRow(
 		ctx,
-		qf.ProposalQuery(),
-		chi.URLParam(r, "proposal_id"),
+		qf.AccountQuery(),
+		chi.URLParam(r, "address"),
 	).Scan(
-		&p.ID,
-		&p.Submitter,
-		&p.State,
-		&p.Deposit,
-		&p.Handler,
-		&p.Target.ConsensusProtocol,
-		&p.Target.RuntimeHostProtocol,
-		&p.Target.RuntimeCommitteeProtocol,
-		&p.Epoch,
-		&p.Cancels,
-		&p.CreatedAt,
-		&p.ClosesAt,
-		&p.InvalidVotes,
+		&a.Address,
+		&a.Nonce,
+		&a.Available,
+		&a.Escrow,
+		&a.Debonding,
+		&a.DelegationsBalance,
+		&a.DebondingDelegationsBalance,
 	); err != nil {
 		c.logger.Info("row scan failed",
 			"request_id", ctx.Value(RequestIDContextKey),
 			"err", err.Error(),
 		)
+		return nil, lookupError(err, "account")
+	}
+
+	allowanceRows, err := c.db.Query(
+		ctx,
+		qf.AccountAllowancesQuery(),
+		chi.URLParam(r, "address"),
+	)
+	if err != nil {
+		c.logger.Info("query failed",
+			"request_id", ctx.Value(RequestIDContextKey),
+			"err", err.Error(),
+		)
 		return nil, common.ErrStorageError
 	}
+	defer allowanceRows.Close()
 
-	return &p, nil
+	for allowanceRows.Next() {
+		var al Allowance
+		if err := allowanceRows.Scan(
+			&al.Address,
+			&al.Amount,
+		); err != nil {
+			c.logger.Info("row scan failed",
+				"request_id", ctx.Value(RequestIDContextKey),
+				"err", err.Error(),
+			)
+			return nil, common.ErrStorageError
+		}
+
+		a.Allowances = append(a.Allowances, al)
+	}
+
+	return &a, nil
 }
 
-// ProposalVotes returns votes for a governance proposal.
-func (c *storageClient) ProposalVotes(ctx context.Context, r *http.Request) (*ProposalVotes, error) {
+// Delegations returns a list of delegations.
+func (c *storageClient) Delegations(ctx context.Context, r *http.Request) (*DelegationList, error) {
 	cid, ok := ctx.Value(ChainIDContextKey).(string)
 	if !ok {
 		return nil, common.ErrBadChainID
@@ -1005,15 +1132,10 @@ func (c *storageClient) ProposalVotes(ctx context.Context, r *http.Request) (*Pr
 		return nil, common.ErrBadRequest
 	}
 
-	id, err := strconv.ParseUint(chi.URLParam(r, "proposal_id"), 10, 64)
-	if err != nil {
-		return nil, common.ErrBadRequest
-	}
-
 	rows, err := c.db.Query(
 		ctx,
-		qf.ProposalVotesQuery(),
-		id,
+		qf.DelegationsQuery(),
+		chi.URLParam(r, "address"),
 		pagination.Limit,
 		pagination.Offset,
 	)
@@ -1026,59 +1148,55 @@ func (c *storageClient) ProposalVotes(ctx context.Context, r *http.Request) (*Pr
 	}
 	defer rows.Close()
 
-	vs := ProposalVotes{
-		Votes: []ProposalVote{},
+	ds := DelegationList{
+		Delegations: []Delegation{},
 	}
 	for rows.Next() {
-		var v ProposalVote
+		var d Delegation
+		var escrowBalanceActive uint64
+		var escrowTotalSharesActive uint64
 		if err := rows.Scan(
-			&v.Address,
-			&v.Vote,
-		); err != nil {
-			c.logger.Info("row scan failed",
-				"request_id", ctx.Value(RequestIDContextKey),
+			&d.ValidatorAddress,
+			&d.Shares,
+			&escrowBalanceActive,
+			&escrowTotalSharesActive,
+		); err != nil {
+			c.logger.Info("row scan failed",
+				"request_id", ctx.Value(RequestIDContextKey),
 				"err", err.Error(),
 			)
 			return nil, common.ErrStorageError
 		}
 
-		vs.Votes = append(vs.Votes, v)
+		d.Amount = uint64(float64(d.Shares) * float64(escrowBalanceActive) / float64(escrowTotalSharesActive))
+
+		ds.Delegations = append(ds.Delegations, d)
 	}
-	vs.ProposalID = id
 
-	return &vs, nil
+	return &ds, nil
 }
 
-// Validators returns a list of validators.
-func (c *storageClient) Validators(ctx context.Context, r *http.Request) (*ValidatorList, error) {
+// DebondingDelegations returns a list of debonding delegations.
+func (c *storageClient) DebondingDelegations(ctx context.Context, r *http.Request) (*DebondingDelegationList, error) {
 	cid, ok := ctx.Value(ChainIDContextKey).(string)
 	if !ok {
 		return nil, common.ErrBadChainID
 	}
 	qf := NewQueryFactory(cid)
 
-	var epoch Epoch
-	if err := c.db.QueryRow(
-		ctx,
-		qf.ValidatorsQuery(),
-	).Scan(&epoch.ID, &epoch.StartHeight); err != nil {
-		c.logger.Info("row scan failed",
+	pagination, err := common.NewPagination(r)
+	if err != nil {
+		c.logger.Info("pagination failed",
 			"request_id", ctx.Value(RequestIDContextKey),
 			"err", err.Error(),
 		)
-		return nil, common.ErrStorageError
-	}
-
-	order := "voting_power"
-	pagination := common.Pagination{
-		Order:  &order,
-		Limit:  1000,
-		Offset: 0,
+		return nil, common.ErrBadRequest
 	}
 
 	rows, err := c.db.Query(
 		ctx,
-		qf.ValidatorsDataQuery(),
+		qf.DebondingDelegationsQuery(),
+		chi.URLParam(r, "address"),
 		pagination.Limit,
 		pagination.Offset,
 	)
@@ -1091,122 +1209,136 @@ func (c *storageClient) Validators(ctx context.Context, r *http.Request) (*Valid
 	}
 	defer rows.Close()
 
-	vs := ValidatorList{
-		Validators: []Validator{},
+	ds := DebondingDelegationList{
+		DebondingDelegations: []DebondingDelegation{},
 	}
 	for rows.Next() {
-		var v Validator
-		var schedule staking.CommissionSchedule
+		var d DebondingDelegation
+		var escrowBalanceDebonding uint64
+		var escrowTotalSharesDebonding uint64
 		if err := rows.Scan(
-			&v.EntityID,
-			&v.EntityAddress,
-			&v.NodeID,
-			&v.Escrow,
-			&schedule,
-			&v.Active,
-			&v.Status,
-			&v.Media,
+			&d.ValidatorAddress,
+			&d.Shares,
+			&d.DebondEnd,
+			&escrowBalanceDebonding,
+			&escrowTotalSharesDebonding,
 		); err != nil {
-			c.logger.Info("query failed",
+			c.logger.Info("row scan failed",
+				"request_id", ctx.Value(RequestIDContextKey),
 				"err", err.Error(),
 			)
 			return nil, common.ErrStorageError
 		}
-		// Match API for now
-		v.Name = v.Media.Name
-
-		currentRate := schedule.CurrentRate(beacon.EpochTime(epoch.ID))
-		if currentRate != nil {
-			v.CurrentRate = currentRate.ToBigInt().Uint64()
-		}
-		bound, next := util.CurrentBound(schedule, beacon.EpochTime(epoch.ID))
-		if bound != nil {
-			v.CurrentCommissionBound = ValidatorCommissionBound{
-				Lower:      bound.RateMin.ToBigInt().Uint64(),
-				Upper:      bound.RateMax.ToBigInt().Uint64(),
-				EpochStart: uint64(bound.Start),
-			}
-		}
-
-		if next > 0 {
-			v.CurrentCommissionBound.EpochEnd = next
-		}
-
-		vs.Validators = append(vs.Validators, v)
+		d.Amount = uint64(float64(d.Shares) * float64(escrowBalanceDebonding) / float64(escrowTotalSharesDebonding))
+		ds.DebondingDelegations = append(ds.DebondingDelegations, d)
 	}
 
-	return &vs, nil
+	return &ds, nil
 }
 
-// Validator returns a single validator.
-func (c *storageClient) Validator(ctx context.Context, r *http.Request) (*Validator, error) {
+// Epochs returns a list of consensus epochs.
+func (c *storageClient) Epochs(ctx context.Context, r *http.Request) (*EpochList, error) {
 	cid, ok := ctx.Value(ChainIDContextKey).(string)
 	if !ok {
 		return nil, common.ErrBadChainID
 	}
 	qf := NewQueryFactory(cid)
 
-	var epoch Epoch
-	if err := c.db.QueryRow(
-		ctx,
-		qf.ValidatorQuery(),
-	).Scan(&epoch.ID, &epoch.StartHeight); err != nil {
-		c.logger.Info("row scan failed",
+	pagination, err := common.NewPagination(r)
+	if err != nil {
+		c.logger.Info("pagination failed",
 			"request_id", ctx.Value(RequestIDContextKey),
 			"err", err.Error(),
 		)
-		return nil, common.ErrStorageError
+		return nil, common.ErrBadRequest
 	}
 
-	row := c.db.QueryRow(
+	rows, err := c.db.Query(
 		ctx,
-		qf.ValidatorDataQuery(),
-		chi.URLParam(r, "entity_id"),
+		qf.EpochsQuery(),
+		pagination.Limit,
+		pagination.Offset,
 	)
-
-	var v Validator
-	var schedule staking.CommissionSchedule
-	if err := row.Scan(
-		&v.EntityID,
-		&v.EntityAddress,
-		&v.NodeID,
-		&v.Escrow,
-		&schedule,
-		&v.Active,
-		&v.Status,
-		&v.Media,
-	); err != nil {
+	if err != nil {
 		c.logger.Info("query failed",
+			"request_id", ctx.Value(RequestIDContextKey),
 			"err", err.Error(),
 		)
 		return nil, common.ErrStorageError
 	}
-	// Match API for now
-	v.Name = v.Media.Name
 
-	currentRate := schedule.CurrentRate(beacon.EpochTime(epoch.ID))
-	if currentRate != nil {
-		v.CurrentRate = currentRate.ToBigInt().Uint64()
+	es := EpochList{
+		Epochs: []Epoch{},
 	}
-	bound, next := util.CurrentBound(schedule, beacon.EpochTime(epoch.ID))
-	if bound != nil {
-		v.CurrentCommissionBound = ValidatorCommissionBound{
-			Lower:      bound.RateMin.ToBigInt().Uint64(),
-			Upper:      bound.RateMax.ToBigInt().Uint64(),
-			EpochStart: uint64(bound.Start),
+	for rows.Next() {
+		var e Epoch
+		var endHeight *uint64
+		if err := rows.Scan(&e.ID, &e.StartHeight, &endHeight); err != nil {
+			c.logger.Info("row scan failed",
+				"request_id", ctx.Value(RequestIDContextKey),
+				"err", err.Error(),
+			)
+			return nil, common.ErrStorageError
+		}
+		if endHeight != nil {
+			e.EndHeight = *endHeight
 		}
+
+		es.Epochs = append(es.Epochs, e)
 	}
 
-	if next > 0 {
-		v.CurrentCommissionBound.EpochEnd = next
+	return &es, nil
+}
+
+// Epoch returns a consensus epoch.
+func (c *storageClient) Epoch(ctx context.Context, r *http.Request) (*Epoch, error) {
+	cid, ok := ctx.Value(ChainIDContextKey).(string)
+	if !ok {
+		return nil, common.ErrBadChainID
 	}
+	qf := NewQueryFactory(cid)
 
-	return &v, nil
+	var e Epoch
+	if err := c.db.QueryRow(
+		ctx,
+		qf.EpochQuery(),
+		chi.URLParam(r, "epoch"),
+	).Scan(&e.ID, &e.StartHeight, &e.EndHeight); err != nil {
+		c.logger.Info("row scan failed",
+			"request_id", ctx.Value(RequestIDContextKey),
+			"err", err.Error(),
+		)
+		return nil, lookupError(err, "epoch")
+	}
+
+	return &e, nil
 }
 
-// TransactionsPerSecond returns a list of tps checkpoint values.
-func (c *storageClient) TransactionsPerSecond(ctx context.Context, r *http.Request) (*TpsCheckpointList, error) {
-	qf := NewQueryFactory(strcase.ToSnake(LatestChainID))
+// lookupEpoch returns the epoch of a request, if it is indexed.
+func (c *storageClient) lookupEpoch(ctx context.Context, qf QueryFactory, r *http.Request) (uint64, error) {
+	var e Epoch
+	if err := c.db.QueryRow(
+		ctx,
+		qf.EpochQuery(),
+		chi.URLParam(r, "epoch"),
+	).Scan(&e.ID, &e.StartHeight, &e.EndHeight); err != nil {
+		c.logger.Info("row scan failed",
+			"request_id", ctx.Value(RequestIDContextKey),
+			"err", err.Error(),
+		)
+		return 0, lookupError(err, "epoch")
+	}
+
+	return e.ID, nil
+}
+
+// EpochCommittees returns the members of the runtime committees of an epoch.
+func (c *storageClient) EpochCommittees(ctx context.Context, r *http.Request) (*EpochCommittees, error) {
+	cid, ok := ctx.Value(ChainIDContextKey).(string)
+	if !ok {
+		return nil, common.ErrBadChainID
+	}
+	qf := NewQueryFactory(cid)
 
 	pagination, err := common.NewPagination(r)
 	if err != nil {
@@ -1217,9 +1349,28 @@ func (c *storageClient) TransactionsPerSecond(ctx context.Context, r *http.Reque
 		return nil, common.ErrBadRequest
 	}
 
+	epoch, err := c.lookupEpoch(ctx, qf, r)
+	if err != nil {
+		return nil, err
+	}
+
+	params := r.URL.Query()
+
+	var runtime *string
+	if v := params.Get("runtime"); v != "" {
+		runtime = &v
+	}
+	var kind *string
+	if v := params.Get("kind"); v != "" {
+		kind = &v
+	}
+
 	rows, err := c.db.Query(
 		ctx,
-		qf.TpsCheckpointQuery(),
+		qf.EpochCommitteesQuery(),
+		epoch,
+		runtime,
+		kind,
 		pagination.Limit,
 		pagination.Offset,
 	)
@@ -1232,40 +1383,39 @@ func (c *storageClient) TransactionsPerSecond(ctx context.Context, r *http.Reque
 	}
 	defer rows.Close()
 
-	ts := TpsCheckpointList{
-		IntervalMinutes: tpsWindowSizeMinutes,
-		TpsCheckpoints:  []TpsCheckpoint{},
+	cs := EpochCommittees{
+		Epoch:   epoch,
+		Members: []CommitteeMember{},
 	}
 	for rows.Next() {
-		var d struct {
-			Hour     time.Time
-			MinSlot  int
-			TxVolume uint64
-		}
+		var m CommitteeMember
 		if err := rows.Scan(
-			&d.Hour,
-			&d.MinSlot,
-			&d.TxVolume,
+			&m.Runtime,
+			&m.Kind,
+			&m.NodeID,
+			&m.EntityID,
+			&m.Role,
 		); err != nil {
-			c.logger.Info("query failed",
+			c.logger.Info("row scan failed",
+				"request_id", ctx.Value(RequestIDContextKey),
 				"err", err.Error(),
 			)
 			return nil, common.ErrStorageError
 		}
 
-		t := TpsCheckpoint{
-			Timestamp: d.Hour.Add(time.Duration(tpsWindowSizeMinutes*d.MinSlot) * time.Minute).UTC(),
-			TxVolume:  d.TxVolume,
-		}
-		ts.TpsCheckpoints = append(ts.TpsCheckpoints, t)
+		cs.Members = append(cs.Members, m)
 	}
 
-	return &ts, nil
+	return &cs, nil
 }
 
-// DailyVolumes returns a list of daily transaction volumes.
-func (c *storageClient) DailyVolumes(ctx context.Context, r *http.Request) (*VolumeList, error) {
-	qf := NewQueryFactory(strcase.ToSnake(LatestChainID))
+// EpochValidators returns the consensus validators of an epoch.
+func (c *storageClient) EpochValidators(ctx context.Context, r *http.Request) (*EpochValidators, error) {
+	cid, ok := ctx.Value(ChainIDContextKey).(string)
+	if !ok {
+		return nil, common.ErrBadChainID
+	}
+	qf := NewQueryFactory(cid)
 
 	pagination, err := common.NewPagination(r)
 	if err != nil {
@@ -1276,9 +1426,15 @@ func (c *storageClient) DailyVolumes(ctx context.Context, r *http.Request) (*Vol
 		return nil, common.ErrBadRequest
 	}
 
+	epoch, err := c.lookupEpoch(ctx, qf, r)
+	if err != nil {
+		return nil, err
+	}
+
 	rows, err := c.db.Query(
 		ctx,
-		qf.TxVolumesQuery(),
+		qf.EpochValidatorsQuery(),
+		epoch,
 		pagination.Limit,
 		pagination.Offset,
 	)
@@ -1291,24 +1447,1560 @@ func (c *storageClient) DailyVolumes(ctx context.Context, r *http.Request) (*Vol
 	}
 	defer rows.Close()
 
-	vs := VolumeList{
-		Volumes: []Volume{},
+	vs := EpochValidators{
+		Epoch:      epoch,
+		Validators: []EpochValidator{},
 	}
 	for rows.Next() {
-		var v Volume
+		var v EpochValidator
 		if err := rows.Scan(
-			&v.Date,
-			&v.TxVolume,
+			&v.NodeID,
+			&v.EntityID,
+			&v.VotingPower,
 		); err != nil {
-			c.logger.Info("query failed",
+			c.logger.Info("row scan failed",
+				"request_id", ctx.Value(RequestIDContextKey),
 				"err", err.Error(),
 			)
 			return nil, common.ErrStorageError
 		}
-		v.Date = v.Date.UTC()
 
-		vs.Volumes = append(vs.Volumes, v)
+		vs.Validators = append(vs.Validators, v)
 	}
 
 	return &vs, nil
 }
+
+// Proposals returns a list of governance proposals.
+func (c *storageClient) Proposals(ctx context.Context, r *http.Request) (*ProposalList, error) {
+	cid, ok := ctx.Value(ChainIDContextKey).(string)
+	if !ok {
+		return nil, common.ErrBadChainID
+	}
+	qf := NewQueryFactory(cid)
+
+	params := r.URL.Query()
+
+	var submitter *string
+	if v := params.Get("submitter"); v != "" {
+		submitter = &v
+	}
+	var state *string
+	if v := params.Get("state"); v != "" {
+		state = &v
+	}
+
+	pagination, err := common.NewPagination(r)
+	if err != nil {
+		c.logger.Info("pagination failed",
+			"request_id", ctx.Value(RequestIDContextKey),
+			"err", err.Error(),
+		)
+		return nil, common.ErrBadRequest
+	}
+
+	rows, err := c.db.Query(
+		ctx,
+		qf.ProposalsQuery(),
+		submitter,
+		state,
+		pagination.Limit,
+		pagination.Offset,
+	)
+	if err != nil {
+		c.logger.Info("query failed",
+			"request_id", ctx.Value(RequestIDContextKey),
+			"err", err.Error(),
+		)
+		return nil, common.ErrStorageError
+	}
+	defer rows.Close()
+
+	ps := ProposalList{
+		Proposals: []Proposal{},
+	}
+	for rows.Next() {
+		var p Proposal
+		var content *governance.ProposalContent
+		var tally proposalTally
+		if err := rows.Scan(
+			&p.ID,
+			&p.Submitter,
+			&p.State,
+			&p.Deposit,
+			&p.Handler,
+			&p.Target.ConsensusProtocol,
+			&p.Target.RuntimeHostProtocol,
+			&p.Target.RuntimeCommitteeProtocol,
+			&p.Epoch,
+			&p.Cancels,
+			&p.CreatedAt,
+			&p.ClosesAt,
+			&p.InvalidVotes,
+			&content,
+			&tally.yesStake,
+			&tally.noStake,
+			&tally.abstainStake,
+			&tally.yesVotes,
+			&tally.noVotes,
+			&tally.abstainVotes,
+		); err != nil {
+			c.logger.Info("row scan failed",
+				"request_id", ctx.Value(RequestIDContextKey),
+				"err", err.Error(),
+			)
+			return nil, common.ErrStorageError
+		}
+		p.Content = newProposalContent(content)
+		p.Results = tally.results()
+
+		ps.Proposals = append(ps.Proposals, p)
+	}
+
+	return &ps, nil
+}
+
+// Proposal returns a governance proposal.
+func (c *storageClient) Proposal(ctx context.Context, r *http.Request) (*Proposal, error) {
+	cid, ok := ctx.Value(ChainIDContextKey).(string)
+	if !ok {
+		return nil, common.ErrBadChainID
+	}
+	qf := NewQueryFactory(cid)
+
+	var p Proposal
+	var content *governance.ProposalContent
+	var tally proposalTally
+	if err := c.db.QueryRow(
+		ctx,
+		qf.ProposalQuery(),
+		chi.URLParam(r, "proposal_id"),
+	).Scan(
+		&p.ID,
+		&p.Submitter,
+		&p.State,
+		&p.Deposit,
+		&p.Handler,
+		&p.Target.ConsensusProtocol,
+		&p.Target.RuntimeHostProtocol,
+		&p.Target.RuntimeCommitteeProtocol,
+		&p.Epoch,
+		&p.Cancels,
+		&p.CreatedAt,
+		&p.ClosesAt,
+		&p.InvalidVotes,
+		&content,
+		&tally.yesStake,
+		&tally.noStake,
+		&tally.abstainStake,
+		&tally.yesVotes,
+		&tally.noVotes,
+		&tally.abstainVotes,
+	); err != nil {
+		c.logger.Info("row scan failed",
+			"request_id", ctx.Value(RequestIDContextKey),
+			"err", err.Error(),
+		)
+		return nil, lookupError(err, "proposal")
+	}
+	p.Content = newProposalContent(content)
+	p.Results = tally.results()
+
+	return &p, nil
+}
+
+// proposalTally is the stake and the number of tallied votes for each choice
+// of a proposal, of which stake is only known once the proposal is closed.
+type proposalTally struct {
+	yesStake, noStake, abstainStake *uint64
+	yesVotes, noVotes, abstainVotes uint64
+}
+
+// results returns the results of a closed proposal, or nil if the proposal
+// has not been tallied.
+func (t *proposalTally) results() *ProposalResults {
+	if t.yesStake == nil || t.noStake == nil || t.abstainStake == nil {
+		return nil
+	}
+	return &ProposalResults{
+		Yes:     VoteTally{Votes: t.yesVotes, Stake: *t.yesStake},
+		No:      VoteTally{Votes: t.noVotes, Stake: *t.noStake},
+		Abstain: VoteTally{Votes: t.abstainVotes, Stake: *t.abstainStake},
+	}
+}
+
+// newProposalContent returns the content of a proposal, or nil if it was
+// indexed before contents were.
+func newProposalContent(content *governance.ProposalContent) *ProposalContent {
+	if content == nil {
+		return nil
+	}
+	var pc ProposalContent
+	if u := content.Upgrade; u != nil {
+		consensusProtocol := u.Target.ConsensusProtocol.String()
+		runtimeHostProtocol := u.Target.RuntimeHostProtocol.String()
+		runtimeCommitteeProtocol := u.Target.RuntimeCommitteeProtocol.String()
+		pc.Upgrade = &UpgradeDescriptor{
+			Version: uint64(u.V),
+			Handler: string(u.Handler),
+			Target: Target{
+				ConsensusProtocol:        &consensusProtocol,
+				RuntimeHostProtocol:      &runtimeHostProtocol,
+				RuntimeCommitteeProtocol: &runtimeCommitteeProtocol,
+			},
+			Epoch: uint64(u.Epoch),
+		}
+	}
+	if cu := content.CancelUpgrade; cu != nil {
+		pc.CancelUpgrade = &CancelUpgradeProposal{
+			ProposalID: cu.ProposalID,
+		}
+	}
+	return &pc
+}
+
+// ProposalVotes returns votes for a governance proposal.
+func (c *storageClient) ProposalVotes(ctx context.Context, r *http.Request) (*ProposalVotes, error) {
+	cid, ok := ctx.Value(ChainIDContextKey).(string)
+	if !ok {
+		return nil, common.ErrBadChainID
+	}
+	qf := NewQueryFactory(cid)
+
+	pagination, err := common.NewPagination(r)
+	if err != nil {
+		c.logger.Info("pagination failed",
+			"request_id", ctx.Value(RequestIDContextKey),
+			"err", err.Error(),
+		)
+		return nil, common.ErrBadRequest
+	}
+
+	id, err := strconv.ParseUint(chi.URLParam(r, "proposal_id"), 10, 64)
+	if err != nil {
+		return nil, common.ErrBadRequest
+	}
+
+	rows, err := c.db.Query(
+		ctx,
+		qf.ProposalVotesQuery(),
+		id,
+		pagination.Limit,
+		pagination.Offset,
+	)
+	if err != nil {
+		c.logger.Info("query failed",
+			"request_id", ctx.Value(RequestIDContextKey),
+			"err", err.Error(),
+		)
+		return nil, common.ErrStorageError
+	}
+	defer rows.Close()
+
+	vs := ProposalVotes{
+		Votes: []ProposalVote{},
+	}
+	for rows.Next() {
+		var v ProposalVote
+		if err := rows.Scan(
+			&v.Address,
+			&v.Vote,
+			&v.Weight,
+		); err != nil {
+			c.logger.Info("row scan failed",
+				"request_id", ctx.Value(RequestIDContextKey),
+				"err", err.Error(),
+			)
+			return nil, common.ErrStorageError
+		}
+
+		vs.Votes = append(vs.Votes, v)
+	}
+	vs.ProposalID = id
+
+	return &vs, nil
+}
+
+// Validators returns a list of validators.
+func (c *storageClient) Validators(ctx context.Context, r *http.Request) (*ValidatorList, error) {
+	cid, ok := ctx.Value(ChainIDContextKey).(string)
+	if !ok {
+		return nil, common.ErrBadChainID
+	}
+	qf := NewQueryFactory(cid)
+
+	var epoch Epoch
+	if err := c.db.QueryRow(
+		ctx,
+		qf.ValidatorsQuery(),
+	).Scan(&epoch.ID, &epoch.StartHeight); err != nil {
+		c.logger.Info("row scan failed",
+			"request_id", ctx.Value(RequestIDContextKey),
+			"err", err.Error(),
+		)
+		return nil, common.ErrStorageError
+	}
+
+	pagination, err := common.NewPagination(r)
+	if err != nil {
+		c.logger.Info("pagination failed",
+			"request_id", ctx.Value(RequestIDContextKey),
+			"err", err.Error(),
+		)
+		return nil, common.ErrBadRequest
+	}
+	// Validators are listed in full unless a limit is requested.
+	if r.URL.Query().Get(common.LimitKey) == "" {
+		pagination.Limit = common.MaximumLimit
+	}
+	clauses, err := validatorsListSpec.parse(r.URL.Query(), pagination, 1)
+	if err != nil {
+		c.logger.Info("malformed list parameters",
+			"request_id", ctx.Value(RequestIDContextKey),
+			"err", err.Error(),
+		)
+		return nil, common.ErrBadRequest
+	}
+
+	rows, err := c.db.Query(
+		ctx,
+		qf.ValidatorsListQuery(clauses),
+		append(clauses.args, pagination.Limit, pagination.Offset)...,
+	)
+	if err != nil {
+		c.logger.Info("query failed",
+			"request_id", ctx.Value(RequestIDContextKey),
+			"err", err.Error(),
+		)
+		return nil, common.ErrStorageError
+	}
+	defer rows.Close()
+
+	vs := ValidatorList{
+		Validators: []Validator{},
+	}
+	for rows.Next() {
+		var v Validator
+		var schedule staking.CommissionSchedule
+		if err := rows.Scan(
+			&v.EntityID,
+			&v.EntityAddress,
+			&v.NodeID,
+			&v.Escrow,
+			&schedule,
+			&v.Active,
+			&v.Status,
+			&v.Media,
+			&v.Participation.Proposals,
+			&v.Participation.Votes,
+			&v.Delegators,
+			&v.DelegatorConcentration.Top1,
+			&v.DelegatorConcentration.Top10,
+			&v.DelegatorConcentration.Top100,
+		); err != nil {
+			c.logger.Info("query failed",
+				"err", err.Error(),
+			)
+			return nil, common.ErrStorageError
+		}
+		// Match API for now
+		v.Name = v.Media.Name
+
+		currentRate := schedule.CurrentRate(beacon.EpochTime(epoch.ID))
+		if currentRate != nil {
+			v.CurrentRate = currentRate.ToBigInt().Uint64()
+		}
+		bound, next := util.CurrentBound(schedule, beacon.EpochTime(epoch.ID))
+		if bound != nil {
+			v.CurrentCommissionBound = ValidatorCommissionBound{
+				Lower:      bound.RateMin.ToBigInt().Uint64(),
+				Upper:      bound.RateMax.ToBigInt().Uint64(),
+				EpochStart: uint64(bound.Start),
+			}
+		}
+
+		if next > 0 {
+			v.CurrentCommissionBound.EpochEnd = next
+		}
+
+		vs.Validators = append(vs.Validators, v)
+	}
+
+	return &vs, nil
+}
+
+// Validator returns a single validator.
+func (c *storageClient) Validator(ctx context.Context, r *http.Request) (*Validator, error) {
+	cid, ok := ctx.Value(ChainIDContextKey).(string)
+	if !ok {
+		return nil, common.ErrBadChainID
+	}
+	qf := NewQueryFactory(cid)
+
+	var epoch Epoch
+	if err := c.db.QueryRow(
+		ctx,
+		qf.ValidatorQuery(),
+	).Scan(&epoch.ID, &epoch.StartHeight); err != nil {
+		c.logger.Info("row scan failed",
+			"request_id", ctx.Value(RequestIDContextKey),
+			"err", err.Error(),
+		)
+		return nil, common.ErrStorageError
+	}
+
+	row := c.db.QueryRow(
+		ctx,
+		qf.ValidatorDataQuery(),
+		chi.URLParam(r, "entity_id"),
+	)
+
+	var v Validator
+	var schedule staking.CommissionSchedule
+	if err := row.Scan(
+		&v.EntityID,
+		&v.EntityAddress,
+		&v.NodeID,
+		&v.Escrow,
+		&schedule,
+		&v.Active,
+		&v.Status,
+		&v.Media,
+		&v.Participation.Proposals,
+		&v.Participation.Votes,
+		&v.Delegators,
+		&v.DelegatorConcentration.Top1,
+		&v.DelegatorConcentration.Top10,
+		&v.DelegatorConcentration.Top100,
+	); err != nil {
+		c.logger.Info("row scan failed",
+			"request_id", ctx.Value(RequestIDContextKey),
+			"err", err.Error(),
+		)
+		return nil, lookupError(err, "validator")
+	}
+	// Match API for now
+	v.Name = v.Media.Name
+
+	currentRate := schedule.CurrentRate(beacon.EpochTime(epoch.ID))
+	if currentRate != nil {
+		v.CurrentRate = currentRate.ToBigInt().Uint64()
+	}
+	bound, next := util.CurrentBound(schedule, beacon.EpochTime(epoch.ID))
+	if bound != nil {
+		v.CurrentCommissionBound = ValidatorCommissionBound{
+			Lower:      bound.RateMin.ToBigInt().Uint64(),
+			Upper:      bound.RateMax.ToBigInt().Uint64(),
+			EpochStart: uint64(bound.Start),
+		}
+	}
+
+	if next > 0 {
+		v.CurrentCommissionBound.EpochEnd = next
+	}
+
+	return &v, nil
+}
+
+// ValidatorDelegators returns a list of accounts delegating to a validator.
+func (c *storageClient) ValidatorDelegators(ctx context.Context, r *http.Request) (*ValidatorDelegatorList, error) {
+	cid, ok := ctx.Value(ChainIDContextKey).(string)
+	if !ok {
+		return nil, common.ErrBadChainID
+	}
+	qf := NewQueryFactory(cid)
+
+	pagination, err := common.NewPagination(r)
+	if err != nil {
+		c.logger.Info("pagination failed",
+			"request_id", ctx.Value(RequestIDContextKey),
+			"err", err.Error(),
+		)
+		return nil, common.ErrBadRequest
+	}
+
+	clauses, err := validatorDelegatorsListSpec.parse(r.URL.Query(), pagination, 2)
+	if err != nil {
+		c.logger.Info("malformed list parameters",
+			"request_id", ctx.Value(RequestIDContextKey),
+			"err", err.Error(),
+		)
+		return nil, common.ErrBadRequest
+	}
+
+	entityID, err := url.PathUnescape(chi.URLParam(r, "entity_id"))
+	if err != nil {
+		return nil, common.ErrBadRequest
+	}
+
+	ds := ValidatorDelegatorList{
+		Delegators: []ValidatorDelegator{},
+	}
+	if err := c.db.QueryRow(
+		ctx,
+		qf.ValidatorDelegationTotalsQuery(),
+		entityID,
+	).Scan(&ds.TotalAmount, &ds.TotalShares, &ds.TotalCount); err != nil {
+		c.logger.Info("row scan failed",
+			"request_id", ctx.Value(RequestIDContextKey),
+			"err", err.Error(),
+		)
+		return nil, lookupError(err, "validator")
+	}
+
+	rows, err := c.db.Query(
+		ctx,
+		qf.ValidatorDelegatorsListQuery(clauses),
+		append([]interface{}{entityID}, append(clauses.args, pagination.Limit, pagination.Offset)...)...,
+	)
+	if err != nil {
+		c.logger.Info("query failed",
+			"request_id", ctx.Value(RequestIDContextKey),
+			"err", err.Error(),
+		)
+		return nil, common.ErrStorageError
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var d ValidatorDelegator
+		if err := rows.Scan(
+			&d.Address,
+			&d.Amount,
+			&d.Shares,
+		); err != nil {
+			c.logger.Info("row scan failed",
+				"request_id", ctx.Value(RequestIDContextKey),
+				"err", err.Error(),
+			)
+			return nil, common.ErrStorageError
+		}
+
+		ds.Delegators = append(ds.Delegators, d)
+	}
+
+	return &ds, nil
+}
+
+// ValidatorDebondingDelegators returns a list of debonding delegations
+// to a validator.
+func (c *storageClient) ValidatorDebondingDelegators(ctx context.Context, r *http.Request) (*ValidatorDebondingDelegatorList, error) {
+	cid, ok := ctx.Value(ChainIDContextKey).(string)
+	if !ok {
+		return nil, common.ErrBadChainID
+	}
+	qf := NewQueryFactory(cid)
+
+	pagination, err := common.NewPagination(r)
+	if err != nil {
+		c.logger.Info("pagination failed",
+			"request_id", ctx.Value(RequestIDContextKey),
+			"err", err.Error(),
+		)
+		return nil, common.ErrBadRequest
+	}
+
+	clauses, err := validatorDebondingDelegatorsListSpec.parse(r.URL.Query(), pagination, 2)
+	if err != nil {
+		c.logger.Info("malformed list parameters",
+			"request_id", ctx.Value(RequestIDContextKey),
+			"err", err.Error(),
+		)
+		return nil, common.ErrBadRequest
+	}
+
+	entityID, err := url.PathUnescape(chi.URLParam(r, "entity_id"))
+	if err != nil {
+		return nil, common.ErrBadRequest
+	}
+
+	ds := ValidatorDebondingDelegatorList{
+		DebondingDelegators: []ValidatorDebondingDelegator{},
+	}
+	if err := c.db.QueryRow(
+		ctx,
+		qf.ValidatorDebondingDelegationTotalsQuery(),
+		entityID,
+	).Scan(&ds.TotalAmount, &ds.TotalShares, &ds.TotalCount); err != nil {
+		c.logger.Info("row scan failed",
+			"request_id", ctx.Value(RequestIDContextKey),
+			"err", err.Error(),
+		)
+		return nil, lookupError(err, "validator")
+	}
+
+	rows, err := c.db.Query(
+		ctx,
+		qf.ValidatorDebondingDelegatorsListQuery(clauses),
+		append([]interface{}{entityID}, append(clauses.args, pagination.Limit, pagination.Offset)...)...,
+	)
+	if err != nil {
+		c.logger.Info("query failed",
+			"request_id", ctx.Value(RequestIDContextKey),
+			"err", err.Error(),
+		)
+		return nil, common.ErrStorageError
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var d ValidatorDebondingDelegator
+		if err := rows.Scan(
+			&d.Address,
+			&d.Amount,
+			&d.Shares,
+			&d.DebondEnd,
+		); err != nil {
+			c.logger.Info("row scan failed",
+				"request_id", ctx.Value(RequestIDContextKey),
+				"err", err.Error(),
+			)
+			return nil, common.ErrStorageError
+		}
+
+		ds.DebondingDelegators = append(ds.DebondingDelegators, d)
+	}
+
+	return &ds, nil
+}
+
+// TransactionsPerSecond returns a list of tps checkpoint values.
+func (c *storageClient) TransactionsPerSecond(ctx context.Context, r *http.Request) (*TpsCheckpointList, error) {
+	qf := NewQueryFactory(strcase.ToSnake(LatestChainID))
+
+	pagination, err := common.NewPagination(r)
+	if err != nil {
+		c.logger.Info("pagination failed",
+			"request_id", ctx.Value(RequestIDContextKey),
+			"err", err.Error(),
+		)
+		return nil, common.ErrBadRequest
+	}
+
+	rows, err := c.db.Query(
+		ctx,
+		qf.TpsCheckpointQuery(),
+		pagination.Limit,
+		pagination.Offset,
+	)
+	if err != nil {
+		c.logger.Info("query failed",
+			"request_id", ctx.Value(RequestIDContextKey),
+			"err", err.Error(),
+		)
+		return nil, common.ErrStorageError
+	}
+	defer rows.Close()
+
+	ts := TpsCheckpointList{
+		IntervalMinutes: tpsWindowSizeMinutes,
+		TpsCheckpoints:  []TpsCheckpoint{},
+	}
+	for rows.Next() {
+		var d struct {
+			Hour     time.Time
+			MinSlot  int
+			TxVolume uint64
+		}
+		if err := rows.Scan(
+			&d.Hour,
+			&d.MinSlot,
+			&d.TxVolume,
+		); err != nil {
+			c.logger.Info("query failed",
+				"err", err.Error(),
+			)
+			return nil, common.ErrStorageError
+		}
+
+		t := TpsCheckpoint{
+			Timestamp: d.Hour.Add(time.Duration(tpsWindowSizeMinutes*d.MinSlot) * time.Minute).UTC(),
+			TxVolume:  d.TxVolume,
+		}
+		ts.TpsCheckpoints = append(ts.TpsCheckpoints, t)
+	}
+
+	return &ts, nil
+}
+
+// DailyVolumes returns a list of daily transaction volumes.
+func (c *storageClient) DailyVolumes(ctx context.Context, r *http.Request) (*VolumeList, error) {
+	qf := NewQueryFactory(strcase.ToSnake(LatestChainID))
+
+	pagination, err := common.NewPagination(r)
+	if err != nil {
+		c.logger.Info("pagination failed",
+			"request_id", ctx.Value(RequestIDContextKey),
+			"err", err.Error(),
+		)
+		return nil, common.ErrBadRequest
+	}
+
+	rows, err := c.db.Query(
+		ctx,
+		qf.TxVolumesQuery(),
+		pagination.Limit,
+		pagination.Offset,
+	)
+	if err != nil {
+		c.logger.Info("query failed",
+			"request_id", ctx.Value(RequestIDContextKey),
+			"err", err.Error(),
+		)
+		return nil, common.ErrStorageError
+	}
+	defer rows.Close()
+
+	vs := VolumeList{
+		Volumes: []Volume{},
+	}
+	for rows.Next() {
+		var v Volume
+		if err := rows.Scan(
+			&v.Date,
+			&v.TxVolume,
+		); err != nil {
+			c.logger.Info("query failed",
+				"err", err.Error(),
+			)
+			return nil, common.ErrStorageError
+		}
+		v.Date = v.Date.UTC()
+
+		vs.Volumes = append(vs.Volumes, v)
+	}
+
+	return &vs, nil
+}
+
+// WebhookSubscriptions returns a list of webhook subscriptions.
+func (c *storageClient) WebhookSubscriptions(ctx context.Context, r *http.Request) (*WebhookSubscriptionList, error) {
+	cid, ok := ctx.Value(ChainIDContextKey).(string)
+	if !ok {
+		return nil, common.ErrBadChainID
+	}
+	qf := NewQueryFactory(cid)
+
+	pagination, err := common.NewPagination(r)
+	if err != nil {
+		c.logger.Info("pagination failed",
+			"request_id", ctx.Value(RequestIDContextKey),
+			"err", err.Error(),
+		)
+		return nil, common.ErrBadRequest
+	}
+
+	keyID, ok := apiKeyID(ctx)
+	if !ok {
+		return nil, common.ErrKeyRequired
+	}
+
+	rows, err := c.db.Query(
+		ctx,
+		qf.WebhookSubscriptionsQuery(),
+		keyID,
+		pagination.Limit,
+		pagination.Offset,
+	)
+	if err != nil {
+		c.logger.Info("query failed",
+			"request_id", ctx.Value(RequestIDContextKey),
+			"err", err.Error(),
+		)
+		return nil, common.ErrStorageError
+	}
+	defer rows.Close()
+
+	ss := WebhookSubscriptionList{
+		Subscriptions: []WebhookSubscription{},
+	}
+	for rows.Next() {
+		var s WebhookSubscription
+		if err := rows.Scan(
+			&s.ID,
+			&s.Kind,
+			&s.URL,
+			&s.Account,
+			&s.EntityID,
+			&s.MinAmount,
+			&s.CreatedAt,
+		); err != nil {
+			c.logger.Info("row scan failed",
+				"request_id", ctx.Value(RequestIDContextKey),
+				"err", err.Error(),
+			)
+			return nil, common.ErrStorageError
+		}
+		s.CreatedAt = s.CreatedAt.UTC()
+
+		ss.Subscriptions = append(ss.Subscriptions, s)
+	}
+
+	return &ss, nil
+}
+
+// WebhookSubscription returns a webhook subscription.
+func (c *storageClient) WebhookSubscription(ctx context.Context, r *http.Request) (*WebhookSubscription, error) {
+	cid, ok := ctx.Value(ChainIDContextKey).(string)
+	if !ok {
+		return nil, common.ErrBadChainID
+	}
+	qf := NewQueryFactory(cid)
+
+	keyID, ok := apiKeyID(ctx)
+	if !ok {
+		return nil, common.ErrKeyRequired
+	}
+
+	var s WebhookSubscription
+	if err := c.db.QueryRow(
+		ctx,
+		qf.WebhookSubscriptionQuery(),
+		chi.URLParam(r, "subscription_id"),
+		keyID,
+	).Scan(
+		&s.ID,
+		&s.Kind,
+		&s.URL,
+		&s.Account,
+		&s.EntityID,
+		&s.MinAmount,
+		&s.CreatedAt,
+	); err != nil {
+		c.logger.Info("row scan failed",
+			"request_id", ctx.Value(RequestIDContextKey),
+			"err", err.Error(),
+		)
+		return nil, lookupError(err, "webhook subscription")
+	}
+	s.CreatedAt = s.CreatedAt.UTC()
+
+	return &s, nil
+}
+
+// CreateWebhookSubscription registers a new webhook subscription, generating
+// the secret used to sign its deliveries.
+func (c *storageClient) CreateWebhookSubscription(ctx context.Context, r *http.Request) (*WebhookSubscription, error) {
+	cid, ok := ctx.Value(ChainIDContextKey).(string)
+	if !ok {
+		return nil, common.ErrBadChainID
+	}
+	qf := NewQueryFactory(cid)
+
+	keyID, ok := apiKeyID(ctx)
+	if !ok {
+		return nil, common.ErrKeyRequired
+	}
+
+	var req WebhookSubscriptionRequest
+	if err := json.NewDecoder(io.LimitReader(r.Body, maxRequestBodySize)).Decode(&req); err != nil {
+		c.logger.Info("request decoding failed",
+			"request_id", ctx.Value(RequestIDContextKey),
+			"err", err.Error(),
+		)
+		return nil, common.ErrBadRequest
+	}
+	if err := validateWebhookSubscriptionRequest(ctx, &req); err != nil {
+		c.logger.Info("request validation failed",
+			"request_id", ctx.Value(RequestIDContextKey),
+			"err", err.Error(),
+		)
+		if errors.Is(err, notifier.ErrURLNotAllowed) {
+			return nil, common.NewInvalidParameterError("url", err.Error())
+		}
+		return nil, common.ErrBadRequest
+	}
+
+	var secret [32]byte
+	if _, err := rand.Read(secret[:]); err != nil {
+		c.logger.Error("secret generation failed",
+			"request_id", ctx.Value(RequestIDContextKey),
+			"err", err.Error(),
+		)
+		return nil, err
+	}
+
+	s := WebhookSubscription{
+		Kind:      req.Kind,
+		URL:       req.URL,
+		Account:   req.Account,
+		EntityID:  req.EntityID,
+		MinAmount: req.MinAmount,
+		Secret:    hex.EncodeToString(secret[:]),
+	}
+	if err := c.db.QueryRow(
+		storage.WithPrimary(ctx),
+		qf.WebhookSubscriptionInsertQuery(),
+		s.Kind,
+		s.URL,
+		s.Secret,
+		s.Account,
+		s.EntityID,
+		s.MinAmount,
+		keyID,
+	).Scan(&s.ID, &s.CreatedAt); err != nil {
+		c.logger.Info("row scan failed",
+			"request_id", ctx.Value(RequestIDContextKey),
+			"err", err.Error(),
+		)
+		return nil, common.ErrStorageError
+	}
+	s.CreatedAt = s.CreatedAt.UTC()
+
+	return &s, nil
+}
+
+// DeleteWebhookSubscription deletes a webhook subscription, along with
+// its dead letters.
+func (c *storageClient) DeleteWebhookSubscription(ctx context.Context, r *http.Request) error {
+	cid, ok := ctx.Value(ChainIDContextKey).(string)
+	if !ok {
+		return common.ErrBadChainID
+	}
+	qf := NewQueryFactory(cid)
+
+	keyID, ok := apiKeyID(ctx)
+	if !ok {
+		return common.ErrKeyRequired
+	}
+
+	var id int64
+	if err := c.db.QueryRow(
+		storage.WithPrimary(ctx),
+		qf.WebhookSubscriptionDeleteQuery(),
+		chi.URLParam(r, "subscription_id"),
+		keyID,
+	).Scan(&id); err != nil {
+		c.logger.Info("row scan failed",
+			"request_id", ctx.Value(RequestIDContextKey),
+			"err", err.Error(),
+		)
+		return lookupError(err, "webhook subscription")
+	}
+
+	return nil
+}
+
+// WebhookDeadLetters returns a list of failed deliveries for a webhook subscription.
+func (c *storageClient) WebhookDeadLetters(ctx context.Context, r *http.Request) (*WebhookDeadLetterList, error) {
+	cid, ok := ctx.Value(ChainIDContextKey).(string)
+	if !ok {
+		return nil, common.ErrBadChainID
+	}
+	qf := NewQueryFactory(cid)
+
+	id, err := strconv.ParseInt(chi.URLParam(r, "subscription_id"), 10, 64)
+	if err != nil {
+		return nil, common.ErrBadRequest
+	}
+
+	pagination, err := common.NewPagination(r)
+	if err != nil {
+		c.logger.Info("pagination failed",
+			"request_id", ctx.Value(RequestIDContextKey),
+			"err", err.Error(),
+		)
+		return nil, common.ErrBadRequest
+	}
+
+	// Dead letters are only listed for subscriptions of the API key.
+	if _, err := c.WebhookSubscription(ctx, r); err != nil {
+		return nil, err
+	}
+
+	rows, err := c.db.Query(
+		ctx,
+		qf.WebhookDeadLettersQuery(),
+		id,
+		pagination.Limit,
+		pagination.Offset,
+	)
+	if err != nil {
+		c.logger.Info("query failed",
+			"request_id", ctx.Value(RequestIDContextKey),
+			"err", err.Error(),
+		)
+		return nil, common.ErrStorageError
+	}
+	defer rows.Close()
+
+	ds := WebhookDeadLetterList{
+		SubscriptionID: id,
+		DeadLetters:    []WebhookDeadLetter{},
+	}
+	for rows.Next() {
+		var d WebhookDeadLetter
+		var payload string
+		if err := rows.Scan(
+			&d.ID,
+			&d.Height,
+			&payload,
+			&d.Attempts,
+			&d.LastError,
+			&d.CreatedAt,
+		); err != nil {
+			c.logger.Info("row scan failed",
+				"request_id", ctx.Value(RequestIDContextKey),
+				"err", err.Error(),
+			)
+			return nil, common.ErrStorageError
+		}
+		d.Payload = json.RawMessage(payload)
+		d.CreatedAt = d.CreatedAt.UTC()
+
+		ds.DeadLetters = append(ds.DeadLetters, d)
+	}
+
+	return &ds, nil
+}
+
+// validateWebhookSubscriptionRequest checks that a webhook subscription
+// request is well formed, and that deliveries may be made to its URL.
+func validateWebhookSubscriptionRequest(ctx context.Context, req *WebhookSubscriptionRequest) error {
+	if err := notifier.Kind(req.Kind).Validate(); err != nil {
+		return err
+	}
+	if err := notifier.ValidateURL(ctx, req.URL); err != nil {
+		return err
+	}
+
+	if req.Account != nil {
+		var address staking.Address
+		if err := address.UnmarshalText([]byte(*req.Account)); err != nil {
+			return err
+		}
+	}
+	if req.EntityID != nil {
+		var entityID signature.PublicKey
+		if err := entityID.UnmarshalText([]byte(*req.EntityID)); err != nil {
+			return err
+		}
+	}
+	if req.MinAmount != nil {
+		amount, ok := new(big.Int).SetString(*req.MinAmount, 10)
+		if !ok || amount.Sign() < 0 {
+			return fmt.Errorf("malformed minimum amount '%s'", *req.MinAmount)
+		}
+	}
+
+	return nil
+}
+
+// exportRange is the range of heights and times an export covers.
+// Bounds are inclusive, and missing bounds are unbounded.
+type exportRange struct {
+	from   *int64
+	to     *int64
+	after  *time.Time
+	before *time.Time
+}
+
+// parseExportRange parses the range of an export. Unlike list endpoints,
+// parameters are validated up front, since exports are streamed.
+func parseExportRange(params url.Values) (*exportRange, error) {
+	var er exportRange
+	for _, p := range []struct {
+		name string
+		dst  **int64
+	}{
+		{"from", &er.from},
+		{"to", &er.to},
+	} {
+		if v := params.Get(p.name); v != "" {
+			height, err := strconv.ParseInt(v, 10, 64)
+			if err != nil {
+				return nil, fmt.Errorf("malformed height '%s'", v)
+			}
+			*p.dst = &height
+		}
+	}
+	for _, p := range []struct {
+		name string
+		dst  **time.Time
+	}{
+		{"after", &er.after},
+		{"before", &er.before},
+	} {
+		if v := params.Get(p.name); v != "" {
+			t, err := time.Parse(time.RFC3339, v)
+			if err != nil {
+				return nil, fmt.Errorf("malformed time '%s'", v)
+			}
+			*p.dst = &t
+		}
+	}
+	return &er, nil
+}
+
+// ExportAccountActivity streams the activity of a consensus account,
+// calling f for each row in order. Errors returned by f are returned as is.
+func (c *storageClient) ExportAccountActivity(ctx context.Context, r *http.Request, f func(*AccountActivity) error) error {
+	cid, ok := ctx.Value(ChainIDContextKey).(string)
+	if !ok {
+		return common.ErrBadChainID
+	}
+	qf := NewQueryFactory(cid)
+
+	params := r.URL.Query()
+
+	var address staking.Address
+	if err := address.UnmarshalText([]byte(params.Get("address"))); err != nil {
+		c.logger.Info("malformed address",
+			"request_id", ctx.Value(RequestIDContextKey),
+			"err", err.Error(),
+		)
+		return common.ErrBadRequest
+	}
+	er, err := parseExportRange(params)
+	if err != nil {
+		c.logger.Info("malformed export range",
+			"request_id", ctx.Value(RequestIDContextKey),
+			"err", err.Error(),
+		)
+		return common.ErrBadRequest
+	}
+
+	rows, err := c.db.Query(
+		ctx,
+		qf.AccountActivityExportQuery(),
+		address.String(),
+		er.from,
+		er.to,
+		er.after,
+		er.before,
+	)
+	if err != nil {
+		c.logger.Info("query failed",
+			"request_id", ctx.Value(RequestIDContextKey),
+			"err", err.Error(),
+		)
+		return common.ErrStorageError
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var a AccountActivity
+		if err := rows.Scan(
+			&a.Height,
+			&a.Timestamp,
+			&a.TxHash,
+			&a.Kind,
+			&a.Type,
+			&a.Fee,
+			&a.Amount,
+			&a.Success,
+			&a.Body,
+		); err != nil {
+			c.logger.Info("row scan failed",
+				"request_id", ctx.Value(RequestIDContextKey),
+				"err", err.Error(),
+			)
+			return common.ErrStorageError
+		}
+		a.Timestamp = a.Timestamp.UTC()
+
+		if err := f(&a); err != nil {
+			return err
+		}
+	}
+	if err := rows.Err(); err != nil {
+		c.logger.Info("query failed",
+			"request_id", ctx.Value(RequestIDContextKey),
+			"err", err.Error(),
+		)
+		return common.ErrStorageError
+	}
+
+	return nil
+}
+
+// ExportStakingEvents streams consensus staking events, optionally
+// filtered by type, calling f for each event in order. Errors returned
+// by f are returned as is.
+func (c *storageClient) ExportStakingEvents(ctx context.Context, r *http.Request, f func(*StakingEvent) error) error {
+	cid, ok := ctx.Value(ChainIDContextKey).(string)
+	if !ok {
+		return common.ErrBadChainID
+	}
+	qf := NewQueryFactory(cid)
+
+	params := r.URL.Query()
+
+	var ty *string
+	if v := params.Get("type"); v != "" {
+		ty = &v
+	}
+	er, err := parseExportRange(params)
+	if err != nil {
+		c.logger.Info("malformed export range",
+			"request_id", ctx.Value(RequestIDContextKey),
+			"err", err.Error(),
+		)
+		return common.ErrBadRequest
+	}
+
+	rows, err := c.db.Query(
+		ctx,
+		qf.StakingEventsExportQuery(),
+		ty,
+		er.from,
+		er.to,
+		er.after,
+		er.before,
+	)
+	if err != nil {
+		c.logger.Info("query failed",
+			"request_id", ctx.Value(RequestIDContextKey),
+			"err", err.Error(),
+		)
+		return common.ErrStorageError
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var e StakingEvent
+		if err := rows.Scan(
+			&e.Height,
+			&e.Timestamp,
+			&e.TxHash,
+			&e.Type,
+			&e.Owner,
+			&e.Counterparty,
+			&e.Amount,
+			&e.Body,
+		); err != nil {
+			c.logger.Info("row scan failed",
+				"request_id", ctx.Value(RequestIDContextKey),
+				"err", err.Error(),
+			)
+			return common.ErrStorageError
+		}
+		e.Timestamp = e.Timestamp.UTC()
+
+		if err := f(&e); err != nil {
+			return err
+		}
+	}
+	if err := rows.Err(); err != nil {
+		c.logger.Info("query failed",
+			"request_id", ctx.Value(RequestIDContextKey),
+			"err", err.Error(),
+		)
+		return common.ErrStorageError
+	}
+
+	return nil
+}
+
+// Search returns the matches of the terms of a search query.
+func (c *storageClient) Search(ctx context.Context, terms *searchTerms) (*SearchResults, error) {
+	cid, ok := ctx.Value(ChainIDContextKey).(string)
+	if !ok {
+		return nil, common.ErrBadChainID
+	}
+	qf := NewQueryFactory(cid)
+
+	rs := SearchResults{
+		Results: []SearchResult{},
+	}
+	// scan adds a match if a query has a result, which is scanned
+	// into dest before the match is added.
+	scan := func(query string, arg interface{}, result *SearchResult, dest ...interface{}) error {
+		if err := c.db.QueryRow(ctx, query, arg).Scan(dest...); err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				return nil
+			}
+			c.logger.Info("row scan failed",
+				"request_id", ctx.Value(RequestIDContextKey),
+				"err", err.Error(),
+			)
+			return common.ErrStorageError
+		}
+		rs.Results = append(rs.Results, *result)
+		return nil
+	}
+
+	if terms.height != nil {
+		id := strconv.FormatInt(*terms.height, 10)
+		var height int64
+		if err := scan(qf.SearchBlockQuery(), *terms.height, &SearchResult{Kind: SearchKindBlock, ID: id}, &height); err != nil {
+			return nil, err
+		}
+		if err := scan(qf.SearchEmeraldRoundQuery(), *terms.height, &SearchResult{Kind: SearchKindEmeraldRound, ID: id}, &height); err != nil {
+			return nil, err
+		}
+	}
+
+	if terms.hash != nil {
+		var height int64
+		if err := scan(qf.SearchTransactionQuery(), *terms.hash, &SearchResult{Kind: SearchKindTransaction, ID: *terms.hash, Height: &height}, &height); err != nil {
+			return nil, err
+		}
+	}
+
+	if terms.publicKey != nil || terms.address != nil {
+		arg := terms.publicKey
+		if arg == nil {
+			arg = terms.address
+		}
+		e := SearchResult{Kind: SearchKindEntity}
+		if err := scan(qf.SearchEntityQuery(), *arg, &e, &e.ID, &e.Address, &e.Name); err != nil {
+			return nil, err
+		}
+	}
+
+	if terms.publicKey != nil {
+		var entityID string
+		if err := scan(qf.SearchNodeQuery(), *terms.publicKey, &SearchResult{Kind: SearchKindNode, ID: *terms.publicKey, EntityID: &entityID}, new(string), &entityID); err != nil {
+			return nil, err
+		}
+	}
+
+	if terms.address != nil {
+		if err := scan(qf.SearchAccountQuery(), *terms.address, &SearchResult{Kind: SearchKindAccount, ID: *terms.address}, new(string)); err != nil {
+			return nil, err
+		}
+	}
+
+	if terms.namePrefix != nil {
+		rows, err := c.db.Query(
+			ctx,
+			qf.SearchValidatorNamesQuery(),
+			*terms.namePrefix,
+			searchMaxNameMatches,
+		)
+		if err != nil {
+			c.logger.Info("query failed",
+				"request_id", ctx.Value(RequestIDContextKey),
+				"err", err.Error(),
+			)
+			return nil, common.ErrStorageError
+		}
+		defer rows.Close()
+
+		for rows.Next() {
+			v := SearchResult{Kind: SearchKindValidator}
+			if err := rows.Scan(&v.ID, &v.Address, &v.Name); err != nil {
+				c.logger.Info("row scan failed",
+					"request_id", ctx.Value(RequestIDContextKey),
+					"err", err.Error(),
+				)
+				return nil, common.ErrStorageError
+			}
+			rs.Results = append(rs.Results, v)
+		}
+		if err := rows.Err(); err != nil {
+			c.logger.Info("query failed",
+				"request_id", ctx.Value(RequestIDContextKey),
+				"err", err.Error(),
+			)
+			return nil, common.ErrStorageError
+		}
+	}
+
+	return &rs, nil
+}
+
+// LatestHeight returns the latest indexed height of the provided layer.
+func (c *storageClient) LatestHeight(ctx context.Context, layer streaming.Layer) (int64, error) {
+	cid, ok := ctx.Value(ChainIDContextKey).(string)
+	if !ok {
+		return 0, common.ErrBadChainID
+	}
+	qf := NewQueryFactory(cid)
+
+	var query string
+	switch layer {
+	case streaming.LayerConsensus:
+		query = qf.LatestBlockHeightQuery()
+	case streaming.LayerEmerald:
+		query = qf.LatestEmeraldRoundQuery()
+	default:
+		return 0, common.ErrBadRequest
+	}
+
+	var height int64
+	if err := c.db.QueryRow(ctx, query).Scan(&height); err != nil {
+		c.logger.Info("row scan failed",
+			"request_id", ctx.Value(RequestIDContextKey),
+			"err", err.Error(),
+		)
+		return 0, common.ErrStorageError
+	}
+
+	return height, nil
+}
+
+// BlockAt returns the consensus block at the provided height,
+// or nil if it has not been indexed.
+func (c *storageClient) BlockAt(ctx context.Context, height int64) (*Block, error) {
+	cid, ok := ctx.Value(ChainIDContextKey).(string)
+	if !ok {
+		return nil, common.ErrBadChainID
+	}
+	qf := NewQueryFactory(cid)
+
+	var b Block
+	if err := c.db.QueryRow(
+		ctx,
+		qf.BlockQuery(),
+		height,
+	).Scan(&b.Height, &b.Hash, &b.Timestamp); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil
+		}
+		c.logger.Info("row scan failed",
+			"request_id", ctx.Value(RequestIDContextKey),
+			"err", err.Error(),
+		)
+		return nil, common.ErrStorageError
+	}
+	b.Timestamp = b.Timestamp.UTC()
+
+	return &b, nil
+}
+
+// BlockTransactions returns the consensus transactions at the provided
+// height, optionally filtered by method and sender.
+func (c *storageClient) BlockTransactions(ctx context.Context, height int64, method *string, sender *string) ([]Transaction, error) {
+	cid, ok := ctx.Value(ChainIDContextKey).(string)
+	if !ok {
+		return nil, common.ErrBadChainID
+	}
+	qf := NewQueryFactory(cid)
+
+	rows, err := c.db.Query(
+		ctx,
+		qf.BlockTransactionsQuery(),
+		height,
+		method,
+		sender,
+	)
+	if err != nil {
+		c.logger.Info("query failed",
+			"request_id", ctx.Value(RequestIDContextKey),
+			"err", err.Error(),
+		)
+		return nil, common.ErrStorageError
+	}
+	defer rows.Close()
+
+	ts := []Transaction{}
+	for rows.Next() {
+		var t Transaction
+		var code uint64
+		if err := rows.Scan(
+			&t.Height,
+			&t.Hash,
+			&t.Sender,
+			&t.Nonce,
+			&t.Fee,
+			&t.Method,
+			&t.Body,
+			&code,
+		); err != nil {
+			c.logger.Info("row scan failed",
+				"request_id", ctx.Value(RequestIDContextKey),
+				"err", err.Error(),
+			)
+			return nil, common.ErrStorageError
+		}
+		if code == oasisErrors.CodeNoError {
+			t.Success = true
+		}
+
+		ts = append(ts, t)
+	}
+
+	return ts, nil
+}
+
+// BlockEvents returns the consensus events at the provided height,
+// optionally filtered by type.
+func (c *storageClient) BlockEvents(ctx context.Context, height int64, ty *string) ([]Event, error) {
+	cid, ok := ctx.Value(ChainIDContextKey).(string)
+	if !ok {
+		return nil, common.ErrBadChainID
+	}
+	qf := NewQueryFactory(cid)
+
+	rows, err := c.db.Query(
+		ctx,
+		qf.BlockEventsQuery(),
+		height,
+		ty,
+	)
+	if err != nil {
+		c.logger.Info("query failed",
+			"request_id", ctx.Value(RequestIDContextKey),
+			"err", err.Error(),
+		)
+		return nil, common.ErrStorageError
+	}
+	defer rows.Close()
+
+	es := []Event{}
+	for rows.Next() {
+		var e Event
+		var body *string
+		if err := rows.Scan(
+			&e.Height,
+			&e.TxHash,
+			&e.TxIndex,
+			&e.Backend,
+			&e.Type,
+			&body,
+		); err != nil {
+			c.logger.Info("row scan failed",
+				"request_id", ctx.Value(RequestIDContextKey),
+				"err", err.Error(),
+			)
+			return nil, common.ErrStorageError
+		}
+		if body != nil {
+			e.Body = json.RawMessage(*body)
+		}
+
+		es = append(es, e)
+	}
+
+	return es, nil
+}
+
+// EmeraldRoundAt returns the Emerald round with the provided number,
+// or nil if it has not been indexed.
+func (c *storageClient) EmeraldRoundAt(ctx context.Context, round int64) (*EmeraldRound, error) {
+	cid, ok := ctx.Value(ChainIDContextKey).(string)
+	if !ok {
+		return nil, common.ErrBadChainID
+	}
+	qf := NewQueryFactory(cid)
+
+	var er EmeraldRound
+	if err := c.db.QueryRow(
+		ctx,
+		qf.EmeraldRoundQuery(),
+		round,
+	).Scan(
+		&er.Round,
+		&er.Version,
+		&er.Timestamp,
+		&er.Hash,
+		&er.PrevHash,
+		&er.IORoot,
+		&er.StateRoot,
+		&er.MessagesHash,
+		&er.InMessagesHash,
+	); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil
+		}
+		c.logger.Info("row scan failed",
+			"request_id", ctx.Value(RequestIDContextKey),
+			"err", err.Error(),
+		)
+		return nil, common.ErrStorageError
+	}
+
+	return &er, nil
+}
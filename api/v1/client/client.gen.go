@@ -0,0 +1,2058 @@
+// Code generated by github.com/oasisprotocol/oasis-indexer/api/spec/gen. DO NOT EDIT.
+
+package client
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/url"
+	"time"
+
+	v1 "github.com/oasisprotocol/oasis-indexer/api/v1"
+)
+
+// GetStatus returns the indexer status.
+func (c *Client) GetStatus(ctx context.Context) (*v1.Status, error) {
+	var out v1.Status
+	if err := c.doJSON(ctx, http.MethodGet, "/", nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// SearchParams are the query parameters of Search.
+type SearchParams struct {
+	// The search query.
+	Q string
+}
+
+func (p *SearchParams) query() url.Values {
+	q := url.Values{}
+	if p == nil {
+		return q
+	}
+	addParam(q, "q", p.Q)
+	return q
+}
+
+// Search searches for blocks, transactions, entities, nodes, accounts, Emerald
+// rounds and validators.
+func (c *Client) Search(ctx context.Context, params *SearchParams) (*v1.SearchResults, error) {
+	var out v1.SearchResults
+	if err := c.doJSON(ctx, http.MethodGet, "/search", params.query(), nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// ListBlocksParams are the query parameters of ListBlocks.
+type ListBlocksParams struct {
+	// The maximum numbers of items to return.
+	Limit *int
+
+	// The number of items to skip before starting to collect the result set.
+	Offset *int
+
+	// A filter on minimum block height.
+	From *int64
+
+	// A filter on maximum block height.
+	To *int64
+
+	// A filter on minimum block time.
+	After *time.Time
+
+	// A filter on maximum block time.
+	Before *time.Time
+}
+
+func (p *ListBlocksParams) query() url.Values {
+	q := url.Values{}
+	if p == nil {
+		return q
+	}
+	addParam(q, "limit", p.Limit)
+	addParam(q, "offset", p.Offset)
+	addParam(q, "from", p.From)
+	addParam(q, "to", p.To)
+	addParam(q, "after", p.After)
+	addParam(q, "before", p.Before)
+	return q
+}
+
+// ListBlocks returns a list of consensus blocks.
+func (c *Client) ListBlocks(ctx context.Context, params *ListBlocksParams) (*v1.BlockList, error) {
+	var out v1.BlockList
+	if err := c.doJSON(ctx, http.MethodGet, "/consensus/blocks", params.query(), nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// ListBlocksPager pages through the results of ListBlocks.
+type ListBlocksPager struct {
+	pager
+	c      *Client
+	params ListBlocksParams
+	page   *v1.BlockList
+}
+
+// ListBlocksPager returns a pager of the results of ListBlocks, starting at
+// the offset and with pages of the limit of the parameters.
+func (c *Client) ListBlocksPager(params *ListBlocksParams) *ListBlocksPager {
+	p := &ListBlocksPager{c: c}
+	if params != nil {
+		p.params = *params
+	}
+	p.pager = newPager(p.params.Offset, p.params.Limit)
+	return p
+}
+
+// Next fetches the next page, which is then returned by Page. It returns
+// false once there are no more results, or if fetching the page failed.
+func (p *ListBlocksPager) Next(ctx context.Context) bool {
+	if p.done {
+		return false
+	}
+	params := p.params
+	params.Offset, params.Limit = p.next()
+	page, err := p.c.ListBlocks(ctx, &params)
+	if err != nil {
+		p.fail(err)
+		return false
+	}
+	if !p.advance(len(page.Blocks)) {
+		return false
+	}
+	p.page = page
+	return true
+}
+
+// Page returns the page fetched by the last call to Next.
+func (p *ListBlocksPager) Page() *v1.BlockList {
+	return p.page
+}
+
+// GetBlock returns a consensus block.
+func (c *Client) GetBlock(ctx context.Context, height int64) (*v1.Block, error) {
+	var out v1.Block
+	if err := c.doJSON(ctx, http.MethodGet, "/consensus/blocks/"+pathParam(height), nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// ListTransactionsParams are the query parameters of ListTransactions.
+type ListTransactionsParams struct {
+	// The maximum numbers of items to return.
+	Limit *int
+
+	// The number of items to skip before starting to collect the result set.
+	Offset *int
+
+	// A filter on block height.
+	Block *int64
+
+	// A filter on transaction method.
+	Method *string
+
+	// A filter on transaction sender.
+	Sender *string
+
+	// A filter on minimum transaction fee.
+	MinFee *int64
+
+	// A filter on maximum transaction fee.
+	MaxFee *int64
+
+	// A filter on transaction status code.
+	Code *int
+}
+
+func (p *ListTransactionsParams) query() url.Values {
+	q := url.Values{}
+	if p == nil {
+		return q
+	}
+	addParam(q, "limit", p.Limit)
+	addParam(q, "offset", p.Offset)
+	addParam(q, "block", p.Block)
+	addParam(q, "method", p.Method)
+	addParam(q, "sender", p.Sender)
+	addParam(q, "minFee", p.MinFee)
+	addParam(q, "maxFee", p.MaxFee)
+	addParam(q, "code", p.Code)
+	return q
+}
+
+// ListTransactions returns a list of consensus transactions.
+func (c *Client) ListTransactions(ctx context.Context, params *ListTransactionsParams) (*v1.TransactionList, error) {
+	var out v1.TransactionList
+	if err := c.doJSON(ctx, http.MethodGet, "/consensus/transactions", params.query(), nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// ListTransactionsPager pages through the results of ListTransactions.
+type ListTransactionsPager struct {
+	pager
+	c      *Client
+	params ListTransactionsParams
+	page   *v1.TransactionList
+}
+
+// ListTransactionsPager returns a pager of the results of ListTransactions,
+// starting at the offset and with pages of the limit of the parameters.
+func (c *Client) ListTransactionsPager(params *ListTransactionsParams) *ListTransactionsPager {
+	p := &ListTransactionsPager{c: c}
+	if params != nil {
+		p.params = *params
+	}
+	p.pager = newPager(p.params.Offset, p.params.Limit)
+	return p
+}
+
+// Next fetches the next page, which is then returned by Page. It returns
+// false once there are no more results, or if fetching the page failed.
+func (p *ListTransactionsPager) Next(ctx context.Context) bool {
+	if p.done {
+		return false
+	}
+	params := p.params
+	params.Offset, params.Limit = p.next()
+	page, err := p.c.ListTransactions(ctx, &params)
+	if err != nil {
+		p.fail(err)
+		return false
+	}
+	if !p.advance(len(page.Transactions)) {
+		return false
+	}
+	p.page = page
+	return true
+}
+
+// Page returns the page fetched by the last call to Next.
+func (p *ListTransactionsPager) Page() *v1.TransactionList {
+	return p.page
+}
+
+// GetTransaction returns a consensus transaction.
+func (c *Client) GetTransaction(ctx context.Context, txHash string) (*v1.Transaction, error) {
+	var out v1.Transaction
+	if err := c.doJSON(ctx, http.MethodGet, "/consensus/transactions/"+pathParam(txHash), nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// ListEntitiesParams are the query parameters of ListEntities.
+type ListEntitiesParams struct {
+	// The maximum numbers of items to return.
+	Limit *int
+
+	// The number of items to skip before starting to collect the result set.
+	Offset *int
+
+	// The block height from which to query state. The Oasis Indexer does not make
+	// any guarantees about availability of historical state data.
+	Height *int64
+
+	// The fields to order by, each prefixed by `-` for descending order. Defaults
+	// to `id`.
+	OrderBy []string
+
+	// A filter on the entity ID.
+	ID Filter
+
+	// A filter on the entity address.
+	Address Filter
+
+	// A filter on the entity name in the metadata registry, e.g.
+	// `name[prefix]=bit`.
+	Name Filter
+
+	// A filter on the active escrow of the entity, in base units.
+	Escrow Filter
+
+	// A filter on the number of delegators to the entity.
+	Delegators Filter
+}
+
+func (p *ListEntitiesParams) query() url.Values {
+	q := url.Values{}
+	if p == nil {
+		return q
+	}
+	addParam(q, "limit", p.Limit)
+	addParam(q, "offset", p.Offset)
+	addParam(q, "height", p.Height)
+	addParam(q, "order_by", p.OrderBy)
+	addParam(q, "id", p.ID)
+	addParam(q, "address", p.Address)
+	addParam(q, "name", p.Name)
+	addParam(q, "escrow", p.Escrow)
+	addParam(q, "delegators", p.Delegators)
+	return q
+}
+
+// ListEntities returns a list of entities registered at the consensus layer.
+func (c *Client) ListEntities(ctx context.Context, params *ListEntitiesParams) (*v1.EntityList, error) {
+	var out v1.EntityList
+	if err := c.doJSON(ctx, http.MethodGet, "/consensus/entities", params.query(), nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// ListEntitiesPager pages through the results of ListEntities.
+type ListEntitiesPager struct {
+	pager
+	c      *Client
+	params ListEntitiesParams
+	page   *v1.EntityList
+}
+
+// ListEntitiesPager returns a pager of the results of ListEntities, starting
+// at the offset and with pages of the limit of the parameters.
+func (c *Client) ListEntitiesPager(params *ListEntitiesParams) *ListEntitiesPager {
+	p := &ListEntitiesPager{c: c}
+	if params != nil {
+		p.params = *params
+	}
+	p.pager = newPager(p.params.Offset, p.params.Limit)
+	return p
+}
+
+// Next fetches the next page, which is then returned by Page. It returns
+// false once there are no more results, or if fetching the page failed.
+func (p *ListEntitiesPager) Next(ctx context.Context) bool {
+	if p.done {
+		return false
+	}
+	params := p.params
+	params.Offset, params.Limit = p.next()
+	page, err := p.c.ListEntities(ctx, &params)
+	if err != nil {
+		p.fail(err)
+		return false
+	}
+	if !p.advance(len(page.Entities)) {
+		return false
+	}
+	p.page = page
+	return true
+}
+
+// Page returns the page fetched by the last call to Next.
+func (p *ListEntitiesPager) Page() *v1.EntityList {
+	return p.page
+}
+
+// GetEntityParams are the query parameters of GetEntity.
+type GetEntityParams struct {
+	// The block height from which to query state. The Oasis Indexer does not make
+	// any guarantees about availability of historical state data.
+	Height *int64
+}
+
+func (p *GetEntityParams) query() url.Values {
+	q := url.Values{}
+	if p == nil {
+		return q
+	}
+	addParam(q, "height", p.Height)
+	return q
+}
+
+// GetEntity returns an entity registered at the consensus layer.
+func (c *Client) GetEntity(ctx context.Context, entityID string, params *GetEntityParams) (*v1.Entity, error) {
+	var out v1.Entity
+	if err := c.doJSON(ctx, http.MethodGet, "/consensus/entities/"+pathParam(entityID), params.query(), nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// ListEntityNodesParams are the query parameters of ListEntityNodes.
+type ListEntityNodesParams struct {
+	// The maximum numbers of items to return.
+	Limit *int
+
+	// The number of items to skip before starting to collect the result set.
+	Offset *int
+
+	// The block height from which to query state. The Oasis Indexer does not make
+	// any guarantees about availability of historical state data.
+	Height *int64
+
+	// A filter on the nodes that are registered for the role.
+	Role *string
+
+	// The fields to order by, each prefixed by `-` for descending order. Defaults
+	// to `id`.
+	OrderBy []string
+
+	// A filter on the node ID.
+	ID Filter
+
+	// A filter on the epoch in which the node's registration expires.
+	Expiration Filter
+
+	// A filter on the software version of the node, e.g.
+	// `software_version[prefix]=22.1`.
+	SoftwareVersion Filter
+}
+
+func (p *ListEntityNodesParams) query() url.Values {
+	q := url.Values{}
+	if p == nil {
+		return q
+	}
+	addParam(q, "limit", p.Limit)
+	addParam(q, "offset", p.Offset)
+	addParam(q, "height", p.Height)
+	addParam(q, "role", p.Role)
+	addParam(q, "order_by", p.OrderBy)
+	addParam(q, "id", p.ID)
+	addParam(q, "expiration", p.Expiration)
+	addParam(q, "software_version", p.SoftwareVersion)
+	return q
+}
+
+// ListEntityNodes returns a list of nodes registered at the consensus layer.
+func (c *Client) ListEntityNodes(ctx context.Context, entityID string, params *ListEntityNodesParams) (*v1.NodeList, error) {
+	var out v1.NodeList
+	if err := c.doJSON(ctx, http.MethodGet, "/consensus/entities/"+pathParam(entityID)+"/nodes", params.query(), nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// ListEntityNodesPager pages through the results of ListEntityNodes.
+type ListEntityNodesPager struct {
+	pager
+	c        *Client
+	entityID string
+	params   ListEntityNodesParams
+	page     *v1.NodeList
+}
+
+// ListEntityNodesPager returns a pager of the results of ListEntityNodes,
+// starting at the offset and with pages of the limit of the parameters.
+func (c *Client) ListEntityNodesPager(entityID string, params *ListEntityNodesParams) *ListEntityNodesPager {
+	p := &ListEntityNodesPager{c: c, entityID: entityID}
+	if params != nil {
+		p.params = *params
+	}
+	p.pager = newPager(p.params.Offset, p.params.Limit)
+	return p
+}
+
+// Next fetches the next page, which is then returned by Page. It returns
+// false once there are no more results, or if fetching the page failed.
+func (p *ListEntityNodesPager) Next(ctx context.Context) bool {
+	if p.done {
+		return false
+	}
+	params := p.params
+	params.Offset, params.Limit = p.next()
+	page, err := p.c.ListEntityNodes(ctx, p.entityID, &params)
+	if err != nil {
+		p.fail(err)
+		return false
+	}
+	if !p.advance(len(page.Nodes)) {
+		return false
+	}
+	p.page = page
+	return true
+}
+
+// Page returns the page fetched by the last call to Next.
+func (p *ListEntityNodesPager) Page() *v1.NodeList {
+	return p.page
+}
+
+// GetEntityNodeParams are the query parameters of GetEntityNode.
+type GetEntityNodeParams struct {
+	// The block height from which to query state. The Oasis Indexer does not make
+	// any guarantees about availability of historical state data.
+	Height *int64
+}
+
+func (p *GetEntityNodeParams) query() url.Values {
+	q := url.Values{}
+	if p == nil {
+		return q
+	}
+	addParam(q, "height", p.Height)
+	return q
+}
+
+// GetEntityNode returns a node registered at the consensus layer.
+func (c *Client) GetEntityNode(ctx context.Context, entityID string, nodeID string, params *GetEntityNodeParams) (*v1.Node, error) {
+	var out v1.Node
+	if err := c.doJSON(ctx, http.MethodGet, "/consensus/entities/"+pathParam(entityID)+"/nodes/"+pathParam(nodeID), params.query(), nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// GetEntityNodeHistoryParams are the query parameters of GetEntityNodeHistory.
+type GetEntityNodeHistoryParams struct {
+	// The maximum numbers of items to return.
+	Limit *int
+
+	// The number of items to skip before starting to collect the result set.
+	Offset *int
+}
+
+func (p *GetEntityNodeHistoryParams) query() url.Values {
+	q := url.Values{}
+	if p == nil {
+		return q
+	}
+	addParam(q, "limit", p.Limit)
+	addParam(q, "offset", p.Offset)
+	return q
+}
+
+// GetEntityNodeHistory returns the registrations, expiries, freezes and
+// unfreezes of a node, from the most recent.
+func (c *Client) GetEntityNodeHistory(ctx context.Context, entityID string, nodeID string, params *GetEntityNodeHistoryParams) (*v1.NodeHistory, error) {
+	var out v1.NodeHistory
+	if err := c.doJSON(ctx, http.MethodGet, "/consensus/entities/"+pathParam(entityID)+"/nodes/"+pathParam(nodeID)+"/history", params.query(), nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// GetEntityNodeHistoryPager pages through the results of GetEntityNodeHistory.
+type GetEntityNodeHistoryPager struct {
+	pager
+	c        *Client
+	entityID string
+	nodeID   string
+	params   GetEntityNodeHistoryParams
+	page     *v1.NodeHistory
+}
+
+// GetEntityNodeHistoryPager returns a pager of the results of
+// GetEntityNodeHistory, starting at the offset and with pages of the limit of
+// the parameters.
+func (c *Client) GetEntityNodeHistoryPager(entityID string, nodeID string, params *GetEntityNodeHistoryParams) *GetEntityNodeHistoryPager {
+	p := &GetEntityNodeHistoryPager{c: c, entityID: entityID, nodeID: nodeID}
+	if params != nil {
+		p.params = *params
+	}
+	p.pager = newPager(p.params.Offset, p.params.Limit)
+	return p
+}
+
+// Next fetches the next page, which is then returned by Page. It returns
+// false once there are no more results, or if fetching the page failed.
+func (p *GetEntityNodeHistoryPager) Next(ctx context.Context) bool {
+	if p.done {
+		return false
+	}
+	params := p.params
+	params.Offset, params.Limit = p.next()
+	page, err := p.c.GetEntityNodeHistory(ctx, p.entityID, p.nodeID, &params)
+	if err != nil {
+		p.fail(err)
+		return false
+	}
+	if !p.advance(len(page.Events)) {
+		return false
+	}
+	p.page = page
+	return true
+}
+
+// Page returns the page fetched by the last call to Next.
+func (p *GetEntityNodeHistoryPager) Page() *v1.NodeHistory {
+	return p.page
+}
+
+// ListRuntimesParams are the query parameters of ListRuntimes.
+type ListRuntimesParams struct {
+	// The maximum numbers of items to return.
+	Limit *int
+
+	// The number of items to skip before starting to collect the result set.
+	Offset *int
+}
+
+func (p *ListRuntimesParams) query() url.Values {
+	q := url.Values{}
+	if p == nil {
+		return q
+	}
+	addParam(q, "limit", p.Limit)
+	addParam(q, "offset", p.Offset)
+	return q
+}
+
+// ListRuntimes returns a list of runtimes registered at the consensus layer.
+func (c *Client) ListRuntimes(ctx context.Context, params *ListRuntimesParams) (*v1.RuntimeList, error) {
+	var out v1.RuntimeList
+	if err := c.doJSON(ctx, http.MethodGet, "/consensus/runtimes", params.query(), nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// ListRuntimesPager pages through the results of ListRuntimes.
+type ListRuntimesPager struct {
+	pager
+	c      *Client
+	params ListRuntimesParams
+	page   *v1.RuntimeList
+}
+
+// ListRuntimesPager returns a pager of the results of ListRuntimes, starting
+// at the offset and with pages of the limit of the parameters.
+func (c *Client) ListRuntimesPager(params *ListRuntimesParams) *ListRuntimesPager {
+	p := &ListRuntimesPager{c: c}
+	if params != nil {
+		p.params = *params
+	}
+	p.pager = newPager(p.params.Offset, p.params.Limit)
+	return p
+}
+
+// Next fetches the next page, which is then returned by Page. It returns
+// false once there are no more results, or if fetching the page failed.
+func (p *ListRuntimesPager) Next(ctx context.Context) bool {
+	if p.done {
+		return false
+	}
+	params := p.params
+	params.Offset, params.Limit = p.next()
+	page, err := p.c.ListRuntimes(ctx, &params)
+	if err != nil {
+		p.fail(err)
+		return false
+	}
+	if !p.advance(len(page.Runtimes)) {
+		return false
+	}
+	p.page = page
+	return true
+}
+
+// Page returns the page fetched by the last call to Next.
+func (p *ListRuntimesPager) Page() *v1.RuntimeList {
+	return p.page
+}
+
+// GetRuntime returns a runtime registered at the consensus layer, along with
+// its current committees and its history.
+func (c *Client) GetRuntime(ctx context.Context, runtimeID string) (*v1.Runtime, error) {
+	var out v1.Runtime
+	if err := c.doJSON(ctx, http.MethodGet, "/consensus/runtimes/"+pathParam(runtimeID), nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// GetRuntimeHistoryParams are the query parameters of GetRuntimeHistory.
+type GetRuntimeHistoryParams struct {
+	// The maximum numbers of items to return.
+	Limit *int
+
+	// The number of items to skip before starting to collect the result set.
+	Offset *int
+}
+
+func (p *GetRuntimeHistoryParams) query() url.Values {
+	q := url.Values{}
+	if p == nil {
+		return q
+	}
+	addParam(q, "limit", p.Limit)
+	addParam(q, "offset", p.Offset)
+	return q
+}
+
+// GetRuntimeHistory returns the registrations, updates, suspensions and
+// unsuspensions of a runtime, from the most recent.
+func (c *Client) GetRuntimeHistory(ctx context.Context, runtimeID string, params *GetRuntimeHistoryParams) (*v1.RuntimeHistory, error) {
+	var out v1.RuntimeHistory
+	if err := c.doJSON(ctx, http.MethodGet, "/consensus/runtimes/"+pathParam(runtimeID)+"/history", params.query(), nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// GetRuntimeHistoryPager pages through the results of GetRuntimeHistory.
+type GetRuntimeHistoryPager struct {
+	pager
+	c         *Client
+	runtimeID string
+	params    GetRuntimeHistoryParams
+	page      *v1.RuntimeHistory
+}
+
+// GetRuntimeHistoryPager returns a pager of the results of GetRuntimeHistory,
+// starting at the offset and with pages of the limit of the parameters.
+func (c *Client) GetRuntimeHistoryPager(runtimeID string, params *GetRuntimeHistoryParams) *GetRuntimeHistoryPager {
+	p := &GetRuntimeHistoryPager{c: c, runtimeID: runtimeID}
+	if params != nil {
+		p.params = *params
+	}
+	p.pager = newPager(p.params.Offset, p.params.Limit)
+	return p
+}
+
+// Next fetches the next page, which is then returned by Page. It returns
+// false once there are no more results, or if fetching the page failed.
+func (p *GetRuntimeHistoryPager) Next(ctx context.Context) bool {
+	if p.done {
+		return false
+	}
+	params := p.params
+	params.Offset, params.Limit = p.next()
+	page, err := p.c.GetRuntimeHistory(ctx, p.runtimeID, &params)
+	if err != nil {
+		p.fail(err)
+		return false
+	}
+	if !p.advance(len(page.Events)) {
+		return false
+	}
+	p.page = page
+	return true
+}
+
+// Page returns the page fetched by the last call to Next.
+func (p *GetRuntimeHistoryPager) Page() *v1.RuntimeHistory {
+	return p.page
+}
+
+// ListValidatorsParams are the query parameters of ListValidators.
+type ListValidatorsParams struct {
+	// The maximum numbers of items to return.
+	Limit *int
+
+	// The number of items to skip before starting to collect the result set.
+	Offset *int
+
+	// The block height from which to query state. The Oasis Indexer does not make
+	// any guarantees about availability of historical state data.
+	Height *int64
+
+	// The fields to order by, each prefixed by `-` for descending order. Defaults
+	// to `-escrow`.
+	OrderBy []string
+
+	// A filter on the validator entity ID.
+	EntityID Filter
+
+	// A filter on the validator entity address.
+	EntityAddress Filter
+
+	// A filter on the validator name, e.g. `name[prefix]=bit`.
+	Name Filter
+
+	// A filter on the active escrow balance of the validator.
+	Escrow Filter
+
+	// A filter on the current commission rate of the validator, e.g.
+	// `commission[lte]=5000`.
+	Commission Filter
+
+	// A filter on whether the validator is in the validator set.
+	Active Filter
+
+	// A filter on whether the validator has a registered validator node.
+	Status Filter
+}
+
+func (p *ListValidatorsParams) query() url.Values {
+	q := url.Values{}
+	if p == nil {
+		return q
+	}
+	addParam(q, "limit", p.Limit)
+	addParam(q, "offset", p.Offset)
+	addParam(q, "height", p.Height)
+	addParam(q, "order_by", p.OrderBy)
+	addParam(q, "entity_id", p.EntityID)
+	addParam(q, "entity_address", p.EntityAddress)
+	addParam(q, "name", p.Name)
+	addParam(q, "escrow", p.Escrow)
+	addParam(q, "commission", p.Commission)
+	addParam(q, "active", p.Active)
+	addParam(q, "status", p.Status)
+	return q
+}
+
+// ListValidators returns a list of validators registered at the consensus
+// layer.
+func (c *Client) ListValidators(ctx context.Context, params *ListValidatorsParams) (*v1.ValidatorList, error) {
+	var out v1.ValidatorList
+	if err := c.doJSON(ctx, http.MethodGet, "/consensus/validators", params.query(), nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// ListValidatorsPager pages through the results of ListValidators.
+type ListValidatorsPager struct {
+	pager
+	c      *Client
+	params ListValidatorsParams
+	page   *v1.ValidatorList
+}
+
+// ListValidatorsPager returns a pager of the results of ListValidators,
+// starting at the offset and with pages of the limit of the parameters.
+func (c *Client) ListValidatorsPager(params *ListValidatorsParams) *ListValidatorsPager {
+	p := &ListValidatorsPager{c: c}
+	if params != nil {
+		p.params = *params
+	}
+	p.pager = newPager(p.params.Offset, p.params.Limit)
+	return p
+}
+
+// Next fetches the next page, which is then returned by Page. It returns
+// false once there are no more results, or if fetching the page failed.
+func (p *ListValidatorsPager) Next(ctx context.Context) bool {
+	if p.done {
+		return false
+	}
+	params := p.params
+	params.Offset, params.Limit = p.next()
+	page, err := p.c.ListValidators(ctx, &params)
+	if err != nil {
+		p.fail(err)
+		return false
+	}
+	if !p.advance(len(page.Validators)) {
+		return false
+	}
+	p.page = page
+	return true
+}
+
+// Page returns the page fetched by the last call to Next.
+func (p *ListValidatorsPager) Page() *v1.ValidatorList {
+	return p.page
+}
+
+// GetValidator returns a validator registered at the consensus layer.
+func (c *Client) GetValidator(ctx context.Context, entityID string) (*v1.Validator, error) {
+	var out v1.Validator
+	if err := c.doJSON(ctx, http.MethodGet, "/consensus/validators/"+pathParam(entityID), nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// GetValidatorDelegatorsParams are the query parameters of GetValidatorDelegators.
+type GetValidatorDelegatorsParams struct {
+	// The maximum numbers of items to return.
+	Limit *int
+
+	// The number of items to skip before starting to collect the result set.
+	Offset *int
+
+	// The fields to order by, each prefixed by `-` for descending order. Defaults
+	// to `-amount`.
+	OrderBy []string
+
+	// A filter on the address of the delegator.
+	Address Filter
+
+	// A filter on the amount delegated, in base units.
+	Amount Filter
+
+	// A filter on the shares of the delegation.
+	Shares Filter
+}
+
+func (p *GetValidatorDelegatorsParams) query() url.Values {
+	q := url.Values{}
+	if p == nil {
+		return q
+	}
+	addParam(q, "limit", p.Limit)
+	addParam(q, "offset", p.Offset)
+	addParam(q, "order_by", p.OrderBy)
+	addParam(q, "address", p.Address)
+	addParam(q, "amount", p.Amount)
+	addParam(q, "shares", p.Shares)
+	return q
+}
+
+// GetValidatorDelegators returns the accounts delegating to a validator, along
+// with the total of its delegations.
+func (c *Client) GetValidatorDelegators(ctx context.Context, entityID string, params *GetValidatorDelegatorsParams) (*v1.ValidatorDelegatorList, error) {
+	var out v1.ValidatorDelegatorList
+	if err := c.doJSON(ctx, http.MethodGet, "/consensus/validators/"+pathParam(entityID)+"/delegators", params.query(), nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// GetValidatorDelegatorsPager pages through the results of GetValidatorDelegators.
+type GetValidatorDelegatorsPager struct {
+	pager
+	c        *Client
+	entityID string
+	params   GetValidatorDelegatorsParams
+	page     *v1.ValidatorDelegatorList
+}
+
+// GetValidatorDelegatorsPager returns a pager of the results of
+// GetValidatorDelegators, starting at the offset and with pages of the limit
+// of the parameters.
+func (c *Client) GetValidatorDelegatorsPager(entityID string, params *GetValidatorDelegatorsParams) *GetValidatorDelegatorsPager {
+	p := &GetValidatorDelegatorsPager{c: c, entityID: entityID}
+	if params != nil {
+		p.params = *params
+	}
+	p.pager = newPager(p.params.Offset, p.params.Limit)
+	return p
+}
+
+// Next fetches the next page, which is then returned by Page. It returns
+// false once there are no more results, or if fetching the page failed.
+func (p *GetValidatorDelegatorsPager) Next(ctx context.Context) bool {
+	if p.done {
+		return false
+	}
+	params := p.params
+	params.Offset, params.Limit = p.next()
+	page, err := p.c.GetValidatorDelegators(ctx, p.entityID, &params)
+	if err != nil {
+		p.fail(err)
+		return false
+	}
+	if !p.advance(len(page.Delegators)) {
+		return false
+	}
+	p.page = page
+	return true
+}
+
+// Page returns the page fetched by the last call to Next.
+func (p *GetValidatorDelegatorsPager) Page() *v1.ValidatorDelegatorList {
+	return p.page
+}
+
+// GetValidatorDebondingDelegatorsParams are the query parameters of GetValidatorDebondingDelegators.
+type GetValidatorDebondingDelegatorsParams struct {
+	// The maximum numbers of items to return.
+	Limit *int
+
+	// The number of items to skip before starting to collect the result set.
+	Offset *int
+
+	// The fields to order by, each prefixed by `-` for descending order. Defaults
+	// to `debond_end`.
+	OrderBy []string
+
+	// A filter on the address of the delegator.
+	Address Filter
+
+	// A filter on the amount debonding, in base units.
+	Amount Filter
+
+	// A filter on the shares of the debonding delegation.
+	Shares Filter
+
+	// A filter on the epoch at which the debonding ends.
+	DebondEnd Filter
+}
+
+func (p *GetValidatorDebondingDelegatorsParams) query() url.Values {
+	q := url.Values{}
+	if p == nil {
+		return q
+	}
+	addParam(q, "limit", p.Limit)
+	addParam(q, "offset", p.Offset)
+	addParam(q, "order_by", p.OrderBy)
+	addParam(q, "address", p.Address)
+	addParam(q, "amount", p.Amount)
+	addParam(q, "shares", p.Shares)
+	addParam(q, "debond_end", p.DebondEnd)
+	return q
+}
+
+// GetValidatorDebondingDelegators returns the debonding delegations to a
+// validator, along with the total of its debonding delegations.
+func (c *Client) GetValidatorDebondingDelegators(ctx context.Context, entityID string, params *GetValidatorDebondingDelegatorsParams) (*v1.ValidatorDebondingDelegatorList, error) {
+	var out v1.ValidatorDebondingDelegatorList
+	if err := c.doJSON(ctx, http.MethodGet, "/consensus/validators/"+pathParam(entityID)+"/debonding_delegators", params.query(), nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// GetValidatorDebondingDelegatorsPager pages through the results of GetValidatorDebondingDelegators.
+type GetValidatorDebondingDelegatorsPager struct {
+	pager
+	c        *Client
+	entityID string
+	params   GetValidatorDebondingDelegatorsParams
+	page     *v1.ValidatorDebondingDelegatorList
+}
+
+// GetValidatorDebondingDelegatorsPager returns a pager of the results of
+// GetValidatorDebondingDelegators, starting at the offset and with pages of
+// the limit of the parameters.
+func (c *Client) GetValidatorDebondingDelegatorsPager(entityID string, params *GetValidatorDebondingDelegatorsParams) *GetValidatorDebondingDelegatorsPager {
+	p := &GetValidatorDebondingDelegatorsPager{c: c, entityID: entityID}
+	if params != nil {
+		p.params = *params
+	}
+	p.pager = newPager(p.params.Offset, p.params.Limit)
+	return p
+}
+
+// Next fetches the next page, which is then returned by Page. It returns
+// false once there are no more results, or if fetching the page failed.
+func (p *GetValidatorDebondingDelegatorsPager) Next(ctx context.Context) bool {
+	if p.done {
+		return false
+	}
+	params := p.params
+	params.Offset, params.Limit = p.next()
+	page, err := p.c.GetValidatorDebondingDelegators(ctx, p.entityID, &params)
+	if err != nil {
+		p.fail(err)
+		return false
+	}
+	if !p.advance(len(page.DebondingDelegators)) {
+		return false
+	}
+	p.page = page
+	return true
+}
+
+// Page returns the page fetched by the last call to Next.
+func (p *GetValidatorDebondingDelegatorsPager) Page() *v1.ValidatorDebondingDelegatorList {
+	return p.page
+}
+
+// ListAccountsParams are the query parameters of ListAccounts.
+type ListAccountsParams struct {
+	// The maximum numbers of items to return.
+	Limit *int
+
+	// The number of items to skip before starting to collect the result set.
+	Offset *int
+
+	// The block height from which to query state. The Oasis Indexer does not make
+	// any guarantees about availability of historical state data.
+	Height *int64
+
+	// A filter on the minimum available account balance.
+	MinAvailable *int64
+
+	// A filter on the maximum available account balance.
+	MaxAvailable *int64
+
+	// A filter on the minimum active escrow account balance.
+	MinEscrow *int64
+
+	// A filter on the maximum active escrow account balance.
+	MaxEscrow *int64
+
+	// A filter on the minimum debonding account balance.
+	MinDebonding *int64
+
+	// A filter on the maximum debonding account balance.
+	MaxDebonding *int64
+
+	// A filter on the minimum total account balance.
+	MinTotalBalance *int64
+
+	// A filter on the maximum total account balance.
+	MaxTotalBalance *int64
+
+	// The fields to order by, each prefixed by `-` for descending order. Defaults
+	// to `address`.
+	OrderBy []string
+
+	// A filter on the account address.
+	Address Filter
+
+	// A filter on the account nonce.
+	Nonce Filter
+
+	// A filter on the available account balance, e.g. `available[gte]=1000`.
+	Available Filter
+
+	// A filter on the active escrow account balance.
+	Escrow Filter
+
+	// A filter on the debonding account balance.
+	Debonding Filter
+
+	// A filter on the total account balance.
+	TotalBalance Filter
+}
+
+func (p *ListAccountsParams) query() url.Values {
+	q := url.Values{}
+	if p == nil {
+		return q
+	}
+	addParam(q, "limit", p.Limit)
+	addParam(q, "offset", p.Offset)
+	addParam(q, "height", p.Height)
+	addParam(q, "minAvailable", p.MinAvailable)
+	addParam(q, "maxAvailable", p.MaxAvailable)
+	addParam(q, "minEscrow", p.MinEscrow)
+	addParam(q, "maxEscrow", p.MaxEscrow)
+	addParam(q, "minDebonding", p.MinDebonding)
+	addParam(q, "maxDebonding", p.MaxDebonding)
+	addParam(q, "minTotalBalance", p.MinTotalBalance)
+	addParam(q, "maxTotalBalance", p.MaxTotalBalance)
+	addParam(q, "order_by", p.OrderBy)
+	addParam(q, "address", p.Address)
+	addParam(q, "nonce", p.Nonce)
+	addParam(q, "available", p.Available)
+	addParam(q, "escrow", p.Escrow)
+	addParam(q, "debonding", p.Debonding)
+	addParam(q, "total_balance", p.TotalBalance)
+	return q
+}
+
+// ListAccounts returns a list of consensus layer accounts.
+func (c *Client) ListAccounts(ctx context.Context, params *ListAccountsParams) (*v1.AccountList, error) {
+	var out v1.AccountList
+	if err := c.doJSON(ctx, http.MethodGet, "/consensus/accounts", params.query(), nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// ListAccountsPager pages through the results of ListAccounts.
+type ListAccountsPager struct {
+	pager
+	c      *Client
+	params ListAccountsParams
+	page   *v1.AccountList
+}
+
+// ListAccountsPager returns a pager of the results of ListAccounts, starting
+// at the offset and with pages of the limit of the parameters.
+func (c *Client) ListAccountsPager(params *ListAccountsParams) *ListAccountsPager {
+	p := &ListAccountsPager{c: c}
+	if params != nil {
+		p.params = *params
+	}
+	p.pager = newPager(p.params.Offset, p.params.Limit)
+	return p
+}
+
+// Next fetches the next page, which is then returned by Page. It returns
+// false once there are no more results, or if fetching the page failed.
+func (p *ListAccountsPager) Next(ctx context.Context) bool {
+	if p.done {
+		return false
+	}
+	params := p.params
+	params.Offset, params.Limit = p.next()
+	page, err := p.c.ListAccounts(ctx, &params)
+	if err != nil {
+		p.fail(err)
+		return false
+	}
+	if !p.advance(len(page.Accounts)) {
+		return false
+	}
+	p.page = page
+	return true
+}
+
+// Page returns the page fetched by the last call to Next.
+func (p *ListAccountsPager) Page() *v1.AccountList {
+	return p.page
+}
+
+// GetAccountParams are the query parameters of GetAccount.
+type GetAccountParams struct {
+	// The block height from which to query state. The Oasis Indexer does not make
+	// any guarantees about availability of historical state data.
+	Height *int64
+}
+
+func (p *GetAccountParams) query() url.Values {
+	q := url.Values{}
+	if p == nil {
+		return q
+	}
+	addParam(q, "height", p.Height)
+	return q
+}
+
+// GetAccount returns a consensus layer account.
+func (c *Client) GetAccount(ctx context.Context, address string, params *GetAccountParams) (*v1.Account, error) {
+	var out v1.Account
+	if err := c.doJSON(ctx, http.MethodGet, "/consensus/accounts/"+pathParam(address), params.query(), nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// GetDelegations returns an account's delegations.
+func (c *Client) GetDelegations(ctx context.Context, address string) (*v1.DelegationList, error) {
+	var out v1.DelegationList
+	if err := c.doJSON(ctx, http.MethodGet, "/consensus/accounts/"+pathParam(address)+"/delegations", nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// GetDebondingDelegations returns an account's debonding delegations.
+func (c *Client) GetDebondingDelegations(ctx context.Context, address string) (*v1.DebondingDelegationList, error) {
+	var out v1.DebondingDelegationList
+	if err := c.doJSON(ctx, http.MethodGet, "/consensus/accounts/"+pathParam(address)+"/debonding_delegations", nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// ListEpochsParams are the query parameters of ListEpochs.
+type ListEpochsParams struct {
+	// The maximum numbers of items to return.
+	Limit *int
+
+	// The number of items to skip before starting to collect the result set.
+	Offset *int
+}
+
+func (p *ListEpochsParams) query() url.Values {
+	q := url.Values{}
+	if p == nil {
+		return q
+	}
+	addParam(q, "limit", p.Limit)
+	addParam(q, "offset", p.Offset)
+	return q
+}
+
+// ListEpochs returns a list of consensus epochs.
+func (c *Client) ListEpochs(ctx context.Context, params *ListEpochsParams) (*v1.EpochList, error) {
+	var out v1.EpochList
+	if err := c.doJSON(ctx, http.MethodGet, "/consensus/epochs", params.query(), nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// ListEpochsPager pages through the results of ListEpochs.
+type ListEpochsPager struct {
+	pager
+	c      *Client
+	params ListEpochsParams
+	page   *v1.EpochList
+}
+
+// ListEpochsPager returns a pager of the results of ListEpochs, starting at
+// the offset and with pages of the limit of the parameters.
+func (c *Client) ListEpochsPager(params *ListEpochsParams) *ListEpochsPager {
+	p := &ListEpochsPager{c: c}
+	if params != nil {
+		p.params = *params
+	}
+	p.pager = newPager(p.params.Offset, p.params.Limit)
+	return p
+}
+
+// Next fetches the next page, which is then returned by Page. It returns
+// false once there are no more results, or if fetching the page failed.
+func (p *ListEpochsPager) Next(ctx context.Context) bool {
+	if p.done {
+		return false
+	}
+	params := p.params
+	params.Offset, params.Limit = p.next()
+	page, err := p.c.ListEpochs(ctx, &params)
+	if err != nil {
+		p.fail(err)
+		return false
+	}
+	if !p.advance(len(page.Epochs)) {
+		return false
+	}
+	p.page = page
+	return true
+}
+
+// Page returns the page fetched by the last call to Next.
+func (p *ListEpochsPager) Page() *v1.EpochList {
+	return p.page
+}
+
+// GetEpoch returns a consensus epoch.
+func (c *Client) GetEpoch(ctx context.Context, epoch int64) (*v1.Epoch, error) {
+	var out v1.Epoch
+	if err := c.doJSON(ctx, http.MethodGet, "/consensus/epochs/"+pathParam(epoch), nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// GetEpochCommitteesParams are the query parameters of GetEpochCommittees.
+type GetEpochCommitteesParams struct {
+	// The maximum numbers of items to return.
+	Limit *int
+
+	// The number of items to skip before starting to collect the result set.
+	Offset *int
+
+	// A filter on the hex-encoded runtime ID of committees.
+	Runtime *string
+
+	// A filter on the kind of committees.
+	Kind *string
+}
+
+func (p *GetEpochCommitteesParams) query() url.Values {
+	q := url.Values{}
+	if p == nil {
+		return q
+	}
+	addParam(q, "limit", p.Limit)
+	addParam(q, "offset", p.Offset)
+	addParam(q, "runtime", p.Runtime)
+	addParam(q, "kind", p.Kind)
+	return q
+}
+
+// GetEpochCommittees returns the members of the runtime committees of an
+// epoch.
+func (c *Client) GetEpochCommittees(ctx context.Context, epoch int64, params *GetEpochCommitteesParams) (*v1.EpochCommittees, error) {
+	var out v1.EpochCommittees
+	if err := c.doJSON(ctx, http.MethodGet, "/consensus/epochs/"+pathParam(epoch)+"/committees", params.query(), nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// GetEpochCommitteesPager pages through the results of GetEpochCommittees.
+type GetEpochCommitteesPager struct {
+	pager
+	c      *Client
+	epoch  int64
+	params GetEpochCommitteesParams
+	page   *v1.EpochCommittees
+}
+
+// GetEpochCommitteesPager returns a pager of the results of
+// GetEpochCommittees, starting at the offset and with pages of the limit of
+// the parameters.
+func (c *Client) GetEpochCommitteesPager(epoch int64, params *GetEpochCommitteesParams) *GetEpochCommitteesPager {
+	p := &GetEpochCommitteesPager{c: c, epoch: epoch}
+	if params != nil {
+		p.params = *params
+	}
+	p.pager = newPager(p.params.Offset, p.params.Limit)
+	return p
+}
+
+// Next fetches the next page, which is then returned by Page. It returns
+// false once there are no more results, or if fetching the page failed.
+func (p *GetEpochCommitteesPager) Next(ctx context.Context) bool {
+	if p.done {
+		return false
+	}
+	params := p.params
+	params.Offset, params.Limit = p.next()
+	page, err := p.c.GetEpochCommittees(ctx, p.epoch, &params)
+	if err != nil {
+		p.fail(err)
+		return false
+	}
+	if !p.advance(len(page.Members)) {
+		return false
+	}
+	p.page = page
+	return true
+}
+
+// Page returns the page fetched by the last call to Next.
+func (p *GetEpochCommitteesPager) Page() *v1.EpochCommittees {
+	return p.page
+}
+
+// GetEpochValidatorsParams are the query parameters of GetEpochValidators.
+type GetEpochValidatorsParams struct {
+	// The maximum numbers of items to return.
+	Limit *int
+
+	// The number of items to skip before starting to collect the result set.
+	Offset *int
+}
+
+func (p *GetEpochValidatorsParams) query() url.Values {
+	q := url.Values{}
+	if p == nil {
+		return q
+	}
+	addParam(q, "limit", p.Limit)
+	addParam(q, "offset", p.Offset)
+	return q
+}
+
+// GetEpochValidators returns the consensus validators of an epoch, by
+// descending voting power.
+func (c *Client) GetEpochValidators(ctx context.Context, epoch int64, params *GetEpochValidatorsParams) (*v1.EpochValidators, error) {
+	var out v1.EpochValidators
+	if err := c.doJSON(ctx, http.MethodGet, "/consensus/epochs/"+pathParam(epoch)+"/validators", params.query(), nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// GetEpochValidatorsPager pages through the results of GetEpochValidators.
+type GetEpochValidatorsPager struct {
+	pager
+	c      *Client
+	epoch  int64
+	params GetEpochValidatorsParams
+	page   *v1.EpochValidators
+}
+
+// GetEpochValidatorsPager returns a pager of the results of
+// GetEpochValidators, starting at the offset and with pages of the limit of
+// the parameters.
+func (c *Client) GetEpochValidatorsPager(epoch int64, params *GetEpochValidatorsParams) *GetEpochValidatorsPager {
+	p := &GetEpochValidatorsPager{c: c, epoch: epoch}
+	if params != nil {
+		p.params = *params
+	}
+	p.pager = newPager(p.params.Offset, p.params.Limit)
+	return p
+}
+
+// Next fetches the next page, which is then returned by Page. It returns
+// false once there are no more results, or if fetching the page failed.
+func (p *GetEpochValidatorsPager) Next(ctx context.Context) bool {
+	if p.done {
+		return false
+	}
+	params := p.params
+	params.Offset, params.Limit = p.next()
+	page, err := p.c.GetEpochValidators(ctx, p.epoch, &params)
+	if err != nil {
+		p.fail(err)
+		return false
+	}
+	if !p.advance(len(page.Validators)) {
+		return false
+	}
+	p.page = page
+	return true
+}
+
+// Page returns the page fetched by the last call to Next.
+func (p *GetEpochValidatorsPager) Page() *v1.EpochValidators {
+	return p.page
+}
+
+// ListProposalsParams are the query parameters of ListProposals.
+type ListProposalsParams struct {
+	// The maximum numbers of items to return.
+	Limit *int
+
+	// The number of items to skip before starting to collect the result set.
+	Offset *int
+}
+
+func (p *ListProposalsParams) query() url.Values {
+	q := url.Values{}
+	if p == nil {
+		return q
+	}
+	addParam(q, "limit", p.Limit)
+	addParam(q, "offset", p.Offset)
+	return q
+}
+
+// ListProposals returns a list of governance proposals.
+func (c *Client) ListProposals(ctx context.Context, params *ListProposalsParams) (*v1.ProposalList, error) {
+	var out v1.ProposalList
+	if err := c.doJSON(ctx, http.MethodGet, "/consensus/proposals", params.query(), nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// ListProposalsPager pages through the results of ListProposals.
+type ListProposalsPager struct {
+	pager
+	c      *Client
+	params ListProposalsParams
+	page   *v1.ProposalList
+}
+
+// ListProposalsPager returns a pager of the results of ListProposals, starting
+// at the offset and with pages of the limit of the parameters.
+func (c *Client) ListProposalsPager(params *ListProposalsParams) *ListProposalsPager {
+	p := &ListProposalsPager{c: c}
+	if params != nil {
+		p.params = *params
+	}
+	p.pager = newPager(p.params.Offset, p.params.Limit)
+	return p
+}
+
+// Next fetches the next page, which is then returned by Page. It returns
+// false once there are no more results, or if fetching the page failed.
+func (p *ListProposalsPager) Next(ctx context.Context) bool {
+	if p.done {
+		return false
+	}
+	params := p.params
+	params.Offset, params.Limit = p.next()
+	page, err := p.c.ListProposals(ctx, &params)
+	if err != nil {
+		p.fail(err)
+		return false
+	}
+	if !p.advance(len(page.Proposals)) {
+		return false
+	}
+	p.page = page
+	return true
+}
+
+// Page returns the page fetched by the last call to Next.
+func (p *ListProposalsPager) Page() *v1.ProposalList {
+	return p.page
+}
+
+// GetProposal returns a governance proposal.
+func (c *Client) GetProposal(ctx context.Context, proposalID int64) (*v1.Proposal, error) {
+	var out v1.Proposal
+	if err := c.doJSON(ctx, http.MethodGet, "/consensus/proposals/"+pathParam(proposalID), nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// GetProposalVotesParams are the query parameters of GetProposalVotes.
+type GetProposalVotesParams struct {
+	// The maximum numbers of items to return.
+	Limit *int
+
+	// The number of items to skip before starting to collect the result set.
+	Offset *int
+}
+
+func (p *GetProposalVotesParams) query() url.Values {
+	q := url.Values{}
+	if p == nil {
+		return q
+	}
+	addParam(q, "limit", p.Limit)
+	addParam(q, "offset", p.Offset)
+	return q
+}
+
+// GetProposalVotes returns a list of votes for a governance proposal.
+func (c *Client) GetProposalVotes(ctx context.Context, proposalID int64, params *GetProposalVotesParams) (*v1.ProposalVotes, error) {
+	var out v1.ProposalVotes
+	if err := c.doJSON(ctx, http.MethodGet, "/consensus/proposals/"+pathParam(proposalID)+"/votes", params.query(), nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// GetProposalVotesPager pages through the results of GetProposalVotes.
+type GetProposalVotesPager struct {
+	pager
+	c          *Client
+	proposalID int64
+	params     GetProposalVotesParams
+	page       *v1.ProposalVotes
+}
+
+// GetProposalVotesPager returns a pager of the results of GetProposalVotes,
+// starting at the offset and with pages of the limit of the parameters.
+func (c *Client) GetProposalVotesPager(proposalID int64, params *GetProposalVotesParams) *GetProposalVotesPager {
+	p := &GetProposalVotesPager{c: c, proposalID: proposalID}
+	if params != nil {
+		p.params = *params
+	}
+	p.pager = newPager(p.params.Offset, p.params.Limit)
+	return p
+}
+
+// Next fetches the next page, which is then returned by Page. It returns
+// false once there are no more results, or if fetching the page failed.
+func (p *GetProposalVotesPager) Next(ctx context.Context) bool {
+	if p.done {
+		return false
+	}
+	params := p.params
+	params.Offset, params.Limit = p.next()
+	page, err := p.c.GetProposalVotes(ctx, p.proposalID, &params)
+	if err != nil {
+		p.fail(err)
+		return false
+	}
+	if !p.advance(len(page.Votes)) {
+		return false
+	}
+	p.page = page
+	return true
+}
+
+// Page returns the page fetched by the last call to Next.
+func (p *GetProposalVotesPager) Page() *v1.ProposalVotes {
+	return p.page
+}
+
+// ListTransactionsPerSecondParams are the query parameters of ListTransactionsPerSecond.
+type ListTransactionsPerSecondParams struct {
+	// The maximum numbers of items to return.
+	Limit *int
+
+	// The number of items to skip before starting to collect the result set.
+	Offset *int
+}
+
+func (p *ListTransactionsPerSecondParams) query() url.Values {
+	q := url.Values{}
+	if p == nil {
+		return q
+	}
+	addParam(q, "limit", p.Limit)
+	addParam(q, "offset", p.Offset)
+	return q
+}
+
+// ListTransactionsPerSecond returns the consensus layer TPS for each 5 minute
+// interval.
+func (c *Client) ListTransactionsPerSecond(ctx context.Context, params *ListTransactionsPerSecondParams) (*v1.TpsCheckpointList, error) {
+	var out v1.TpsCheckpointList
+	if err := c.doJSON(ctx, http.MethodGet, "/consensus/stats/tps", params.query(), nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// ListTransactionsPerSecondPager pages through the results of ListTransactionsPerSecond.
+type ListTransactionsPerSecondPager struct {
+	pager
+	c      *Client
+	params ListTransactionsPerSecondParams
+	page   *v1.TpsCheckpointList
+}
+
+// ListTransactionsPerSecondPager returns a pager of the results of
+// ListTransactionsPerSecond, starting at the offset and with pages of the
+// limit of the parameters.
+func (c *Client) ListTransactionsPerSecondPager(params *ListTransactionsPerSecondParams) *ListTransactionsPerSecondPager {
+	p := &ListTransactionsPerSecondPager{c: c}
+	if params != nil {
+		p.params = *params
+	}
+	p.pager = newPager(p.params.Offset, p.params.Limit)
+	return p
+}
+
+// Next fetches the next page, which is then returned by Page. It returns
+// false once there are no more results, or if fetching the page failed.
+func (p *ListTransactionsPerSecondPager) Next(ctx context.Context) bool {
+	if p.done {
+		return false
+	}
+	params := p.params
+	params.Offset, params.Limit = p.next()
+	page, err := p.c.ListTransactionsPerSecond(ctx, &params)
+	if err != nil {
+		p.fail(err)
+		return false
+	}
+	if !p.advance(len(page.TpsCheckpoints)) {
+		return false
+	}
+	p.page = page
+	return true
+}
+
+// Page returns the page fetched by the last call to Next.
+func (p *ListTransactionsPerSecondPager) Page() *v1.TpsCheckpointList {
+	return p.page
+}
+
+// ListDailyVolumeParams are the query parameters of ListDailyVolume.
+type ListDailyVolumeParams struct {
+	// The maximum numbers of items to return.
+	Limit *int
+
+	// The number of items to skip before starting to collect the result set.
+	Offset *int
+}
+
+func (p *ListDailyVolumeParams) query() url.Values {
+	q := url.Values{}
+	if p == nil {
+		return q
+	}
+	addParam(q, "limit", p.Limit)
+	addParam(q, "offset", p.Offset)
+	return q
+}
+
+// ListDailyVolume returns the consensus layer daily transaction volume for
+// each day.
+func (c *Client) ListDailyVolume(ctx context.Context, params *ListDailyVolumeParams) (*v1.VolumeList, error) {
+	var out v1.VolumeList
+	if err := c.doJSON(ctx, http.MethodGet, "/consensus/stats/daily_volume", params.query(), nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// ListDailyVolumePager pages through the results of ListDailyVolume.
+type ListDailyVolumePager struct {
+	pager
+	c      *Client
+	params ListDailyVolumeParams
+	page   *v1.VolumeList
+}
+
+// ListDailyVolumePager returns a pager of the results of ListDailyVolume,
+// starting at the offset and with pages of the limit of the parameters.
+func (c *Client) ListDailyVolumePager(params *ListDailyVolumeParams) *ListDailyVolumePager {
+	p := &ListDailyVolumePager{c: c}
+	if params != nil {
+		p.params = *params
+	}
+	p.pager = newPager(p.params.Offset, p.params.Limit)
+	return p
+}
+
+// Next fetches the next page, which is then returned by Page. It returns
+// false once there are no more results, or if fetching the page failed.
+func (p *ListDailyVolumePager) Next(ctx context.Context) bool {
+	if p.done {
+		return false
+	}
+	params := p.params
+	params.Offset, params.Limit = p.next()
+	page, err := p.c.ListDailyVolume(ctx, &params)
+	if err != nil {
+		p.fail(err)
+		return false
+	}
+	if !p.advance(len(page.Volumes)) {
+		return false
+	}
+	p.page = page
+	return true
+}
+
+// Page returns the page fetched by the last call to Next.
+func (p *ListDailyVolumePager) Page() *v1.VolumeList {
+	return p.page
+}
+
+// ListWebhookSubscriptionsParams are the query parameters of ListWebhookSubscriptions.
+type ListWebhookSubscriptionsParams struct {
+	// The maximum numbers of items to return.
+	Limit *int
+
+	// The number of items to skip before starting to collect the result set.
+	Offset *int
+}
+
+func (p *ListWebhookSubscriptionsParams) query() url.Values {
+	q := url.Values{}
+	if p == nil {
+		return q
+	}
+	addParam(q, "limit", p.Limit)
+	addParam(q, "offset", p.Offset)
+	return q
+}
+
+// ListWebhookSubscriptions returns a list of the webhook subscriptions of the
+// API key of the request.
+func (c *Client) ListWebhookSubscriptions(ctx context.Context, params *ListWebhookSubscriptionsParams) (*v1.WebhookSubscriptionList, error) {
+	var out v1.WebhookSubscriptionList
+	if err := c.doJSON(ctx, http.MethodGet, "/consensus/webhooks", params.query(), nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// ListWebhookSubscriptionsPager pages through the results of ListWebhookSubscriptions.
+type ListWebhookSubscriptionsPager struct {
+	pager
+	c      *Client
+	params ListWebhookSubscriptionsParams
+	page   *v1.WebhookSubscriptionList
+}
+
+// ListWebhookSubscriptionsPager returns a pager of the results of
+// ListWebhookSubscriptions, starting at the offset and with pages of the limit
+// of the parameters.
+func (c *Client) ListWebhookSubscriptionsPager(params *ListWebhookSubscriptionsParams) *ListWebhookSubscriptionsPager {
+	p := &ListWebhookSubscriptionsPager{c: c}
+	if params != nil {
+		p.params = *params
+	}
+	p.pager = newPager(p.params.Offset, p.params.Limit)
+	return p
+}
+
+// Next fetches the next page, which is then returned by Page. It returns
+// false once there are no more results, or if fetching the page failed.
+func (p *ListWebhookSubscriptionsPager) Next(ctx context.Context) bool {
+	if p.done {
+		return false
+	}
+	params := p.params
+	params.Offset, params.Limit = p.next()
+	page, err := p.c.ListWebhookSubscriptions(ctx, &params)
+	if err != nil {
+		p.fail(err)
+		return false
+	}
+	if !p.advance(len(page.Subscriptions)) {
+		return false
+	}
+	p.page = page
+	return true
+}
+
+// Page returns the page fetched by the last call to Next.
+func (p *ListWebhookSubscriptionsPager) Page() *v1.WebhookSubscriptionList {
+	return p.page
+}
+
+// CreateWebhookSubscription creates a webhook subscription.
+func (c *Client) CreateWebhookSubscription(ctx context.Context, body *v1.WebhookSubscriptionRequest) (*v1.WebhookSubscription, error) {
+	var out v1.WebhookSubscription
+	if err := c.doJSON(ctx, http.MethodPost, "/consensus/webhooks", nil, body, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// GetWebhookSubscription returns a webhook subscription.
+func (c *Client) GetWebhookSubscription(ctx context.Context, subscriptionID int64) (*v1.WebhookSubscription, error) {
+	var out v1.WebhookSubscription
+	if err := c.doJSON(ctx, http.MethodGet, "/consensus/webhooks/"+pathParam(subscriptionID), nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// DeleteWebhookSubscription deletes a webhook subscription and its dead
+// letters.
+func (c *Client) DeleteWebhookSubscription(ctx context.Context, subscriptionID int64) error {
+	return c.doJSON(ctx, http.MethodDelete, "/consensus/webhooks/"+pathParam(subscriptionID), nil, nil, nil)
+}
+
+// ListWebhookDeadLettersParams are the query parameters of ListWebhookDeadLetters.
+type ListWebhookDeadLettersParams struct {
+	// The maximum numbers of items to return.
+	Limit *int
+
+	// The number of items to skip before starting to collect the result set.
+	Offset *int
+}
+
+func (p *ListWebhookDeadLettersParams) query() url.Values {
+	q := url.Values{}
+	if p == nil {
+		return q
+	}
+	addParam(q, "limit", p.Limit)
+	addParam(q, "offset", p.Offset)
+	return q
+}
+
+// ListWebhookDeadLetters returns a list of failed deliveries for a webhook
+// subscription.
+func (c *Client) ListWebhookDeadLetters(ctx context.Context, subscriptionID int64, params *ListWebhookDeadLettersParams) (*v1.WebhookDeadLetterList, error) {
+	var out v1.WebhookDeadLetterList
+	if err := c.doJSON(ctx, http.MethodGet, "/consensus/webhooks/"+pathParam(subscriptionID)+"/dead_letters", params.query(), nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// ListWebhookDeadLettersPager pages through the results of ListWebhookDeadLetters.
+type ListWebhookDeadLettersPager struct {
+	pager
+	c              *Client
+	subscriptionID int64
+	params         ListWebhookDeadLettersParams
+	page           *v1.WebhookDeadLetterList
+}
+
+// ListWebhookDeadLettersPager returns a pager of the results of
+// ListWebhookDeadLetters, starting at the offset and with pages of the limit
+// of the parameters.
+func (c *Client) ListWebhookDeadLettersPager(subscriptionID int64, params *ListWebhookDeadLettersParams) *ListWebhookDeadLettersPager {
+	p := &ListWebhookDeadLettersPager{c: c, subscriptionID: subscriptionID}
+	if params != nil {
+		p.params = *params
+	}
+	p.pager = newPager(p.params.Offset, p.params.Limit)
+	return p
+}
+
+// Next fetches the next page, which is then returned by Page. It returns
+// false once there are no more results, or if fetching the page failed.
+func (p *ListWebhookDeadLettersPager) Next(ctx context.Context) bool {
+	if p.done {
+		return false
+	}
+	params := p.params
+	params.Offset, params.Limit = p.next()
+	page, err := p.c.ListWebhookDeadLetters(ctx, p.subscriptionID, &params)
+	if err != nil {
+		p.fail(err)
+		return false
+	}
+	if !p.advance(len(page.DeadLetters)) {
+		return false
+	}
+	p.page = page
+	return true
+}
+
+// Page returns the page fetched by the last call to Next.
+func (p *ListWebhookDeadLettersPager) Page() *v1.WebhookDeadLetterList {
+	return p.page
+}
+
+// ExportAccountActivityParams are the query parameters of ExportAccountActivity.
+type ExportAccountActivityParams struct {
+	// The staking address of the account.
+	Address string
+
+	// A filter on minimum block height, inclusive.
+	From *int64
+
+	// A filter on maximum block height, inclusive.
+	To *int64
+
+	// A filter on minimum block time, inclusive.
+	After *time.Time
+
+	// A filter on maximum block time, inclusive.
+	Before *time.Time
+}
+
+func (p *ExportAccountActivityParams) query() url.Values {
+	q := url.Values{}
+	if p == nil {
+		return q
+	}
+	addParam(q, "address", p.Address)
+	addParam(q, "from", p.From)
+	addParam(q, "to", p.To)
+	addParam(q, "after", p.After)
+	addParam(q, "before", p.Before)
+	return q
+}
+
+// ExportAccountActivity streams the activity of a consensus account, which are
+// the transactions it sent and the staking events involving it, in order.
+//
+// The response is of the accept media type, which is one of
+// application/x-ndjson, text/csv.
+func (c *Client) ExportAccountActivity(ctx context.Context, params *ExportAccountActivityParams, accept string) (io.ReadCloser, error) {
+	return c.doStream(ctx, http.MethodGet, "/consensus/export/account_activity", params.query(), accept)
+}
+
+// ExportStakingEventsParams are the query parameters of ExportStakingEvents.
+type ExportStakingEventsParams struct {
+	// A filter on the event type.
+	Type *string
+
+	// A filter on minimum block height, inclusive.
+	From *int64
+
+	// A filter on maximum block height, inclusive.
+	To *int64
+
+	// A filter on minimum block time, inclusive.
+	After *time.Time
+
+	// A filter on maximum block time, inclusive.
+	Before *time.Time
+}
+
+func (p *ExportStakingEventsParams) query() url.Values {
+	q := url.Values{}
+	if p == nil {
+		return q
+	}
+	addParam(q, "type", p.Type)
+	addParam(q, "from", p.From)
+	addParam(q, "to", p.To)
+	addParam(q, "after", p.After)
+	addParam(q, "before", p.Before)
+	return q
+}
+
+// ExportStakingEvents streams consensus staking events in order, as
+// newline-delimited JSON or, if requested with an `Accept` header, as CSV.
+//
+// The response is of the accept media type, which is one of
+// application/x-ndjson, text/csv.
+func (c *Client) ExportStakingEvents(ctx context.Context, params *ExportStakingEventsParams, accept string) (io.ReadCloser, error) {
+	return c.doStream(ctx, http.MethodGet, "/consensus/export/staking_events", params.query(), accept)
+}
+
+// StreamParams are the query parameters of Stream.
+type StreamParams struct {
+	// The kind of data to stream.
+	Topic string
+
+	// The height or round from which to resume the stream. At most 10000 heights
+	// may be replayed. Defaults to the next height.
+	From *int64
+
+	// Only stream transactions with this method. Only valid for the
+	// `consensus_transactions` topic.
+	Method *string
+
+	// Only stream transactions from this sender. Only valid for the
+	// `consensus_transactions` topic.
+	Sender *string
+
+	// Only stream events of this type. Only valid for the `consensus_events`
+	// topic.
+	Type *string
+}
+
+func (p *StreamParams) query() url.Values {
+	q := url.Values{}
+	if p == nil {
+		return q
+	}
+	addParam(q, "topic", p.Topic)
+	addParam(q, "from", p.From)
+	addParam(q, "method", p.Method)
+	addParam(q, "sender", p.Sender)
+	addParam(q, "type", p.Type)
+	return q
+}
+
+// Stream streams newly indexed data as Server-Sent Events or, if the request
+// is a WebSocket upgrade, as WebSocket text messages.
+func (c *Client) Stream(ctx context.Context, params *StreamParams) (io.ReadCloser, error) {
+	return c.doStream(ctx, http.MethodGet, "/stream", params.query(), "text/event-stream")
+}
@@ -0,0 +1,351 @@
+// Package client is a client of the V1 API of the Oasis Indexer.
+//
+// The methods of the client are generated from the OpenAPI specification of
+// the API, and decode responses into the types of the API server, so that the
+// client cannot drift from the server.
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/oasisprotocol/oasis-indexer/api/auth"
+	"github.com/oasisprotocol/oasis-indexer/api/common"
+)
+
+//go:generate go run ../../spec/gen -client -package client -o client.gen.go
+
+const (
+	// DefaultRetries is the number of times failed requests are retried
+	// by default.
+	DefaultRetries = 3
+	// DefaultBackoff is the time waited before the first retry by default.
+	// It is doubled with each retry.
+	DefaultBackoff = 500 * time.Millisecond
+
+	// maxBackoff is the longest time waited before a retry. Requests that
+	// may not be retried sooner, such as requests over a daily quota,
+	// are not retried.
+	maxBackoff = 30 * time.Second
+
+	// maxErrorSize is the size of the largest error response read.
+	maxErrorSize = 1 << 16
+)
+
+// Client is a client of the V1 API.
+type Client struct {
+	server  string
+	http    *http.Client
+	apiKey  string
+	retries int
+	backoff time.Duration
+}
+
+// Option is an option of a client.
+type Option func(c *Client)
+
+// WithHTTPClient sets the HTTP client that requests are sent with.
+func WithHTTPClient(hc *http.Client) Option {
+	return func(c *Client) {
+		c.http = hc
+	}
+}
+
+// WithAPIKey sets the API key that requests are authenticated with.
+func WithAPIKey(key string) Option {
+	return func(c *Client) {
+		c.apiKey = key
+	}
+}
+
+// WithRetries sets the number of times failed requests are retried, and
+// the time waited before the first retry.
+func WithRetries(retries int, backoff time.Duration) Option {
+	return func(c *Client) {
+		c.retries = retries
+		c.backoff = backoff
+	}
+}
+
+// New returns a client of the API served at the server URL, such
+// as http://index.oasis.dev/v1.
+func New(server string, opts ...Option) *Client {
+	c := &Client{
+		server:  strings.TrimSuffix(server, "/"),
+		http:    http.DefaultClient,
+		retries: DefaultRetries,
+		backoff: DefaultBackoff,
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+// Error is an error response of the API. Errors are told apart by their
+// Code, which is empty if the response was not from the indexer.
+type Error struct {
+	// StatusCode is the HTTP status code of the response.
+	StatusCode int
+
+	common.ErrorResponse
+}
+
+func (e *Error) Error() string {
+	if e.Code == "" {
+		return fmt.Sprintf("indexer responded with %d: %s", e.StatusCode, e.Msg)
+	}
+	return fmt.Sprintf("indexer responded with %d %s: %s", e.StatusCode, e.Code, e.Msg)
+}
+
+// Filter filters the results of a list by a field. It maps operators, such
+// as gt, to their operands, which are sent as field[operator]=operand.
+type Filter map[string]string
+
+// doJSON sends a request, and decodes the JSON response into out
+// unless it is nil.
+func (c *Client) doJSON(ctx context.Context, method string, path string, query url.Values, body interface{}, out interface{}) error {
+	resp, err := c.do(ctx, method, path, query, body, "application/json")
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if out == nil {
+		return nil
+	}
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("failed to decode response: %w", err)
+	}
+	return nil
+}
+
+// doStream sends a request, and returns the body of the response of
+// the accepted media type. The caller must close it.
+func (c *Client) doStream(ctx context.Context, method string, path string, query url.Values, accept string) (io.ReadCloser, error) {
+	resp, err := c.do(ctx, method, path, query, nil, accept)
+	if err != nil {
+		return nil, err
+	}
+	return resp.Body, nil
+}
+
+// do sends a request, and returns the response if it is successful.
+// Otherwise, the error response is returned as an *Error.
+//
+// Rate limited requests are retried, as are requests of idempotent methods
+// that failed to be sent or found the server unavailable. The client waits
+// as long as the server asks it to before retrying, and otherwise backs off
+// exponentially.
+func (c *Client) do(ctx context.Context, method string, path string, query url.Values, body interface{}, accept string) (*http.Response, error) {
+	var payload []byte
+	if body != nil {
+		var err error
+		if payload, err = json.Marshal(body); err != nil {
+			return nil, fmt.Errorf("failed to encode request: %w", err)
+		}
+	}
+	target := c.server + path
+	if len(query) > 0 {
+		target += "?" + query.Encode()
+	}
+
+	backoff := c.backoff
+	for attempt := 0; ; attempt++ {
+		req, err := http.NewRequestWithContext(ctx, method, target, bytes.NewReader(payload))
+		if err != nil {
+			return nil, err
+		}
+		req.Header.Set("accept", accept)
+		if payload != nil {
+			req.Header.Set("content-type", "application/json")
+		}
+		if c.apiKey != "" {
+			req.Header.Set(auth.KeyHeader, c.apiKey)
+		}
+
+		var wait time.Duration
+		resp, err := c.http.Do(req)
+		switch {
+		case err != nil:
+			if ctx.Err() != nil || !isIdempotent(method) || attempt >= c.retries {
+				return nil, err
+			}
+		case resp.StatusCode < http.StatusMultipleChoices:
+			return resp, nil
+		default:
+			respErr := decodeError(resp)
+			if !isRetryable(method, resp.StatusCode) || attempt >= c.retries {
+				return nil, respErr
+			}
+			wait = retryAfter(resp)
+			if wait > maxBackoff {
+				return nil, respErr
+			}
+		}
+
+		if wait == 0 {
+			wait = backoff
+			if backoff *= 2; backoff > maxBackoff {
+				backoff = maxBackoff
+			}
+		}
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(wait):
+		}
+	}
+}
+
+// decodeError decodes an error response, and closes its body.
+func decodeError(resp *http.Response) *Error {
+	defer resp.Body.Close()
+
+	e := Error{StatusCode: resp.StatusCode}
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxErrorSize))
+	if err != nil || json.Unmarshal(body, &e.ErrorResponse) != nil || e.Msg == "" {
+		e.Msg = http.StatusText(resp.StatusCode)
+	}
+	return &e
+}
+
+// isIdempotent returns true if requests of the method may be sent
+// several times.
+func isIdempotent(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodPut, http.MethodDelete:
+		return true
+	default:
+		return false
+	}
+}
+
+// isRetryable returns true if a request of the method that failed
+// with the status code may be retried.
+func isRetryable(method string, status int) bool {
+	switch status {
+	case http.StatusTooManyRequests:
+		// Rate limited requests are rejected before they are handled.
+		return true
+	case http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return isIdempotent(method)
+	default:
+		return false
+	}
+}
+
+// retryAfter returns the time the server asked to wait before a retry,
+// or zero if it did not.
+func retryAfter(resp *http.Response) time.Duration {
+	seconds, err := strconv.Atoi(resp.Header.Get("retry-after"))
+	if err != nil || seconds < 0 {
+		return 0
+	}
+	return time.Duration(seconds) * time.Second
+}
+
+// addParam adds the value of a query parameter to a query, unless
+// it is not set.
+func addParam(q url.Values, name string, v interface{}) {
+	switch v := v.(type) {
+	case string:
+		if v != "" {
+			q.Set(name, v)
+		}
+	case *string:
+		if v != nil {
+			q.Set(name, *v)
+		}
+	case *int:
+		if v != nil {
+			q.Set(name, strconv.Itoa(*v))
+		}
+	case *int64:
+		if v != nil {
+			q.Set(name, strconv.FormatInt(*v, 10))
+		}
+	case *bool:
+		if v != nil {
+			q.Set(name, strconv.FormatBool(*v))
+		}
+	case *time.Time:
+		if v != nil {
+			q.Set(name, v.Format(time.RFC3339Nano))
+		}
+	case []string:
+		if len(v) > 0 {
+			q.Set(name, strings.Join(v, ","))
+		}
+	case Filter:
+		for op, operand := range v {
+			q.Set(name+"["+op+"]", operand)
+		}
+	default:
+		panic(fmt.Sprintf("client: unsupported type %T of parameter %s", v, name))
+	}
+}
+
+// pathParam formats the value of a path parameter.
+func pathParam(v interface{}) string {
+	return url.PathEscape(fmt.Sprint(v))
+}
+
+// pager is the position of a pager in a paginated list.
+type pager struct {
+	offset int
+	limit  int
+	done   bool
+	err    error
+}
+
+func newPager(offset *int, limit *int) pager {
+	p := pager{limit: int(common.DefaultLimit)}
+	if offset != nil {
+		p.offset = *offset
+	}
+	if limit != nil && *limit > 0 {
+		p.limit = *limit
+	}
+	if p.limit > int(common.MaximumLimit) {
+		// The server would return shorter pages, which would
+		// be mistaken for the last.
+		p.limit = int(common.MaximumLimit)
+	}
+	return p
+}
+
+// next returns the offset and limit of the next page.
+func (p *pager) next() (*int, *int) {
+	offset, limit := p.offset, p.limit
+	return &offset, &limit
+}
+
+// advance moves past a page of n results, and returns false if it is
+// empty. Pages that are shorter than the limit are the last.
+func (p *pager) advance(n int) bool {
+	p.offset += n
+	if n < p.limit {
+		p.done = true
+	}
+	return n > 0
+}
+
+// fail stops the pager on an error.
+func (p *pager) fail(err error) {
+	p.err = err
+	p.done = true
+}
+
+// Err returns the error of fetching a page, if any.
+func (p *pager) Err() error {
+	return p.err
+}
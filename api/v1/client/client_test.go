@@ -0,0 +1,270 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/oasisprotocol/oasis-indexer/api/auth"
+	"github.com/oasisprotocol/oasis-indexer/api/common"
+	"github.com/oasisprotocol/oasis-indexer/api/spec"
+	v1 "github.com/oasisprotocol/oasis-indexer/api/v1"
+)
+
+func newTestClient(t *testing.T, handler http.HandlerFunc, opts ...Option) *Client {
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+	return New(server.URL+"/v1/", append([]Option{WithRetries(DefaultRetries, time.Millisecond)}, opts...)...)
+}
+
+func reply(w http.ResponseWriter, code int, v interface{}) {
+	w.Header().Set("content-type", "application/json")
+	w.WriteHeader(code)
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+func TestGeneratedClient(t *testing.T) {
+	doc, err := spec.V1()
+	require.Nil(t, err)
+	src, err := spec.GenerateClient(doc, "client", "github.com/oasisprotocol/oasis-indexer/api/v1")
+	require.Nil(t, err)
+
+	generated, err := ioutil.ReadFile("client.gen.go")
+	require.Nil(t, err)
+	require.Equal(t, string(src), string(generated), "client.gen.go is out of date, run go generate")
+}
+
+func TestRequests(t *testing.T) {
+	var r *http.Request
+	var body []byte
+	c := newTestClient(t, func(w http.ResponseWriter, req *http.Request) {
+		r = req
+		body, _ = io.ReadAll(req.Body)
+		switch req.URL.Path {
+		case "/v1/consensus/accounts":
+			reply(w, http.StatusOK, v1.AccountList{Accounts: []v1.Account{{Address: "oasis1", Available: 100}}})
+		case "/v1/consensus/webhooks":
+			reply(w, http.StatusCreated, v1.WebhookSubscription{ID: 1, Kind: "transfer"})
+		case "/v1/consensus/webhooks/1":
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			reply(w, http.StatusOK, map[string]interface{}{})
+		}
+	}, WithAPIKey("key"))
+	ctx := context.Background()
+
+	limit := 10
+	after := time.Date(2022, 4, 11, 9, 30, 0, 0, time.UTC)
+	accounts, err := c.ListAccounts(ctx, &ListAccountsParams{
+		Limit:     &limit,
+		OrderBy:   []string{"-escrow", "address"},
+		Available: Filter{"gte": "1000"},
+	})
+	require.Nil(t, err)
+	require.Equal(t, "oasis1", accounts.Accounts[0].Address)
+	require.Equal(t, uint64(100), accounts.Accounts[0].Available)
+	require.Equal(t, "available%5Bgte%5D=1000&limit=10&order_by=-escrow%2Caddress", r.URL.RawQuery)
+	require.Equal(t, "key", r.Header.Get(auth.KeyHeader))
+	require.Equal(t, "application/json", r.Header.Get("accept"))
+
+	_, err = c.ListBlocks(ctx, &ListBlocksParams{After: &after})
+	require.Nil(t, err)
+	require.Equal(t, "/v1/consensus/blocks", r.URL.Path)
+	require.Equal(t, "2022-04-11T09:30:00Z", r.URL.Query().Get("after"))
+
+	_, err = c.GetEntityNode(ctx, "entity/id", "node", nil)
+	require.Nil(t, err)
+	require.Equal(t, "/v1/consensus/entities/entity%2Fid/nodes/node", r.URL.RawPath)
+	require.Empty(t, r.URL.RawQuery)
+
+	sub, err := c.CreateWebhookSubscription(ctx, &v1.WebhookSubscriptionRequest{Kind: "transfer", URL: "https://example.com"})
+	require.Nil(t, err)
+	require.Equal(t, int64(1), sub.ID)
+	require.Equal(t, http.MethodPost, r.Method)
+	require.Equal(t, "application/json", r.Header.Get("content-type"))
+	require.JSONEq(t, `{"kind": "transfer", "url": "https://example.com"}`, string(body))
+
+	require.Nil(t, c.DeleteWebhookSubscription(ctx, 1))
+	require.Equal(t, http.MethodDelete, r.Method)
+}
+
+func TestExports(t *testing.T) {
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("content-type", r.Header.Get("accept"))
+		_, _ = w.Write([]byte("height,type\n8048956,Transfer\n"))
+	})
+
+	address := "oasis1qpg2xuz46g53737343r20yxeddhlvc2ldqsjh70p"
+	body, err := c.ExportAccountActivity(context.Background(), &ExportAccountActivityParams{Address: address}, "text/csv")
+	require.Nil(t, err)
+	defer body.Close()
+	csv, err := io.ReadAll(body)
+	require.Nil(t, err)
+	require.Equal(t, "height,type\n8048956,Transfer\n", string(csv))
+}
+
+func TestErrors(t *testing.T) {
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/v1/consensus/blocks/1":
+			reply(w, http.StatusBadRequest, map[string]string{"msg": "invalid request parameters"})
+		case "/v1/consensus/blocks/3":
+			reply(w, http.StatusServiceUnavailable, common.ErrorResponse{
+				Code:      common.CodeNotIndexed,
+				Status:    http.StatusServiceUnavailable,
+				Msg:       "not yet indexed",
+				RequestID: "2f4c0a9e-7b61-4a4e-9d3e-1f7c2b8a6d15",
+			})
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	})
+	ctx := context.Background()
+
+	_, err := c.GetBlock(ctx, 1)
+	var apiErr *Error
+	require.True(t, errors.As(err, &apiErr))
+	require.Equal(t, http.StatusBadRequest, apiErr.StatusCode)
+	require.Equal(t, "invalid request parameters", apiErr.Msg)
+	require.EqualError(t, err, "indexer responded with 400: invalid request parameters")
+
+	_, err = c.GetBlock(ctx, 2)
+	require.True(t, errors.As(err, &apiErr))
+	require.Equal(t, http.StatusNotFound, apiErr.StatusCode)
+	require.Equal(t, "Not Found", apiErr.Msg)
+
+	_, err = c.GetBlock(ctx, 3)
+	require.True(t, errors.As(err, &apiErr))
+	require.Equal(t, common.CodeNotIndexed, apiErr.Code)
+	require.Equal(t, "2f4c0a9e-7b61-4a4e-9d3e-1f7c2b8a6d15", apiErr.RequestID)
+	require.EqualError(t, err, "indexer responded with 503 not_indexed: not yet indexed")
+}
+
+func TestRetries(t *testing.T) {
+	var calls map[string]int
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		calls[r.Method+" "+r.URL.Path]++
+		n := calls[r.Method+" "+r.URL.Path]
+		switch r.URL.Path {
+		case "/v1/consensus/blocks/1":
+			// Unavailable twice, then served.
+			if n <= 2 {
+				w.WriteHeader(http.StatusServiceUnavailable)
+				return
+			}
+		case "/v1/consensus/blocks/2":
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		case "/v1/consensus/blocks/3":
+			// Over a daily quota.
+			w.Header().Set("retry-after", strconv.Itoa(int(time.Hour/time.Second)))
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		case "/v1/consensus/webhooks":
+			if n == 1 {
+				w.Header().Set("retry-after", "0")
+				w.WriteHeader(http.StatusTooManyRequests)
+				return
+			}
+			if r.Method == http.MethodPost {
+				w.WriteHeader(http.StatusServiceUnavailable)
+				return
+			}
+		}
+		reply(w, http.StatusOK, map[string]interface{}{})
+	})
+	ctx := context.Background()
+
+	for _, tc := range []struct {
+		call  func() error
+		calls map[string]int
+		code  int
+	}{
+		{
+			call:  func() error { _, err := c.GetBlock(ctx, 1); return err },
+			calls: map[string]int{"GET /v1/consensus/blocks/1": 3},
+		},
+		{
+			call:  func() error { _, err := c.GetBlock(ctx, 2); return err },
+			calls: map[string]int{"GET /v1/consensus/blocks/2": DefaultRetries + 1},
+			code:  http.StatusServiceUnavailable,
+		},
+		{
+			call:  func() error { _, err := c.GetBlock(ctx, 3); return err },
+			calls: map[string]int{"GET /v1/consensus/blocks/3": 1},
+			code:  http.StatusTooManyRequests,
+		},
+		{
+			// Rate limited requests are retried, but others that
+			// may have been handled are not.
+			call: func() error {
+				_, err := c.CreateWebhookSubscription(ctx, &v1.WebhookSubscriptionRequest{Kind: "transfer"})
+				return err
+			},
+			calls: map[string]int{"POST /v1/consensus/webhooks": 2},
+			code:  http.StatusServiceUnavailable,
+		},
+	} {
+		calls = map[string]int{}
+		err := tc.call()
+		require.Equal(t, tc.calls, calls)
+		if tc.code == 0 {
+			require.Nil(t, err)
+			continue
+		}
+		var apiErr *Error
+		require.True(t, errors.As(err, &apiErr), err)
+		require.Equal(t, tc.code, apiErr.StatusCode)
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	cancel()
+	_, err := c.GetBlock(ctx, 2)
+	require.True(t, errors.Is(err, context.Canceled), err)
+}
+
+func TestPager(t *testing.T) {
+	const total = 250
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		offset, _ := strconv.Atoi(r.URL.Query().Get("offset"))
+		limit, _ := strconv.Atoi(r.URL.Query().Get("limit"))
+		votes := v1.ProposalVotes{ProposalID: 1, Votes: []v1.ProposalVote{}}
+		for i := offset; i < offset+limit && i < total; i++ {
+			votes.Votes = append(votes.Votes, v1.ProposalVote{Address: strconv.Itoa(i), Vote: "yes"})
+		}
+		reply(w, http.StatusOK, votes)
+	})
+	ctx := context.Background()
+
+	offset := 20
+	var addresses []string
+	pages := c.GetProposalVotesPager(1, &GetProposalVotesParams{Offset: &offset})
+	for pages.Next(ctx) {
+		for _, vote := range pages.Page().Votes {
+			addresses = append(addresses, vote.Address)
+		}
+	}
+	require.Nil(t, pages.Err())
+	require.Len(t, addresses, total-offset)
+	require.Equal(t, "20", addresses[0])
+	require.Equal(t, "249", addresses[len(addresses)-1])
+
+	// Pages end exactly at the end of the results.
+	limit := 125
+	var n int
+	pages = c.GetProposalVotesPager(1, &GetProposalVotesParams{Limit: &limit})
+	for pages.Next(ctx) {
+		n += len(pages.Page().Votes)
+	}
+	require.Nil(t, pages.Err())
+	require.Equal(t, total, n)
+}
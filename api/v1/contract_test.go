@@ -0,0 +1,428 @@
+package v1
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"mime"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/iancoleman/strcase"
+	"github.com/jackc/pgx/v4"
+	"github.com/stretchr/testify/require"
+
+	"github.com/oasisprotocol/oasis-indexer/api/common"
+	"github.com/oasisprotocol/oasis-indexer/api/spec"
+	"github.com/oasisprotocol/oasis-indexer/log"
+	"github.com/oasisprotocol/oasis-indexer/metrics"
+	"github.com/oasisprotocol/oasis-indexer/storage"
+	"github.com/oasisprotocol/oasis-indexer/streaming"
+)
+
+// sampleStorage is a target storage that answers every query with
+// a single row of sample values.
+type sampleStorage struct {
+	storage.TargetStorage
+}
+
+func (s *sampleStorage) Query(ctx context.Context, sql string, args ...interface{}) (storage.QueryResults, error) {
+	return &sampleRows{}, nil
+}
+
+func (s *sampleStorage) QueryRow(ctx context.Context, sql string, args ...interface{}) storage.QueryResult {
+	return sampleRow{}
+}
+
+type sampleRow struct{}
+
+func (sampleRow) Scan(dest ...interface{}) error {
+	for _, d := range dest {
+		setSample(reflect.ValueOf(d).Elem())
+	}
+	return nil
+}
+
+type sampleRows struct {
+	pgx.Rows
+	read bool
+}
+
+func (r *sampleRows) Next() bool {
+	next := !r.read
+	r.read = true
+	return next
+}
+
+func (r *sampleRows) Scan(dest ...interface{}) error {
+	return sampleRow{}.Scan(dest...)
+}
+
+func (r *sampleRows) Err() error {
+	return nil
+}
+
+func (r *sampleRows) Close() {}
+
+// emptyStorage is a target storage without rows, other than the status
+// of indexing at the latest height.
+type emptyStorage struct {
+	storage.TargetStorage
+	latest int64
+}
+
+func (s *emptyStorage) QueryRow(ctx context.Context, sql string, args ...interface{}) storage.QueryResult {
+	if sql == NewQueryFactory(strcase.ToSnake(LatestChainID)).StatusQuery() {
+		return statusRow{s.latest}
+	}
+	return emptyRow{}
+}
+
+type statusRow struct {
+	latest int64
+}
+
+func (r statusRow) Scan(dest ...interface{}) error {
+	*dest[0].(*int64) = r.latest
+	*dest[1].(*time.Time) = time.Unix(1649669400, 0).UTC()
+	return nil
+}
+
+type emptyRow struct{}
+
+func (emptyRow) Scan(dest ...interface{}) error {
+	return pgx.ErrNoRows
+}
+
+// sampleJSON is the sample of JSON columns. Strings are sampled as JSON
+// too, since some JSON columns are scanned into strings.
+const sampleJSON = `{"amount":"100"}`
+
+// setSample sets a value to a sample of its type. Structs, which are
+// scanned from JSON columns, are left empty.
+func setSample(v reflect.Value) {
+	switch x := v.Addr().Interface().(type) {
+	case *time.Time:
+		*x = time.Unix(1649669400, 0).UTC()
+		return
+	case *json.RawMessage:
+		*x = json.RawMessage(sampleJSON)
+		return
+	case *[]byte:
+		*x = []byte(sampleJSON)
+		return
+	}
+
+	switch v.Kind() {
+	case reflect.Ptr:
+		p := reflect.New(v.Type().Elem())
+		setSample(p.Elem())
+		v.Set(p)
+	case reflect.Slice:
+		s := reflect.MakeSlice(v.Type(), 1, 1)
+		setSample(s.Index(0))
+		v.Set(s)
+	case reflect.String:
+		v.SetString(sampleJSON)
+	case reflect.Bool:
+		v.SetBool(true)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		v.SetInt(1)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		v.SetUint(1)
+	}
+}
+
+// loadContractSpec loads the specification that responses are checked
+// against. Sample values are not of the enumerated values of schemas,
+// so enums are not checked.
+func loadContractSpec(t *testing.T) *spec.Document {
+	raw, err := ioutil.ReadFile("../spec/v1.yaml")
+	require.Nil(t, err)
+	doc, err := spec.Parse(raw)
+	require.Nil(t, err)
+
+	var clearEnums func(s *spec.Schema)
+	clearEnums = func(s *spec.Schema) {
+		if s == nil {
+			return
+		}
+		s.Enum = nil
+		for _, p := range s.Properties {
+			clearEnums(p.Schema)
+		}
+		for _, o := range s.OneOf {
+			clearEnums(o)
+		}
+		clearEnums(s.Items)
+	}
+	for _, ns := range doc.Components.Schemas {
+		clearEnums(ns.Schema)
+	}
+	return doc
+}
+
+// contractMetrics are shared by the routers of tests, since metrics
+// can only be registered once.
+var contractMetrics = metrics.NewDefaultRequestMetrics("test_v1_contract")
+
+func newContractRouter(db storage.TargetStorage) chi.Router {
+	logger := log.NewDefaultLogger("v1")
+	h := &Handler{
+		client:  newStorageClient(db, logger),
+		bus:     streaming.NewMemoryBus(),
+		logger:  logger,
+		metrics: contractMetrics,
+	}
+	r := chi.NewRouter()
+	// Requests are authenticated with an API key, as they would be by
+	// the auth handler, so that endpoints that require one are served.
+	r.Use(func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), APIKeyIDContextKey, int64(1))))
+		})
+	})
+	h.RegisterMiddlewares(r)
+	h.RegisterRoutes(r)
+	return r
+}
+
+// sampleRequest builds a request for an operation from the examples
+// of its parameters and request body.
+func sampleRequest(t *testing.T, method string, path string, op *spec.Operation) *http.Request {
+	query := url.Values{}
+	for _, p := range op.Parameters {
+		switch {
+		case p.In == "path":
+			require.NotNil(t, p.Example, "%s has no example", p.Name)
+			path = strings.Replace(path, "{"+p.Name+"}", url.PathEscape(fmt.Sprint(p.Example)), 1)
+		case p.In == "query" && p.Required:
+			require.NotNil(t, p.Example, "%s has no example", p.Name)
+			query.Set(p.Name, fmt.Sprint(p.Example))
+		}
+	}
+
+	var body []byte
+	if op.RequestBody != nil {
+		example := map[string]interface{}{}
+		for _, p := range op.RequestBody.Content["application/json"].Schema.Resolve().Properties {
+			if p.Schema.Example != nil {
+				example[p.Name] = p.Schema.Example
+			}
+		}
+		var err error
+		body, err = json.Marshal(example)
+		require.Nil(t, err)
+	}
+
+	target := "/v1" + path
+	if len(query) > 0 {
+		target += "?" + query.Encode()
+	}
+	return httptest.NewRequest(strings.ToUpper(method), target, bytes.NewReader(body))
+}
+
+// successResponse returns the status code and response of successful
+// requests for an operation.
+func successResponse(op *spec.Operation) (int, *spec.Response) {
+	var codes []string
+	for code := range op.Responses {
+		if strings.HasPrefix(code, "2") {
+			codes = append(codes, code)
+		}
+	}
+	sort.Strings(codes)
+	var code int
+	fmt.Sscan(codes[0], &code)
+	return code, op.Responses[codes[0]].Resolve()
+}
+
+func TestGeneratedTypes(t *testing.T) {
+	doc, err := spec.V1()
+	require.Nil(t, err)
+	src, err := spec.GenerateGo(doc, "v1")
+	require.Nil(t, err)
+
+	generated, err := ioutil.ReadFile("api.gen.go")
+	require.Nil(t, err)
+	require.Equal(t, string(src), string(generated), "api.gen.go is out of date, run go generate")
+}
+
+func TestRoutesMatchSpec(t *testing.T) {
+	doc, err := spec.V1()
+	require.Nil(t, err)
+
+	var specified []string
+	for _, item := range doc.Paths {
+		for method := range item.Operations {
+			specified = append(specified, strings.ToUpper(method)+" "+item.Path)
+		}
+	}
+	sort.Strings(specified)
+
+	var routed []string
+	require.Nil(t, chi.Walk(newContractRouter(&sampleStorage{}), func(method string, route string, _ http.Handler, _ ...func(http.Handler) http.Handler) error {
+		route = strings.TrimPrefix(route, "/v1")
+		if route != "/" {
+			route = strings.TrimSuffix(route, "/")
+		}
+		routed = append(routed, method+" "+route)
+		return nil
+	}))
+	sort.Strings(routed)
+
+	require.Equal(t, specified, routed)
+}
+
+func TestHandlersMatchSpec(t *testing.T) {
+	doc := loadContractSpec(t)
+	router := newContractRouter(&sampleStorage{})
+
+	for _, item := range doc.Paths {
+		for _, method := range spec.Methods {
+			op, ok := item.Operations[method]
+			if !ok {
+				continue
+			}
+			code, resp := successResponse(op)
+			if _, ok := resp.Content["text/event-stream"]; ok {
+				// Streams do not end, and are tested separately.
+				continue
+			}
+
+			r := sampleRequest(t, method, item.Path, op)
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, r)
+			require.Equal(t, code, w.Code, "%s %s: %s", op.OperationID, r.URL, w.Body.String())
+			if len(resp.Content) == 0 {
+				continue
+			}
+
+			mediaType, _, err := mime.ParseMediaType(w.Header().Get("content-type"))
+			require.Nil(t, err, op.OperationID)
+			content, ok := resp.Content[mediaType]
+			require.True(t, ok, "%s: unspecified content type %s", op.OperationID, mediaType)
+
+			var values []interface{}
+			switch mediaType {
+			case "application/json":
+				values = append(values, decodeJSON(t, w.Body.Bytes()))
+			case "application/x-ndjson":
+				lines := bufio.NewScanner(w.Body)
+				for lines.Scan() {
+					values = append(values, decodeJSON(t, lines.Bytes()))
+				}
+				require.NotEmpty(t, values, op.OperationID)
+			}
+			for _, v := range values {
+				require.Nil(t, content.Schema.ValidateStrict(v), "%s: %v", op.OperationID, v)
+			}
+		}
+	}
+}
+
+func decodeJSON(t *testing.T, b []byte) interface{} {
+	dec := json.NewDecoder(bytes.NewReader(b))
+	dec.UseNumber()
+	var v interface{}
+	require.Nil(t, dec.Decode(&v), string(b))
+	return v
+}
+
+func TestValidationMiddleware(t *testing.T) {
+	router := newContractRouter(&sampleStorage{})
+
+	for _, tc := range []struct {
+		target string
+		code   int
+	}{
+		{"/v1/consensus/blocks?from=8048956", http.StatusOK},
+		{"/v1/consensus/blocks?from=latest", http.StatusBadRequest},
+		{"/v1/consensus/blocks/latest", http.StatusBadRequest},
+		{"/v1/consensus/accounts?escrow[gt]=1000", http.StatusOK},
+		{"/v1/consensus/accounts?escrow[gt]=all", http.StatusBadRequest},
+		{"/v1/search", http.StatusBadRequest},
+		{"/v1/stream?topic=consensus_votes", http.StatusBadRequest},
+		{"/v1/consensus/unknown", http.StatusNotFound},
+	} {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, tc.target, nil))
+		require.Equal(t, tc.code, w.Code, tc.target)
+	}
+}
+
+func TestErrorResponses(t *testing.T) {
+	router := newContractRouter(&emptyStorage{latest: 8048956})
+
+	for _, tc := range []struct {
+		target    string
+		code      common.ErrorCode
+		msg       string
+		parameter string
+	}{
+		{"/v1/consensus/blocks/8048956", common.CodeNotFound, "block not found", ""},
+		{"/v1/consensus/blocks/8048957", common.CodeNotIndexed, "not yet indexed", ""},
+		{"/v1/consensus/blocks/latest", common.CodeInvalidParameter, "invalid height: must be an integer", "height"},
+		{"/v1/consensus/transactions/0d1e8e7f2bdbb7a0b4ba3c1b9ad3a4e1e0c5c3ab0b2b1c76b10a9c44b06c1d1e", common.CodeNotFound, "transaction not found", ""},
+		{"/v1/consensus/accounts/oasis1", common.CodeInvalidParameter, "invalid address: must be a staking address", "address"},
+		{"/v1/consensus/entities/entity", common.CodeInvalidParameter, "invalid entity_id: must be a base64-encoded public key", "entity_id"},
+		{"/v1/consensus/epochs/1", common.CodeNotFound, "epoch not found", ""},
+		{"/v1/consensus/blocks?order_by=height", common.CodeInvalidParameter, "invalid order_by: is not supported", "order_by"},
+	} {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, tc.target, nil))
+
+		var resp common.ErrorResponse
+		require.Nil(t, json.Unmarshal(w.Body.Bytes(), &resp), tc.target)
+		require.Equal(t, resp.Status, w.Code, tc.target)
+		require.Equal(t, tc.code, resp.Code, tc.target)
+		require.Equal(t, tc.msg, resp.Msg, tc.target)
+		require.NotEmpty(t, resp.RequestID, tc.target)
+		if tc.parameter != "" {
+			require.Equal(t, tc.parameter, resp.Details["parameter"], tc.target)
+		}
+	}
+}
+
+// timeoutStorage is a sample storage that records the statement timeout
+// of the contexts of queries.
+type timeoutStorage struct {
+	sampleStorage
+	timeouts []time.Duration
+}
+
+func (s *timeoutStorage) Query(ctx context.Context, sql string, args ...interface{}) (storage.QueryResults, error) {
+	s.timeouts = append(s.timeouts, storage.StatementTimeout(ctx))
+	return s.sampleStorage.Query(ctx, sql, args...)
+}
+
+func TestExportStatementTimeout(t *testing.T) {
+	db := &timeoutStorage{}
+	router := newContractRouter(db)
+
+	// Exports are outside of the request timeout, so their queries have a
+	// statement timeout of their own.
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/v1/consensus/export/staking_events", nil))
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	require.Equal(t, []time.Duration{exportTimeout}, db.timeouts)
+
+	db.timeouts = nil
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/v1/consensus/runtimes", nil))
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	require.NotEmpty(t, db.timeouts)
+	for _, timeout := range db.timeouts {
+		require.Zero(t, timeout)
+	}
+}
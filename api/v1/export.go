@@ -0,0 +1,142 @@
+package v1
+
+import (
+	"context"
+	"net/http"
+	"reflect"
+	"time"
+
+	"github.com/oasisprotocol/oasis-indexer/api/common"
+	"github.com/oasisprotocol/oasis-indexer/storage"
+)
+
+const (
+	// exportTimeout bounds how long exports may take.
+	exportTimeout = 30 * time.Minute
+
+	// exportFlushRows is how many rows are written between flushes,
+	// so that clients receive exports progressively.
+	exportFlushRows = 1000
+)
+
+// ExportAccountActivity streams the transactions and staking events
+// of a consensus account over a range of heights or times.
+func (h *Handler) ExportAccountActivity(w http.ResponseWriter, r *http.Request) {
+	h.export(w, r, reflect.TypeOf(AccountActivity{}), "failed to export account activity",
+		func(ctx context.Context, write func(interface{}) error) error {
+			return h.client.ExportAccountActivity(ctx, r, func(a *AccountActivity) error {
+				return write(a)
+			})
+		},
+	)
+}
+
+// ExportStakingEvents streams consensus staking events over a range
+// of heights or times.
+func (h *Handler) ExportStakingEvents(w http.ResponseWriter, r *http.Request) {
+	h.export(w, r, reflect.TypeOf(StakingEvent{}), "failed to export staking events",
+		func(ctx context.Context, write func(interface{}) error) error {
+			return h.client.ExportStakingEvents(ctx, r, func(e *StakingEvent) error {
+				return write(e)
+			})
+		},
+	)
+}
+
+// export streams rows of the provided type as NDJSON or, if requested
+// by the Accept header, as CSV. Rows are passed to write by produce as
+// they are read from storage, so exports are not held in memory.
+//
+// Errors that occur before anything is written are replied as usual.
+// Afterwards, the connection is closed instead, so that clients can
+// tell the export is incomplete.
+func (h *Handler) export(w http.ResponseWriter, r *http.Request, rowType reflect.Type, msg string, produce func(context.Context, func(interface{}) error) error) {
+	// Exports may take longer than graceful shutdown of the server, so
+	// they are aborted when it shuts down.
+	ctx, end := common.UntilShutdown(r.Context())
+	defer end()
+	ctx, cancel := context.WithTimeout(ctx, exportTimeout)
+	defer cancel()
+
+	// Exports are not subject to the request timeout, so their queries are
+	// cancelled by the database once they run for as long as exports may
+	// take, rather than after the statement timeout of other requests.
+	ctx = storage.WithStatementTimeout(ctx, exportTimeout)
+
+	// Exports outlive the write timeout of the server, so they may
+	// write for as long as they may take instead.
+	if err := common.SetWriteDeadline(ctx, time.Now().Add(exportTimeout)); err != nil {
+		h.logAndReply(ctx, msg, w, err)
+		h.metrics.RequestCounter(r.URL.Path, "failure", common.ErrorCause(err)).Inc()
+		return
+	}
+
+	f := negotiateFormat(r.Header.Get("accept"), formatNDJSON, formatCSV)
+	ew := &exportWriter{w: w}
+	rw := newRowWriter(ew, f, rowType)
+	flusher, _ := w.(http.Flusher)
+
+	w.Header().Set("content-type", f.contentType())
+	w.Header().Set("cache-control", "no-store")
+	w.Header().Set("x-accel-buffering", "no")
+
+	var n int
+	err := produce(ctx, func(row interface{}) error {
+		if err := rw.Write(row); err != nil {
+			return err
+		}
+		if n++; n%exportFlushRows == 0 {
+			if err := rw.Flush(); err != nil {
+				return err
+			}
+			if flusher != nil {
+				flusher.Flush()
+			}
+		}
+		return nil
+	})
+	if err == nil {
+		err = rw.Flush()
+	}
+
+	switch {
+	case err == nil:
+		h.metrics.RequestCounter(r.URL.Path, "success").Inc()
+	case !ew.written:
+		h.logAndReply(ctx, msg, w, err)
+		h.metrics.RequestCounter(r.URL.Path, "failure", common.ErrorCause(err)).Inc()
+	default:
+		h.logger.Error(msg,
+			"request_id", ctx.Value(RequestIDContextKey),
+			"rows", n,
+			"error", err,
+		)
+		abortResponse(w)
+		h.metrics.RequestCounter(r.URL.Path, "failure", "export_aborted").Inc()
+	}
+}
+
+// exportWriter is a writer that records whether anything was written
+// to a response.
+type exportWriter struct {
+	w       http.ResponseWriter
+	written bool
+}
+
+func (w *exportWriter) Write(b []byte) (int, error) {
+	w.written = true
+	return w.w.Write(b)
+}
+
+// abortResponse closes the connection of a partially written response.
+// Responses of unknown length are chunked, so clients are then missing
+// the final chunk.
+func abortResponse(w http.ResponseWriter) {
+	hj, ok := w.(http.Hijacker)
+	if !ok {
+		return
+	}
+	if conn, _, err := hj.Hijack(); err == nil {
+		conn.Close()
+	}
+}
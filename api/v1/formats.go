@@ -0,0 +1,278 @@
+package v1
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// format is a response format.
+type format string
+
+const (
+	formatJSON   format = "application/json"
+	formatCSV    format = "text/csv"
+	formatNDJSON format = "application/x-ndjson"
+)
+
+// negotiateFormat returns the format requested by the Accept header
+// among the supported formats, which are tried in order. The first
+// supported format is returned if none is acceptable.
+func negotiateFormat(accept string, supported ...format) format {
+	best, bestQ := supported[0], 0.0
+	for _, part := range strings.Split(accept, ",") {
+		mediaType, params, err := mime.ParseMediaType(strings.TrimSpace(part))
+		if err != nil {
+			continue
+		}
+		q := 1.0
+		if v, ok := params["q"]; ok {
+			if q, err = strconv.ParseFloat(v, 64); err != nil {
+				continue
+			}
+		}
+		for _, f := range supported {
+			if q > bestQ && mediaMatches(mediaType, string(f)) {
+				best, bestQ = f, q
+			}
+		}
+	}
+	return best
+}
+
+// mediaMatches returns true if a media range matches a media type.
+func mediaMatches(mediaRange string, mediaType string) bool {
+	if mediaRange == "*/*" || mediaRange == mediaType {
+		return true
+	}
+	return strings.HasSuffix(mediaRange, "/*") && strings.HasPrefix(mediaType, strings.TrimSuffix(mediaRange, "*"))
+}
+
+// contentType returns the content type of responses in the format.
+func (f format) contentType() string {
+	if f == formatCSV {
+		return string(f) + "; charset=utf-8"
+	}
+	return string(f)
+}
+
+// marshalList marshals a list response in the format requested by the
+// Accept header. List responses are marshaled as JSON objects, or as rows
+// of the first list of objects they contain in CSV and NDJSON.
+func marshalList(accept string, v interface{}) ([]byte, string, error) {
+	f := negotiateFormat(accept, formatJSON, formatCSV, formatNDJSON)
+	if f == formatJSON {
+		resp, err := json.Marshal(v)
+		return resp, f.contentType(), err
+	}
+
+	rows := listRows(reflect.ValueOf(v))
+	if !rows.IsValid() {
+		return nil, "", fmt.Errorf("%T has no rows", v)
+	}
+
+	var buf bytes.Buffer
+	w := newRowWriter(&buf, f, rows.Type().Elem())
+	for i := 0; i < rows.Len(); i++ {
+		if err := w.Write(rows.Index(i).Interface()); err != nil {
+			return nil, "", err
+		}
+	}
+	if err := w.Flush(); err != nil {
+		return nil, "", err
+	}
+	return buf.Bytes(), f.contentType(), nil
+}
+
+// listRows returns the first field of a struct that is a list of objects.
+func listRows(v reflect.Value) reflect.Value {
+	v = reflect.Indirect(v)
+	if v.Kind() != reflect.Struct {
+		return reflect.Value{}
+	}
+	for i := 0; i < v.NumField(); i++ {
+		field := v.Field(i)
+		if field.Kind() == reflect.Slice && indirectType(field.Type().Elem()).Kind() == reflect.Struct {
+			return field
+		}
+	}
+	return reflect.Value{}
+}
+
+// rowWriter writes rows of objects of a single type.
+type rowWriter interface {
+	Write(row interface{}) error
+	Flush() error
+}
+
+// newRowWriter creates a writer of rows of the provided type in the format,
+// which must be CSV or NDJSON.
+func newRowWriter(w io.Writer, f format, t reflect.Type) rowWriter {
+	if f == formatCSV {
+		return &csvRowWriter{w: csv.NewWriter(w), columns: csvColumns(indirectType(t), nil)}
+	}
+	return &ndjsonRowWriter{json.NewEncoder(w)}
+}
+
+// ndjsonRowWriter writes rows as newline-delimited JSON objects.
+type ndjsonRowWriter struct {
+	enc *json.Encoder
+}
+
+func (w *ndjsonRowWriter) Write(row interface{}) error {
+	return w.enc.Encode(row)
+}
+
+func (w *ndjsonRowWriter) Flush() error {
+	return nil
+}
+
+// csvRowWriter writes rows as CSV records, with a header of column names.
+// Columns are the JSON fields of rows, with nested objects flattened.
+type csvRowWriter struct {
+	w           *csv.Writer
+	columns     []csvColumn
+	wroteHeader bool
+}
+
+// csvColumn is a column of CSV records, and the path of struct
+// fields it is read from.
+type csvColumn struct {
+	name  string
+	index []int
+}
+
+func (w *csvRowWriter) Write(row interface{}) error {
+	if err := w.writeHeader(); err != nil {
+		return err
+	}
+
+	v := reflect.Indirect(reflect.ValueOf(row))
+	record := make([]string, len(w.columns))
+	for i, c := range w.columns {
+		s, err := csvValue(fieldByIndex(v, c.index))
+		if err != nil {
+			return err
+		}
+		record[i] = s
+	}
+	return w.w.Write(record)
+}
+
+// Flush implements rowWriter. The header is written even if there
+// are no rows.
+func (w *csvRowWriter) Flush() error {
+	if err := w.writeHeader(); err != nil {
+		return err
+	}
+	w.w.Flush()
+	return w.w.Error()
+}
+
+func (w *csvRowWriter) writeHeader() error {
+	if w.wroteHeader {
+		return nil
+	}
+	w.wroteHeader = true
+	header := make([]string, len(w.columns))
+	for i, c := range w.columns {
+		header[i] = c.name
+	}
+	return w.w.Write(header)
+}
+
+// csvColumns returns the columns of a struct type, named after its JSON
+// fields. Fields of nested structs are named after both fields.
+func csvColumns(t reflect.Type, index []int) []csvColumn {
+	var columns []csvColumn
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		name := strings.Split(field.Tag.Get("json"), ",")[0]
+		if field.PkgPath != "" || name == "-" {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+		fieldIndex := append(append([]int{}, index...), i)
+
+		ft := indirectType(field.Type)
+		if ft.Kind() == reflect.Struct && ft != reflect.TypeOf(time.Time{}) {
+			for _, c := range csvColumns(ft, fieldIndex) {
+				columns = append(columns, csvColumn{name + "." + c.name, c.index})
+			}
+			continue
+		}
+		columns = append(columns, csvColumn{name, fieldIndex})
+	}
+	return columns
+}
+
+// fieldByIndex returns the nested field of a struct, or an invalid
+// value if it is within a nil pointer.
+func fieldByIndex(v reflect.Value, index []int) reflect.Value {
+	for _, i := range index {
+		if v.Kind() == reflect.Ptr {
+			if v.IsNil() {
+				return reflect.Value{}
+			}
+			v = v.Elem()
+		}
+		v = v.Field(i)
+	}
+	return v
+}
+
+// csvValue formats a value as a CSV field. Missing values are empty.
+func csvValue(v reflect.Value) (string, error) {
+	if v.Kind() == reflect.Ptr {
+		if v.IsNil() {
+			return "", nil
+		}
+		v = v.Elem()
+	}
+	if !v.IsValid() {
+		return "", nil
+	}
+
+	switch x := v.Interface().(type) {
+	case time.Time:
+		return x.Format(time.RFC3339), nil
+	case json.RawMessage:
+		return string(x), nil
+	case []byte:
+		return base64.StdEncoding.EncodeToString(x), nil
+	}
+
+	switch v.Kind() {
+	case reflect.String:
+		return v.String(), nil
+	case reflect.Bool:
+		return strconv.FormatBool(v.Bool()), nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(v.Int(), 10), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return strconv.FormatUint(v.Uint(), 10), nil
+	case reflect.Float32, reflect.Float64:
+		return strconv.FormatFloat(v.Float(), 'f', -1, 64), nil
+	default:
+		// Lists and other values are embedded as JSON.
+		b, err := json.Marshal(v.Interface())
+		return string(b), err
+	}
+}
+
+func indirectType(t reflect.Type) reflect.Type {
+	if t.Kind() == reflect.Ptr {
+		return t.Elem()
+	}
+	return t
+}
@@ -0,0 +1,83 @@
+package v1
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestNegotiateFormat(t *testing.T) {
+	for _, tc := range []struct {
+		accept string
+		f      format
+	}{
+		{"", formatJSON},
+		{"*/*", formatJSON},
+		{"application/json", formatJSON},
+		{"text/csv", formatCSV},
+		{"text/*", formatCSV},
+		{"application/x-ndjson", formatNDJSON},
+		{"text/csv;q=0.5, application/x-ndjson", formatNDJSON},
+		{"text/csv, application/x-ndjson;q=0.5", formatCSV},
+		{"text/html", formatJSON},
+		{"text/csv;q=invalid", formatJSON},
+	} {
+		require.Equal(t, tc.f, negotiateFormat(tc.accept, formatJSON, formatCSV, formatNDJSON), tc.accept)
+	}
+}
+
+func TestMarshalList(t *testing.T) {
+	amount := "100"
+	events := struct {
+		Events []StakingEvent `json:"events"`
+	}{
+		Events: []StakingEvent{
+			{
+				Height:    8048956,
+				Timestamp: time.Date(2022, 4, 11, 9, 30, 0, 0, time.UTC),
+				TxHash:    "aa",
+				Type:      "Transfer",
+				Amount:    &amount,
+				Body:      json.RawMessage(`{"amount":"100"}`),
+			},
+			{
+				Height:    8048957,
+				Timestamp: time.Date(2022, 4, 11, 9, 30, 6, 0, time.UTC),
+				TxHash:    "bb",
+				Type:      "Burn",
+			},
+		},
+	}
+
+	resp, contentType, err := marshalList("text/csv", events)
+	require.Nil(t, err)
+	require.Equal(t, "text/csv; charset=utf-8", contentType)
+	require.Equal(t, `height,timestamp,tx_hash,type,owner,counterparty,amount,body
+8048956,2022-04-11T09:30:00Z,aa,Transfer,,,100,"{""amount"":""100""}"
+8048957,2022-04-11T09:30:06Z,bb,Burn,,,,
+`, string(resp))
+
+	resp, contentType, err = marshalList("application/x-ndjson", events)
+	require.Nil(t, err)
+	require.Equal(t, "application/x-ndjson", contentType)
+	require.Equal(t, `{"height":8048956,"timestamp":"2022-04-11T09:30:00Z","tx_hash":"aa","type":"Transfer","amount":"100","body":{"amount":"100"}}
+{"height":8048957,"timestamp":"2022-04-11T09:30:06Z","tx_hash":"bb","type":"Burn","body":null}
+`, string(resp))
+
+	// Nested objects are flattened, and empty lists still have a header.
+	resp, _, err = marshalList("text/csv", struct {
+		Rows []struct {
+			ID     int `json:"id"`
+			Nested struct {
+				Name string `json:"name"`
+			} `json:"nested"`
+		} `json:"rows"`
+	}{})
+	require.Nil(t, err)
+	require.Equal(t, "id,nested.name\n", string(resp))
+
+	_, _, err = marshalList("text/csv", struct{ Count int }{})
+	require.NotNil(t, err)
+}
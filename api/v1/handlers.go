@@ -3,8 +3,11 @@ package v1
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"net/http"
 
+	governance "github.com/oasisprotocol/oasis-core/go/governance/api"
+
 	"github.com/oasisprotocol/oasis-indexer/api/common"
 )
 
@@ -15,7 +18,7 @@ func (h *Handler) GetStatus(w http.ResponseWriter, r *http.Request) {
 	status, err := h.client.Status(ctx)
 	if err != nil {
 		h.logAndReply(ctx, "failed to get status", w, err)
-		h.metrics.RequestCounter(r.URL.Path, "failure", "database_error").Inc()
+		h.metrics.RequestCounter(r.URL.Path, "failure", common.ErrorCause(err)).Inc()
 		return
 	}
 
@@ -45,18 +48,18 @@ func (h *Handler) ListBlocks(w http.ResponseWriter, r *http.Request) {
 	blocks, err := h.client.Blocks(ctx, r)
 	if err != nil {
 		h.logAndReply(ctx, "failed to list blocks", w, err)
-		h.metrics.RequestCounter(r.URL.Path, "failure", "database_error").Inc()
+		h.metrics.RequestCounter(r.URL.Path, "failure", common.ErrorCause(err)).Inc()
 		return
 	}
 
-	resp, err := json.Marshal(blocks)
+	resp, contentType, err := marshalList(r.Header.Get("accept"), blocks)
 	if err != nil {
 		h.logAndReply(ctx, "failed to marshal blocks", w, err)
 		h.metrics.RequestCounter(r.URL.Path, "failure", "serde_error").Inc()
 		return
 	}
 
-	w.Header().Set("content-type", "application/json")
+	w.Header().Set("content-type", contentType)
 	if _, err := w.Write(resp); err != nil {
 		h.logger.Error("failed to write response",
 			"request_id", ctx.Value(RequestIDContextKey),
@@ -75,7 +78,7 @@ func (h *Handler) GetBlock(w http.ResponseWriter, r *http.Request) {
 	block, err := h.client.Block(ctx, r)
 	if err != nil {
 		h.logAndReply(ctx, "failed to get block", w, err)
-		h.metrics.RequestCounter(r.URL.Path, "failure", "database_error").Inc()
+		h.metrics.RequestCounter(r.URL.Path, "failure", common.ErrorCause(err)).Inc()
 		return
 	}
 
@@ -87,6 +90,8 @@ func (h *Handler) GetBlock(w http.ResponseWriter, r *http.Request) {
 	}
 
 	w.Header().Set("content-type", "application/json")
+	// Indexed blocks do not change.
+	setImmutable(w)
 	if _, err := w.Write(resp); err != nil {
 		h.logger.Error("failed to write response",
 			"request_id", ctx.Value(RequestIDContextKey),
@@ -105,18 +110,18 @@ func (h *Handler) ListTransactions(w http.ResponseWriter, r *http.Request) {
 	transactions, err := h.client.Transactions(ctx, r)
 	if err != nil {
 		h.logAndReply(ctx, "failed to list transactions", w, err)
-		h.metrics.RequestCounter(r.URL.Path, "failure", "database_error").Inc()
+		h.metrics.RequestCounter(r.URL.Path, "failure", common.ErrorCause(err)).Inc()
 		return
 	}
 
-	resp, err := json.Marshal(transactions)
+	resp, contentType, err := marshalList(r.Header.Get("accept"), transactions)
 	if err != nil {
 		h.logAndReply(ctx, "failed to marshal transactions", w, err)
 		h.metrics.RequestCounter(r.URL.Path, "failure", "serde_error").Inc()
 		return
 	}
 
-	w.Header().Set("content-type", "application/json")
+	w.Header().Set("content-type", contentType)
 	if _, err := w.Write(resp); err != nil {
 		h.logger.Error("failed to write response",
 			"request_id", ctx.Value(RequestIDContextKey),
@@ -135,7 +140,7 @@ func (h *Handler) GetTransaction(w http.ResponseWriter, r *http.Request) {
 	transaction, err := h.client.Transaction(ctx, r)
 	if err != nil {
 		h.logAndReply(ctx, "failed to get transaction", w, err)
-		h.metrics.RequestCounter(r.URL.Path, "failure", "database_error").Inc()
+		h.metrics.RequestCounter(r.URL.Path, "failure", common.ErrorCause(err)).Inc()
 		return
 	}
 
@@ -147,6 +152,8 @@ func (h *Handler) GetTransaction(w http.ResponseWriter, r *http.Request) {
 	}
 
 	w.Header().Set("content-type", "application/json")
+	// Indexed transactions do not change.
+	setImmutable(w)
 	if _, err := w.Write(resp); err != nil {
 		h.logger.Error("failed to write response",
 			"request_id", ctx.Value(RequestIDContextKey),
@@ -165,18 +172,18 @@ func (h *Handler) ListEntities(w http.ResponseWriter, r *http.Request) {
 	entities, err := h.client.Entities(ctx, r)
 	if err != nil {
 		h.logAndReply(ctx, "failed to list entities", w, err)
-		h.metrics.RequestCounter(r.URL.Path, "failure", "database_error").Inc()
+		h.metrics.RequestCounter(r.URL.Path, "failure", common.ErrorCause(err)).Inc()
 		return
 	}
 
-	resp, err := json.Marshal(entities)
+	resp, contentType, err := marshalList(r.Header.Get("accept"), entities)
 	if err != nil {
 		h.logAndReply(ctx, "failed to marshal entities", w, err)
 		h.metrics.RequestCounter(r.URL.Path, "failure", "serde_error").Inc()
 		return
 	}
 
-	w.Header().Set("content-type", "application/json")
+	w.Header().Set("content-type", contentType)
 	if _, err := w.Write(resp); err != nil {
 		h.logger.Error("failed to write response",
 			"request_id", ctx.Value(RequestIDContextKey),
@@ -195,7 +202,7 @@ func (h *Handler) GetEntity(w http.ResponseWriter, r *http.Request) {
 	entity, err := h.client.Entity(ctx, r)
 	if err != nil {
 		h.logAndReply(ctx, "failed to get entity", w, err)
-		h.metrics.RequestCounter(r.URL.Path, "failure", "database_error").Inc()
+		h.metrics.RequestCounter(r.URL.Path, "failure", common.ErrorCause(err)).Inc()
 		return
 	}
 
@@ -225,18 +232,18 @@ func (h *Handler) ListEntityNodes(w http.ResponseWriter, r *http.Request) {
 	nodes, err := h.client.EntityNodes(ctx, r)
 	if err != nil {
 		h.logAndReply(ctx, "failed to list entity nodes", w, err)
-		h.metrics.RequestCounter(r.URL.Path, "failure", "database_error").Inc()
+		h.metrics.RequestCounter(r.URL.Path, "failure", common.ErrorCause(err)).Inc()
 		return
 	}
 
-	resp, err := json.Marshal(nodes)
+	resp, contentType, err := marshalList(r.Header.Get("accept"), nodes)
 	if err != nil {
 		h.logAndReply(ctx, "failed to marshal entity nodes", w, err)
 		h.metrics.RequestCounter(r.URL.Path, "failure", "serde_error").Inc()
 		return
 	}
 
-	w.Header().Set("content-type", "application/json")
+	w.Header().Set("content-type", contentType)
 	if _, err := w.Write(resp); err != nil {
 		h.logger.Error("failed to write response",
 			"request_id", ctx.Value(RequestIDContextKey),
@@ -255,7 +262,7 @@ func (h *Handler) GetEntityNode(w http.ResponseWriter, r *http.Request) {
 	node, err := h.client.EntityNode(ctx, r)
 	if err != nil {
 		h.logAndReply(ctx, "failed to get entity node", w, err)
-		h.metrics.RequestCounter(r.URL.Path, "failure", "database_error").Inc()
+		h.metrics.RequestCounter(r.URL.Path, "failure", common.ErrorCause(err)).Inc()
 		return
 	}
 
@@ -278,6 +285,126 @@ func (h *Handler) GetEntityNode(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
+// GetEntityNodeHistory gets the history of a node controlled by the provided entity.
+func (h *Handler) GetEntityNodeHistory(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	history, err := h.client.EntityNodeHistory(ctx, r)
+	if err != nil {
+		h.logAndReply(ctx, "failed to get entity node history", w, err)
+		h.metrics.RequestCounter(r.URL.Path, "failure", common.ErrorCause(err)).Inc()
+		return
+	}
+
+	resp, err := json.Marshal(history)
+	if err != nil {
+		h.logAndReply(ctx, "failed to marshal entity node history", w, err)
+		h.metrics.RequestCounter(r.URL.Path, "failure", "serde_error").Inc()
+		return
+	}
+
+	w.Header().Set("content-type", "application/json")
+	if _, err := w.Write(resp); err != nil {
+		h.logger.Error("failed to write response",
+			"request_id", ctx.Value(RequestIDContextKey),
+			"error", err,
+		)
+		h.metrics.RequestCounter(r.URL.Path, "failure", "http_error").Inc()
+	} else {
+		h.metrics.RequestCounter(r.URL.Path, "success").Inc()
+	}
+}
+
+// ListRuntimes gets a list of registered runtimes.
+func (h *Handler) ListRuntimes(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	runtimes, err := h.client.Runtimes(ctx, r)
+	if err != nil {
+		h.logAndReply(ctx, "failed to list runtimes", w, err)
+		h.metrics.RequestCounter(r.URL.Path, "failure", common.ErrorCause(err)).Inc()
+		return
+	}
+
+	resp, contentType, err := marshalList(r.Header.Get("accept"), runtimes)
+	if err != nil {
+		h.logAndReply(ctx, "failed to marshal runtimes", w, err)
+		h.metrics.RequestCounter(r.URL.Path, "failure", "serde_error").Inc()
+		return
+	}
+
+	w.Header().Set("content-type", contentType)
+	if _, err := w.Write(resp); err != nil {
+		h.logger.Error("failed to write response",
+			"request_id", ctx.Value(RequestIDContextKey),
+			"error", err,
+		)
+		h.metrics.RequestCounter(r.URL.Path, "failure", "http_error").Inc()
+	} else {
+		h.metrics.RequestCounter(r.URL.Path, "success").Inc()
+	}
+}
+
+// GetRuntime gets a registered runtime.
+func (h *Handler) GetRuntime(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	runtime, err := h.client.Runtime(ctx, r)
+	if err != nil {
+		h.logAndReply(ctx, "failed to get runtime", w, err)
+		h.metrics.RequestCounter(r.URL.Path, "failure", common.ErrorCause(err)).Inc()
+		return
+	}
+
+	resp, err := json.Marshal(runtime)
+	if err != nil {
+		h.logAndReply(ctx, "failed to marshal runtime", w, err)
+		h.metrics.RequestCounter(r.URL.Path, "failure", "serde_error").Inc()
+		return
+	}
+
+	w.Header().Set("content-type", "application/json")
+	if _, err := w.Write(resp); err != nil {
+		h.logger.Error("failed to write response",
+			"request_id", ctx.Value(RequestIDContextKey),
+			"error", err,
+		)
+		h.metrics.RequestCounter(r.URL.Path, "failure", "http_error").Inc()
+	} else {
+		h.metrics.RequestCounter(r.URL.Path, "success").Inc()
+	}
+}
+
+// GetRuntimeHistory gets the history of a runtime.
+func (h *Handler) GetRuntimeHistory(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	history, err := h.client.RuntimeHistory(ctx, r)
+	if err != nil {
+		h.logAndReply(ctx, "failed to get runtime history", w, err)
+		h.metrics.RequestCounter(r.URL.Path, "failure", common.ErrorCause(err)).Inc()
+		return
+	}
+
+	resp, err := json.Marshal(history)
+	if err != nil {
+		h.logAndReply(ctx, "failed to marshal runtime history", w, err)
+		h.metrics.RequestCounter(r.URL.Path, "failure", "serde_error").Inc()
+		return
+	}
+
+	w.Header().Set("content-type", "application/json")
+	if _, err := w.Write(resp); err != nil {
+		h.logger.Error("failed to write response",
+			"request_id", ctx.Value(RequestIDContextKey),
+			"error", err,
+		)
+		h.metrics.RequestCounter(r.URL.Path, "failure", "http_error").Inc()
+	} else {
+		h.metrics.RequestCounter(r.URL.Path, "success").Inc()
+	}
+}
+
 // ListAccounts gets a list of consensus accounts.
 func (h *Handler) ListAccounts(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
@@ -285,18 +412,18 @@ func (h *Handler) ListAccounts(w http.ResponseWriter, r *http.Request) {
 	accounts, err := h.client.Accounts(ctx, r)
 	if err != nil {
 		h.logAndReply(ctx, "failed to list accounts", w, err)
-		h.metrics.RequestCounter(r.URL.Path, "failure", "database_error").Inc()
+		h.metrics.RequestCounter(r.URL.Path, "failure", common.ErrorCause(err)).Inc()
 		return
 	}
 
-	resp, err := json.Marshal(accounts)
+	resp, contentType, err := marshalList(r.Header.Get("accept"), accounts)
 	if err != nil {
 		h.logAndReply(ctx, "failed to marshal accounts", w, err)
 		h.metrics.RequestCounter(r.URL.Path, "failure", "serde_error").Inc()
 		return
 	}
 
-	w.Header().Set("content-type", "application/json")
+	w.Header().Set("content-type", contentType)
 	if _, err := w.Write(resp); err != nil {
 		h.logger.Error("failed to write response",
 			"request_id", ctx.Value(RequestIDContextKey),
@@ -315,7 +442,7 @@ func (h *Handler) GetAccount(w http.ResponseWriter, r *http.Request) {
 	account, err := h.client.Account(ctx, r)
 	if err != nil {
 		h.logAndReply(ctx, "failed to get account", w, err)
-		h.metrics.RequestCounter(r.URL.Path, "failure", "database_error").Inc()
+		h.metrics.RequestCounter(r.URL.Path, "failure", common.ErrorCause(err)).Inc()
 		return
 	}
 
@@ -345,18 +472,18 @@ func (h *Handler) GetDelegations(w http.ResponseWriter, r *http.Request) {
 	delegations, err := h.client.Delegations(ctx, r)
 	if err != nil {
 		h.logAndReply(ctx, "failed to get delegations", w, err)
-		h.metrics.RequestCounter(r.URL.Path, "failure", "database_error").Inc()
+		h.metrics.RequestCounter(r.URL.Path, "failure", common.ErrorCause(err)).Inc()
 		return
 	}
 
-	resp, err := json.Marshal(delegations)
+	resp, contentType, err := marshalList(r.Header.Get("accept"), delegations)
 	if err != nil {
 		h.logAndReply(ctx, "failed to marshal delegations", w, err)
 		h.metrics.RequestCounter(r.URL.Path, "failure", "serde_error").Inc()
 		return
 	}
 
-	w.Header().Set("content-type", "application/json")
+	w.Header().Set("content-type", contentType)
 	if _, err := w.Write(resp); err != nil {
 		h.logger.Error("failed to write response",
 			"request_id", ctx.Value(RequestIDContextKey),
@@ -375,18 +502,78 @@ func (h *Handler) GetDebondingDelegations(w http.ResponseWriter, r *http.Request
 	debondingDelegations, err := h.client.DebondingDelegations(ctx, r)
 	if err != nil {
 		h.logAndReply(ctx, "failed to get debonding delegations", w, err)
-		h.metrics.RequestCounter(r.URL.Path, "failure", "database_error").Inc()
+		h.metrics.RequestCounter(r.URL.Path, "failure", common.ErrorCause(err)).Inc()
 		return
 	}
 
-	resp, err := json.Marshal(debondingDelegations)
+	resp, contentType, err := marshalList(r.Header.Get("accept"), debondingDelegations)
 	if err != nil {
 		h.logAndReply(ctx, "failed to marshal debonding delegations", w, err)
 		h.metrics.RequestCounter(r.URL.Path, "failure", "serde_error").Inc()
 		return
 	}
 
-	w.Header().Set("content-type", "application/json")
+	w.Header().Set("content-type", contentType)
+	if _, err := w.Write(resp); err != nil {
+		h.logger.Error("failed to write response",
+			"request_id", ctx.Value(RequestIDContextKey),
+			"error", err,
+		)
+		h.metrics.RequestCounter(r.URL.Path, "failure", "http_error").Inc()
+	} else {
+		h.metrics.RequestCounter(r.URL.Path, "success").Inc()
+	}
+}
+
+// GetValidatorDelegators gets the accounts delegating to a validator.
+func (h *Handler) GetValidatorDelegators(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	delegators, err := h.client.ValidatorDelegators(ctx, r)
+	if err != nil {
+		h.logAndReply(ctx, "failed to get validator delegators", w, err)
+		h.metrics.RequestCounter(r.URL.Path, "failure", common.ErrorCause(err)).Inc()
+		return
+	}
+
+	resp, contentType, err := marshalList(r.Header.Get("accept"), delegators)
+	if err != nil {
+		h.logAndReply(ctx, "failed to marshal validator delegators", w, err)
+		h.metrics.RequestCounter(r.URL.Path, "failure", "serde_error").Inc()
+		return
+	}
+
+	w.Header().Set("content-type", contentType)
+	if _, err := w.Write(resp); err != nil {
+		h.logger.Error("failed to write response",
+			"request_id", ctx.Value(RequestIDContextKey),
+			"error", err,
+		)
+		h.metrics.RequestCounter(r.URL.Path, "failure", "http_error").Inc()
+	} else {
+		h.metrics.RequestCounter(r.URL.Path, "success").Inc()
+	}
+}
+
+// GetValidatorDebondingDelegators gets the debonding delegations to a validator.
+func (h *Handler) GetValidatorDebondingDelegators(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	debondingDelegators, err := h.client.ValidatorDebondingDelegators(ctx, r)
+	if err != nil {
+		h.logAndReply(ctx, "failed to get validator debonding delegators", w, err)
+		h.metrics.RequestCounter(r.URL.Path, "failure", common.ErrorCause(err)).Inc()
+		return
+	}
+
+	resp, contentType, err := marshalList(r.Header.Get("accept"), debondingDelegators)
+	if err != nil {
+		h.logAndReply(ctx, "failed to marshal validator debonding delegators", w, err)
+		h.metrics.RequestCounter(r.URL.Path, "failure", "serde_error").Inc()
+		return
+	}
+
+	w.Header().Set("content-type", contentType)
 	if _, err := w.Write(resp); err != nil {
 		h.logger.Error("failed to write response",
 			"request_id", ctx.Value(RequestIDContextKey),
@@ -405,19 +592,18 @@ func (h *Handler) ListEpochs(w http.ResponseWriter, r *http.Request) {
 	epochs, err := h.client.Epochs(ctx, r)
 	if err != nil {
 		h.logAndReply(ctx, "failed to list epochs", w, err)
-		h.metrics.RequestCounter(r.URL.Path, "failure", "database_error").Inc()
+		h.metrics.RequestCounter(r.URL.Path, "failure", common.ErrorCause(err)).Inc()
 		return
 	}
 
-	var resp []byte
-	resp, err = json.Marshal(epochs)
+	resp, contentType, err := marshalList(r.Header.Get("accept"), epochs)
 	if err != nil {
 		h.logAndReply(ctx, "failed to marshal epochs", w, err)
-		h.metrics.RequestCounter(r.URL.Path, "failure", "database_error").Inc()
+		h.metrics.RequestCounter(r.URL.Path, "failure", common.ErrorCause(err)).Inc()
 		return
 	}
 
-	w.Header().Set("content-type", "application/json")
+	w.Header().Set("content-type", contentType)
 	if _, err := w.Write(resp); err != nil {
 		h.logger.Error("failed to write response",
 			"request_id", ctx.Value(RequestIDContextKey),
@@ -436,7 +622,7 @@ func (h *Handler) GetEpoch(w http.ResponseWriter, r *http.Request) {
 	epoch, err := h.client.Epoch(ctx, r)
 	if err != nil {
 		h.logAndReply(ctx, "failed to get epoch", w, err)
-		h.metrics.RequestCounter(r.URL.Path, "failure", "database_error").Inc()
+		h.metrics.RequestCounter(r.URL.Path, "failure", common.ErrorCause(err)).Inc()
 		return
 	}
 
@@ -444,11 +630,82 @@ func (h *Handler) GetEpoch(w http.ResponseWriter, r *http.Request) {
 	resp, err = json.Marshal(epoch)
 	if err != nil {
 		h.logAndReply(ctx, "failed to marshal epoch", w, err)
-		h.metrics.RequestCounter(r.URL.Path, "failure", "database_error").Inc()
+		h.metrics.RequestCounter(r.URL.Path, "failure", common.ErrorCause(err)).Inc()
 		return
 	}
 
 	w.Header().Set("content-type", "application/json")
+	if epoch.EndHeight != 0 {
+		setImmutable(w)
+	}
+	if _, err := w.Write(resp); err != nil {
+		h.logger.Error("failed to write response",
+			"request_id", ctx.Value(RequestIDContextKey),
+			"error", err,
+		)
+		h.metrics.RequestCounter(r.URL.Path, "failure", "http_error").Inc()
+	} else {
+		h.metrics.RequestCounter(r.URL.Path, "success").Inc()
+	}
+}
+
+// GetEpochCommittees gets the members of the runtime committees of an epoch.
+func (h *Handler) GetEpochCommittees(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	committees, err := h.client.EpochCommittees(ctx, r)
+	if err != nil {
+		h.logAndReply(ctx, "failed to get epoch committees", w, err)
+		h.metrics.RequestCounter(r.URL.Path, "failure", common.ErrorCause(err)).Inc()
+		return
+	}
+
+	resp, contentType, err := marshalList(r.Header.Get("accept"), committees)
+	if err != nil {
+		h.logAndReply(ctx, "failed to marshal epoch committees", w, err)
+		h.metrics.RequestCounter(r.URL.Path, "failure", "serde_error").Inc()
+		return
+	}
+
+	w.Header().Set("content-type", contentType)
+	// Committees are elected for a single epoch.
+	if epoch, err := h.client.Epoch(ctx, r); err == nil && epoch.EndHeight != 0 {
+		setImmutable(w)
+	}
+	if _, err := w.Write(resp); err != nil {
+		h.logger.Error("failed to write response",
+			"request_id", ctx.Value(RequestIDContextKey),
+			"error", err,
+		)
+		h.metrics.RequestCounter(r.URL.Path, "failure", "http_error").Inc()
+	} else {
+		h.metrics.RequestCounter(r.URL.Path, "success").Inc()
+	}
+}
+
+// GetEpochValidators gets the consensus validators of an epoch.
+func (h *Handler) GetEpochValidators(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	validators, err := h.client.EpochValidators(ctx, r)
+	if err != nil {
+		h.logAndReply(ctx, "failed to get epoch validators", w, err)
+		h.metrics.RequestCounter(r.URL.Path, "failure", common.ErrorCause(err)).Inc()
+		return
+	}
+
+	resp, contentType, err := marshalList(r.Header.Get("accept"), validators)
+	if err != nil {
+		h.logAndReply(ctx, "failed to marshal epoch validators", w, err)
+		h.metrics.RequestCounter(r.URL.Path, "failure", "serde_error").Inc()
+		return
+	}
+
+	w.Header().Set("content-type", contentType)
+	// The validator set of an epoch no longer changes once it has ended.
+	if epoch, err := h.client.Epoch(ctx, r); err == nil && epoch.EndHeight != 0 {
+		setImmutable(w)
+	}
 	if _, err := w.Write(resp); err != nil {
 		h.logger.Error("failed to write response",
 			"request_id", ctx.Value(RequestIDContextKey),
@@ -467,18 +724,18 @@ func (h *Handler) ListProposals(w http.ResponseWriter, r *http.Request) {
 	proposals, err := h.client.Proposals(ctx, r)
 	if err != nil {
 		h.logAndReply(ctx, "failed to list proposals", w, err)
-		h.metrics.RequestCounter(r.URL.Path, "failure", "database_error").Inc()
+		h.metrics.RequestCounter(r.URL.Path, "failure", common.ErrorCause(err)).Inc()
 		return
 	}
 
-	resp, err := json.Marshal(proposals)
+	resp, contentType, err := marshalList(r.Header.Get("accept"), proposals)
 	if err != nil {
 		h.logAndReply(ctx, "failed to marshal proposals", w, err)
 		h.metrics.RequestCounter(r.URL.Path, "failure", "serde_error").Inc()
 		return
 	}
 
-	w.Header().Set("content-type", "application/json")
+	w.Header().Set("content-type", contentType)
 	if _, err := w.Write(resp); err != nil {
 		h.logger.Error("failed to write response",
 			"request_id", ctx.Value(RequestIDContextKey),
@@ -497,7 +754,7 @@ func (h *Handler) GetProposal(w http.ResponseWriter, r *http.Request) {
 	proposal, err := h.client.Proposal(ctx, r)
 	if err != nil {
 		h.logAndReply(ctx, "failed to get proposal", w, err)
-		h.metrics.RequestCounter(r.URL.Path, "failure", "database_error").Inc()
+		h.metrics.RequestCounter(r.URL.Path, "failure", common.ErrorCause(err)).Inc()
 		return
 	}
 
@@ -509,6 +766,9 @@ func (h *Handler) GetProposal(w http.ResponseWriter, r *http.Request) {
 	}
 
 	w.Header().Set("content-type", "application/json")
+	if isProposalClosed(proposal) {
+		setImmutable(w)
+	}
 	if _, err := w.Write(resp); err != nil {
 		h.logger.Error("failed to write response",
 			"request_id", ctx.Value(RequestIDContextKey),
@@ -527,18 +787,22 @@ func (h *Handler) GetProposalVotes(w http.ResponseWriter, r *http.Request) {
 	votes, err := h.client.ProposalVotes(ctx, r)
 	if err != nil {
 		h.logAndReply(ctx, "failed to get proposal votes", w, err)
-		h.metrics.RequestCounter(r.URL.Path, "failure", "database_error").Inc()
+		h.metrics.RequestCounter(r.URL.Path, "failure", common.ErrorCause(err)).Inc()
 		return
 	}
 
-	resp, err := json.Marshal(votes)
+	resp, contentType, err := marshalList(r.Header.Get("accept"), votes)
 	if err != nil {
 		h.logAndReply(ctx, "failed to marshal proposal votes", w, err)
 		h.metrics.RequestCounter(r.URL.Path, "failure", "serde_error").Inc()
 		return
 	}
 
-	w.Header().Set("content-type", "application/json")
+	w.Header().Set("content-type", contentType)
+	// Votes can no longer be cast once the proposal is closed.
+	if proposal, err := h.client.Proposal(ctx, r); err == nil && isProposalClosed(proposal) {
+		setImmutable(w)
+	}
 	if _, err := w.Write(resp); err != nil {
 		h.logger.Error("failed to write response",
 			"request_id", ctx.Value(RequestIDContextKey),
@@ -557,7 +821,7 @@ func (h *Handler) GetValidator(w http.ResponseWriter, r *http.Request) {
 	validator, err := h.client.Validator(ctx, r)
 	if err != nil {
 		h.logAndReply(ctx, "failed to get validator", w, err)
-		h.metrics.RequestCounter(r.URL.Path, "failure", "database_error").Inc()
+		h.metrics.RequestCounter(r.URL.Path, "failure", common.ErrorCause(err)).Inc()
 		return
 	}
 
@@ -587,19 +851,18 @@ func (h *Handler) ListValidators(w http.ResponseWriter, r *http.Request) {
 	validators, err := h.client.Validators(ctx, r)
 	if err != nil {
 		h.logAndReply(ctx, "failed to list validators", w, err)
-		h.metrics.RequestCounter(r.URL.Path, "failure", "database_error").Inc()
+		h.metrics.RequestCounter(r.URL.Path, "failure", common.ErrorCause(err)).Inc()
 		return
 	}
 
-	var resp []byte
-	resp, err = json.Marshal(validators)
+	resp, contentType, err := marshalList(r.Header.Get("accept"), validators)
 	if err != nil {
 		h.logAndReply(ctx, "failed to marshal validators", w, err)
-		h.metrics.RequestCounter(r.URL.Path, "failure", "database_error").Inc()
+		h.metrics.RequestCounter(r.URL.Path, "failure", common.ErrorCause(err)).Inc()
 		return
 	}
 
-	w.Header().Set("content-type", "application/json")
+	w.Header().Set("content-type", contentType)
 	if _, err := w.Write(resp); err != nil {
 		h.logger.Error("failed to write response",
 			"request_id", ctx.Value(RequestIDContextKey),
@@ -618,19 +881,18 @@ func (h *Handler) ListTransactionsPerSecond(w http.ResponseWriter, r *http.Reque
 	tps, err := h.client.TransactionsPerSecond(ctx, r)
 	if err != nil {
 		h.logAndReply(ctx, "failed to list tps", w, err)
-		h.metrics.RequestCounter(r.URL.Path, "failure", "database_error").Inc()
+		h.metrics.RequestCounter(r.URL.Path, "failure", common.ErrorCause(err)).Inc()
 		return
 	}
 
-	var resp []byte
-	resp, err = json.Marshal(tps)
+	resp, contentType, err := marshalList(r.Header.Get("accept"), tps)
 	if err != nil {
 		h.logAndReply(ctx, "failed to marshal tps", w, err)
-		h.metrics.RequestCounter(r.URL.Path, "failure", "database_error").Inc()
+		h.metrics.RequestCounter(r.URL.Path, "failure", common.ErrorCause(err)).Inc()
 		return
 	}
 
-	w.Header().Set("content-type", "application/json")
+	w.Header().Set("content-type", contentType)
 	if _, err := w.Write(resp); err != nil {
 		h.logger.Error("failed to write response",
 			"request_id", ctx.Value(RequestIDContextKey),
@@ -649,19 +911,139 @@ func (h *Handler) ListDailyVolume(w http.ResponseWriter, r *http.Request) {
 	volumes, err := h.client.DailyVolumes(ctx, r)
 	if err != nil {
 		h.logAndReply(ctx, "failed to list daily tx volume", w, err)
-		h.metrics.RequestCounter(r.URL.Path, "failure", "database_error").Inc()
+		h.metrics.RequestCounter(r.URL.Path, "failure", common.ErrorCause(err)).Inc()
 		return
 	}
 
-	var resp []byte
-	resp, err = json.Marshal(volumes)
+	resp, contentType, err := marshalList(r.Header.Get("accept"), volumes)
 	if err != nil {
 		h.logAndReply(ctx, "failed to marshal volumes", w, err)
-		h.metrics.RequestCounter(r.URL.Path, "failure", "database_error").Inc()
+		h.metrics.RequestCounter(r.URL.Path, "failure", common.ErrorCause(err)).Inc()
+		return
+	}
+
+	w.Header().Set("content-type", contentType)
+	if _, err := w.Write(resp); err != nil {
+		h.logger.Error("failed to write response",
+			"request_id", ctx.Value(RequestIDContextKey),
+			"error", err,
+		)
+		h.metrics.RequestCounter(r.URL.Path, "failure", "http_error").Inc()
+	} else {
+		h.metrics.RequestCounter(r.URL.Path, "success").Inc()
+	}
+}
+
+// ListWebhookSubscriptions gets a list of webhook subscriptions.
+func (h *Handler) ListWebhookSubscriptions(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	subscriptions, err := h.client.WebhookSubscriptions(ctx, r)
+	if err != nil {
+		h.logAndReply(ctx, "failed to list webhook subscriptions", w, err)
+		h.metrics.RequestCounter(r.URL.Path, "failure", common.ErrorCause(err)).Inc()
+		return
+	}
+
+	resp, contentType, err := marshalList(r.Header.Get("accept"), subscriptions)
+	if err != nil {
+		h.logAndReply(ctx, "failed to marshal webhook subscriptions", w, err)
+		h.metrics.RequestCounter(r.URL.Path, "failure", "serde_error").Inc()
+		return
+	}
+
+	w.Header().Set("content-type", contentType)
+	if _, err := w.Write(resp); err != nil {
+		h.logger.Error("failed to write response",
+			"request_id", ctx.Value(RequestIDContextKey),
+			"error", err,
+		)
+		h.metrics.RequestCounter(r.URL.Path, "failure", "http_error").Inc()
+	} else {
+		h.metrics.RequestCounter(r.URL.Path, "success").Inc()
+	}
+}
+
+// GetWebhookSubscription gets a webhook subscription.
+func (h *Handler) GetWebhookSubscription(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	subscription, err := h.client.WebhookSubscription(ctx, r)
+	if err != nil {
+		h.logAndReply(ctx, "failed to get webhook subscription", w, err)
+		h.metrics.RequestCounter(r.URL.Path, "failure", common.ErrorCause(err)).Inc()
+		return
+	}
+
+	resp, err := json.Marshal(subscription)
+	if err != nil {
+		h.logAndReply(ctx, "failed to marshal webhook subscription", w, err)
+		h.metrics.RequestCounter(r.URL.Path, "failure", "serde_error").Inc()
+		return
+	}
+
+	w.Header().Set("content-type", "application/json")
+	if _, err := w.Write(resp); err != nil {
+		h.logger.Error("failed to write response",
+			"request_id", ctx.Value(RequestIDContextKey),
+			"error", err,
+		)
+		h.metrics.RequestCounter(r.URL.Path, "failure", "http_error").Inc()
+	} else {
+		h.metrics.RequestCounter(r.URL.Path, "success").Inc()
+	}
+}
+
+// CreateWebhookSubscription creates a webhook subscription.
+func (h *Handler) CreateWebhookSubscription(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	subscription, err := h.client.CreateWebhookSubscription(ctx, r)
+	if err != nil {
+		h.logAndReply(ctx, "failed to create webhook subscription", w, err)
+		h.metrics.RequestCounter(r.URL.Path, "failure", common.ErrorCause(err)).Inc()
+		return
+	}
+
+	resp, err := json.Marshal(subscription)
+	if err != nil {
+		h.logAndReply(ctx, "failed to marshal webhook subscription", w, err)
+		h.metrics.RequestCounter(r.URL.Path, "failure", "serde_error").Inc()
 		return
 	}
 
 	w.Header().Set("content-type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	if _, err := w.Write(resp); err != nil {
+		h.logger.Error("failed to write response",
+			"request_id", ctx.Value(RequestIDContextKey),
+			"error", err,
+		)
+		h.metrics.RequestCounter(r.URL.Path, "failure", "http_error").Inc()
+	} else {
+		h.metrics.RequestCounter(r.URL.Path, "success").Inc()
+	}
+}
+
+// ListWebhookDeadLetters gets a list of failed deliveries for a webhook subscription.
+func (h *Handler) ListWebhookDeadLetters(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	deadLetters, err := h.client.WebhookDeadLetters(ctx, r)
+	if err != nil {
+		h.logAndReply(ctx, "failed to list webhook dead letters", w, err)
+		h.metrics.RequestCounter(r.URL.Path, "failure", common.ErrorCause(err)).Inc()
+		return
+	}
+
+	resp, contentType, err := marshalList(r.Header.Get("accept"), deadLetters)
+	if err != nil {
+		h.logAndReply(ctx, "failed to marshal webhook dead letters", w, err)
+		h.metrics.RequestCounter(r.URL.Path, "failure", "serde_error").Inc()
+		return
+	}
+
+	w.Header().Set("content-type", contentType)
 	if _, err := w.Write(resp); err != nil {
 		h.logger.Error("failed to write response",
 			"request_id", ctx.Value(RequestIDContextKey),
@@ -673,12 +1055,43 @@ func (h *Handler) ListDailyVolume(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
+// DeleteWebhookSubscription deletes a webhook subscription.
+func (h *Handler) DeleteWebhookSubscription(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	if err := h.client.DeleteWebhookSubscription(ctx, r); err != nil {
+		h.logAndReply(ctx, "failed to delete webhook subscription", w, err)
+		h.metrics.RequestCounter(r.URL.Path, "failure", common.ErrorCause(err)).Inc()
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+	h.metrics.RequestCounter(r.URL.Path, "success").Inc()
+}
+
+// isProposalClosed returns true if the proposal is final, in which case it
+// does not change anymore. Passed proposals are not, since they fail if
+// they cannot be executed.
+func isProposalClosed(p *Proposal) bool {
+	return p.State == governance.StateRejectedName || p.State == governance.StateFailedName
+}
+
+// logAndReply logs an error of a request and replies with it. Errors
+// of the request itself, rather than of the server, are logged as info.
 func (h *Handler) logAndReply(ctx context.Context, msg string, w http.ResponseWriter, err error) {
-	h.logger.Error(msg,
-		"request_id", ctx.Value(RequestIDContextKey),
-		"error", err,
-	)
-	if err = common.ReplyWithError(w, err); err != nil {
+	var e *common.Error
+	if errors.As(err, &e) && e.Status < http.StatusInternalServerError {
+		h.logger.Info(msg,
+			"request_id", ctx.Value(RequestIDContextKey),
+			"error", err,
+		)
+	} else {
+		h.logger.Error(msg,
+			"request_id", ctx.Value(RequestIDContextKey),
+			"error", err,
+		)
+	}
+	if err = common.ReplyWithError(w, RequestID(ctx), err); err != nil {
 		h.logger.Error("failed to reply with error",
 			"request_id", ctx.Value(RequestIDContextKey),
 			"error", err,
@@ -0,0 +1,255 @@
+package v1
+
+import (
+	"fmt"
+	"math/big"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/oasisprotocol/oasis-indexer/api/common"
+)
+
+// filterOp is an operator of list filters, which are query parameters
+// of the form field[op]=value.
+type filterOp string
+
+const (
+	opEq     filterOp = "eq"
+	opNe     filterOp = "ne"
+	opGt     filterOp = "gt"
+	opGte    filterOp = "gte"
+	opLt     filterOp = "lt"
+	opLte    filterOp = "lte"
+	opPrefix filterOp = "prefix"
+)
+
+var (
+	numericOps = []filterOp{opEq, opNe, opGt, opGte, opLt, opLte}
+	textOps    = []filterOp{opEq, opNe, opPrefix}
+	idOps      = []filterOp{opEq, opNe}
+	boolOps    = []filterOp{opEq, opNe}
+)
+
+// sqlOperators are the SQL operators of filter operators. Prefixes
+// are matched with patterns.
+var sqlOperators = map[filterOp]string{
+	opEq:     "=",
+	opNe:     "<>",
+	opGt:     ">",
+	opGte:    ">=",
+	opLt:     "<",
+	opLte:    "<=",
+	opPrefix: "ILIKE",
+}
+
+// fieldType is the type of a list field, which determines how
+// filter values are parsed.
+type fieldType string
+
+const (
+	numericField fieldType = "numeric"
+	textField    fieldType = "text"
+	boolField    fieldType = "boolean"
+)
+
+// listField is a field of list items that lists may be ordered
+// or filtered by.
+type listField struct {
+	// expr is the SQL expression of the field, in terms of the
+	// columns of the list query.
+	expr string
+	typ  fieldType
+	// ops are the allowed filter operators, if any.
+	ops []filterOp
+	// sortable is true if lists may be ordered by the field.
+	sortable bool
+}
+
+// listSpec declares how the items of a list endpoint may be ordered
+// and filtered. Lists are ordered by the order_by query parameter,
+// which is a comma separated list of fields, each prefixed by "-"
+// for descending order.
+type listSpec struct {
+	fields map[string]listField
+	// defaultOrder is the order of lists if none is requested.
+	defaultOrder string
+	// key is the expression of a unique key of list items, which
+	// breaks ties so that pages are deterministic.
+	key string
+}
+
+// listClauses are the SQL clauses of a list query that were requested.
+type listClauses struct {
+	where   string
+	orderBy string
+	// args are the arguments of the where clause.
+	args []interface{}
+}
+
+// parse parses the order and filters of a list request. Placeholders
+// of filter arguments are numbered from firstArg.
+func (s *listSpec) parse(params url.Values, p common.Pagination, firstArg int) (*listClauses, error) {
+	order := s.defaultOrder
+	if p.Order != nil && *p.Order != common.DefaultOrder {
+		order = *p.Order
+	}
+
+	var orderBy []string
+	for _, name := range strings.Split(order, ",") {
+		dir := "ASC"
+		if strings.HasPrefix(name, "-") {
+			name, dir = name[1:], "DESC"
+		}
+		f, ok := s.fields[name]
+		if !ok || !f.sortable {
+			return nil, fmt.Errorf("cannot order by '%s'", name)
+		}
+		orderBy = append(orderBy, fmt.Sprintf("%s %s NULLS LAST", f.expr, dir))
+	}
+	orderBy = append(orderBy, s.key)
+
+	// Filters are applied in a deterministic order, so that queries
+	// of the same request are the same.
+	keys := make([]string, 0, len(params))
+	for key := range params {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var clauses listClauses
+	var where []string
+	for _, key := range keys {
+		open := strings.IndexByte(key, '[')
+		if open < 0 || !strings.HasSuffix(key, "]") {
+			continue
+		}
+		name, op := key[:open], filterOp(key[open+1:len(key)-1])
+		f, ok := s.fields[name]
+		if !ok || !hasOp(f.ops, op) {
+			return nil, fmt.Errorf("cannot filter by '%s'", key)
+		}
+		for _, v := range params[key] {
+			arg, err := f.typ.parse(v)
+			if err != nil {
+				return nil, fmt.Errorf("malformed filter '%s': %w", key, err)
+			}
+			if op == opPrefix {
+				arg = likeEscaper.Replace(v) + "%"
+			}
+			where = append(where, fmt.Sprintf("%s %s $%d::%s", f.expr, sqlOperators[op], firstArg+len(clauses.args), f.typ))
+			clauses.args = append(clauses.args, arg)
+		}
+	}
+
+	clauses.where = "TRUE"
+	if len(where) > 0 {
+		clauses.where = strings.Join(where, " AND ")
+	}
+	clauses.orderBy = strings.Join(orderBy, ", ")
+	return &clauses, nil
+}
+
+// parse validates a filter value of the type. Values are passed to
+// queries as text, and cast to the type of the field.
+func (t fieldType) parse(v string) (interface{}, error) {
+	switch t {
+	case numericField:
+		if _, ok := new(big.Int).SetString(v, 10); !ok {
+			return nil, fmt.Errorf("'%s' is not an integer", v)
+		}
+	case boolField:
+		if _, err := strconv.ParseBool(v); err != nil {
+			return nil, err
+		}
+	}
+	return v, nil
+}
+
+func hasOp(ops []filterOp, op filterOp) bool {
+	for _, o := range ops {
+		if o == op {
+			return true
+		}
+	}
+	return false
+}
+
+// accountsListSpec declares the order and filters of ListAccounts.
+var accountsListSpec = listSpec{
+	fields: map[string]listField{
+		"address":       {expr: "address", typ: textField, ops: idOps, sortable: true},
+		"nonce":         {expr: "nonce", typ: numericField, ops: numericOps, sortable: true},
+		"available":     {expr: "general_balance", typ: numericField, ops: numericOps, sortable: true},
+		"escrow":        {expr: "escrow_balance_active", typ: numericField, ops: numericOps, sortable: true},
+		"debonding":     {expr: "escrow_balance_debonding", typ: numericField, ops: numericOps, sortable: true},
+		"total_balance": {expr: "general_balance + escrow_balance_active + escrow_balance_debonding", typ: numericField, ops: numericOps, sortable: true},
+	},
+	defaultOrder: "address",
+	key:          "address",
+}
+
+// entitiesListSpec declares the order and filters of ListEntities.
+var entitiesListSpec = listSpec{
+	fields: map[string]listField{
+		"id":         {expr: "id", typ: textField, ops: idOps, sortable: true},
+		"address":    {expr: "address", typ: textField, ops: idOps, sortable: true},
+		"name":       {expr: "meta->>'name'", typ: textField, ops: textOps, sortable: true},
+		"escrow":     {expr: "escrow", typ: numericField, ops: numericOps, sortable: true},
+		"delegators": {expr: "delegators", typ: numericField, ops: numericOps, sortable: true},
+	},
+	defaultOrder: "id",
+	key:          "id",
+}
+
+// entityNodesListSpec declares the order and filters of ListEntityNodes.
+var entityNodesListSpec = listSpec{
+	fields: map[string]listField{
+		"id":               {expr: "id", typ: textField, ops: idOps, sortable: true},
+		"expiration":       {expr: "expiration", typ: numericField, ops: numericOps, sortable: true},
+		"software_version": {expr: "software_version", typ: textField, ops: textOps, sortable: true},
+	},
+	defaultOrder: "id",
+	key:          "id",
+}
+
+// validatorsListSpec declares the order and filters of ListValidators.
+var validatorsListSpec = listSpec{
+	fields: map[string]listField{
+		"entity_id":      {expr: "entity_id", typ: textField, ops: idOps, sortable: true},
+		"entity_address": {expr: "entity_address", typ: textField, ops: idOps, sortable: true},
+		"name":           {expr: "meta->>'name'", typ: textField, ops: textOps, sortable: true},
+		"escrow":         {expr: "escrow", typ: numericField, ops: numericOps, sortable: true},
+		"commission":     {expr: "current_rate", typ: numericField, ops: numericOps, sortable: true},
+		"active":         {expr: "active", typ: boolField, ops: boolOps, sortable: true},
+		"status":         {expr: "status", typ: boolField, ops: boolOps, sortable: true},
+	},
+	defaultOrder: "-escrow",
+	key:          "entity_id",
+}
+
+// validatorDelegatorsListSpec declares the order and filters of
+// GetValidatorDelegators.
+var validatorDelegatorsListSpec = listSpec{
+	fields: map[string]listField{
+		"address": {expr: "address", typ: textField, ops: idOps, sortable: true},
+		"amount":  {expr: "amount", typ: numericField, ops: numericOps, sortable: true},
+		"shares":  {expr: "shares", typ: numericField, ops: numericOps, sortable: true},
+	},
+	defaultOrder: "-amount",
+	key:          "address",
+}
+
+// validatorDebondingDelegatorsListSpec declares the order and filters of
+// GetValidatorDebondingDelegators.
+var validatorDebondingDelegatorsListSpec = listSpec{
+	fields: map[string]listField{
+		"address":    {expr: "address", typ: textField, ops: idOps, sortable: true},
+		"amount":     {expr: "amount", typ: numericField, ops: numericOps, sortable: true},
+		"shares":     {expr: "shares", typ: numericField, ops: numericOps, sortable: true},
+		"debond_end": {expr: "debond_end", typ: numericField, ops: numericOps, sortable: true},
+	},
+	defaultOrder: "debond_end",
+	key:          "id",
+}
@@ -0,0 +1,118 @@
+package v1
+
+import (
+	"io/ioutil"
+	"net/url"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v2"
+
+	"github.com/oasisprotocol/oasis-indexer/api/common"
+)
+
+func TestListSpecParse(t *testing.T) {
+	parse := func(query string) (*listClauses, error) {
+		params, err := url.ParseQuery(query)
+		require.Nil(t, err)
+		order := common.DefaultOrder
+		if v := params.Get(common.OrderKey); v != "" {
+			order = v
+		}
+		return validatorsListSpec.parse(params, common.Pagination{Order: &order}, 3)
+	}
+
+	// Lists have a default order, and ties are broken by key.
+	c, err := parse("")
+	require.Nil(t, err)
+	require.Equal(t, "TRUE", c.where)
+	require.Equal(t, "escrow DESC NULLS LAST, entity_id", c.orderBy)
+	require.Empty(t, c.args)
+
+	c, err = parse("order_by=name,-commission&escrow[gte]=100&escrow[lt]=200&name[prefix]=100%25_&active[eq]=true&limit=10")
+	require.Nil(t, err)
+	require.Equal(t, "meta->>'name' ASC NULLS LAST, current_rate DESC NULLS LAST, entity_id", c.orderBy)
+	require.Equal(t, "active = $3::boolean AND escrow >= $4::numeric AND escrow < $5::numeric AND meta->>'name' ILIKE $6::text", c.where)
+	require.Equal(t, []interface{}{"true", "100", "200", `100\%\_%`}, c.args)
+
+	// Only declared fields and operators are allowed.
+	for _, query := range []string{
+		"order_by=meta",
+		"order_by=-",
+		"order_by=escrow,",
+		"node_address[eq]=a",
+		"escrow[prefix]=1",
+		"escrow[gte]=1.5",
+		"active[eq]=maybe",
+		"name[]=bit",
+	} {
+		_, err := parse(query)
+		require.NotNil(t, err, query)
+	}
+}
+
+// TestListSpecsMatchSpec checks that the orders and filters of list
+// endpoints are documented in the OpenAPI spec.
+func TestListSpecsMatchSpec(t *testing.T) {
+	raw, err := ioutil.ReadFile("../spec/v1.yaml")
+	require.Nil(t, err)
+
+	var spec struct {
+		Paths map[string]struct {
+			Get struct {
+				Parameters []struct {
+					Name   string `yaml:"name"`
+					Style  string `yaml:"style"`
+					Schema struct {
+						Properties map[string]interface{} `yaml:"properties"`
+						Items      struct {
+							Enum []string `yaml:"enum"`
+						} `yaml:"items"`
+					} `yaml:"schema"`
+				} `yaml:"parameters"`
+			} `yaml:"get"`
+		} `yaml:"paths"`
+	}
+	require.Nil(t, yaml.Unmarshal(raw, &spec))
+
+	for path, s := range map[string]*listSpec{
+		"/consensus/accounts":                   &accountsListSpec,
+		"/consensus/entities":                   &entitiesListSpec,
+		"/consensus/entities/{entity_id}/nodes": &entityNodesListSpec,
+		"/consensus/validators":                 &validatorsListSpec,
+	} {
+		var orders []string
+		filters := map[string][]string{}
+		for name, f := range s.fields {
+			if f.sortable {
+				orders = append(orders, name, "-"+name)
+			}
+			for _, op := range f.ops {
+				filters[name] = append(filters[name], string(op))
+			}
+			sort.Strings(filters[name])
+		}
+		sort.Strings(orders)
+
+		var specOrders []string
+		specFilters := map[string][]string{}
+		for _, p := range spec.Paths[path].Get.Parameters {
+			switch {
+			case p.Name == common.OrderKey:
+				specOrders = append(specOrders, p.Schema.Items.Enum...)
+			case p.Style == "deepObject":
+				for op := range p.Schema.Properties {
+					specFilters[p.Name] = append(specFilters[p.Name], op)
+				}
+				sort.Strings(specFilters[p.Name])
+			}
+		}
+		sort.Strings(specOrders)
+
+		require.Equal(t, orders, specOrders, path)
+		require.Equal(t, filters, specFilters, path)
+		require.Contains(t, specOrders, strings.Split(s.defaultOrder, ",")[0], path)
+	}
+}
@@ -2,11 +2,20 @@ package v1
 
 import (
 	"context"
+	"errors"
+	"io"
 	"net/http"
 	"time"
 
+	"github.com/go-chi/chi/v5"
 	"github.com/google/uuid"
 	"github.com/iancoleman/strcase"
+	"github.com/oasisprotocol/oasis-core/go/common/crypto/hash"
+	"github.com/oasisprotocol/oasis-core/go/common/crypto/signature"
+	staking "github.com/oasisprotocol/oasis-core/go/staking/api"
+
+	"github.com/oasisprotocol/oasis-indexer/api/common"
+	"github.com/oasisprotocol/oasis-indexer/api/spec"
 )
 
 type ContextKey string
@@ -18,6 +27,9 @@ const (
 	// RequestIDContextKey is used to set a request id for tracing
 	// in a request context.
 	RequestIDContextKey ContextKey = "request_id"
+	// APIKeyIDContextKey is used to set the ID of the API key that a
+	// request was authenticated with in a request context.
+	APIKeyIDContextKey ContextKey = "api_key_id"
 )
 
 // metricsMiddleware is a middleware that measures the start and end of each request,
@@ -48,6 +60,37 @@ func (h *Handler) metricsMiddleware(next http.Handler) http.Handler {
 	})
 }
 
+// RequestID returns the ID of the request of a context, or an empty
+// string if it has none.
+func RequestID(ctx context.Context) string {
+	id, ok := ctx.Value(RequestIDContextKey).(uuid.UUID)
+	if !ok {
+		return ""
+	}
+	return id.String()
+}
+
+// apiKeyID returns the ID of the API key that the request of a context
+// was authenticated with, if any.
+func apiKeyID(ctx context.Context) (int64, bool) {
+	id, ok := ctx.Value(APIKeyIDContextKey).(int64)
+	return id, ok
+}
+
+// keyMiddleware is a middleware that rejects requests that were not
+// authenticated with an API key, e.g. since the API is served without
+// API keys.
+func (h *Handler) keyMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if _, ok := apiKeyID(r.Context()); !ok {
+			h.logAndReply(r.Context(), "api key required", w, common.ErrKeyRequired)
+			h.metrics.RequestCounter(r.URL.Path, "failure", common.ErrorCause(common.ErrKeyRequired)).Inc()
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
 // chainMiddleware is a middleware that adds chain-specific information
 // to the request context.
 func (h *Handler) chainMiddleware(next http.Handler) http.Handler {
@@ -61,3 +104,89 @@ func (h *Handler) chainMiddleware(next http.Handler) http.Handler {
 		))
 	})
 }
+
+func init() {
+	spec.DefineStringFormat("address", "a staking address", func(s string) error {
+		var address staking.Address
+		return address.UnmarshalText([]byte(s))
+	})
+	spec.DefineStringFormat("public-key", "a base64-encoded public key", func(s string) error {
+		var pk signature.PublicKey
+		return pk.UnmarshalText([]byte(s))
+	})
+	spec.DefineStringFormat("hash", "a hex-encoded hash", func(s string) error {
+		var h hash.Hash
+		return h.UnmarshalHex(s)
+	})
+}
+
+// validationMiddleware is a middleware that validates requests against the
+// OpenAPI specification, so that handlers only receive parameters of the
+// specified types. Requests for operations that are not specified are left
+// to the router.
+func (h *Handler) validationMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+
+		doc, err := spec.V1()
+		if err != nil {
+			h.logAndReply(ctx, "failed to load spec", w, err)
+			h.metrics.RequestCounter(r.URL.Path, "failure", "spec_error").Inc()
+			return
+		}
+
+		// Within the routes of the API, the route path is relative
+		// to the server URL of the specification.
+		path := r.URL.Path
+		if rctx := chi.RouteContext(ctx); rctx != nil && rctx.RoutePath != "" {
+			path = rctx.RoutePath
+		}
+		route := doc.FindRoute(r.Method, path)
+		if route == nil {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		if r.Body != nil {
+			r.Body = io.NopCloser(io.LimitReader(r.Body, maxRequestBodySize))
+		}
+		if err := route.ValidateRequest(r); err != nil {
+			h.logger.Info("invalid request",
+				"request_id", ctx.Value(RequestIDContextKey),
+				"operation", route.Operation.OperationID,
+				"err", err.Error(),
+			)
+			reply := error(common.ErrBadRequest)
+			var ve *spec.ValidationError
+			if errors.As(err, &ve) && ve.Path != "" {
+				reply = common.NewInvalidParameterError(ve.Path, ve.Msg)
+			}
+			h.logAndReply(ctx, "failed to validate request", w, reply)
+			h.metrics.RequestCounter(r.URL.Path, "failure", common.ErrorCause(reply)).Inc()
+			return
+		}
+
+		// Other query parameters that are not specified are ignored, but
+		// lists that cannot be ordered reject an order, so that clients
+		// do not take them to be ordered as requested.
+		if r.URL.Query().Get(common.OrderKey) != "" && !hasQueryParameter(route.Operation, common.OrderKey) {
+			reply := common.NewInvalidParameterError(common.OrderKey, "is not supported")
+			h.logAndReply(ctx, "failed to validate request", w, reply)
+			h.metrics.RequestCounter(r.URL.Path, "failure", common.ErrorCause(reply)).Inc()
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+// hasQueryParameter returns whether an operation specifies the named
+// query parameter.
+func hasQueryParameter(op *spec.Operation, name string) bool {
+	for _, p := range op.Parameters {
+		if p.In == "query" && p.Name == name {
+			return true
+		}
+	}
+	return false
+}
@@ -21,6 +21,20 @@ func (qf QueryFactory) StatusQuery() string {
 		LIMIT 1`, qf.chainID)
 }
 
+func (qf QueryFactory) LatestProcessedTimeQuery() string {
+	return fmt.Sprintf(`
+		SELECT COALESCE(MAX(processed_time), 'epoch'::timestamptz)
+			FROM %s.processed_blocks`, qf.chainID)
+}
+
+func (qf QueryFactory) LatestBlockTimeQuery() string {
+	return fmt.Sprintf(`
+		SELECT time
+			FROM %s.blocks
+		ORDER BY height DESC
+		LIMIT 1`, qf.chainID)
+}
+
 func (qf QueryFactory) BlocksQuery() string {
 	return fmt.Sprintf(`
 		SELECT height, block_hash, time
@@ -71,13 +85,38 @@ func (qf QueryFactory) EntitiesQuery() string {
 		OFFSET $2::bigint`, qf.chainID)
 }
 
+func (qf QueryFactory) EntitiesListQuery(c *listClauses) string {
+	return listQuery(
+		entityColumns,
+		qf.entitiesQuery(),
+		0, c,
+	)
+}
+
 func (qf QueryFactory) EntityQuery() string {
 	return fmt.Sprintf(`
-		SELECT id, address
-			FROM %s.entities
-			WHERE id = $1::text`, qf.chainID)
+		SELECT %s
+			FROM (%s) AS entity
+			WHERE id = $1::text`, entityColumns, qf.entitiesQuery())
 }
 
+// entitiesQuery selects entities, along with the active escrow and the
+// number of delegators of their staking account.
+func (qf QueryFactory) entitiesQuery() string {
+	return fmt.Sprintf(`
+		SELECT
+				%[1]s.entities.id AS id,
+				%[1]s.entities.address AS address,
+				%[1]s.entities.meta AS meta,
+				COALESCE(%[1]s.accounts.escrow_balance_active, 0) AS escrow,
+				(SELECT COUNT(*) FROM %[1]s.delegations WHERE delegatee = %[1]s.entities.address AND shares > 0) AS delegators
+			FROM %[1]s.entities
+			LEFT JOIN %[1]s.accounts ON %[1]s.entities.address = %[1]s.accounts.address`, qf.chainID)
+}
+
+// entityColumns are the columns of entities that are scanned.
+const entityColumns = "id, address, meta, escrow, delegators"
+
 func (qf QueryFactory) EntityNodeIdsQuery() string {
 	return fmt.Sprintf(`
 		SELECT id
@@ -85,23 +124,96 @@ func (qf QueryFactory) EntityNodeIdsQuery() string {
 			WHERE entity_id = $1::text`, qf.chainID)
 }
 
-func (qf QueryFactory) EntityNodesQuery() string {
-	return fmt.Sprintf(`
-		SELECT id, entity_id, expiration, tls_pubkey, tls_next_pubkey, p2p_pubkey, consensus_pubkey, roles
+func (qf QueryFactory) EntityNodesListQuery(c *listClauses) string {
+	return listQuery(
+		nodeColumns,
+		fmt.Sprintf(`
+		SELECT *
 			FROM %s.nodes
 			WHERE entity_id = $1::text
-		LIMIT $2::bigint
-		OFFSET $3::bigint`, qf.chainID)
+				AND ($2::text IS NULL OR $2::text = ANY(string_to_array(roles, ',')))`, qf.chainID),
+		2, c,
+	)
 }
 
 func (qf QueryFactory) EntityNodeQuery() string {
 	return fmt.Sprintf(`
-		SELECT id, entity_id, expiration, tls_pubkey, tls_next_pubkey, p2p_pubkey, consensus_pubkey, roles
+		SELECT %s
 			FROM %s.nodes
-			WHERE entity_id = $1::text AND id = $2::text`, qf.chainID)
+			WHERE entity_id = $1::text AND id = $2::text`, nodeColumns, qf.chainID)
 }
 
-func (qf QueryFactory) AccountsQuery() string {
+// nodeColumns are the columns of nodes that are scanned. Consensus
+// addresses are kept as a comma-separated list.
+const nodeColumns = `
+		id, entity_id, expiration, tls_pubkey, tls_next_pubkey, p2p_pubkey, consensus_pubkey, roles,
+		COALESCE(tls_addresses, '{}'), COALESCE(p2p_addresses, '{}'),
+		COALESCE(string_to_array(NULLIF(consensus_address, ''), ','), '{}'),
+		COALESCE(vrf_pubkey, ''), COALESCE(software_version, ''), COALESCE(runtimes, '[]'), freeze_end`
+
+func (qf QueryFactory) EntityNodeHistoryLookupQuery() string {
+	return fmt.Sprintf(`
+		SELECT node_id
+			FROM %s.node_history
+			WHERE entity_id = $1::text AND node_id = $2::text
+		LIMIT 1`, qf.chainID)
+}
+
+func (qf QueryFactory) EntityNodeHistoryQuery() string {
+	return fmt.Sprintf(`
+		SELECT height, event, expiration, COALESCE(software_version, ''), COALESCE(roles, ''),
+			COALESCE(tls_addresses, '{}'), COALESCE(p2p_addresses, '{}'), COALESCE(consensus_address, ''), freeze_end
+			FROM %s.node_history
+			WHERE entity_id = $1::text AND node_id = $2::text
+		ORDER BY height DESC, id DESC
+		LIMIT $3::bigint
+		OFFSET $4::bigint`, qf.chainID)
+}
+
+const runtimeColumns = `id, kind, tee_hardware, COALESCE(NULLIF(key_manager, 'none'), ''), suspended, genesis_round, COALESCE(genesis_state_root, '')`
+
+func (qf QueryFactory) RuntimesQuery() string {
+	return fmt.Sprintf(`
+		SELECT `+runtimeColumns+`
+			FROM %s.runtimes
+		ORDER BY id
+		LIMIT $1::bigint
+		OFFSET $2::bigint`, qf.chainID)
+}
+
+func (qf QueryFactory) RuntimeQuery() string {
+	return fmt.Sprintf(`
+		SELECT `+runtimeColumns+`
+			FROM %s.runtimes
+			WHERE id = $1::text`, qf.chainID)
+}
+
+func (qf QueryFactory) RuntimeCommitteesQuery() string {
+	return fmt.Sprintf(`
+		SELECT runtime, kind, node, `+nodeEntityColumn+`, role
+			FROM %[1]s.committee_members
+			WHERE runtime = $1::text
+		ORDER BY kind, role, node`, qf.chainID, "node")
+}
+
+func (qf QueryFactory) RuntimeHistoryLookupQuery() string {
+	return fmt.Sprintf(`
+		SELECT id
+			FROM %s.runtimes
+			WHERE id = $1::text`, qf.chainID)
+}
+
+func (qf QueryFactory) RuntimeHistoryQuery() string {
+	return fmt.Sprintf(`
+		SELECT height, event
+			FROM %s.runtime_history
+			WHERE runtime = $1::text
+		ORDER BY height DESC, id DESC
+		LIMIT $2::bigint
+		OFFSET $3::bigint`, qf.chainID)
+}
+
+func (qf QueryFactory) accountsQuery() string {
 	return fmt.Sprintf(`
 		SELECT address, nonce, general_balance, escrow_balance_active, escrow_balance_debonding
 			FROM %s.accounts
@@ -112,9 +224,20 @@ func (qf QueryFactory) AccountsQuery() string {
 						($5::bigint IS NULL OR escrow_balance_debonding >= $5::bigint) AND
 						($6::bigint IS NULL OR escrow_balance_debonding <= $6::bigint) AND
 						($7::bigint IS NULL OR general_balance + escrow_balance_active + escrow_balance_debonding >= $7::bigint) AND
-						($8::bigint IS NULL OR general_balance + escrow_balance_active + escrow_balance_debonding <= $8::bigint)
+						($8::bigint IS NULL OR general_balance + escrow_balance_active + escrow_balance_debonding <= $8::bigint)`, qf.chainID)
+}
+
+func (qf QueryFactory) AccountsQuery() string {
+	return qf.accountsQuery() + `
 		LIMIT $9::bigint
-		OFFSET $10::bigint`, qf.chainID)
+		OFFSET $10::bigint`
+}
+
+func (qf QueryFactory) AccountsListQuery(c *listClauses) string {
+	return listQuery(
+		"address, nonce, general_balance, escrow_balance_active, escrow_balance_debonding",
+		qf.accountsQuery(), 8, c,
+	)
 }
 
 func (qf QueryFactory) AccountQuery() string {
@@ -164,6 +287,62 @@ func (qf QueryFactory) DebondingDelegationsQuery() string {
 		OFFSET $3::bigint`, qf.chainID)
 }
 
+func (qf QueryFactory) ValidatorDelegationTotalsQuery() string {
+	return fmt.Sprintf(`
+		SELECT
+				COALESCE(%[1]s.accounts.escrow_balance_active, 0),
+				COALESCE(%[1]s.accounts.escrow_total_shares_active, 0),
+				(SELECT COUNT(*) FROM %[1]s.delegations WHERE delegatee = %[1]s.entities.address AND shares > 0)
+			FROM %[1]s.entities
+			LEFT JOIN %[1]s.accounts ON %[1]s.entities.address = %[1]s.accounts.address
+			WHERE %[1]s.entities.id = $1::text`, qf.chainID)
+}
+
+func (qf QueryFactory) ValidatorDelegatorsListQuery(c *listClauses) string {
+	return listQuery(
+		"address, amount, shares",
+		fmt.Sprintf(`
+		SELECT
+				%[1]s.delegations.delegator AS address,
+				COALESCE(div(%[1]s.delegations.shares * %[1]s.accounts.escrow_balance_active, NULLIF(%[1]s.accounts.escrow_total_shares_active, 0)), 0) AS amount,
+				%[1]s.delegations.shares AS shares
+			FROM %[1]s.entities
+			JOIN %[1]s.delegations ON %[1]s.entities.address = %[1]s.delegations.delegatee
+			JOIN %[1]s.accounts ON %[1]s.entities.address = %[1]s.accounts.address
+			WHERE %[1]s.entities.id = $1::text AND %[1]s.delegations.shares > 0`, qf.chainID),
+		1, c,
+	)
+}
+
+func (qf QueryFactory) ValidatorDebondingDelegationTotalsQuery() string {
+	return fmt.Sprintf(`
+		SELECT
+				COALESCE(%[1]s.accounts.escrow_balance_debonding, 0),
+				COALESCE(%[1]s.accounts.escrow_total_shares_debonding, 0),
+				(SELECT COUNT(*) FROM %[1]s.debonding_delegations WHERE delegatee = %[1]s.entities.address)
+			FROM %[1]s.entities
+			LEFT JOIN %[1]s.accounts ON %[1]s.entities.address = %[1]s.accounts.address
+			WHERE %[1]s.entities.id = $1::text`, qf.chainID)
+}
+
+func (qf QueryFactory) ValidatorDebondingDelegatorsListQuery(c *listClauses) string {
+	return listQuery(
+		"address, amount, shares, debond_end",
+		fmt.Sprintf(`
+		SELECT
+				%[1]s.debonding_delegations.id AS id,
+				%[1]s.debonding_delegations.delegator AS address,
+				COALESCE(div(%[1]s.debonding_delegations.shares * %[1]s.accounts.escrow_balance_debonding, NULLIF(%[1]s.accounts.escrow_total_shares_debonding, 0)), 0) AS amount,
+				%[1]s.debonding_delegations.shares AS shares,
+				%[1]s.debonding_delegations.debond_end AS debond_end
+			FROM %[1]s.entities
+			JOIN %[1]s.debonding_delegations ON %[1]s.entities.address = %[1]s.debonding_delegations.delegatee
+			JOIN %[1]s.accounts ON %[1]s.entities.address = %[1]s.accounts.address
+			WHERE %[1]s.entities.id = $1::text`, qf.chainID),
+		1, c,
+	)
+}
+
 func (qf QueryFactory) EpochsQuery() string {
 	return fmt.Sprintf(`
 		SELECT id, start_height, end_height
@@ -180,11 +359,50 @@ func (qf QueryFactory) EpochQuery() string {
 			WHERE id = $1::bigint`, qf.chainID)
 }
 
+// nodeEntityColumn selects the entity controlling a node, which is kept in
+// the history of the node once it expires.
+const nodeEntityColumn = `
+				COALESCE(
+					(SELECT entity_id FROM %[1]s.nodes WHERE id = %[2]s),
+					(SELECT entity_id FROM %[1]s.node_history WHERE node_id = %[2]s ORDER BY height DESC LIMIT 1),
+					''
+				)`
+
+func (qf QueryFactory) EpochCommitteesQuery() string {
+	return fmt.Sprintf(`
+		SELECT runtime, kind, node, `+nodeEntityColumn+`, role
+			FROM %[1]s.epoch_committee_members
+			WHERE epoch = $1::bigint
+				AND ($2::text IS NULL OR runtime = $2::text)
+				AND ($3::text IS NULL OR kind = $3::text)
+		ORDER BY runtime, kind, role, node
+		LIMIT $4::bigint
+		OFFSET $5::bigint`, qf.chainID, "node")
+}
+
+func (qf QueryFactory) EpochValidatorsQuery() string {
+	return fmt.Sprintf(`
+		SELECT node_id, `+nodeEntityColumn+`, voting_power
+			FROM %[1]s.epoch_validators
+			WHERE epoch = $1::bigint
+		ORDER BY voting_power DESC, node_id
+		LIMIT $2::bigint
+		OFFSET $3::bigint`, qf.chainID, "node_id")
+}
+
+// proposalResultsColumns selects the stake and the number of tallied votes
+// for each choice of a proposal.
+const proposalResultsColumns = `
+				yes_stake, no_stake, abstain_stake,
+				(SELECT COUNT(*) FROM %[1]s.votes WHERE proposal = %[1]s.proposals.id AND weight IS NOT NULL AND vote = 'yes') AS yes_votes,
+				(SELECT COUNT(*) FROM %[1]s.votes WHERE proposal = %[1]s.proposals.id AND weight IS NOT NULL AND vote = 'no') AS no_votes,
+				(SELECT COUNT(*) FROM %[1]s.votes WHERE proposal = %[1]s.proposals.id AND weight IS NOT NULL AND vote = 'abstain') AS abstain_votes`
+
 func (qf QueryFactory) ProposalsQuery() string {
 	return fmt.Sprintf(`
 		SELECT id, submitter, state, deposit, handler, cp_target_version, rhp_target_version, rcp_target_version,
-				upgrade_epoch, cancels, created_at, closes_at, invalid_votes
-			FROM %s.proposals
+				upgrade_epoch, cancels, created_at, closes_at, invalid_votes, content,`+proposalResultsColumns+`
+			FROM %[1]s.proposals
 			WHERE ($1::text IS NULL OR submitter = $1::text) AND
 						($2::text IS NULL OR state = $2::text)
 		ORDER BY id DESC
@@ -195,14 +413,14 @@ func (qf QueryFactory) ProposalsQuery() string {
 func (qf QueryFactory) ProposalQuery() string {
 	return fmt.Sprintf(`
 		SELECT id, submitter, state, deposit, handler, cp_target_version, rhp_target_version, rcp_target_version,
-				upgrade_epoch, cancels, created_at, closes_at, invalid_votes
-			FROM %s.proposals
+				upgrade_epoch, cancels, created_at, closes_at, invalid_votes, content,`+proposalResultsColumns+`
+			FROM %[1]s.proposals
 			WHERE id = $1::bigint`, qf.chainID)
 }
 
 func (qf QueryFactory) ProposalVotesQuery() string {
 	return fmt.Sprintf(`
-		SELECT voter, vote
+		SELECT voter, vote, weight
 			FROM %s.votes
 			WHERE proposal = $1::bigint
 		ORDER BY proposal DESC
@@ -228,10 +446,14 @@ func (qf QueryFactory) ValidatorDataQuery() string {
 				%[1]s.commissions.schedule AS commissions_schedule,
 				CASE WHEN EXISTS(SELECT null FROM %[1]s.nodes WHERE %[1]s.entities.id = %[1]s.nodes.entity_id AND voting_power > 0) THEN true ELSE false END AS active,
 				CASE WHEN EXISTS(SELECT null FROM %[1]s.nodes WHERE %[1]s.entities.id = %[1]s.nodes.entity_id AND %[1]s.nodes.roles like '%%validator%%') THEN true ELSE false END AS status,
-				%[1]s.entities.meta AS meta
+				%[1]s.entities.meta AS meta,
+				(SELECT COUNT(*) FROM %[1]s.proposals WHERE state <> 'active') AS closed_proposals,
+				(SELECT COUNT(*) FROM %[1]s.votes WHERE voter = %[1]s.entities.address AND weight IS NOT NULL) AS tallied_votes,
+				`+delegatorStatsColumns+`
 			FROM %[1]s.entities
 			JOIN %[1]s.accounts ON %[1]s.entities.address = %[1]s.accounts.address
 			LEFT JOIN %[1]s.commissions ON %[1]s.entities.address = %[1]s.commissions.address
+			`+delegatorStatsJoin+`
 			JOIN %[1]s.nodes ON %[1]s.entities.id = %[1]s.nodes.entity_id
 				AND %[1]s.nodes.roles like '%%validator%%'
 				AND %[1]s.nodes.voting_power = (
@@ -250,7 +472,9 @@ func (qf QueryFactory) ValidatorsQuery() string {
 			ORDER BY id DESC`, qf.chainID)
 }
 
-func (qf QueryFactory) ValidatorsDataQuery() string {
+// validatorsDataQuery selects validators, along with their current
+// commission rate.
+func (qf QueryFactory) validatorsDataQuery() string {
 	return fmt.Sprintf(`
 		SELECT
 				%[1]s.entities.id AS entity_id,
@@ -260,10 +484,21 @@ func (qf QueryFactory) ValidatorsDataQuery() string {
 				%[1]s.commissions.schedule AS commissions_schedule,
 				CASE WHEN EXISTS(SELECT NULL FROM %[1]s.nodes WHERE %[1]s.entities.id = %[1]s.nodes.entity_id AND voting_power > 0) THEN true ELSE false END AS active,
 				CASE WHEN EXISTS(SELECT NULL FROM %[1]s.nodes WHERE %[1]s.entities.id = %[1]s.nodes.entity_id AND %[1]s.nodes.roles like '%%validator%%') THEN true ELSE false END AS status,
-				%[1]s.entities.meta AS meta
+				%[1]s.entities.meta AS meta,
+				(SELECT COUNT(*) FROM %[1]s.proposals WHERE state <> 'active') AS closed_proposals,
+				(SELECT COUNT(*) FROM %[1]s.votes WHERE voter = %[1]s.entities.address AND weight IS NOT NULL) AS tallied_votes,
+				`+delegatorStatsColumns+`,
+				(
+					SELECT (rate->>'rate')::numeric
+						FROM json_array_elements(%[1]s.commissions.schedule->'rates') AS rate
+						WHERE COALESCE((rate->>'start')::bigint, 0) <= (SELECT MAX(id) FROM %[1]s.epochs)
+					ORDER BY COALESCE((rate->>'start')::bigint, 0) DESC
+					LIMIT 1
+				) AS current_rate
 			FROM %[1]s.entities
 			JOIN %[1]s.accounts ON %[1]s.entities.address = %[1]s.accounts.address
 			LEFT JOIN %[1]s.commissions ON %[1]s.entities.address = %[1]s.commissions.address
+			`+delegatorStatsJoin+`
 			JOIN %[1]s.nodes ON %[1]s.entities.id = %[1]s.nodes.entity_id
 				AND %[1]s.nodes.roles like '%%validator%%'
 				AND %[1]s.nodes.voting_power = (
@@ -271,10 +506,47 @@ func (qf QueryFactory) ValidatorsDataQuery() string {
 					FROM %[1]s.nodes
 					WHERE %[1]s.entities.id = %[1]s.nodes.entity_id
 						AND %[1]s.nodes.roles like '%%validator%%'
-				)
-		ORDER BY escrow_balance_active DESC
+				)`, qf.chainID)
+}
+
+// delegatorStatsJoin joins the delegators of each entity, ranked by
+// their shares, to the entities of a query.
+const delegatorStatsJoin = `LEFT JOIN LATERAL (
+				SELECT
+						COUNT(*) AS delegators,
+						SUM(shares) AS total_shares,
+						SUM(shares) FILTER (WHERE delegator_rank <= 1) AS top_1_shares,
+						SUM(shares) FILTER (WHERE delegator_rank <= 10) AS top_10_shares,
+						SUM(shares) FILTER (WHERE delegator_rank <= 100) AS top_100_shares
+					FROM (
+						SELECT shares, row_number() OVER (ORDER BY shares DESC) AS delegator_rank
+							FROM %[1]s.delegations
+							WHERE delegatee = %[1]s.entities.address AND shares > 0
+					) AS ranked
+			) AS delegator_stats ON TRUE`
+
+// delegatorStatsColumns selects the number of delegators of an entity,
+// and the share of its delegated stake that the largest of them account
+// for.
+const delegatorStatsColumns = `delegator_stats.delegators AS delegators,
+				COALESCE(delegator_stats.top_1_shares / delegator_stats.total_shares, 0)::double precision AS top_1_share,
+				COALESCE(delegator_stats.top_10_shares / delegator_stats.total_shares, 0)::double precision AS top_10_share,
+				COALESCE(delegator_stats.top_100_shares / delegator_stats.total_shares, 0)::double precision AS top_100_share`
+
+// validatorsDataColumns are the columns of validators that are scanned.
+const validatorsDataColumns = "entity_id, entity_address, node_address, escrow, commissions_schedule, active, status, meta, closed_proposals, tallied_votes, delegators, top_1_share, top_10_share, top_100_share"
+
+func (qf QueryFactory) ValidatorsDataQuery() string {
+	return fmt.Sprintf(`
+		SELECT %s
+			FROM (%s) AS validators
+		ORDER BY escrow DESC
 		LIMIT $1::bigint
-		OFFSET $2::bigint`, qf.chainID)
+		OFFSET $2::bigint`, validatorsDataColumns, qf.validatorsDataQuery())
+}
+
+func (qf QueryFactory) ValidatorsListQuery(c *listClauses) string {
+	return listQuery(validatorsDataColumns, qf.validatorsDataQuery(), 0, c)
 }
 
 func (qf QueryFactory) TpsCheckpointQuery() string {
@@ -297,3 +569,191 @@ func (qf QueryFactory) TxVolumesQuery() string {
 		OFFSET $2::bigint
 	`
 }
+
+func (qf QueryFactory) WebhookSubscriptionsQuery() string {
+	return fmt.Sprintf(`
+		SELECT id, kind, url, account, entity_id, min_amount::text, created_at
+			FROM %s.webhook_subscriptions
+			WHERE api_key_id = $1::bigint
+		ORDER BY id
+		LIMIT $2::bigint
+		OFFSET $3::bigint`, qf.chainID)
+}
+
+func (qf QueryFactory) WebhookSubscriptionQuery() string {
+	return fmt.Sprintf(`
+		SELECT id, kind, url, account, entity_id, min_amount::text, created_at
+			FROM %s.webhook_subscriptions
+			WHERE id = $1::bigint AND api_key_id = $2::bigint`, qf.chainID)
+}
+
+func (qf QueryFactory) WebhookSubscriptionInsertQuery() string {
+	return fmt.Sprintf(`
+		INSERT INTO %s.webhook_subscriptions (kind, url, secret, account, entity_id, min_amount, api_key_id)
+			VALUES ($1, $2, $3, $4, $5, $6::numeric, $7)
+		RETURNING id, created_at`, qf.chainID)
+}
+
+func (qf QueryFactory) WebhookSubscriptionDeleteQuery() string {
+	return fmt.Sprintf(`
+		DELETE FROM %s.webhook_subscriptions
+			WHERE id = $1::bigint AND api_key_id = $2::bigint
+		RETURNING id`, qf.chainID)
+}
+
+func (qf QueryFactory) WebhookDeadLettersQuery() string {
+	return fmt.Sprintf(`
+		SELECT id, height, payload, attempts, last_error, created_at
+			FROM %s.webhook_dead_letters
+			WHERE subscription_id = $1::bigint
+		ORDER BY id DESC
+		LIMIT $2::bigint
+		OFFSET $3::bigint`, qf.chainID)
+}
+
+func (qf QueryFactory) LatestBlockHeightQuery() string {
+	return fmt.Sprintf(`
+		SELECT COALESCE(MAX(height), 0)
+			FROM %s.blocks`, qf.chainID)
+}
+
+func (qf QueryFactory) BlockTransactionsQuery() string {
+	return fmt.Sprintf(`
+		SELECT block, txn_hash, sender, nonce, fee_amount, method, body, code
+			FROM %s.transactions
+			WHERE block = $1::bigint AND
+						($2::text IS NULL OR method = $2::text) AND
+						($3::text IS NULL OR sender = $3::text)
+		ORDER BY txn_index`, qf.chainID)
+}
+
+func (qf QueryFactory) BlockEventsQuery() string {
+	return fmt.Sprintf(`
+		SELECT txn_block, txn_hash, txn_index, backend, type, body
+			FROM %s.events
+			WHERE txn_block = $1::bigint AND
+						($2::text IS NULL OR type = $2::text)
+		ORDER BY txn_index`, qf.chainID)
+}
+
+func (qf QueryFactory) LatestEmeraldRoundQuery() string {
+	return fmt.Sprintf(`
+		SELECT COALESCE(MAX(height), 0)::bigint
+			FROM %s.emerald_rounds`, qf.chainID)
+}
+
+func (qf QueryFactory) EmeraldRoundQuery() string {
+	return fmt.Sprintf(`
+		SELECT height::bigint, version, timestamp::bigint, block_hash, prev_block_hash, io_root, state_root, messages_hash, in_messages_hash
+			FROM %s.emerald_rounds
+			WHERE height = $1::bigint`, qf.chainID)
+}
+
+func (qf QueryFactory) AccountActivityExportQuery() string {
+	return fmt.Sprintf(`
+		SELECT height, time, txn_hash, kind, type, fee, amount, success, body
+			FROM (
+				SELECT t.block AS height, b.time, t.txn_hash, t.txn_index, 'transaction' AS kind, t.method AS type,
+						t.fee_amount::text AS fee, NULL::text AS amount, COALESCE(t.code = 0, true) AS success, NULL::json AS body
+					FROM %[1]s.transactions AS t
+					JOIN %[1]s.blocks AS b ON b.height = t.block
+					WHERE t.sender = $1::text
+				UNION ALL
+				SELECT e.txn_block, b.time, e.txn_hash, e.txn_index, 'event', e.type,
+						NULL, COALESCE(e.body->>'amount', e.body->>'amount_change'), NULL, e.body
+					FROM %[1]s.events AS e
+					JOIN %[1]s.blocks AS b ON b.height = e.txn_block
+					WHERE e.backend = 'staking' AND
+								ARRAY[e.body->>'from', e.body->>'to', e.body->>'owner', e.body->>'escrow', e.body->>'beneficiary'] @> ARRAY[$1::text]
+			) AS activity
+			WHERE ($2::bigint IS NULL OR height >= $2::bigint) AND
+						($3::bigint IS NULL OR height <= $3::bigint) AND
+						($4::timestamptz IS NULL OR time >= $4::timestamptz) AND
+						($5::timestamptz IS NULL OR time <= $5::timestamptz)
+		ORDER BY height, txn_index, kind DESC`, qf.chainID)
+}
+
+func (qf QueryFactory) StakingEventsExportQuery() string {
+	return fmt.Sprintf(`
+		SELECT e.txn_block, b.time, e.txn_hash, e.type,
+				COALESCE(e.body->>'from', e.body->>'owner'),
+				COALESCE(e.body->>'to', e.body->>'escrow', e.body->>'beneficiary'),
+				COALESCE(e.body->>'amount', e.body->>'amount_change'),
+				e.body
+			FROM %[1]s.events AS e
+			JOIN %[1]s.blocks AS b ON b.height = e.txn_block
+			WHERE e.backend = 'staking' AND
+						($1::text IS NULL OR e.type = $1::text) AND
+						($2::bigint IS NULL OR e.txn_block >= $2::bigint) AND
+						($3::bigint IS NULL OR e.txn_block <= $3::bigint) AND
+						($4::timestamptz IS NULL OR b.time >= $4::timestamptz) AND
+						($5::timestamptz IS NULL OR b.time <= $5::timestamptz)
+		ORDER BY e.txn_block, e.txn_index`, qf.chainID)
+}
+
+func (qf QueryFactory) SearchBlockQuery() string {
+	return fmt.Sprintf(`
+		SELECT height
+			FROM %s.blocks
+			WHERE height = $1::bigint`, qf.chainID)
+}
+
+func (qf QueryFactory) SearchTransactionQuery() string {
+	return fmt.Sprintf(`
+		SELECT block
+			FROM %s.transactions
+			WHERE txn_hash = $1::text
+		LIMIT 1`, qf.chainID)
+}
+
+func (qf QueryFactory) SearchEntityQuery() string {
+	return fmt.Sprintf(`
+		SELECT id, address, meta->>'name'
+			FROM %s.entities
+			WHERE id = $1::text OR address = $1::text`, qf.chainID)
+}
+
+func (qf QueryFactory) SearchNodeQuery() string {
+	return fmt.Sprintf(`
+		SELECT id, entity_id
+			FROM %s.nodes
+			WHERE id = $1::text`, qf.chainID)
+}
+
+func (qf QueryFactory) SearchAccountQuery() string {
+	return fmt.Sprintf(`
+		SELECT address
+			FROM %s.accounts
+			WHERE address = $1::text`, qf.chainID)
+}
+
+func (qf QueryFactory) SearchEmeraldRoundQuery() string {
+	return fmt.Sprintf(`
+		SELECT height
+			FROM %s.emerald_rounds
+			WHERE height = $1::numeric`, qf.chainID)
+}
+
+func (qf QueryFactory) SearchValidatorNamesQuery() string {
+	return fmt.Sprintf(`
+		SELECT id, address, meta->>'name' AS name
+			FROM %s.entities
+			WHERE meta->>'name' ILIKE $1::text
+		ORDER BY lower(meta->>'name'), id
+		LIMIT $2::bigint`, qf.chainID)
+}
+
+// listQuery wraps the query of a list endpoint, which takes n arguments,
+// so that its results are filtered and ordered by the clauses of the
+// request, and paginated. The clauses refer to columns of the query,
+// and the provided columns are selected. Arguments of the clauses follow
+// those of the query, and are followed by the limit and offset.
+func listQuery(columns string, query string, n int, c *listClauses) string {
+	return fmt.Sprintf(`
+		SELECT %s
+			FROM (%s) AS list
+			WHERE %s
+		ORDER BY %s
+		LIMIT $%d::bigint
+		OFFSET $%d::bigint`, columns, query, c.where, c.orderBy, n+len(c.args)+1, n+len(c.args)+2)
+}
@@ -0,0 +1,134 @@
+package v1
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/oasisprotocol/oasis-core/go/common/crypto/signature"
+	staking "github.com/oasisprotocol/oasis-core/go/staking/api"
+
+	"github.com/oasisprotocol/oasis-indexer/api/common"
+)
+
+const (
+	// Kinds of search results.
+	SearchKindBlock        = "block"
+	SearchKindTransaction  = "transaction"
+	SearchKindEntity       = "entity"
+	SearchKindNode         = "node"
+	SearchKindAccount      = "account"
+	SearchKindEmeraldRound = "emerald_round"
+	SearchKindValidator    = "validator"
+
+	// searchMaxQueryLength is the maximum length of a search query.
+	searchMaxQueryLength = 128
+
+	// searchMaxNameMatches is the maximum number of validators
+	// matched by name.
+	searchMaxNameMatches = 10
+)
+
+// searchTerms are the terms a search query may be interpreted as.
+// Only terms in the format of the query are set.
+type searchTerms struct {
+	// height is a block height or an Emerald round.
+	height *int64
+	// hash is a transaction hash.
+	hash *string
+	// publicKey is an entity or node ID.
+	publicKey *string
+	// address is a staking address of an account or entity.
+	address *string
+	// namePrefix is a prefix of validator names, as a LIKE pattern.
+	namePrefix *string
+}
+
+// parseSearchTerms detects the format of a search query. Queries that are
+// not heights, hashes, public keys or addresses are matched against
+// validator names.
+func parseSearchTerms(q string) (*searchTerms, error) {
+	q = strings.TrimSpace(q)
+	if q == "" {
+		return nil, fmt.Errorf("must not be empty")
+	}
+	if len(q) > searchMaxQueryLength {
+		return nil, fmt.Errorf("must be at most %d bytes", searchMaxQueryLength)
+	}
+
+	var terms searchTerms
+	if height, err := strconv.ParseInt(q, 10, 64); err == nil && height >= 0 {
+		terms.height = &height
+		return &terms, nil
+	}
+	if b, err := hex.DecodeString(q); err == nil && len(b) == 32 {
+		hash := hex.EncodeToString(b)
+		terms.hash = &hash
+		return &terms, nil
+	}
+	var pk signature.PublicKey
+	if err := pk.UnmarshalText([]byte(q)); err == nil {
+		publicKey := pk.String()
+		terms.publicKey = &publicKey
+		return &terms, nil
+	}
+	var address staking.Address
+	if err := address.UnmarshalText([]byte(q)); err == nil {
+		a := address.String()
+		terms.address = &a
+		return &terms, nil
+	}
+
+	prefix := likeEscaper.Replace(q) + "%"
+	terms.namePrefix = &prefix
+	return &terms, nil
+}
+
+// likeEscaper escapes the wildcards of LIKE patterns.
+var likeEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+
+// Search finds blocks, transactions, entities, nodes, accounts,
+// Emerald rounds and validators matching a search query.
+func (h *Handler) Search(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	terms, err := parseSearchTerms(r.URL.Query().Get("q"))
+	if err != nil {
+		h.logger.Info("malformed search query",
+			"request_id", ctx.Value(RequestIDContextKey),
+			"err", err.Error(),
+		)
+		err = common.NewInvalidParameterError("q", err.Error())
+		h.logAndReply(ctx, "failed to search", w, err)
+		h.metrics.RequestCounter(r.URL.Path, "failure", common.ErrorCause(err)).Inc()
+		return
+	}
+
+	results, err := h.client.Search(ctx, terms)
+	if err != nil {
+		h.logAndReply(ctx, "failed to search", w, err)
+		h.metrics.RequestCounter(r.URL.Path, "failure", common.ErrorCause(err)).Inc()
+		return
+	}
+
+	resp, err := json.Marshal(results)
+	if err != nil {
+		h.logAndReply(ctx, "failed to marshal search results", w, err)
+		h.metrics.RequestCounter(r.URL.Path, "failure", "serde_error").Inc()
+		return
+	}
+
+	w.Header().Set("content-type", "application/json")
+	if _, err := w.Write(resp); err != nil {
+		h.logger.Error("failed to write response",
+			"request_id", ctx.Value(RequestIDContextKey),
+			"error", err,
+		)
+		h.metrics.RequestCounter(r.URL.Path, "failure", "http_error").Inc()
+	} else {
+		h.metrics.RequestCounter(r.URL.Path, "success").Inc()
+	}
+}
@@ -0,0 +1,39 @@
+package v1
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseSearchTerms(t *testing.T) {
+	height := int64(8048956)
+	hash := "0d0531d6b8a468c07440182b1cdda517f5a076d69fb2199126a83082ecfc0f41"
+	publicKey := "gb8SHLeDc69Elk7OTfqhtVgE2sqxrBCDQI84xKR+Bjg="
+	address := "oasis1qpg2xuz46g53737343r20yxeddhlvc2ldqsjh70p"
+	namePrefix := `Bit%`
+	escapedPrefix := `100\% \_uptime\\%`
+
+	for _, tc := range []struct {
+		q     string
+		terms searchTerms
+	}{
+		{"8048956", searchTerms{height: &height}},
+		{" 8048956 ", searchTerms{height: &height}},
+		{hash, searchTerms{hash: &hash}},
+		{"0D0531D6B8A468C07440182B1CDDA517F5A076D69FB2199126A83082ECFC0F41", searchTerms{hash: &hash}},
+		{publicKey, searchTerms{publicKey: &publicKey}},
+		{address, searchTerms{address: &address}},
+		{"Bit", searchTerms{namePrefix: &namePrefix}},
+		{`100% _uptime\`, searchTerms{namePrefix: &escapedPrefix}},
+	} {
+		terms, err := parseSearchTerms(tc.q)
+		require.Nil(t, err, tc.q)
+		require.Equal(t, tc.terms, *terms, tc.q)
+	}
+
+	for _, q := range []string{"", "   ", string(make([]byte, searchMaxQueryLength+1))} {
+		_, err := parseSearchTerms(q)
+		require.NotNil(t, err)
+	}
+}
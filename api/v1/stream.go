@@ -0,0 +1,375 @@
+package v1
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"golang.org/x/net/websocket"
+
+	"github.com/oasisprotocol/oasis-indexer/api/common"
+	"github.com/oasisprotocol/oasis-indexer/storage"
+	"github.com/oasisprotocol/oasis-indexer/streaming"
+)
+
+const (
+	// Streaming topics.
+	TopicConsensusBlocks       = "consensus_blocks"
+	TopicConsensusTransactions = "consensus_transactions"
+	TopicConsensusEvents       = "consensus_events"
+	TopicEmeraldRounds         = "emerald_rounds"
+
+	// streamMaxReplay is the maximum number of heights that may be
+	// replayed when resuming a stream.
+	streamMaxReplay = 10000
+
+	// streamPollInterval is how often streams check target storage for
+	// progress that was not announced on the bus, and send keep-alives.
+	streamPollInterval = 15 * time.Second
+
+	// streamWriteTimeout bounds how long sending a single message may take.
+	streamWriteTimeout = 10 * time.Second
+)
+
+// streamRequest is a parsed request to the streaming API.
+type streamRequest struct {
+	topic string
+	layer streaming.Layer
+
+	// Optional filters, depending on the topic.
+	method    *string
+	sender    *string
+	eventType *string
+
+	// from is the height from which to resume the stream, if any.
+	from *int64
+}
+
+// parseStreamRequest parses a request to the streaming API.
+func parseStreamRequest(r *http.Request) (*streamRequest, error) {
+	params := r.URL.Query()
+
+	req := streamRequest{
+		topic: params.Get("topic"),
+	}
+	switch req.topic {
+	case TopicConsensusBlocks, TopicConsensusTransactions, TopicConsensusEvents:
+		req.layer = streaming.LayerConsensus
+	case TopicEmeraldRounds:
+		req.layer = streaming.LayerEmerald
+	default:
+		return nil, common.NewInvalidParameterError("topic", "must be a known topic")
+	}
+
+	if v := params.Get("method"); v != "" {
+		req.method = &v
+	}
+	if v := params.Get("sender"); v != "" {
+		req.sender = &v
+	}
+	if req.topic != TopicConsensusTransactions {
+		switch {
+		case req.method != nil:
+			return nil, common.NewInvalidParameterError("method", "is only supported by the "+TopicConsensusTransactions+" topic")
+		case req.sender != nil:
+			return nil, common.NewInvalidParameterError("sender", "is only supported by the "+TopicConsensusTransactions+" topic")
+		}
+	}
+	if v := params.Get("type"); v != "" {
+		if req.topic != TopicConsensusEvents {
+			return nil, common.NewInvalidParameterError("type", "is only supported by the "+TopicConsensusEvents+" topic")
+		}
+		req.eventType = &v
+	}
+
+	// Server-Sent Events clients resume from the last received event.
+	if v := r.Header.Get("Last-Event-ID"); v != "" {
+		lastHeight, err := strconv.ParseInt(v, 10, 64)
+		if err != nil {
+			return nil, common.NewInvalidParameterError("Last-Event-ID", "must be a height")
+		}
+		from := lastHeight + 1
+		req.from = &from
+	}
+	if v := params.Get("from"); v != "" {
+		from, err := strconv.ParseInt(v, 10, 64)
+		if err != nil || from < 0 {
+			return nil, common.NewInvalidParameterError("from", "must be a height")
+		}
+		req.from = &from
+	}
+
+	return &req, nil
+}
+
+// Stream streams newly indexed data as Server-Sent Events or, if the request
+// is a WebSocket upgrade, as WebSocket messages.
+func (h *Handler) Stream(w http.ResponseWriter, r *http.Request) {
+	// Streams never end on their own, so they end when the server
+	// shuts down.
+	ctx, end := common.UntilShutdown(r.Context())
+	defer end()
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	req, err := parseStreamRequest(r)
+	if err != nil {
+		h.logAndReply(ctx, "failed to parse stream request", w, err)
+		h.metrics.RequestCounter(r.URL.Path, "failure", common.ErrorCause(err)).Inc()
+		return
+	}
+
+	// Subscribe before determining the latest height, so that
+	// no progress is missed in between.
+	msgs, err := h.bus.Subscribe(ctx)
+	if err != nil {
+		h.logAndReply(ctx, "failed to subscribe to bus", w, err)
+		h.metrics.RequestCounter(r.URL.Path, "failure", "bus_error").Inc()
+		return
+	}
+	latest, err := h.client.LatestHeight(ctx, req.layer)
+	if err != nil {
+		h.logAndReply(ctx, "failed to get latest height", w, err)
+		h.metrics.RequestCounter(r.URL.Path, "failure", common.ErrorCause(err)).Inc()
+		return
+	}
+
+	next := latest + 1
+	if req.from != nil {
+		if *req.from < latest-streamMaxReplay {
+			err = common.NewInvalidParameterError("from", fmt.Sprintf("must be at most %d heights before the latest height", streamMaxReplay))
+			h.logAndReply(ctx, "stream resumes too far back", w, err)
+			h.metrics.RequestCounter(r.URL.Path, "failure", common.ErrorCause(err)).Inc()
+			return
+		}
+		next = *req.from
+	}
+
+	s := &stream{
+		h:    h,
+		req:  req,
+		msgs: msgs,
+		next: next,
+	}
+	if strings.EqualFold(r.Header.Get("Upgrade"), "websocket") {
+		websocket.Server{
+			Handler: func(ws *websocket.Conn) {
+				s.serveWebSocket(ctx, cancel, ws)
+			},
+		}.ServeHTTP(w, r)
+	} else {
+		s.serveSSE(ctx, w)
+	}
+	h.metrics.RequestCounter(r.URL.Path, "success").Inc()
+}
+
+// stream is an open stream of indexed data.
+type stream struct {
+	h    *Handler
+	req  *streamRequest
+	msgs <-chan *streaming.Message
+
+	// next is the next height to be sent.
+	next int64
+}
+
+// serveSSE serves the stream as Server-Sent Events, where the event ID
+// is the height of the data.
+func (s *stream) serveSSE(ctx context.Context, w http.ResponseWriter) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		s.h.logAndReply(ctx, "streaming unsupported", w, fmt.Errorf("response writer cannot flush"))
+		return
+	}
+
+	w.Header().Set("content-type", "text/event-stream")
+	w.Header().Set("cache-control", "no-cache")
+	w.Header().Set("x-accel-buffering", "no")
+	if err := common.SetWriteDeadline(ctx, time.Now().Add(streamWriteTimeout)); err != nil {
+		s.h.logAndReply(ctx, "failed to set write deadline", w, err)
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+	flusher.Flush()
+
+	// Streams outlive the write timeout of the server, so each write
+	// is given its own deadline instead.
+	send := func(m *StreamMessage) error {
+		data, err := json.Marshal(m)
+		if err != nil {
+			return err
+		}
+		if err := common.SetWriteDeadline(ctx, time.Now().Add(streamWriteTimeout)); err != nil {
+			return err
+		}
+		if _, err := fmt.Fprintf(w, "id: %d\nevent: %s\ndata: %s\n\n", m.Height, m.Topic, data); err != nil {
+			return err
+		}
+		flusher.Flush()
+		return nil
+	}
+	keepAlive := func() error {
+		if err := common.SetWriteDeadline(ctx, time.Now().Add(streamWriteTimeout)); err != nil {
+			return err
+		}
+		if _, err := io.WriteString(w, ": keep-alive\n\n"); err != nil {
+			return err
+		}
+		flusher.Flush()
+		return nil
+	}
+
+	if err := s.run(ctx, send, keepAlive); err != nil {
+		s.h.logger.Info("stream closed",
+			"request_id", ctx.Value(RequestIDContextKey),
+			"error", err,
+		)
+	}
+}
+
+// serveWebSocket serves the stream as WebSocket messages, each containing
+// a JSON encoded StreamMessage.
+func (s *stream) serveWebSocket(ctx context.Context, cancel context.CancelFunc, ws *websocket.Conn) {
+	// The connection was hijacked from the HTTP server, and is not subject
+	// to its timeouts any longer.
+	if err := ws.SetReadDeadline(time.Time{}); err != nil {
+		return
+	}
+
+	// Clients do not send messages, so reading only detects disconnects.
+	go func() {
+		defer cancel()
+		_, _ = io.Copy(io.Discard, ws)
+	}()
+
+	send := func(m *StreamMessage) error {
+		if err := ws.SetWriteDeadline(time.Now().Add(streamWriteTimeout)); err != nil {
+			return err
+		}
+		return websocket.JSON.Send(ws, m)
+	}
+	keepAlive := func() error {
+		return nil
+	}
+
+	if err := s.run(ctx, send, keepAlive); err != nil {
+		s.h.logger.Info("stream closed",
+			"request_id", ctx.Value(RequestIDContextKey),
+			"error", err,
+		)
+	}
+}
+
+// run sends indexed data until the context is cancelled or sending fails.
+func (s *stream) run(ctx context.Context, send func(*StreamMessage) error, keepAlive func() error) error {
+	// Heights are announced once they are committed on the primary, and
+	// are skipped once sent, so data of a height must not be read from a
+	// replica that has not caught up with it yet.
+	ctx = storage.WithPrimary(ctx)
+
+	latest, err := s.h.client.LatestHeight(ctx, s.req.layer)
+	if err != nil {
+		return err
+	}
+	if err = s.catchUp(ctx, latest, send); err != nil {
+		return err
+	}
+
+	ticker := time.NewTicker(streamPollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case msg, ok := <-s.msgs:
+			if !ok {
+				return nil
+			}
+			if msg.Layer != s.req.layer {
+				continue
+			}
+			if err := s.catchUp(ctx, msg.Height, send); err != nil {
+				return err
+			}
+		case <-ticker.C:
+			// Messages on the bus are best effort, so also
+			// poll for progress that was not announced.
+			latest, err := s.h.client.LatestHeight(ctx, s.req.layer)
+			if err != nil {
+				return err
+			}
+			if err := s.catchUp(ctx, latest, send); err != nil {
+				return err
+			}
+			if err := keepAlive(); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// catchUp sends data for all heights up to and including the provided height.
+func (s *stream) catchUp(ctx context.Context, height int64, send func(*StreamMessage) error) error {
+	for ; s.next <= height; s.next++ {
+		if err := s.sendHeight(ctx, s.next, send); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// sendHeight sends data at the provided height that matches the stream filters.
+func (s *stream) sendHeight(ctx context.Context, height int64, send func(*StreamMessage) error) error {
+	var data []interface{}
+	switch s.req.topic {
+	case TopicConsensusBlocks:
+		b, err := s.h.client.BlockAt(ctx, height)
+		if err != nil {
+			return err
+		}
+		if b != nil {
+			data = append(data, b)
+		}
+	case TopicConsensusTransactions:
+		ts, err := s.h.client.BlockTransactions(ctx, height, s.req.method, s.req.sender)
+		if err != nil {
+			return err
+		}
+		for i := range ts {
+			data = append(data, &ts[i])
+		}
+	case TopicConsensusEvents:
+		es, err := s.h.client.BlockEvents(ctx, height, s.req.eventType)
+		if err != nil {
+			return err
+		}
+		for i := range es {
+			data = append(data, &es[i])
+		}
+	case TopicEmeraldRounds:
+		er, err := s.h.client.EmeraldRoundAt(ctx, height)
+		if err != nil {
+			return err
+		}
+		if er != nil {
+			data = append(data, er)
+		}
+	}
+
+	for _, d := range data {
+		if err := send(&StreamMessage{
+			Topic:  s.req.topic,
+			Height: height,
+			Data:   d,
+		}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -1,35 +1,85 @@
 package v1
 
 import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
 	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/iancoleman/strcase"
+	"github.com/jackc/pgx/v4"
 
+	"github.com/oasisprotocol/oasis-indexer/cache"
 	"github.com/oasisprotocol/oasis-indexer/log"
 	"github.com/oasisprotocol/oasis-indexer/metrics"
 	"github.com/oasisprotocol/oasis-indexer/storage"
+	"github.com/oasisprotocol/oasis-indexer/streaming"
 )
 
+//go:generate go run ../spec/gen -package v1 -o api.gen.go
+
 const (
 	LatestChainID = "oasis-3"
 
 	moduleName = "api_v1"
+
+	// requestTimeout bounds how long requests other than streams
+	// and exports may take.
+	requestTimeout = 10 * time.Second
 )
 
 // Handler is the Oasis Indexer V1 API handler.
 type Handler struct {
 	client  *storageClient
+	bus     streaming.Bus
+	cache   *responseCache
 	logger  *log.Logger
 	metrics metrics.RequestMetrics
 }
 
-// NewHandler creates a new V1 API handler.
-func NewHandler(db storage.TargetStorage, l *log.Logger) *Handler {
+// Handler serves the operations of the specification.
+var _ ServerInterface = (*Handler)(nil)
+
+// NewHandler creates a new V1 API handler. The streaming API is
+// only served if a bus is provided, and responses are only cached
+// by the server if a cache is provided.
+func NewHandler(db storage.TargetStorage, b streaming.Bus, c cache.Cache, l *log.Logger) *Handler {
+	client := newStorageClient(db, l)
+	logger := l.WithModule(moduleName)
+
+	var rc *responseCache
+	if c != nil {
+		rc = newResponseCache(c, client, logger)
+	}
+
 	return &Handler{
-		client:  newStorageClient(db, l),
-		logger:  l.WithModule(moduleName),
+		client:  client,
+		bus:     b,
+		cache:   rc,
+		logger:  logger,
 		metrics: metrics.NewDefaultRequestMetrics(moduleName),
 	}
 }
 
+// LatestBlockTime returns a function that returns the time of the latest
+// consensus block indexed in target storage, by which indexing lag is
+// measured.
+func LatestBlockTime(db storage.TargetStorage) func(ctx context.Context) (time.Time, error) {
+	qf := NewQueryFactory(strcase.ToSnake(LatestChainID))
+	return func(ctx context.Context) (time.Time, error) {
+		var t time.Time
+		if err := db.QueryRow(ctx, qf.LatestBlockTimeQuery()).Scan(&t); err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				return time.Time{}, fmt.Errorf("no blocks indexed")
+			}
+			return time.Time{}, err
+		}
+		return t, nil
+	}
+}
+
 // RegisterRoutes implements the APIHandler interface.
 func (h *Handler) RegisterMiddlewares(r chi.Router) {
 	r.Use(h.metricsMiddleware)
@@ -39,59 +89,105 @@ func (h *Handler) RegisterMiddlewares(r chi.Router) {
 // RegisterRoutes implements the APIHandler interface.
 func (h *Handler) RegisterRoutes(r chi.Router) {
 	r.Route("/v1", func(r chi.Router) {
-		// Status endpoints.
-		r.Get("/", h.GetStatus)
-
-		r.Route("/consensus", func(r chi.Router) {
-			// Block Endpoints.
-			r.Route("/blocks", func(r chi.Router) {
-				r.Get("/", h.ListBlocks)
-				r.Get("/{height}", h.GetBlock)
-			})
-			r.Route("/transactions", func(r chi.Router) {
-				r.Get("/", h.ListTransactions)
-				r.Get("/{txn_hash}", h.GetTransaction)
-			})
-
-			// Registry Endpoints.
-			r.Route("/entities", func(r chi.Router) {
-				r.Get("/", h.ListEntities)
-				r.Get("/{entity_id}", h.GetEntity)
-				r.Get("/{entity_id}/nodes", h.ListEntityNodes)
-				r.Get("/{entity_id}/nodes/{node_id}", h.GetEntityNode)
-			})
-
-			// Staking Endpoints.
-			r.Route("/accounts", func(r chi.Router) {
-				r.Get("/", h.ListAccounts)
-				r.Get("/{address}", h.GetAccount)
-				r.Get("/{address}/delegations", h.GetDelegations)
-				r.Get("/{address}/debonding_delegations", h.GetDebondingDelegations)
-			})
+		r.Use(h.validationMiddleware)
 
-			// Scheduler Endpoints.
-			r.Route("/epochs", func(r chi.Router) {
-				r.Get("/", h.ListEpochs)
-				r.Get("/{epoch}", h.GetEpoch)
-			})
-
-			// Governance Endpoints.
-			r.Route("/proposals", func(r chi.Router) {
-				r.Get("/", h.ListProposals)
-				r.Get("/{proposal_id}", h.GetProposal)
-				r.Get("/{proposal_id}/votes", h.GetProposalVotes)
-			})
+		// Streams are long-lived, so they are not subject to the request timeout.
+		if h.bus != nil {
+			r.Get("/stream", h.Stream)
+		}
 
-			// Validator Endpoints.
-			r.Route("/validators", func(r chi.Router) {
-				r.Get("/", h.ListValidators)
-				r.Get("/{entity_id}", h.GetValidator)
-			})
+		// Exports are streamed from storage, and may take long as well.
+		r.Route("/consensus/export", func(r chi.Router) {
+			r.Get("/account_activity", h.ExportAccountActivity)
+			r.Get("/staking_events", h.ExportStakingEvents)
+		})
 
-			// Aggregate Statistics.
-			r.Route("/stats", func(r chi.Router) {
-				r.Get("/tps", h.ListTransactionsPerSecond)
-				r.Get("/daily_volume", h.ListDailyVolume)
+		r.Group(func(r chi.Router) {
+			r.Use(middleware.Timeout(requestTimeout))
+
+			// Status endpoints.
+			r.With(h.cacheMiddleware).Get("/", h.GetStatus)
+
+			// Search Endpoints.
+			r.With(h.cacheMiddleware).Get("/search", h.Search)
+
+			r.Route("/consensus", func(r chi.Router) {
+				// Webhook Endpoints. Subscriptions are owned by the API key
+				// that created them, so responses are not cached.
+				r.Route("/webhooks", func(r chi.Router) {
+					r.Use(h.keyMiddleware)
+
+					r.Get("/", h.ListWebhookSubscriptions)
+					r.Post("/", h.CreateWebhookSubscription)
+					r.Get("/{subscription_id}", h.GetWebhookSubscription)
+					r.Delete("/{subscription_id}", h.DeleteWebhookSubscription)
+					r.Get("/{subscription_id}/dead_letters", h.ListWebhookDeadLetters)
+				})
+
+				r.Group(func(r chi.Router) {
+					r.Use(h.cacheMiddleware)
+
+					// Block Endpoints.
+					r.Route("/blocks", func(r chi.Router) {
+						r.Get("/", h.ListBlocks)
+						r.Get("/{height}", h.GetBlock)
+					})
+					r.Route("/transactions", func(r chi.Router) {
+						r.Get("/", h.ListTransactions)
+						r.Get("/{tx_hash}", h.GetTransaction)
+					})
+
+					// Registry Endpoints.
+					r.Route("/entities", func(r chi.Router) {
+						r.Get("/", h.ListEntities)
+						r.Get("/{entity_id}", h.GetEntity)
+						r.Get("/{entity_id}/nodes", h.ListEntityNodes)
+						r.Get("/{entity_id}/nodes/{node_id}", h.GetEntityNode)
+						r.Get("/{entity_id}/nodes/{node_id}/history", h.GetEntityNodeHistory)
+					})
+					r.Route("/runtimes", func(r chi.Router) {
+						r.Get("/", h.ListRuntimes)
+						r.Get("/{runtime_id}", h.GetRuntime)
+						r.Get("/{runtime_id}/history", h.GetRuntimeHistory)
+					})
+
+					// Staking Endpoints.
+					r.Route("/accounts", func(r chi.Router) {
+						r.Get("/", h.ListAccounts)
+						r.Get("/{address}", h.GetAccount)
+						r.Get("/{address}/delegations", h.GetDelegations)
+						r.Get("/{address}/debonding_delegations", h.GetDebondingDelegations)
+					})
+
+					// Scheduler Endpoints.
+					r.Route("/epochs", func(r chi.Router) {
+						r.Get("/", h.ListEpochs)
+						r.Get("/{epoch}", h.GetEpoch)
+						r.Get("/{epoch}/committees", h.GetEpochCommittees)
+						r.Get("/{epoch}/validators", h.GetEpochValidators)
+					})
+
+					// Governance Endpoints.
+					r.Route("/proposals", func(r chi.Router) {
+						r.Get("/", h.ListProposals)
+						r.Get("/{proposal_id}", h.GetProposal)
+						r.Get("/{proposal_id}/votes", h.GetProposalVotes)
+					})
+
+					// Validator Endpoints.
+					r.Route("/validators", func(r chi.Router) {
+						r.Get("/", h.ListValidators)
+						r.Get("/{entity_id}", h.GetValidator)
+						r.Get("/{entity_id}/delegators", h.GetValidatorDelegators)
+						r.Get("/{entity_id}/debonding_delegators", h.GetValidatorDebondingDelegators)
+					})
+
+					// Aggregate Statistics.
+					r.Route("/stats", func(r chi.Router) {
+						r.Get("/tps", h.ListTransactionsPerSecond)
+						r.Get("/daily_volume", h.ListDailyVolume)
+					})
+				})
 			})
 		})
 	})
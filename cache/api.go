@@ -0,0 +1,19 @@
+// Package cache implements caches of API responses, which may be kept
+// in memory or shared between API servers.
+package cache
+
+import (
+	"context"
+	"time"
+)
+
+// Cache is a cache of values by key.
+//
+// Caches are best effort: values may be evicted before they expire.
+type Cache interface {
+	// Get returns the value cached for the key, if any.
+	Get(ctx context.Context, key string) ([]byte, bool, error)
+
+	// Set caches a value for the key, until it expires after ttl.
+	Set(ctx context.Context, key string, value []byte, ttl time.Duration) error
+}
@@ -0,0 +1,35 @@
+package cache
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestMemoryCache(t *testing.T) {
+	ctx := context.Background()
+	c := NewMemoryCache(2)
+
+	require.Nil(t, c.Set(ctx, "a", []byte("1"), time.Minute))
+	require.Nil(t, c.Set(ctx, "b", []byte("2"), time.Minute))
+
+	v, ok, err := c.Get(ctx, "a")
+	require.Nil(t, err)
+	require.True(t, ok)
+	require.Equal(t, []byte("1"), v)
+
+	// The least recently used value is evicted.
+	require.Nil(t, c.Set(ctx, "c", []byte("3"), time.Minute))
+	_, ok, _ = c.Get(ctx, "b")
+	require.False(t, ok)
+	_, ok, _ = c.Get(ctx, "a")
+	require.True(t, ok)
+
+	// Expired values are not returned.
+	require.Nil(t, c.Set(ctx, "a", []byte("1"), -time.Second))
+	_, ok, _ = c.Get(ctx, "a")
+	require.False(t, ok)
+	require.Len(t, c.entries, 1)
+}
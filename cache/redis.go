@@ -0,0 +1,208 @@
+package cache
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// redisTimeout bounds how long a command may take, unless
+	// the provided context has an earlier deadline.
+	redisTimeout = time.Second
+
+	// redisMaxIdleConns is the maximum number of idle connections
+	// kept open to the server.
+	redisMaxIdleConns = 16
+)
+
+// RedisCache is a cache backed by a Redis-compatible server, which may be
+// shared by several API servers. It speaks the RESP protocol directly, since
+// only a handful of commands are needed.
+type RedisCache struct {
+	addr     string
+	password string
+	db       int
+
+	idle chan *redisConn
+}
+
+type redisConn struct {
+	net.Conn
+	r *bufio.Reader
+}
+
+// redisError is an error reply from the server.
+type redisError string
+
+func (e redisError) Error() string {
+	return "redis: " + string(e)
+}
+
+// NewRedisCache creates a new cache backed by the server at the provided
+// endpoint, which is a URL of the form redis://[:password@]host:port[/db].
+func NewRedisCache(endpoint string) (*RedisCache, error) {
+	u, err := url.Parse(endpoint)
+	if err != nil {
+		return nil, err
+	}
+	if u.Scheme != "redis" || u.Host == "" {
+		return nil, fmt.Errorf("malformed redis endpoint '%s'", endpoint)
+	}
+
+	c := &RedisCache{
+		addr: u.Host,
+		idle: make(chan *redisConn, redisMaxIdleConns),
+	}
+	if password, ok := u.User.Password(); ok {
+		c.password = password
+	}
+	if path := strings.TrimPrefix(u.Path, "/"); path != "" {
+		if c.db, err = strconv.Atoi(path); err != nil {
+			return nil, fmt.Errorf("malformed redis database '%s'", path)
+		}
+	}
+	return c, nil
+}
+
+// Get implements the Cache interface.
+func (c *RedisCache) Get(ctx context.Context, key string) ([]byte, bool, error) {
+	reply, err := c.do(ctx, "GET", key)
+	if err != nil {
+		return nil, false, err
+	}
+	switch v := reply.(type) {
+	case nil:
+		return nil, false, nil
+	case []byte:
+		return v, true, nil
+	default:
+		return nil, false, fmt.Errorf("redis: unexpected reply %v to GET", reply)
+	}
+}
+
+// Set implements the Cache interface.
+func (c *RedisCache) Set(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	_, err := c.do(ctx, "SET", key, string(value), "PX", strconv.FormatInt(ttl.Milliseconds(), 10))
+	return err
+}
+
+// do sends a command and returns its reply. Connections are reused,
+// unless they failed.
+func (c *RedisCache) do(ctx context.Context, args ...string) (interface{}, error) {
+	conn, err := c.conn(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	reply, err := conn.do(ctx, args...)
+	var replyErr redisError
+	if err != nil && !errors.As(err, &replyErr) {
+		conn.Close()
+		return nil, err
+	}
+
+	select {
+	case c.idle <- conn:
+	default:
+		conn.Close()
+	}
+	return reply, err
+}
+
+// conn returns an idle connection, or a new connection if there is none.
+func (c *RedisCache) conn(ctx context.Context) (*redisConn, error) {
+	select {
+	case conn := <-c.idle:
+		return conn, nil
+	default:
+	}
+
+	var d net.Dialer
+	dialCtx, cancel := context.WithTimeout(ctx, redisTimeout)
+	defer cancel()
+	netConn, err := d.DialContext(dialCtx, "tcp", c.addr)
+	if err != nil {
+		return nil, err
+	}
+	conn := &redisConn{netConn, bufio.NewReader(netConn)}
+
+	if c.password != "" {
+		if _, err := conn.do(ctx, "AUTH", c.password); err != nil {
+			conn.Close()
+			return nil, err
+		}
+	}
+	if c.db != 0 {
+		if _, err := conn.do(ctx, "SELECT", strconv.Itoa(c.db)); err != nil {
+			conn.Close()
+			return nil, err
+		}
+	}
+	return conn, nil
+}
+
+// do sends a command on the connection and reads its reply.
+func (conn *redisConn) do(ctx context.Context, args ...string) (interface{}, error) {
+	deadline := time.Now().Add(redisTimeout)
+	if d, ok := ctx.Deadline(); ok && d.Before(deadline) {
+		deadline = d
+	}
+	if err := conn.SetDeadline(deadline); err != nil {
+		return nil, err
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "*%d\r\n", len(args))
+	for _, arg := range args {
+		fmt.Fprintf(&b, "$%d\r\n%s\r\n", len(arg), arg)
+	}
+	if _, err := io.WriteString(conn, b.String()); err != nil {
+		return nil, err
+	}
+	return conn.readReply()
+}
+
+// readReply reads a reply to a command. Replies are simple strings,
+// errors, integers or bulk strings, which are nil if missing.
+func (conn *redisConn) readReply() (interface{}, error) {
+	line, err := conn.r.ReadString('\n')
+	if err != nil {
+		return nil, err
+	}
+	if len(line) < 3 || !strings.HasSuffix(line, "\r\n") {
+		return nil, fmt.Errorf("redis: malformed reply '%s'", line)
+	}
+	kind, line := line[0], line[1:len(line)-2]
+
+	switch kind {
+	case '+':
+		return line, nil
+	case '-':
+		return nil, redisError(line)
+	case ':':
+		return strconv.ParseInt(line, 10, 64)
+	case '$':
+		n, err := strconv.Atoi(line)
+		if err != nil {
+			return nil, fmt.Errorf("redis: malformed bulk string length '%s'", line)
+		}
+		if n < 0 {
+			return nil, nil
+		}
+		buf := make([]byte, n+2)
+		if _, err := io.ReadFull(conn.r, buf); err != nil {
+			return nil, err
+		}
+		return buf[:n], nil
+	default:
+		return nil, fmt.Errorf("redis: unsupported reply '%c%s'", kind, line)
+	}
+}
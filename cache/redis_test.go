@@ -0,0 +1,151 @@
+package cache
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// fakeRedis is a Redis-compatible server supporting the commands used by RedisCache.
+type fakeRedis struct {
+	listener net.Listener
+
+	mu       sync.Mutex
+	values   map[string]string
+	commands []string
+}
+
+func newFakeRedis(t *testing.T) *fakeRedis {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	require.Nil(t, err)
+	s := &fakeRedis{listener: l, values: make(map[string]string)}
+	go func() {
+		for {
+			conn, err := l.Accept()
+			if err != nil {
+				return
+			}
+			go s.serve(conn)
+		}
+	}()
+	t.Cleanup(func() { l.Close() })
+	return s
+}
+
+func (s *fakeRedis) serve(conn net.Conn) {
+	defer conn.Close()
+	r := bufio.NewReader(conn)
+	for {
+		args, err := readCommand(r)
+		if err != nil {
+			return
+		}
+
+		s.mu.Lock()
+		s.commands = append(s.commands, args[0])
+		var reply string
+		switch args[0] {
+		case "AUTH":
+			reply = "+OK\r\n"
+			if args[1] != "secret" {
+				reply = "-WRONGPASS invalid password\r\n"
+			}
+		case "GET":
+			if v, ok := s.values[args[1]]; ok {
+				reply = fmt.Sprintf("$%d\r\n%s\r\n", len(v), v)
+			} else {
+				reply = "$-1\r\n"
+			}
+		case "SET":
+			s.values[args[1]] = args[2]
+			reply = "+OK\r\n"
+		default:
+			reply = "-ERR unknown command\r\n"
+		}
+		s.mu.Unlock()
+
+		if _, err := io.WriteString(conn, reply); err != nil {
+			return
+		}
+	}
+}
+
+func readCommand(r *bufio.Reader) ([]string, error) {
+	line, err := r.ReadString('\n')
+	if err != nil {
+		return nil, err
+	}
+	n, err := strconv.Atoi(strings.TrimSpace(line[1:]))
+	if err != nil {
+		return nil, err
+	}
+	args := make([]string, n)
+	for i := range args {
+		if line, err = r.ReadString('\n'); err != nil {
+			return nil, err
+		}
+		size, err := strconv.Atoi(strings.TrimSpace(line[1:]))
+		if err != nil {
+			return nil, err
+		}
+		buf := make([]byte, size+2)
+		if _, err := io.ReadFull(r, buf); err != nil {
+			return nil, err
+		}
+		args[i] = string(buf[:size])
+	}
+	return args, nil
+}
+
+func TestRedisCache(t *testing.T) {
+	ctx := context.Background()
+	s := newFakeRedis(t)
+
+	c, err := NewRedisCache(fmt.Sprintf("redis://:secret@%s", s.listener.Addr()))
+	require.Nil(t, err)
+
+	_, ok, err := c.Get(ctx, "a")
+	require.Nil(t, err)
+	require.False(t, ok)
+
+	value := []byte("{\"height\": 8048956}\r\n")
+	require.Nil(t, c.Set(ctx, "a", value, time.Minute))
+	v, ok, err := c.Get(ctx, "a")
+	require.Nil(t, err)
+	require.True(t, ok)
+	require.Equal(t, value, v)
+
+	// Connections are authenticated once, and reused.
+	require.Equal(t, []string{"AUTH", "GET", "SET", "GET"}, s.commands)
+
+	// Error replies are returned.
+	c, err = NewRedisCache(fmt.Sprintf("redis://:wrong@%s", s.listener.Addr()))
+	require.Nil(t, err)
+	_, _, err = c.Get(ctx, "a")
+	require.EqualError(t, err, "redis: WRONGPASS invalid password")
+}
+
+func TestNewRedisCache(t *testing.T) {
+	c, err := NewRedisCache("redis://localhost:6379/2")
+	require.Nil(t, err)
+	require.Equal(t, "localhost:6379", c.addr)
+	require.Equal(t, 2, c.db)
+
+	for _, endpoint := range []string{
+		"localhost:6379",
+		"http://localhost:6379",
+		"redis://localhost:6379/db",
+	} {
+		_, err := NewRedisCache(endpoint)
+		require.NotNil(t, err, endpoint)
+	}
+}
@@ -2,6 +2,7 @@
 package analyzer
 
 import (
+	"context"
 	"os"
 	"sync"
 
@@ -16,8 +17,11 @@ import (
 	"github.com/oasisprotocol/oasis-indexer/analyzer/emerald"
 	"github.com/oasisprotocol/oasis-indexer/cmd/common"
 	"github.com/oasisprotocol/oasis-indexer/config"
+	"github.com/oasisprotocol/oasis-indexer/health"
 	"github.com/oasisprotocol/oasis-indexer/log"
+	"github.com/oasisprotocol/oasis-indexer/notifier"
 	"github.com/oasisprotocol/oasis-indexer/storage"
+	"github.com/oasisprotocol/oasis-indexer/streaming"
 )
 
 const (
@@ -65,7 +69,10 @@ func runAnalyzer(cmd *cobra.Command, args []string) {
 	}
 	defer service.Shutdown()
 
-	service.Start()
+	ctx, stop := common.SignalContext()
+	defer stop()
+
+	service.Start(ctx)
 }
 
 // Init initializes the analysis service.
@@ -110,6 +117,8 @@ type Service struct {
 	Analyzers map[string]analyzer.Analyzer
 
 	target storage.TargetStorage
+	prober *health.Prober
+	health *config.HealthConfig
 	logger *log.Logger
 }
 
@@ -118,23 +127,38 @@ func NewService(cfg *config.AnalysisConfig) (*Service, error) {
 	logger := common.Logger().WithModule(moduleName)
 
 	// Initialize target storage.
-	client, err := common.NewClient(cfg.Storage, logger)
+	client, err := common.NewClient(cfg.Storage, moduleName, logger)
 	if err != nil {
 		return nil, err
 	}
 
+	// Initialize message bus.
+	var b streaming.Bus
+	if cfg.Bus != nil {
+		if b, err = common.NewBus(cfg.Bus, cfg.Storage, client, logger); err != nil {
+			return nil, err
+		}
+	}
+
 	// Initialize analyzers.
 	analyzers := map[string]analyzer.Analyzer{}
 	for _, analyzerCfg := range cfg.Analyzers {
 		switch analyzerCfg.Name {
 		case "consensus_main_damask":
-			consensusMainDamask, err := consensus.NewMain(analyzerCfg, client, logger)
+			var n *notifier.Notifier
+			if cfg.Notifier != nil {
+				n, err = notifier.NewNotifier(analyzerCfg.ChainID, cfg.Notifier, client, logger)
+				if err != nil {
+					return nil, err
+				}
+			}
+			consensusMainDamask, err := consensus.NewMain(analyzerCfg, client, n, b, logger)
 			if err != nil {
 				return nil, err
 			}
 			analyzers[consensusMainDamask.Name()] = consensusMainDamask
 		case "emerald_main_damask":
-			emeraldMainDamask, err := emerald.NewMain(analyzerCfg, client, logger)
+			emeraldMainDamask, err := emerald.NewMain(analyzerCfg, client, b, logger)
 			if err != nil {
 				return nil, err
 			}
@@ -144,32 +168,60 @@ func NewService(cfg *config.AnalysisConfig) (*Service, error) {
 
 	logger.Info("initialized analyzers")
 
+	// Initialize the prober that serves the health of the service. The
+	// service is ready once storage is migrated and the sources of all
+	// analyzers serve the configured network.
+	prober := health.NewProber(logger)
+	prober.AddCheck("storage", health.StorageCheck(client))
+	prober.AddCheck("migrations", health.MigrationsCheck(client, storage.MigrationVersion))
+	for name, an := range analyzers {
+		prober.AddCheck(name, an.CheckReady)
+	}
+
 	return &Service{
 		Analyzers: analyzers,
 
 		target: client,
+		prober: prober,
+		health: cfg.Health,
 		logger: logger,
 	}, nil
 }
 
-// Start starts the analysis service.
-func (a *Service) Start() {
+// Start starts the analysis service. Analyzers are stopped once the provided
+// context is cancelled, after committing any in-flight batch and storing
+// undelivered notifications, so target storage may be shut down once it
+// returns.
+func (a *Service) Start(ctx context.Context) {
 	a.logger.Info("starting analysis service")
 
+	if a.health != nil {
+		go func() {
+			if err := a.prober.Serve(ctx, a.health.Endpoint); err != nil {
+				a.logger.Error("failed to serve health probes",
+					"endpoint", a.health.Endpoint,
+					"error", err,
+				)
+			}
+		}()
+	}
+
 	var wg sync.WaitGroup
 	for _, an := range a.Analyzers {
 		wg.Add(1)
 		go func(an analyzer.Analyzer) {
 			defer wg.Done()
-			an.Start()
+			an.Start(ctx)
 		}(an)
 	}
 
 	wg.Wait()
+	a.logger.Info("analysis service stopped")
 }
 
 // Shutdown gracefully shuts down the service.
 func (a *Service) Shutdown() {
+	a.logger.Info("closing target storage")
 	a.target.Shutdown()
 }
 
@@ -2,6 +2,9 @@
 package api
 
 import (
+	"context"
+	"errors"
+	"net"
 	"net/http"
 	"os"
 	"time"
@@ -9,14 +12,23 @@ import (
 	"github.com/spf13/cobra"
 
 	"github.com/oasisprotocol/oasis-indexer/api"
+	apiCommon "github.com/oasisprotocol/oasis-indexer/api/common"
+	v1 "github.com/oasisprotocol/oasis-indexer/api/v1"
+	"github.com/oasisprotocol/oasis-indexer/cache"
 	"github.com/oasisprotocol/oasis-indexer/cmd/common"
 	"github.com/oasisprotocol/oasis-indexer/config"
+	"github.com/oasisprotocol/oasis-indexer/health"
 	"github.com/oasisprotocol/oasis-indexer/log"
 	"github.com/oasisprotocol/oasis-indexer/storage"
+	"github.com/oasisprotocol/oasis-indexer/streaming"
 )
 
 const (
 	moduleName = "api"
+
+	// shutdownTimeout is how long in-flight requests are given
+	// to complete when the server is shutting down.
+	shutdownTimeout = 10 * time.Second
 )
 
 var (
@@ -60,7 +72,10 @@ func runServer(cmd *cobra.Command, args []string) {
 	}
 	defer service.Shutdown()
 
-	service.Start()
+	ctx, stop := common.SignalContext()
+	defer stop()
+
+	service.Start(ctx)
 }
 
 // Init initializes the API service.
@@ -90,38 +105,107 @@ func NewService(cfg *config.ServerConfig) (*Service, error) {
 	logger := common.Logger().WithModule(moduleName)
 
 	// Initialize target storage.
-	client, err := common.NewClient(cfg.Storage, logger)
+	client, err := common.NewClient(cfg.Storage, moduleName, logger)
 	if err != nil {
 		return nil, err
 	}
 
+	// Initialize the bus that newly indexed data is streamed from.
+	var bus streaming.Bus
+	if cfg.Bus != nil {
+		if bus, err = common.NewBus(cfg.Bus, cfg.Storage, client, logger); err != nil {
+			return nil, err
+		}
+	}
+
+	// Initialize the cache that responses are cached in.
+	var c cache.Cache
+	if cfg.Cache != nil {
+		if c, err = common.NewCache(cfg.Cache); err != nil {
+			return nil, err
+		}
+	}
+
+	// Initialize the prober that serves the health of the API. The API
+	// is ready once storage is migrated and, if a maximum lag is
+	// configured, indexing has caught up.
+	prober := health.NewProber(logger)
+	prober.AddCheck("storage", health.StorageCheck(client))
+	prober.AddCheck("migrations", health.MigrationsCheck(client, storage.MigrationVersion))
+	if cfg.Health != nil && cfg.Health.MaxLag != "" {
+		maxLag, err := time.ParseDuration(cfg.Health.MaxLag)
+		if err != nil {
+			return nil, err
+		}
+		prober.AddCheck("lag", health.LagCheck(v1.LatestBlockTime(client), maxLag))
+	}
+
 	return &Service{
 		server: cfg.Endpoint,
-		api:    api.NewIndexerAPI(client, logger),
+		api:    api.NewIndexerAPI(client, bus, c, cfg.RateLimit, prober, logger),
 		target: client,
 		logger: logger,
 	}, nil
 }
 
-// Start starts the API service.
-func (s *Service) Start() {
+// Start starts the API service. The server is shut down gracefully
+// once the provided context is cancelled.
+func (s *Service) Start(ctx context.Context) {
 	s.logger.Info("starting api service")
 
+	// Streams and exports outlive the write timeout, so they extend
+	// the write deadline of their connection as they write. They never
+	// go idle, so they are ended on shutdown, which waits for them to
+	// end before target storage is closed.
+	shutdown := apiCommon.NewShutdown()
 	server := &http.Server{
 		Addr:           s.server,
 		Handler:        s.api.Router(),
 		ReadTimeout:    10 * time.Second,
 		WriteTimeout:   10 * time.Second,
 		MaxHeaderBytes: 1 << 20,
+		BaseContext: func(net.Listener) context.Context {
+			return shutdown.Context(context.Background())
+		},
+		ConnContext: apiCommon.WithConn,
 	}
+	server.RegisterOnShutdown(shutdown.Start)
+
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- server.ListenAndServe()
+	}()
 
-	s.logger.Error("shutting down",
-		"error", server.ListenAndServe(),
-	)
+	select {
+	case err := <-errCh:
+		s.logger.Error("shutting down",
+			"error", err,
+		)
+		return
+	case <-ctx.Done():
+	}
+
+	s.logger.Info("shutting down api service")
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+	defer cancel()
+
+	if err := server.Shutdown(shutdownCtx); err != nil {
+		s.logger.Error("failed to shut down gracefully",
+			"error", err,
+		)
+	}
+	shutdown.Wait()
+	if err := <-errCh; err != nil && !errors.Is(err, http.ErrServerClosed) {
+		s.logger.Error("server failed",
+			"error", err,
+		)
+	}
 }
 
 // Shutdown gracefully shuts down the service.
 func (s *Service) Shutdown() {
+	s.logger.Info("closing target storage")
 	s.target.Shutdown()
 }
 
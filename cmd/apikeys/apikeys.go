@@ -0,0 +1,198 @@
+// Package apikeys implements the `apikeys` sub-command, for managing
+// the API keys that clients of the API authenticate with.
+package apikeys
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strconv"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/oasisprotocol/oasis-indexer/api/auth"
+	"github.com/oasisprotocol/oasis-indexer/cmd/common"
+	"github.com/oasisprotocol/oasis-indexer/config"
+	"github.com/oasisprotocol/oasis-indexer/log"
+)
+
+const (
+	moduleName = "apikeys"
+
+	// commandTimeout bounds how long a command may take.
+	commandTimeout = 30 * time.Second
+)
+
+var (
+	// Path to the configuration file.
+	configFile string
+
+	keyName string
+	keyTier string
+
+	apiKeysCmd = &cobra.Command{
+		Use:   "apikeys",
+		Short: "Manage API keys",
+	}
+
+	createCmd = &cobra.Command{
+		Use:   "create",
+		Short: "Create an API key",
+		Args:  cobra.NoArgs,
+		Run:   runCreate,
+	}
+
+	listCmd = &cobra.Command{
+		Use:   "list",
+		Short: "List API keys",
+		Args:  cobra.NoArgs,
+		Run:   runList,
+	}
+
+	revokeCmd = &cobra.Command{
+		Use:   "revoke <id>",
+		Short: "Revoke an API key",
+		Args:  cobra.ExactArgs(1),
+		Run:   runRevoke,
+	}
+)
+
+func runCreate(cmd *cobra.Command, args []string) {
+	cfg, store, logger := initStore()
+	defer store.close()
+
+	if keyName == "" {
+		logger.Error("key name not provided")
+		os.Exit(1)
+	}
+	if cfg.RateLimit != nil {
+		if _, ok := cfg.RateLimit.Tiers[keyTier]; !ok {
+			logger.Error("tier not configured",
+				"tier", keyTier,
+			)
+			os.Exit(1)
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), commandTimeout)
+	defer cancel()
+
+	key, secret, err := store.Create(ctx, keyName, keyTier)
+	if err != nil {
+		logger.Error("failed to create api key",
+			"error", err,
+		)
+		os.Exit(1)
+	}
+
+	fmt.Printf("Created API key %d for '%s' in tier '%s'.\n", key.ID, key.Name, key.Tier)
+	fmt.Println("It is only shown once, so store it securely:")
+	fmt.Println(secret)
+}
+
+func runList(cmd *cobra.Command, args []string) {
+	_, store, logger := initStore()
+	defer store.close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), commandTimeout)
+	defer cancel()
+
+	keys, err := store.List(ctx)
+	if err != nil {
+		logger.Error("failed to list api keys",
+			"error", err,
+		)
+		os.Exit(1)
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "ID\tNAME\tTIER\tPREFIX\tCREATED\tREVOKED")
+	for _, k := range keys {
+		revoked := "-"
+		if k.RevokedAt != nil {
+			revoked = k.RevokedAt.UTC().Format(time.RFC3339)
+		}
+		fmt.Fprintf(w, "%d\t%s\t%s\t%s\t%s\t%s\n", k.ID, k.Name, k.Tier, k.Prefix, k.CreatedAt.UTC().Format(time.RFC3339), revoked)
+	}
+	w.Flush()
+}
+
+func runRevoke(cmd *cobra.Command, args []string) {
+	_, store, logger := initStore()
+	defer store.close()
+
+	id, err := strconv.ParseInt(args[0], 10, 64)
+	if err != nil {
+		logger.Error("malformed key id",
+			"id", args[0],
+		)
+		os.Exit(1)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), commandTimeout)
+	defer cancel()
+
+	if err := store.Revoke(ctx, id); err != nil {
+		logger.Error("failed to revoke api key",
+			"id", id,
+			"error", err,
+		)
+		os.Exit(1)
+	}
+	fmt.Printf("Revoked API key %d.\n", id)
+}
+
+// keyStore is an API key store along with its underlying target storage.
+type keyStore struct {
+	*auth.Store
+	close func()
+}
+
+// initStore initializes the environment and the API key store of the
+// configured API server, exiting on failure.
+func initStore() (*config.ServerConfig, *keyStore, *log.Logger) {
+	// Initialize config.
+	cfg, err := config.InitConfig(configFile)
+	if err != nil {
+		log.NewDefaultLogger("init").Error("init failed",
+			"error", err,
+		)
+		os.Exit(1)
+	}
+
+	// Initialize common environment.
+	if err = common.Init(cfg); err != nil {
+		log.NewDefaultLogger("init").Error("init failed",
+			"error", err,
+		)
+		os.Exit(1)
+	}
+	logger := common.Logger().WithModule(moduleName)
+
+	if cfg.Server == nil {
+		logger.Error("server config not provided")
+		os.Exit(1)
+	}
+
+	client, err := common.NewClient(cfg.Server.Storage, moduleName, logger)
+	if err != nil {
+		logger.Error("failed to connect to target storage",
+			"error", err,
+		)
+		os.Exit(1)
+	}
+
+	return cfg.Server, &keyStore{auth.NewStore(client), client.Shutdown}, logger
+}
+
+// Register registers the apikeys sub-command.
+func Register(parentCmd *cobra.Command) {
+	apiKeysCmd.PersistentFlags().StringVar(&configFile, "config", "./config/local.yml", "path to the config.yml file")
+	createCmd.Flags().StringVar(&keyName, "name", "", "name of the client the key is issued to")
+	createCmd.Flags().StringVar(&keyTier, "tier", "default", "rate limit tier of the key")
+
+	apiKeysCmd.AddCommand(createCmd, listCmd, revokeCmd)
+	parentCmd.AddCommand(apiKeysCmd)
+}
@@ -2,16 +2,25 @@
 package common
 
 import (
+	"context"
 	"fmt"
 	"io"
 	"os"
+	"os/signal"
+	"syscall"
+	"time"
 
+	"github.com/jackc/pgx/v4/pgxpool"
+
+	"github.com/oasisprotocol/oasis-indexer/cache"
 	"github.com/oasisprotocol/oasis-indexer/config"
 	"github.com/oasisprotocol/oasis-indexer/log"
 	"github.com/oasisprotocol/oasis-indexer/metrics"
 	"github.com/oasisprotocol/oasis-indexer/storage"
 	"github.com/oasisprotocol/oasis-indexer/storage/cockroach"
+	"github.com/oasisprotocol/oasis-indexer/storage/pools"
 	"github.com/oasisprotocol/oasis-indexer/storage/postgres"
+	"github.com/oasisprotocol/oasis-indexer/streaming"
 )
 
 var rootLogger = log.NewDefaultLogger("oasis-indexer")
@@ -51,6 +60,12 @@ func Init(cfg *config.Config) error {
 	return nil
 }
 
+// SignalContext returns a context that is cancelled when the process
+// receives an interrupt or termination signal.
+func SignalContext() (context.Context, context.CancelFunc) {
+	return signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+}
+
 // Logger returns the logger defined by logging flags.
 func Logger() *log.Logger {
 	return rootLogger
@@ -67,25 +82,100 @@ func getLoggingStream(cfg *config.LogConfig) (io.Writer, error) {
 	return w, nil
 }
 
-// NewClient creates a new client to target storage.
-func NewClient(cfg *config.StorageConfig, logger *log.Logger) (storage.TargetStorage, error) {
+// NewClient creates a new client to target storage, and instruments the
+// connection pools of the client with metrics prefixed by pkg.
+func NewClient(cfg *config.StorageConfig, pkg string, logger *log.Logger) (storage.TargetStorage, error) {
 	var backend config.StorageBackend
 	if err := backend.Set(cfg.Backend); err != nil {
 		return nil, err
 	}
+	poolsCfg, err := newPoolsConfig(cfg)
+	if err != nil {
+		return nil, err
+	}
 
-	var client storage.TargetStorage
-	var err error
+	var client interface {
+		storage.TargetStorage
+		Stats() map[string]*pgxpool.Stat
+	}
 	switch backend {
 	case config.BackendCockroach:
-		client, err = cockroach.NewClient(cfg.Endpoint, logger)
+		client, err = cockroach.NewPooledClient(poolsCfg, logger)
 	case config.BackendPostgres:
-		client, err = postgres.NewClient(cfg.Endpoint, logger)
+		client, err = postgres.NewPooledClient(poolsCfg, logger)
 	default:
 		panic(fmt.Sprintf("unsupported storage backend: %v", backend))
 	}
 	if err != nil {
 		return nil, err
 	}
+	metrics.NewDefaultPoolMetrics(pkg, client.Stats)
 	return client, nil
 }
+
+// newPoolsConfig creates the connection pool configuration of target storage.
+func newPoolsConfig(cfg *config.StorageConfig) (*pools.Config, error) {
+	poolsCfg := pools.Config{
+		Primary:  cfg.Endpoint,
+		Replicas: cfg.ReadEndpoints,
+	}
+	if cfg.Pool == nil {
+		return &poolsCfg, nil
+	}
+	poolsCfg.MaxConns = cfg.Pool.MaxConns
+	poolsCfg.MinConns = cfg.Pool.MinConns
+	for _, d := range []struct {
+		s string
+		v *time.Duration
+	}{
+		{cfg.Pool.MaxConnLifetime, &poolsCfg.MaxConnLifetime},
+		{cfg.Pool.MaxConnIdleTime, &poolsCfg.MaxConnIdleTime},
+		{cfg.Pool.HealthCheckPeriod, &poolsCfg.HealthCheckPeriod},
+		{cfg.Pool.StatementTimeout, &poolsCfg.StatementTimeout},
+	} {
+		if d.s == "" {
+			continue
+		}
+		v, err := time.ParseDuration(d.s)
+		if err != nil {
+			return nil, err
+		}
+		*d.v = v
+	}
+	return &poolsCfg, nil
+}
+
+// NewBus creates a new message bus. The postgres backend publishes through the
+// provided target storage, which must be backed by the same database.
+func NewBus(cfg *config.BusConfig, storageCfg *config.StorageConfig, target storage.TargetStorage, logger *log.Logger) (streaming.Bus, error) {
+	var backend config.BusBackend
+	if err := backend.Set(cfg.Backend); err != nil {
+		return nil, err
+	}
+
+	switch backend {
+	case config.BusMemory:
+		return streaming.DefaultMemoryBus(), nil
+	case config.BusPostgres:
+		return streaming.NewPostgresBus(storageCfg.Endpoint, target, logger), nil
+	default:
+		panic(fmt.Sprintf("unsupported bus backend: %v", backend))
+	}
+}
+
+// NewCache creates a new response cache.
+func NewCache(cfg *config.CacheConfig) (cache.Cache, error) {
+	var backend config.CacheBackend
+	if err := backend.Set(cfg.Backend); err != nil {
+		return nil, err
+	}
+
+	switch backend {
+	case config.CacheMemory:
+		return cache.NewMemoryCache(cfg.Size), nil
+	case config.CacheRedis:
+		return cache.NewRedisCache(cfg.Endpoint)
+	default:
+		panic(fmt.Sprintf("unsupported cache backend: %v", backend))
+	}
+}
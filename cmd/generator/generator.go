@@ -1,6 +1,7 @@
 // Package generator implements the `generate` sub-command. This is intended
-// to primarily be a utility command for generating migrations for populating
-// the Oasis Indexer database from genesis state.
+// to primarily be a utility command for populating the Oasis Indexer database
+// from genesis state, either by generating migrations or by loading it into
+// target storage directly.
 package generator
 
 import (
@@ -16,6 +17,7 @@ import (
 
 	oasisConfig "github.com/oasisprotocol/oasis-sdk/client-sdk/go/config"
 
+	"github.com/oasisprotocol/oasis-indexer/analyzer/consensus"
 	"github.com/oasisprotocol/oasis-indexer/cmd/common"
 	"github.com/oasisprotocol/oasis-indexer/config"
 	"github.com/oasisprotocol/oasis-indexer/log"
@@ -35,6 +37,10 @@ const (
 	// CfgNetworkConfigFile is the config file for connecting to an oasis-node.
 	CfgNetworkConfigFile = "generator.network_config_file"
 
+	// CfgLoad loads genesis state into the target storage of the analysis
+	// service, instead of generating a migration.
+	CfgLoad = "generator.load"
+
 	moduleName = "generator"
 )
 
@@ -45,10 +51,11 @@ var (
 	cfgMigrationFile     string
 	cfgGenesisFile       string
 	cfgNetworkConfigFile string
+	cfgLoad              bool
 
 	generateCmd = &cobra.Command{
 		Use:   "generate",
-		Short: "Generate migrations",
+		Short: "Generate migrations or load genesis state",
 		Run:   runGenerator,
 	}
 )
@@ -68,13 +75,27 @@ func runGenerator(cmd *cobra.Command, args []string) {
 
 	g, err := NewGenerator()
 	if err != nil {
-		logger.Error("migration failed to run",
+		logger.Error("generator failed to initialize",
 			"error", err,
 		)
 		os.Exit(1)
 	}
+
+	if cfgLoad {
+		if cfg.Analysis == nil {
+			logger.Error("analysis config not provided")
+			os.Exit(1)
+		}
+		if err := g.LoadGenesis(context.Background(), cfg.Analysis.Storage); err != nil {
+			logger.Error("genesis state failed to load",
+				"error", err,
+			)
+			os.Exit(1)
+		}
+		return
+	}
 	if err := g.WriteMigration(); err != nil {
-		logger.Error("generator failed to initialize",
+		logger.Error("migration failed to run",
 			"error", err,
 		)
 		os.Exit(1)
@@ -99,28 +120,14 @@ func NewGenerator() (*Generator, error) {
 
 // WriteMigration writes the state migration.
 func (g *Generator) WriteMigration() error {
-	var d *genesis.Document
-	switch {
-	case cfgGenesisFile != "":
-		doc, err := g.genesisDocFromFile()
-		if err != nil {
-			return err
-		}
-		d = doc
-	case cfgNetworkConfigFile != "":
-		doc, err := g.genesisDocFromClient()
-		if err != nil {
-			return err
-		}
-		d = doc
-	default:
-		return errors.New("neither genesis file nor network config provided")
+	d, err := g.genesisDoc()
+	if err != nil {
+		return err
 	}
 
 	// Create output file.
 	w := os.Stdout
 	if cfgMigrationFile != "" {
-		var err error
 		w, err = os.Create(cfgMigrationFile)
 		if err != nil {
 			return err
@@ -147,6 +154,48 @@ func (g *Generator) WriteMigration() error {
 	return nil
 }
 
+// LoadGenesis loads genesis state into target storage, from which the
+// consensus analyzer then resumes at the height that follows genesis.
+func (g *Generator) LoadGenesis(ctx context.Context, cfg *config.StorageConfig) error {
+	d, err := g.genesisDoc()
+	if err != nil {
+		return err
+	}
+	switch d.ChainID {
+	case "oasis-3", "test":
+	default:
+		g.logger.Error("unsupported chain id")
+		return errors.New("unsupported chain id")
+	}
+
+	client, err := common.NewClient(cfg, moduleName, g.logger)
+	if err != nil {
+		return err
+	}
+	defer client.Shutdown()
+
+	loader := generator.NewGenesisLoader(client, g.logger)
+	if err := loader.LoadGenesisDocumentOasis3(ctx, d, consensus.MainDamaskName); err != nil {
+		return err
+	}
+
+	g.logger.Info("successfully loaded genesis state",
+		"height", d.Height,
+	)
+	return nil
+}
+
+func (g *Generator) genesisDoc() (*genesis.Document, error) {
+	switch {
+	case cfgGenesisFile != "":
+		return g.genesisDocFromFile()
+	case cfgNetworkConfigFile != "":
+		return g.genesisDocFromClient()
+	default:
+		return nil, errors.New("neither genesis file nor network config provided")
+	}
+}
+
 func (g *Generator) genesisDocFromFile() (*genesis.Document, error) {
 	rawDoc, err := ioutil.ReadFile(cfgGenesisFile)
 	if err != nil {
@@ -197,5 +246,6 @@ func Register(parentCmd *cobra.Command) {
 	generateCmd.Flags().StringVar(&cfgMigrationFile, CfgMigrationFile, "", "path to output migration file")
 	generateCmd.Flags().StringVar(&cfgGenesisFile, CfgGenesisFile, "", "path to input genesis file")
 	generateCmd.Flags().StringVar(&cfgNetworkConfigFile, CfgNetworkConfigFile, "", "path to a network configuration file")
+	generateCmd.Flags().BoolVar(&cfgLoad, CfgLoad, false, "load genesis state into target storage instead of generating a migration")
 	parentCmd.AddCommand(generateCmd)
 }
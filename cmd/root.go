@@ -2,6 +2,7 @@
 package cmd
 
 import (
+	"context"
 	"fmt"
 	"os"
 	"sync"
@@ -10,8 +11,10 @@ import (
 
 	"github.com/oasisprotocol/oasis-indexer/cmd/analyzer"
 	"github.com/oasisprotocol/oasis-indexer/cmd/api"
+	"github.com/oasisprotocol/oasis-indexer/cmd/apikeys"
 	"github.com/oasisprotocol/oasis-indexer/cmd/common"
 	"github.com/oasisprotocol/oasis-indexer/cmd/generator"
+	"github.com/oasisprotocol/oasis-indexer/cmd/verifier"
 	"github.com/oasisprotocol/oasis-indexer/config"
 	"github.com/oasisprotocol/oasis-indexer/log"
 )
@@ -29,8 +32,9 @@ var (
 
 // Service is a service run by the indexer.
 type Service interface {
-	// Start starts the service.
-	Start()
+	// Start starts the service. It returns once the service has stopped,
+	// which happens when the provided context is cancelled.
+	Start(ctx context.Context)
 
 	// Shutdown shuts down the service.
 	Shutdown()
@@ -65,6 +69,9 @@ func rootMain(cmd *cobra.Command, args []string) {
 		os.Exit(1)
 	}
 
+	ctx, stop := common.SignalContext()
+	defer stop()
+
 	var wg sync.WaitGroup
 	for _, service := range []Service{
 		analysisService,
@@ -74,12 +81,13 @@ func rootMain(cmd *cobra.Command, args []string) {
 		go func(s Service) {
 			defer wg.Done()
 			defer s.Shutdown()
-			s.Start()
+			s.Start(ctx)
 		}(service)
 	}
 
 	logger.Info("started all services")
 	wg.Wait()
+	logger.Info("stopped all services")
 }
 
 // Execute spawns the main entry point after handing the config file.
@@ -96,7 +104,9 @@ func init() {
 	for _, f := range []func(*cobra.Command){
 		analyzer.Register,
 		api.Register,
+		apikeys.Register,
 		generator.Register,
+		verifier.Register,
 	} {
 		f(rootCmd)
 	}
@@ -0,0 +1,177 @@
+// Package verifier implements the `verify` sub-command, for verifying
+// indexed state against node state.
+package verifier
+
+import (
+	"encoding/json"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	oasisConfig "github.com/oasisprotocol/oasis-sdk/client-sdk/go/config"
+
+	"github.com/oasisprotocol/oasis-indexer/analyzer/consensus"
+	"github.com/oasisprotocol/oasis-indexer/cmd/common"
+	"github.com/oasisprotocol/oasis-indexer/config"
+	"github.com/oasisprotocol/oasis-indexer/log"
+	"github.com/oasisprotocol/oasis-indexer/storage/oasis"
+	"github.com/oasisprotocol/oasis-indexer/verifier"
+)
+
+const (
+	moduleName = "verifier"
+)
+
+var (
+	// Path to the configuration file.
+	configFile string
+
+	height     int64
+	checkpoint bool
+	record     bool
+
+	verifyCmd = &cobra.Command{
+		Use:   "verify",
+		Short: "Verify indexed state against node state",
+		Long: `Verify the checkpoint of indexed state at a height against node state at
+that height, and print a report of their differences. The command exits with
+a non-zero status if they differ.`,
+		Args: cobra.NoArgs,
+		Run:  runVerify,
+	}
+)
+
+func runVerify(cmd *cobra.Command, args []string) {
+	// Initialize config.
+	cfg, err := config.InitConfig(configFile)
+	if err != nil {
+		log.NewDefaultLogger("init").Error("config init failed",
+			"error", err,
+		)
+		os.Exit(1)
+	}
+
+	// Initialize common environment.
+	if err = common.Init(cfg); err != nil {
+		log.NewDefaultLogger("init").Error("init failed",
+			"error", err,
+		)
+		os.Exit(1)
+	}
+	logger := common.Logger().WithModule(moduleName)
+
+	if cfg.Analysis == nil {
+		logger.Error("analysis config not provided")
+		os.Exit(1)
+	}
+	if checkpoint && height != 0 {
+		logger.Error("checkpoints are taken at the latest processed height, which cannot be provided")
+		os.Exit(1)
+	}
+
+	// Node state is that of the node of the consensus analyzer.
+	var analyzerCfg *config.AnalyzerConfig
+	for _, a := range cfg.Analysis.Analyzers {
+		if a.Name == consensus.MainDamaskName {
+			analyzerCfg = a
+		}
+	}
+	if analyzerCfg == nil {
+		logger.Error("consensus analyzer config not provided")
+		os.Exit(1)
+	}
+
+	ctx, stop := common.SignalContext()
+	defer stop()
+
+	factory, err := oasis.NewClientFactory(ctx, &oasisConfig.Network{
+		ChainContext: analyzerCfg.ChainContext,
+		RPC:          analyzerCfg.RPC,
+	})
+	if err != nil {
+		logger.Error("failed to connect to node",
+			"error", err,
+		)
+		os.Exit(1)
+	}
+	source, err := factory.Consensus()
+	if err != nil {
+		logger.Error("failed to create consensus client",
+			"error", err,
+		)
+		os.Exit(1)
+	}
+
+	target, err := common.NewClient(cfg.Analysis.Storage, moduleName, logger)
+	if err != nil {
+		logger.Error("failed to connect to target storage",
+			"error", err,
+		)
+		os.Exit(1)
+	}
+	defer target.Shutdown()
+
+	v := verifier.NewVerifier(analyzerCfg.ChainID, source, target, logger)
+	switch {
+	case checkpoint:
+		if height, err = v.Checkpoint(ctx, consensus.MainDamaskName); err != nil {
+			logger.Error("failed to checkpoint indexed state",
+				"error", err,
+			)
+			os.Exit(1)
+		}
+	case height == 0:
+		if height, err = v.LatestCheckpoint(ctx); err != nil {
+			logger.Error("failed to find checkpoint",
+				"error", err,
+			)
+			os.Exit(1)
+		}
+	}
+
+	report, err := v.Verify(ctx, height)
+	if err != nil {
+		logger.Error("failed to verify indexed state",
+			"height", height,
+			"error", err,
+		)
+		os.Exit(1)
+	}
+	if record {
+		if err := v.Record(ctx, report); err != nil {
+			logger.Error("failed to record verification",
+				"height", height,
+				"error", err,
+			)
+			os.Exit(1)
+		}
+	}
+
+	enc := json.NewEncoder(os.Stdout)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(report); err != nil {
+		logger.Error("failed to print report",
+			"error", err,
+		)
+		os.Exit(1)
+	}
+	if report.Mismatches > 0 {
+		logger.Error("indexed state differs from node state",
+			"height", height,
+			"mismatches", report.Mismatches,
+		)
+		os.Exit(1)
+	}
+	logger.Info("indexed state matches node state",
+		"height", height,
+	)
+}
+
+// Register registers the verify sub-command.
+func Register(parentCmd *cobra.Command) {
+	verifyCmd.Flags().StringVar(&configFile, "config", "./config/local-dev.yml", "path to the config.yml file")
+	verifyCmd.Flags().Int64Var(&height, "height", 0, "height of the checkpoint to verify, by default the latest")
+	verifyCmd.Flags().BoolVar(&checkpoint, "checkpoint", false, "checkpoint indexed state at the latest processed height and verify it")
+	verifyCmd.Flags().BoolVar(&record, "record", false, "record the result of the verification with its checkpoint")
+	parentCmd.AddCommand(verifyCmd)
+}
@@ -4,6 +4,7 @@ package config
 import (
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/knadh/koanf"
 	"github.com/knadh/koanf/parsers/yaml"
@@ -56,6 +57,18 @@ type AnalysisConfig struct {
 	Migrations string `koanf:"migrations"`
 
 	Storage *StorageConfig `koanf:"storage"`
+
+	// Notifier is the webhook notifier configuration. Omitting this
+	// parameter disables webhook notifications.
+	Notifier *NotifierConfig `koanf:"notifier"`
+
+	// Bus is the message bus on which indexing progress is published.
+	// Omitting this parameter disables publishing.
+	Bus *BusConfig `koanf:"bus"`
+
+	// Health is the health probe configuration. Omitting this parameter
+	// disables health probes, since analyzers do not serve HTTP otherwise.
+	Health *HealthConfig `koanf:"health"`
 }
 
 // Validate validates the analysis configuration.
@@ -70,9 +83,86 @@ func (cfg *AnalysisConfig) Validate() error {
 		}
 		names[analyzerCfg.Name] = true
 	}
+	if cfg.Notifier != nil {
+		if err := cfg.Notifier.Validate(); err != nil {
+			return fmt.Errorf("notifier: %w", err)
+		}
+	}
+	if cfg.Bus != nil {
+		if err := cfg.Bus.Validate(cfg.Storage); err != nil {
+			return fmt.Errorf("bus: %w", err)
+		}
+	}
+	if cfg.Health != nil {
+		if err := cfg.Health.Validate(); err != nil {
+			return fmt.Errorf("health: %w", err)
+		}
+		if cfg.Health.Endpoint == "" {
+			return fmt.Errorf("health: no endpoint provided")
+		}
+	}
+	if len(cfg.Storage.ReadEndpoints) > 0 {
+		// Analyzers resume from what they have written, so they
+		// must not read from replicas that may lag behind.
+		return fmt.Errorf("storage: read endpoints are not supported by analyzers")
+	}
 	return cfg.Storage.Validate()
 }
 
+// HealthConfig contains the health probe configuration.
+type HealthConfig struct {
+	// Endpoint is the endpoint from which to serve health probes. The API
+	// server serves them from its own endpoint, so it is only used by
+	// the analysis service.
+	Endpoint string `koanf:"endpoint"`
+
+	// MaxLag is how far the latest indexed block may lag behind the
+	// current time for the API server to be ready. Omitting this parameter
+	// disables the check. It is only used by the API server, and should be
+	// specified as a string compliant with time.ParseDuration
+	// (https://pkg.go.dev/time#ParseDuration).
+	MaxLag string `koanf:"max_lag"`
+}
+
+// Validate validates the health probe configuration.
+func (cfg *HealthConfig) Validate() error {
+	if cfg.MaxLag != "" {
+		if lag, err := time.ParseDuration(cfg.MaxLag); err != nil || lag <= 0 {
+			return fmt.Errorf("malformed max lag '%s'", cfg.MaxLag)
+		}
+	}
+	return nil
+}
+
+// NotifierConfig is the configuration for webhook notifications.
+type NotifierConfig struct {
+	// Workers is the number of concurrent webhook deliveries.
+	Workers int `koanf:"workers"`
+
+	// MaxAttempts is the number of delivery attempts made before
+	// a notification is moved to the dead letter table.
+	MaxAttempts int `koanf:"max_attempts"`
+
+	// Timeout is the timeout of a single delivery attempt.
+	// It should be specified as a string compliant with
+	// time.ParseDuration (https://pkg.go.dev/time#ParseDuration).
+	Timeout string `koanf:"timeout"`
+}
+
+// Validate validates the notifier configuration.
+func (cfg *NotifierConfig) Validate() error {
+	if cfg.Workers <= 0 {
+		return fmt.Errorf("malformed number of workers %d", cfg.Workers)
+	}
+	if cfg.MaxAttempts <= 0 {
+		return fmt.Errorf("malformed number of attempts %d", cfg.MaxAttempts)
+	}
+	if _, err := time.ParseDuration(cfg.Timeout); err != nil {
+		return fmt.Errorf("malformed timeout '%s': %w", cfg.Timeout, err)
+	}
+	return nil
+}
+
 // AnalyzerConfig is the configuration for a chain analyzer.
 //
 // If an analyzer is intended to run periodically, it should specify
@@ -104,6 +194,12 @@ type AnalyzerConfig struct {
 	// It should be specified as a string compliant with
 	// time.ParseDuration (https://pkg.go.dev/time#ParseDuration).
 	Interval string `koanf:"interval"`
+
+	// CheckpointInterval is the number of epochs between periodic
+	// checkpoints of consensus state, which are taken at the first block
+	// of each epoch that is a multiple of it. Omitting this parameter
+	// disables periodic checkpoints.
+	CheckpointInterval uint64 `koanf:"checkpoint_interval"`
 }
 
 // Validate validates the analysis configuration.
@@ -132,6 +228,24 @@ type ServerConfig struct {
 	Endpoint string `koanf:"endpoint"`
 
 	Storage *StorageConfig `koanf:"storage"`
+
+	// Bus is the message bus from which indexing progress is received.
+	// Omitting this parameter disables streaming endpoints.
+	Bus *BusConfig `koanf:"bus"`
+
+	// RateLimit is the API key and rate limit configuration. Omitting
+	// this parameter serves the API to all clients without limits.
+	RateLimit *RateLimitConfig `koanf:"rate_limit"`
+
+	// Cache is the response cache configuration. Omitting this parameter
+	// disables caching by the server, though clients may still cache
+	// responses.
+	Cache *CacheConfig `koanf:"cache"`
+
+	// Health is the health probe configuration. Probes are served from
+	// the server endpoint regardless, so omitting this parameter only
+	// disables optional checks.
+	Health *HealthConfig `koanf:"health"`
 }
 
 // Validate validates the server configuration.
@@ -142,9 +256,233 @@ func (cfg *ServerConfig) Validate() error {
 	if cfg.Storage == nil {
 		return fmt.Errorf("no storage config provided")
 	}
+	if cfg.Bus != nil {
+		if err := cfg.Bus.Validate(cfg.Storage); err != nil {
+			return fmt.Errorf("bus: %w", err)
+		}
+	}
+	if cfg.RateLimit != nil {
+		if err := cfg.RateLimit.Validate(); err != nil {
+			return fmt.Errorf("rate_limit: %w", err)
+		}
+	}
+	if cfg.Cache != nil {
+		if err := cfg.Cache.Validate(); err != nil {
+			return fmt.Errorf("cache: %w", err)
+		}
+	}
+	if cfg.Health != nil {
+		if err := cfg.Health.Validate(); err != nil {
+			return fmt.Errorf("health: %w", err)
+		}
+	}
 	return cfg.Storage.Validate()
 }
 
+// CacheBackend is a response cache backend.
+type CacheBackend uint
+
+const (
+	// CacheMemory is the in-process response cache backend.
+	CacheMemory CacheBackend = iota
+	// CacheRedis is the Redis-compatible response cache backend, which
+	// may be shared by several API servers.
+	CacheRedis
+)
+
+// String returns the string representation of a CacheBackend.
+func (cb *CacheBackend) String() string {
+	switch *cb {
+	case CacheMemory:
+		return "memory"
+	case CacheRedis:
+		return "redis"
+	default:
+		panic("config: unsupported cache backend")
+	}
+}
+
+// Set sets the CacheBackend to the value specified by the provided string.
+func (cb *CacheBackend) Set(s string) error {
+	switch strings.ToLower(s) {
+	case "memory":
+		*cb = CacheMemory
+	case "redis":
+		*cb = CacheRedis
+	default:
+		return fmt.Errorf("config: invalid cache backend: '%s'", s)
+	}
+
+	return nil
+}
+
+// Type returns the list of supported CacheBackends.
+func (cb *CacheBackend) Type() string {
+	return "[memory,redis]"
+}
+
+// CacheConfig contains the response cache configuration.
+type CacheConfig struct {
+	// Backend is the response cache backend to select.
+	Backend string `koanf:"backend"`
+
+	// Size is the maximum number of responses cached by the memory backend.
+	Size int `koanf:"size"`
+
+	// Endpoint is the URL of the server used by the redis backend,
+	// e.g. redis://:password@localhost:6379/0.
+	Endpoint string `koanf:"endpoint"`
+}
+
+// Validate validates the response cache configuration.
+func (cfg *CacheConfig) Validate() error {
+	var cb CacheBackend
+	if err := cb.Set(cfg.Backend); err != nil {
+		return err
+	}
+	switch cb {
+	case CacheMemory:
+		if cfg.Size <= 0 {
+			return fmt.Errorf("malformed cache size %d", cfg.Size)
+		}
+	case CacheRedis:
+		if cfg.Endpoint == "" {
+			return fmt.Errorf("no redis endpoint provided")
+		}
+	}
+	return nil
+}
+
+// RateLimitConfig contains the API key and rate limit configuration.
+type RateLimitConfig struct {
+	// RequireKey rejects requests that do not provide an API key.
+	RequireKey bool `koanf:"require_key"`
+
+	// TrustProxy identifies clients by the X-Forwarded-For or X-Real-IP
+	// headers, which should only be set if the API is served behind a
+	// reverse proxy that sets them.
+	TrustProxy bool `koanf:"trust_proxy"`
+
+	// Anonymous is the tier of requests without an API key, which
+	// are limited per client IP. It is required unless RequireKey is set.
+	Anonymous *TierConfig `koanf:"anonymous"`
+
+	// Tiers are the tiers API keys may be assigned, by name.
+	// Requests with an API key are limited per key.
+	Tiers map[string]*TierConfig `koanf:"tiers"`
+}
+
+// Validate validates the rate limit configuration.
+func (cfg *RateLimitConfig) Validate() error {
+	if cfg.Anonymous == nil && !cfg.RequireKey {
+		return fmt.Errorf("anonymous tier is required unless keys are required")
+	}
+	if cfg.Anonymous != nil {
+		if err := cfg.Anonymous.Validate(); err != nil {
+			return fmt.Errorf("anonymous: %w", err)
+		}
+	}
+	for name, tier := range cfg.Tiers {
+		if tier == nil {
+			return fmt.Errorf("tier '%s' is empty", name)
+		}
+		if err := tier.Validate(); err != nil {
+			return fmt.Errorf("tier '%s': %w", name, err)
+		}
+	}
+	return nil
+}
+
+// TierConfig contains the limits of a rate limit tier. Requests for
+// pages larger than the default count as several requests towards them.
+type TierConfig struct {
+	// Rate is the number of requests per second that may be sustained.
+	Rate float64 `koanf:"rate"`
+
+	// Burst is the number of requests that may be made at once.
+	Burst int `koanf:"burst"`
+
+	// DailyQuota is the number of requests that may be made per
+	// UTC day. Zero means there is no quota.
+	DailyQuota int64 `koanf:"daily_quota"`
+}
+
+// Validate validates the tier configuration.
+func (cfg *TierConfig) Validate() error {
+	if cfg.Rate <= 0 {
+		return fmt.Errorf("malformed rate %v", cfg.Rate)
+	}
+	if cfg.Burst <= 0 {
+		return fmt.Errorf("malformed burst %d", cfg.Burst)
+	}
+	if cfg.DailyQuota < 0 {
+		return fmt.Errorf("malformed daily quota %d", cfg.DailyQuota)
+	}
+	return nil
+}
+
+// BusBackend is a message bus backend.
+type BusBackend uint
+
+const (
+	// BusMemory is the in-process message bus backend. It requires
+	// analyzers and API servers to run within the same process.
+	BusMemory BusBackend = iota
+	// BusPostgres is the PostgreSQL LISTEN/NOTIFY message bus backend.
+	BusPostgres
+)
+
+// String returns the string representation of a BusBackend.
+func (bb *BusBackend) String() string {
+	switch *bb {
+	case BusMemory:
+		return "memory"
+	case BusPostgres:
+		return "postgres"
+	default:
+		panic("config: unsupported bus backend")
+	}
+}
+
+// Set sets the BusBackend to the value specified by the provided string.
+func (bb *BusBackend) Set(s string) error {
+	switch strings.ToLower(s) {
+	case "memory":
+		*bb = BusMemory
+	case "postgres":
+		*bb = BusPostgres
+	default:
+		return fmt.Errorf("config: invalid bus backend: '%s'", s)
+	}
+
+	return nil
+}
+
+// Type returns the list of supported BusBackends.
+func (bb *BusBackend) Type() string {
+	return "[memory,postgres]"
+}
+
+// BusConfig contains the message bus configuration.
+type BusConfig struct {
+	// Backend is the message bus backend to select.
+	Backend string `koanf:"backend"`
+}
+
+// Validate validates the message bus configuration against
+// the storage configuration of the same service.
+func (cfg *BusConfig) Validate(storageCfg *StorageConfig) error {
+	var bb BusBackend
+	if err := bb.Set(cfg.Backend); err != nil {
+		return err
+	}
+	var sb StorageBackend
+	if bb == BusPostgres && (storageCfg == nil || sb.Set(storageCfg.Backend) != nil || sb != BackendPostgres) {
+		return fmt.Errorf("bus backend '%s' requires the postgres storage backend", cfg.Backend)
+	}
+	return nil
+}
+
 // StorageBackend is a storage backend.
 type StorageBackend uint
 
@@ -194,11 +532,23 @@ func (sb *StorageBackend) Type() string {
 
 // StorageConfig contains the storage layer configuration.
 type StorageConfig struct {
-	// Endpoint is the storage endpoint from which to read/write indexed data.
+	// Endpoint is the storage endpoint of the primary, to which indexed
+	// data is written, and from which it is read unless read endpoints
+	// are provided.
 	Endpoint string `koanf:"endpoint"`
 
+	// ReadEndpoints are the storage endpoints of read replicas of the
+	// primary, between which reads are balanced. Writes, and reads that
+	// must see preceding writes, are still sent to the primary.
+	ReadEndpoints []string `koanf:"read_endpoints"`
+
 	// Backend is the storage backend to select.
 	Backend string `koanf:"backend"`
+
+	// Pool is the connection pool configuration, which applies to the
+	// pools of the primary and of each read replica. Omitting this
+	// parameter uses the settings of the endpoints, or the defaults.
+	Pool *PoolConfig `koanf:"pool"`
 }
 
 // Validate validates the storage configuration.
@@ -206,10 +556,68 @@ func (cfg *StorageConfig) Validate() error {
 	if cfg.Endpoint == "" {
 		return fmt.Errorf("malformed storage endpoint '%s'", cfg.Endpoint)
 	}
+	for _, endpoint := range cfg.ReadEndpoints {
+		if endpoint == "" {
+			return fmt.Errorf("malformed storage read endpoint '%s'", endpoint)
+		}
+	}
+	if cfg.Pool != nil {
+		if err := cfg.Pool.Validate(); err != nil {
+			return fmt.Errorf("pool: %w", err)
+		}
+	}
 	var sb StorageBackend
 	return sb.Set(cfg.Backend)
 }
 
+// PoolConfig contains the connection pool configuration. Durations should
+// be specified as strings compliant with time.ParseDuration
+// (https://pkg.go.dev/time#ParseDuration).
+type PoolConfig struct {
+	// MaxConns is the maximum number of connections of a pool.
+	MaxConns int32 `koanf:"max_conns"`
+
+	// MinConns is the number of connections a pool keeps open.
+	MinConns int32 `koanf:"min_conns"`
+
+	// MaxConnLifetime is how long a connection is used before
+	// it is replaced.
+	MaxConnLifetime string `koanf:"max_conn_lifetime"`
+
+	// MaxConnIdleTime is how long a connection may be idle before
+	// it is closed.
+	MaxConnIdleTime string `koanf:"max_conn_idle_time"`
+
+	// HealthCheckPeriod is the interval at which idle connections
+	// are checked and closed if they are broken.
+	HealthCheckPeriod string `koanf:"health_check_period"`
+
+	// StatementTimeout is how long a statement may run before it is
+	// cancelled by the database.
+	StatementTimeout string `koanf:"statement_timeout"`
+}
+
+// Validate validates the connection pool configuration.
+func (cfg *PoolConfig) Validate() error {
+	if cfg.MaxConns < 0 || cfg.MinConns < 0 || (cfg.MaxConns > 0 && cfg.MinConns > cfg.MaxConns) {
+		return fmt.Errorf("malformed pool size from %d to %d connections", cfg.MinConns, cfg.MaxConns)
+	}
+	for name, d := range map[string]string{
+		"max_conn_lifetime":   cfg.MaxConnLifetime,
+		"max_conn_idle_time":  cfg.MaxConnIdleTime,
+		"health_check_period": cfg.HealthCheckPeriod,
+		"statement_timeout":   cfg.StatementTimeout,
+	} {
+		if d == "" {
+			continue
+		}
+		if v, err := time.ParseDuration(d); err != nil || v < 0 {
+			return fmt.Errorf("malformed %s '%s'", name, d)
+		}
+	}
+	return nil
+}
+
 // LogConfig contains the logging configuration.
 type LogConfig struct {
 	Format string `koanf:"format"`
@@ -0,0 +1,57 @@
+package health
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/oasisprotocol/oasis-indexer/storage"
+)
+
+// StorageCheck returns a check that target storage can be queried.
+func StorageCheck(db storage.TargetStorage) Check {
+	return func(ctx context.Context) error {
+		var one int
+		if err := db.QueryRow(ctx, "SELECT 1").Scan(&one); err != nil {
+			return fmt.Errorf("target storage unreachable: %w", err)
+		}
+		return nil
+	}
+}
+
+// MigrationsCheck returns a check that the migrations of target storage
+// are applied up to at least the provided version, and that none failed.
+func MigrationsCheck(db storage.TargetStorage, version uint) Check {
+	return func(ctx context.Context) error {
+		var current int64
+		var dirty bool
+		if err := db.QueryRow(
+			ctx,
+			"SELECT version, dirty FROM schema_migrations",
+		).Scan(&current, &dirty); err != nil {
+			return fmt.Errorf("migration version unavailable: %w", err)
+		}
+		if dirty {
+			return fmt.Errorf("migration %d failed", current)
+		}
+		if current < int64(version) {
+			return fmt.Errorf("migrated to version %d, expected at least %d", current, version)
+		}
+		return nil
+	}
+}
+
+// LagCheck returns a check that the latest time, such as that of the
+// latest indexed block, lags behind the current time by at most maxLag.
+func LagCheck(latest func(ctx context.Context) (time.Time, error), maxLag time.Duration) Check {
+	return func(ctx context.Context) error {
+		t, err := latest(ctx)
+		if err != nil {
+			return err
+		}
+		if lag := time.Since(t); lag > maxLag {
+			return fmt.Errorf("lagging by %s, more than %s", lag.Round(time.Second), maxLag)
+		}
+		return nil
+	}
+}
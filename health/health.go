@@ -0,0 +1,185 @@
+// Package health implements the liveness and readiness probes of services.
+package health
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/oasisprotocol/oasis-indexer/log"
+)
+
+const (
+	moduleName = "health"
+
+	// LivenessPath is the path of the liveness probe.
+	LivenessPath = "/healthz"
+	// ReadinessPath is the path of the readiness probe.
+	ReadinessPath = "/readyz"
+
+	// checkTimeout is how long a readiness check may take before
+	// it is considered failed.
+	checkTimeout = 5 * time.Second
+
+	// shutdownTimeout is how long in-flight probes are given to
+	// complete when the probe server is shutting down.
+	shutdownTimeout = 5 * time.Second
+)
+
+const (
+	// StatusOK is the status of passing probes and checks.
+	StatusOK = "ok"
+	// StatusFail is the status of failing probes.
+	StatusFail = "fail"
+)
+
+// Check checks whether a dependency of a service is ready, and returns
+// an error describing why if it is not.
+type Check func(ctx context.Context) error
+
+// Report is the response of a probe.
+type Report struct {
+	// Status is the status of the service, which is StatusOK if
+	// all checks passed and StatusFail otherwise.
+	Status string `json:"status"`
+
+	// Checks are the results of the readiness checks by name. Each is
+	// StatusOK if the check passed, or the reason it failed otherwise.
+	Checks map[string]string `json:"checks,omitempty"`
+}
+
+// Mux is a router that probes are registered on, such as an
+// *http.ServeMux or a chi.Router.
+type Mux interface {
+	Handle(pattern string, handler http.Handler)
+}
+
+type namedCheck struct {
+	name  string
+	check Check
+}
+
+// Prober serves the liveness and readiness of a service. Services are
+// live as long as they serve probes, and ready once all of their
+// readiness checks pass.
+type Prober struct {
+	checks []namedCheck
+	logger *log.Logger
+}
+
+// NewProber creates a new prober without readiness checks.
+func NewProber(logger *log.Logger) *Prober {
+	return &Prober{
+		logger: logger.WithModule(moduleName),
+	}
+}
+
+// AddCheck adds a readiness check. Checks must be added before
+// probes are served.
+func (p *Prober) AddCheck(name string, check Check) {
+	p.checks = append(p.checks, namedCheck{name, check})
+}
+
+// Ready runs the readiness checks concurrently, and reports their results.
+func (p *Prober) Ready(ctx context.Context) *Report {
+	ctx, cancel := context.WithTimeout(ctx, checkTimeout)
+	defer cancel()
+
+	results := make([]error, len(p.checks))
+	var wg sync.WaitGroup
+	for i, c := range p.checks {
+		wg.Add(1)
+		go func(i int, check Check) {
+			defer wg.Done()
+			results[i] = check(ctx)
+		}(i, c.check)
+	}
+	wg.Wait()
+
+	report := Report{
+		Status: StatusOK,
+		Checks: make(map[string]string, len(p.checks)),
+	}
+	for i, c := range p.checks {
+		err := results[i]
+		if err == nil {
+			report.Checks[c.name] = StatusOK
+			continue
+		}
+		if errors.Is(err, context.DeadlineExceeded) {
+			err = errors.New("timed out")
+		}
+		report.Status = StatusFail
+		report.Checks[c.name] = err.Error()
+	}
+	return &report
+}
+
+// Register registers the liveness and readiness probes on a router.
+func (p *Prober) Register(mux Mux) {
+	mux.Handle(LivenessPath, http.HandlerFunc(p.serveLiveness))
+	mux.Handle(ReadinessPath, http.HandlerFunc(p.serveReadiness))
+}
+
+// Serve serves the probes from an endpoint, for services that do not
+// serve HTTP otherwise. It returns once the provided context is cancelled.
+func (p *Prober) Serve(ctx context.Context, endpoint string) error {
+	mux := http.NewServeMux()
+	p.Register(mux)
+	server := &http.Server{
+		Addr:              endpoint,
+		Handler:           mux,
+		ReadHeaderTimeout: checkTimeout,
+	}
+
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- server.ListenAndServe()
+	}()
+
+	select {
+	case err := <-errCh:
+		return err
+	case <-ctx.Done():
+	}
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+	defer cancel()
+	if err := server.Shutdown(shutdownCtx); err != nil {
+		return err
+	}
+	if err := <-errCh; !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	return nil
+}
+
+func (p *Prober) serveLiveness(w http.ResponseWriter, r *http.Request) {
+	p.reply(w, http.StatusOK, &Report{Status: StatusOK})
+}
+
+func (p *Prober) serveReadiness(w http.ResponseWriter, r *http.Request) {
+	report := p.Ready(r.Context())
+	if report.Status != StatusOK {
+		p.logger.Warn("not ready",
+			"checks", report.Checks,
+		)
+		p.reply(w, http.StatusServiceUnavailable, report)
+		return
+	}
+	p.reply(w, http.StatusOK, report)
+}
+
+func (p *Prober) reply(w http.ResponseWriter, status int, report *Report) {
+	w.Header().Set("content-type", "application/json")
+	w.Header().Set("cache-control", "no-store")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(report); err != nil {
+		p.logger.Error("failed to write probe response",
+			"error", err,
+		)
+	}
+}
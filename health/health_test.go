@@ -0,0 +1,115 @@
+package health
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v4"
+	"github.com/stretchr/testify/require"
+
+	"github.com/oasisprotocol/oasis-indexer/log"
+	"github.com/oasisprotocol/oasis-indexer/storage"
+)
+
+// migrationsStorage is a target storage whose migrations are at a version.
+type migrationsStorage struct {
+	storage.TargetStorage
+	version int64
+	dirty   bool
+	err     error
+}
+
+func (s *migrationsStorage) QueryRow(ctx context.Context, sql string, args ...interface{}) storage.QueryResult {
+	return migrationsRow{s}
+}
+
+type migrationsRow struct {
+	s *migrationsStorage
+}
+
+func (r migrationsRow) Scan(dest ...interface{}) error {
+	if r.s.err != nil {
+		return r.s.err
+	}
+	switch len(dest) {
+	case 1:
+		*dest[0].(*int) = 1
+	case 2:
+		*dest[0].(*int64) = r.s.version
+		*dest[1].(*bool) = r.s.dirty
+	}
+	return nil
+}
+
+func probe(t *testing.T, p *Prober, path string) (int, Report) {
+	mux := http.NewServeMux()
+	p.Register(mux)
+	w := httptest.NewRecorder()
+	mux.ServeHTTP(w, httptest.NewRequest(http.MethodGet, path, nil))
+
+	var report Report
+	require.Nil(t, json.Unmarshal(w.Body.Bytes(), &report))
+	return w.Code, report
+}
+
+func TestProber(t *testing.T) {
+	p := NewProber(log.NewDefaultLogger("health"))
+
+	// Services without checks are ready.
+	code, report := probe(t, p, ReadinessPath)
+	require.Equal(t, http.StatusOK, code)
+	require.Equal(t, StatusOK, report.Status)
+
+	fail := errors.New("unreachable")
+	p.AddCheck("ok", func(ctx context.Context) error { return nil })
+	p.AddCheck("fail", func(ctx context.Context) error { return fail })
+	code, report = probe(t, p, ReadinessPath)
+	require.Equal(t, http.StatusServiceUnavailable, code)
+	require.Equal(t, Report{
+		Status: StatusFail,
+		Checks: map[string]string{"ok": StatusOK, "fail": "unreachable"},
+	}, report)
+
+	// Services are live even when they are not ready.
+	code, report = probe(t, p, LivenessPath)
+	require.Equal(t, http.StatusOK, code)
+	require.Equal(t, Report{Status: StatusOK}, report)
+}
+
+func TestChecks(t *testing.T) {
+	ctx := context.Background()
+
+	for _, tc := range []struct {
+		s   *migrationsStorage
+		err string
+	}{
+		{s: &migrationsStorage{version: 13}},
+		{s: &migrationsStorage{version: 14}},
+		{s: &migrationsStorage{version: 12}, err: "migrated to version 12, expected at least 13"},
+		{s: &migrationsStorage{version: 13, dirty: true}, err: "migration 13 failed"},
+		{s: &migrationsStorage{err: pgx.ErrNoRows}, err: "migration version unavailable: no rows in result set"},
+	} {
+		err := MigrationsCheck(tc.s, 13)(ctx)
+		if tc.err == "" {
+			require.Nil(t, err)
+		} else {
+			require.EqualError(t, err, tc.err)
+		}
+	}
+
+	require.Nil(t, StorageCheck(&migrationsStorage{})(ctx))
+	require.EqualError(t, StorageCheck(&migrationsStorage{err: errors.New("connection refused")})(ctx), "target storage unreachable: connection refused")
+
+	latest := func(d time.Duration) func(context.Context) (time.Time, error) {
+		return func(context.Context) (time.Time, error) {
+			return time.Now().Add(-d), nil
+		}
+	}
+	require.Nil(t, LagCheck(latest(time.Second), time.Minute)(ctx))
+	require.EqualError(t, LagCheck(latest(time.Hour), time.Minute)(ctx), "lagging by 1h0m0s, more than 1m0s")
+}
@@ -0,0 +1,144 @@
+package metrics
+
+import (
+	"sync"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+var (
+	// Labels to use for partitioning analyzer progress.
+	analysisLabels = []string{"analyzer", "chain"}
+
+	// Labels to use for partitioning source storage latencies.
+	sourceLatencyLabels = []string{"analyzer", "chain", "method"}
+
+	// Labels to use for partitioning module handler latencies.
+	moduleLatencyLabels = []string{"analyzer", "chain", "module"}
+
+	// Labels to use for partitioning skipped transactions.
+	skippedTransactionLabels = []string{"analyzer", "chain", "reason"}
+
+	// Analysis metrics are shared by all analyzers and partitioned by
+	// analyzer and chain, so they are only registered once.
+	analysisLatestHeights = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "analysis_latest_height",
+			Help: "The latest height processed by the analyzer, partitioned by analyzer and chain.",
+		},
+		analysisLabels,
+	)
+	analysisChainHeads = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "analysis_chain_head",
+			Help: "The latest height available from the analyzer's source, partitioned by analyzer and chain.",
+		},
+		analysisLabels,
+	)
+	analysisSourceLatencies = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Name:    "analysis_source_latencies",
+			Help:    "How long source storage fetches take, partitioned by analyzer, chain, and method.",
+			Buckets: prometheus.ExponentialBuckets(0.005, 2, 12),
+		},
+		sourceLatencyLabels,
+	)
+	analysisModuleLatencies = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Name:    "analysis_module_latencies",
+			Help:    "How long module handlers take to prepare data, partitioned by analyzer, chain, and module.",
+			Buckets: prometheus.ExponentialBuckets(0.005, 2, 12),
+		},
+		moduleLatencyLabels,
+	)
+	analysisBatchSizes = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Name:    "analysis_batch_sizes",
+			Help:    "How many queries are sent per batch, partitioned by analyzer and chain.",
+			Buckets: prometheus.ExponentialBuckets(1, 2, 14),
+		},
+		analysisLabels,
+	)
+	analysisRetries = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "analysis_retries",
+			Help: "How many times processing a height was retried, partitioned by analyzer and chain.",
+		},
+		analysisLabels,
+	)
+	analysisSkippedTransactions = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "analysis_skipped_transactions",
+			Help: "How many transactions were not indexed, partitioned by analyzer, chain, and reason.",
+		},
+		skippedTransactionLabels,
+	)
+
+	registerAnalysisMetrics sync.Once
+)
+
+// Default service metrics for analyzers.
+type AnalysisMetrics struct {
+	analyzer string
+	chain    string
+}
+
+// NewDefaultAnalysisMetrics creates Prometheus metric instrumentation
+// for basic metrics common to analyzers. Default metrics include:
+//
+// 1. The latest processed height and the chain head.
+// 2. Latencies for source storage fetches and module handlers.
+// 3. Sizes of query batches sent to target storage.
+// 4. Counts of retries and skipped transactions.
+func NewDefaultAnalysisMetrics(analyzer string, chain string) AnalysisMetrics {
+	registerAnalysisMetrics.Do(func() {
+		prometheus.MustRegister(analysisLatestHeights)
+		prometheus.MustRegister(analysisChainHeads)
+		prometheus.MustRegister(analysisSourceLatencies)
+		prometheus.MustRegister(analysisModuleLatencies)
+		prometheus.MustRegister(analysisBatchSizes)
+		prometheus.MustRegister(analysisRetries)
+		prometheus.MustRegister(analysisSkippedTransactions)
+	})
+	return AnalysisMetrics{
+		analyzer: analyzer,
+		chain:    chain,
+	}
+}
+
+// LatestHeight returns the gauge for the latest processed height.
+func (m *AnalysisMetrics) LatestHeight() prometheus.Gauge {
+	return analysisLatestHeights.WithLabelValues(m.analyzer, m.chain)
+}
+
+// ChainHead returns the gauge for the latest height available from source.
+func (m *AnalysisMetrics) ChainHead() prometheus.Gauge {
+	return analysisChainHeads.WithLabelValues(m.analyzer, m.chain)
+}
+
+// SourceTimer returns a new latency timer for the provided
+// source storage method.
+func (m *AnalysisMetrics) SourceTimer(method string) *prometheus.Timer {
+	return prometheus.NewTimer(analysisSourceLatencies.WithLabelValues(m.analyzer, m.chain, method))
+}
+
+// ModuleTimer returns a new latency timer for the provided module handler.
+func (m *AnalysisMetrics) ModuleTimer(module string) *prometheus.Timer {
+	return prometheus.NewTimer(analysisModuleLatencies.WithLabelValues(m.analyzer, m.chain, module))
+}
+
+// BatchSize returns the observer for query batch sizes.
+func (m *AnalysisMetrics) BatchSize() prometheus.Observer {
+	return analysisBatchSizes.WithLabelValues(m.analyzer, m.chain)
+}
+
+// RetryCounter returns the counter for processing retries.
+func (m *AnalysisMetrics) RetryCounter() prometheus.Counter {
+	return analysisRetries.WithLabelValues(m.analyzer, m.chain)
+}
+
+// SkippedTransactionCounter returns the counter for transactions skipped
+// for the provided reason.
+func (m *AnalysisMetrics) SkippedTransactionCounter(reason string) prometheus.Counter {
+	return analysisSkippedTransactions.WithLabelValues(m.analyzer, m.chain, reason)
+}
@@ -0,0 +1,88 @@
+package metrics
+
+import (
+	"fmt"
+
+	"github.com/jackc/pgx/v4/pgxpool"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// Labels to use for partitioning connection pool statistics.
+var poolLabels = []string{"pool"}
+
+// PoolStats returns the statistics of connection pools by name.
+type PoolStats func() map[string]*pgxpool.Stat
+
+// poolMetric is a metric of connection pools, which is read from
+// their statistics.
+type poolMetric struct {
+	desc      *prometheus.Desc
+	valueType prometheus.ValueType
+	value     func(s *pgxpool.Stat) float64
+}
+
+// PoolMetrics are metrics of the connection pools of target storage,
+// which are read from their statistics whenever metrics are collected.
+type PoolMetrics struct {
+	stats   PoolStats
+	metrics []poolMetric
+}
+
+var _ prometheus.Collector = (*PoolMetrics)(nil)
+
+// NewDefaultPoolMetrics creates Prometheus metric instrumentation of
+// connection pools, partitioned by pool. Default metrics include:
+//
+// 1. Counts of connections, by state, and the maximum.
+// 2. Counts and total duration of connection acquisitions.
+// 3. Counts of acquisitions that waited or were cancelled.
+func NewDefaultPoolMetrics(pkg string, stats PoolStats) *PoolMetrics {
+	metric := func(name string, help string, valueType prometheus.ValueType, value func(s *pgxpool.Stat) float64) poolMetric {
+		return poolMetric{
+			desc:      prometheus.NewDesc(fmt.Sprintf("%s_db_pool_%s", pkg, name), help, poolLabels, nil),
+			valueType: valueType,
+			value:     value,
+		}
+	}
+	m := &PoolMetrics{
+		stats: stats,
+		metrics: []poolMetric{
+			metric("total_conns", "How many connections are open, partitioned by pool.", prometheus.GaugeValue,
+				func(s *pgxpool.Stat) float64 { return float64(s.TotalConns()) }),
+			metric("acquired_conns", "How many connections are in use, partitioned by pool.", prometheus.GaugeValue,
+				func(s *pgxpool.Stat) float64 { return float64(s.AcquiredConns()) }),
+			metric("idle_conns", "How many connections are idle, partitioned by pool.", prometheus.GaugeValue,
+				func(s *pgxpool.Stat) float64 { return float64(s.IdleConns()) }),
+			metric("constructing_conns", "How many connections are being opened, partitioned by pool.", prometheus.GaugeValue,
+				func(s *pgxpool.Stat) float64 { return float64(s.ConstructingConns()) }),
+			metric("max_conns", "The maximum number of connections, partitioned by pool.", prometheus.GaugeValue,
+				func(s *pgxpool.Stat) float64 { return float64(s.MaxConns()) }),
+			metric("acquires", "How many connections were acquired, partitioned by pool.", prometheus.CounterValue,
+				func(s *pgxpool.Stat) float64 { return float64(s.AcquireCount()) }),
+			metric("acquire_seconds", "How long acquiring connections took in total, partitioned by pool.", prometheus.CounterValue,
+				func(s *pgxpool.Stat) float64 { return s.AcquireDuration().Seconds() }),
+			metric("empty_acquires", "How many acquisitions waited for a connection, partitioned by pool.", prometheus.CounterValue,
+				func(s *pgxpool.Stat) float64 { return float64(s.EmptyAcquireCount()) }),
+			metric("canceled_acquires", "How many acquisitions were cancelled, partitioned by pool.", prometheus.CounterValue,
+				func(s *pgxpool.Stat) float64 { return float64(s.CanceledAcquireCount()) }),
+		},
+	}
+	prometheus.MustRegister(m)
+	return m
+}
+
+// Describe implements the prometheus.Collector interface.
+func (m *PoolMetrics) Describe(ch chan<- *prometheus.Desc) {
+	for _, metric := range m.metrics {
+		ch <- metric.desc
+	}
+}
+
+// Collect implements the prometheus.Collector interface.
+func (m *PoolMetrics) Collect(ch chan<- prometheus.Metric) {
+	for pool, stat := range m.stats() {
+		for _, metric := range m.metrics {
+			ch <- prometheus.MustNewConstMetric(metric.desc, metric.valueType, metric.value(stat), pool)
+		}
+	}
+}
@@ -12,6 +12,9 @@ var (
 
 	// Labels to use for partitioning request latencies.
 	requestLatencyLabels = []string{"endpoint"}
+
+	// Labels to use for partitioning requests made with API keys.
+	keyRequestLabels = []string{"key", "tier", "status"}
 )
 
 // Default service metrics for requests.
@@ -21,6 +24,9 @@ type RequestMetrics struct {
 
 	// Latencies of serving incoming requests.
 	RequestLatencies *prometheus.SummaryVec
+
+	// Counts of requests made with each API key.
+	KeyRequestCounts *prometheus.CounterVec
 }
 
 // NewDefaultRequestMetrics creates Prometheus metric instrumentation for
@@ -28,6 +34,7 @@ type RequestMetrics struct {
 //
 // 1. Counts of service endpoints hit.
 // 2. Latencies for requests.
+// 3. Counts of requests made with each API key.
 func NewDefaultRequestMetrics(pkg string) RequestMetrics {
 	metrics := RequestMetrics{
 		RequestCounts: prometheus.NewCounterVec(
@@ -44,9 +51,17 @@ func NewDefaultRequestMetrics(pkg string) RequestMetrics {
 			},
 			requestLatencyLabels,
 		),
+		KeyRequestCounts: prometheus.NewCounterVec(
+			prometheus.CounterOpts{
+				Name: fmt.Sprintf("%s_key_requests", pkg),
+				Help: "How many requests were made with API keys, partitioned by key ID, tier, and status.",
+			},
+			keyRequestLabels,
+		),
 	}
 	prometheus.MustRegister(metrics.RequestCounts)
 	prometheus.MustRegister(metrics.RequestLatencies)
+	prometheus.MustRegister(metrics.KeyRequestCounts)
 	return metrics
 }
 
@@ -68,3 +83,13 @@ func (m *RequestMetrics) RequestTimer(labels ...string) *prometheus.Timer {
 	labels = append(labels, make([]string, len(requestLatencyLabels)-len(labels))...)
 	return prometheus.NewTimer(m.RequestLatencies.WithLabelValues(labels...))
 }
+
+// KeyRequestCounter returns the counter for requests made with an API key.
+// Provided labels should be key, tier, and status.
+func (m *RequestMetrics) KeyRequestCounter(labels ...string) prometheus.Counter {
+	if len(labels) > len(keyRequestLabels) {
+		labels = labels[:len(keyRequestLabels)]
+	}
+	labels = append(labels, make([]string, len(keyRequestLabels)-len(labels))...)
+	return m.KeyRequestCounts.WithLabelValues(labels...)
+}
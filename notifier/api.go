@@ -0,0 +1,132 @@
+// Package notifier implements webhook notifications for indexed events.
+package notifier
+
+import (
+	"errors"
+	"math/big"
+)
+
+// ErrKindUnknown is returned if a subscription kind is not supported.
+var ErrKindUnknown = errors.New("subscription kind unknown")
+
+// Kind is the kind of event a subscription is notified of.
+type Kind string
+
+const (
+	// KindTransfer is a staking transfer between two accounts.
+	KindTransfer Kind = "transfer"
+	// KindProposalSubmitted is a newly submitted governance proposal.
+	KindProposalSubmitted Kind = "proposal_submitted"
+	// KindNodeExpired is a node whose registration expired, which removes
+	// it from the registry.
+	KindNodeExpired Kind = "node_expired"
+	// KindNodeFrozen is a node that was frozen, e.g. when its entity was
+	// slashed.
+	KindNodeFrozen Kind = "node_frozen"
+	// KindNodeUnfrozen is a node that was unfrozen.
+	KindNodeUnfrozen Kind = "node_unfrozen"
+)
+
+// Validate returns an error if the kind is not supported.
+func (k Kind) Validate() error {
+	switch k {
+	case KindTransfer, KindProposalSubmitted, KindNodeExpired, KindNodeFrozen, KindNodeUnfrozen:
+		return nil
+	default:
+		return ErrKindUnknown
+	}
+}
+
+// Event is an indexed event that subscriptions may be notified of.
+type Event struct {
+	Kind   Kind
+	Height int64
+
+	// Accounts are the addresses of the accounts involved in the event.
+	Accounts []string
+
+	// EntityID is the ID of the entity involved in the event, if any.
+	EntityID string
+
+	// Amount is the amount of tokens moved by the event, if any.
+	Amount *big.Int
+
+	// Body is the event delivered to subscribers, encoded as JSON.
+	Body interface{}
+}
+
+// Subscription is a registered subscription to indexed events.
+type Subscription struct {
+	ID     int64
+	Kind   Kind
+	URL    string
+	Secret string
+
+	// Account restricts notifications to events involving this account.
+	Account *string
+
+	// EntityID restricts notifications to events involving this entity.
+	EntityID *string
+
+	// MinAmount restricts notifications to events moving at least this amount.
+	MinAmount *big.Int
+}
+
+// Matches returns true if the subscription should be notified of the event.
+func (s *Subscription) Matches(e *Event) bool {
+	if s.Kind != e.Kind {
+		return false
+	}
+	if s.Account != nil {
+		found := false
+		for _, account := range e.Accounts {
+			if account == *s.Account {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	if s.EntityID != nil && *s.EntityID != e.EntityID {
+		return false
+	}
+	if s.MinAmount != nil && (e.Amount == nil || e.Amount.Cmp(s.MinAmount) < 0) {
+		return false
+	}
+	return true
+}
+
+// Payload is the body of a webhook delivery.
+type Payload struct {
+	SubscriptionID int64       `json:"subscription_id"`
+	Kind           Kind        `json:"kind"`
+	Height         int64       `json:"height"`
+	Event          interface{} `json:"event"`
+}
+
+// TransferEvent is the body of a KindTransfer event.
+type TransferEvent struct {
+	From   string `json:"from"`
+	To     string `json:"to"`
+	Amount string `json:"amount"`
+}
+
+// ProposalSubmittedEvent is the body of a KindProposalSubmitted event.
+type ProposalSubmittedEvent struct {
+	ID        uint64 `json:"id"`
+	Submitter string `json:"submitter"`
+	Deposit   string `json:"deposit"`
+	ClosesAt  uint64 `json:"closes_at"`
+}
+
+// NodeEvent is the body of KindNodeExpired, KindNodeFrozen and
+// KindNodeUnfrozen events.
+type NodeEvent struct {
+	NodeID   string `json:"node_id"`
+	EntityID string `json:"entity_id"`
+
+	// FreezeEndTime is the epoch after which a frozen node may be unfrozen.
+	FreezeEndTime uint64 `json:"freeze_end_time,omitempty"`
+}
@@ -0,0 +1,406 @@
+package notifier
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"net"
+	"net/http"
+	"net/url"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/iancoleman/strcase"
+
+	"github.com/oasisprotocol/oasis-indexer/analyzer/util"
+	"github.com/oasisprotocol/oasis-indexer/config"
+	"github.com/oasisprotocol/oasis-indexer/log"
+	"github.com/oasisprotocol/oasis-indexer/metrics"
+	"github.com/oasisprotocol/oasis-indexer/storage"
+)
+
+const (
+	moduleName = "notifier"
+
+	// Headers set on every webhook delivery.
+	SignatureHeader = "X-Indexer-Signature"
+	TimestampHeader = "X-Indexer-Timestamp"
+	DeliveryHeader  = "X-Indexer-Delivery"
+
+	// deliveryQueueSize is the number of deliveries that may be pending.
+	deliveryQueueSize = 1024
+
+	// deadLetterTimeout bounds how long storing a dead letter may take,
+	// which may happen after the notifier has been asked to stop.
+	deadLetterTimeout = 10 * time.Second
+)
+
+var (
+	// errQueueFull is recorded for deliveries dropped because too many are pending.
+	errQueueFull = errors.New("delivery queue full")
+
+	// errStopped is recorded for deliveries still pending when the notifier stops.
+	errStopped = errors.New("notifier stopped")
+)
+
+// delivery is a pending delivery of a payload to a subscription.
+type delivery struct {
+	subscription *Subscription
+	height       int64
+	body         []byte
+}
+
+// Notifier delivers indexed events to matching webhook subscriptions.
+//
+// Analyzers stage events while preparing a batch and commit them once
+// the batch has been committed to target storage, so that subscribers are
+// only notified of events that have actually been indexed.
+//
+// A nil *Notifier is valid and discards all events.
+type Notifier struct {
+	qf          QueryFactory
+	target      storage.TargetStorage
+	client      *http.Client
+	logger      *log.Logger
+	metrics     metrics.DatabaseMetrics
+	workers     int
+	maxAttempts int
+
+	deliveries chan *delivery
+
+	mu     sync.Mutex
+	staged map[int64][]*Event
+}
+
+// NewNotifier creates a new webhook notifier for the provided chain.
+func NewNotifier(chainID string, cfg *config.NotifierConfig, target storage.TargetStorage, logger *log.Logger) (*Notifier, error) {
+	timeout, err := time.ParseDuration(cfg.Timeout)
+	if err != nil {
+		return nil, err
+	}
+
+	// Deliveries are only made to public addresses, and redirects are not
+	// followed, so that subscriptions cannot reach the network of the
+	// indexer.
+	dialer := &net.Dialer{Control: dialControl}
+	client := &http.Client{
+		Timeout:   timeout,
+		Transport: &http.Transport{DialContext: dialer.DialContext},
+		CheckRedirect: func(*http.Request, []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+
+	return &Notifier{
+		qf:          NewQueryFactory(strcase.ToSnake(chainID)),
+		target:      target,
+		client:      client,
+		logger:      logger.WithModule(moduleName),
+		metrics:     metrics.NewDefaultDatabaseMetrics(moduleName),
+		workers:     cfg.Workers,
+		maxAttempts: cfg.MaxAttempts,
+		deliveries:  make(chan *delivery, deliveryQueueSize),
+		staged:      make(map[int64][]*Event),
+	}, nil
+}
+
+// Start starts the delivery workers. It returns once the provided context
+// is cancelled; deliveries interrupted by cancellation, and deliveries still
+// queued, are stored as dead letters.
+func (n *Notifier) Start(ctx context.Context) {
+	if n == nil {
+		return
+	}
+
+	n.logger.Info("starting notifier")
+
+	var wg sync.WaitGroup
+	for i := 0; i < n.workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			n.deliveryWorker(ctx)
+		}()
+	}
+	wg.Wait()
+
+	n.drain()
+}
+
+// drain stores the deliveries still queued as dead letters, so that they
+// are not lost on shutdown.
+func (n *Notifier) drain() {
+	batch := &storage.QueryBatch{}
+queued:
+	for {
+		select {
+		case d := <-n.deliveries:
+			n.queueDeadLetter(batch, d, 0, errStopped)
+		default:
+			break queued
+		}
+	}
+	if batch.Len() == 0 {
+		return
+	}
+
+	n.logger.Info("storing queued deliveries as dead letters",
+		"deliveries", batch.Len(),
+	)
+	n.sendDeadLetters(batch)
+}
+
+// Stage stages events at the provided height, to be delivered once
+// the height has been committed.
+func (n *Notifier) Stage(height int64, events ...*Event) {
+	if n == nil || len(events) == 0 {
+		return
+	}
+
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	n.staged[height] = append(n.staged[height], events...)
+}
+
+// Discard discards events staged at the provided height.
+func (n *Notifier) Discard(height int64) {
+	if n == nil {
+		return
+	}
+
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	delete(n.staged, height)
+}
+
+// Commit queues events staged at the provided height for delivery
+// to all matching subscriptions.
+//
+// Failures are logged rather than returned, since notifications must
+// not hold back indexing.
+func (n *Notifier) Commit(ctx context.Context, height int64) {
+	if n == nil {
+		return
+	}
+
+	n.mu.Lock()
+	events := n.staged[height]
+	delete(n.staged, height)
+	n.mu.Unlock()
+
+	if len(events) == 0 {
+		return
+	}
+
+	subscriptions, err := n.subscriptions(ctx)
+	if err != nil {
+		n.logger.Error("failed to load subscriptions",
+			"height", height,
+			"err", err,
+		)
+		return
+	}
+
+	for _, e := range events {
+		for _, s := range subscriptions {
+			if !s.Matches(e) {
+				continue
+			}
+
+			body, err := json.Marshal(&Payload{
+				SubscriptionID: s.ID,
+				Kind:           e.Kind,
+				Height:         e.Height,
+				Event:          e.Body,
+			})
+			if err != nil {
+				n.logger.Error("failed to marshal payload",
+					"subscription_id", s.ID,
+					"height", height,
+					"err", err,
+				)
+				continue
+			}
+
+			d := &delivery{s, e.Height, body}
+			select {
+			case n.deliveries <- d:
+			default:
+				// Deliveries are falling behind, but indexing must not.
+				n.deadLetter(d, 0, errQueueFull)
+			}
+		}
+	}
+}
+
+// subscriptions returns all registered subscriptions. They are read from the
+// primary, so that newly created and deleted subscriptions apply at once.
+func (n *Notifier) subscriptions(ctx context.Context) ([]*Subscription, error) {
+	rows, err := n.target.Query(storage.WithPrimary(ctx), n.qf.SubscriptionsQuery())
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var subscriptions []*Subscription
+	for rows.Next() {
+		var s Subscription
+		var minAmount *string
+		if err := rows.Scan(
+			&s.ID,
+			&s.Kind,
+			&s.URL,
+			&s.Secret,
+			&s.Account,
+			&s.EntityID,
+			&minAmount,
+		); err != nil {
+			return nil, err
+		}
+		if minAmount != nil {
+			amount, ok := new(big.Int).SetString(*minAmount, 10)
+			if !ok {
+				return nil, fmt.Errorf("malformed minimum amount '%s' for subscription %d", *minAmount, s.ID)
+			}
+			s.MinAmount = amount
+		}
+		subscriptions = append(subscriptions, &s)
+	}
+
+	return subscriptions, rows.Err()
+}
+
+// deliveryWorker delivers queued payloads until the context is cancelled.
+func (n *Notifier) deliveryWorker(ctx context.Context) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case d := <-n.deliveries:
+			n.deliver(ctx, d)
+		}
+	}
+}
+
+// deliver delivers a payload, retrying with backoff on failure. Payloads that
+// could not be delivered are stored in the dead letter table.
+func (n *Notifier) deliver(ctx context.Context, d *delivery) {
+	backoff, err := util.NewBackoff(time.Second, 30*time.Second)
+	if err != nil {
+		n.logger.Error("error configuring delivery backoff policy",
+			"err", err,
+		)
+		return
+	}
+
+	deliveryID := uuid.New().String()
+
+	var attempts int
+	for attempts < n.maxAttempts {
+		attempts++
+		if err = n.post(ctx, deliveryID, d); err == nil {
+			return
+		}
+
+		n.logger.Info("webhook delivery failed",
+			"subscription_id", d.subscription.ID,
+			"delivery_id", deliveryID,
+			"attempt", attempts,
+			"err", err,
+		)
+		if attempts == n.maxAttempts || backoff.WaitContext(ctx) != nil {
+			break
+		}
+	}
+
+	n.deadLetter(d, attempts, err)
+}
+
+// post performs a single delivery attempt.
+func (n *Notifier) post(ctx context.Context, deliveryID string, d *delivery) error {
+	if u, err := url.Parse(d.subscription.URL); err != nil || u.Scheme != "https" {
+		return fmt.Errorf("%w: '%s' is not an https url", ErrURLNotAllowed, d.subscription.URL)
+	}
+	timestamp := time.Now().Unix()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, d.subscription.URL, bytes.NewReader(d.body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("content-type", "application/json")
+	req.Header.Set(DeliveryHeader, deliveryID)
+	req.Header.Set(TimestampHeader, strconv.FormatInt(timestamp, 10))
+	req.Header.Set(SignatureHeader, Sign(d.subscription.Secret, timestamp, d.body))
+
+	resp, err := n.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("unexpected status code %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// deadLetter stores a payload that could not be delivered.
+func (n *Notifier) deadLetter(d *delivery, attempts int, lastErr error) {
+	batch := &storage.QueryBatch{}
+	n.queueDeadLetter(batch, d, attempts, lastErr)
+	n.sendDeadLetters(batch)
+}
+
+// queueDeadLetter queues storing a payload that could not be delivered.
+func (n *Notifier) queueDeadLetter(batch *storage.QueryBatch, d *delivery, attempts int, lastErr error) {
+	lastError := ""
+	if lastErr != nil {
+		lastError = lastErr.Error()
+	}
+
+	batch.Queue(n.qf.DeadLetterInsertQuery(),
+		d.subscription.ID,
+		d.height,
+		string(d.body),
+		attempts,
+		lastError,
+	)
+}
+
+// sendDeadLetters stores the queued dead letters.
+func (n *Notifier) sendDeadLetters(batch *storage.QueryBatch) {
+	ctx, cancel := context.WithTimeout(context.Background(), deadLetterTimeout)
+	defer cancel()
+
+	opName := "webhook_dead_letter"
+	timer := n.metrics.DatabaseTimer(n.target.Name(), opName)
+	defer timer.ObserveDuration()
+
+	if err := n.target.SendBatch(ctx, batch); err != nil {
+		n.metrics.DatabaseCounter(n.target.Name(), opName, "failure").Inc()
+		n.logger.Error("failed to store dead letters",
+			"dead_letters", batch.Len(),
+			"err", err,
+		)
+		return
+	}
+	n.metrics.DatabaseCounter(n.target.Name(), opName, "success").Inc()
+}
+
+// Sign returns the signature of a webhook delivery, which is the hex-encoded
+// HMAC-SHA256 of "<timestamp>.<body>" keyed by the subscription secret.
+// Receivers should recompute it to authenticate deliveries.
+func Sign(secret string, timestamp int64, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	fmt.Fprintf(mac, "%d.", timestamp)
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
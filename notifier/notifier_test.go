@@ -0,0 +1,138 @@
+package notifier
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/oasisprotocol/oasis-indexer/config"
+	"github.com/oasisprotocol/oasis-indexer/log"
+	"github.com/oasisprotocol/oasis-indexer/storage"
+)
+
+func TestKindValidate(t *testing.T) {
+	for _, k := range []Kind{
+		KindTransfer,
+		KindProposalSubmitted,
+		KindNodeExpired,
+		KindNodeFrozen,
+		KindNodeUnfrozen,
+	} {
+		require.Nil(t, k.Validate())
+	}
+	require.ErrorIs(t, Kind("unknown").Validate(), ErrKindUnknown)
+}
+
+func TestSubscriptionMatches(t *testing.T) {
+	account := "oasis1qrvsa8ukfw3p6kw2vcs0fk9t59mceqq7fyttwqgx"
+	entityID := "gb8SHLeDc69Elk7OTfqhtVgE2sqxrBCDQI84xKR+Bjg="
+
+	transfer := &Event{
+		Kind:     KindTransfer,
+		Height:   1,
+		Accounts: []string{"oasis1qzzd6khm3acqskpxlk9vd5044cmmcce78y5l6000", account},
+		Amount:   big.NewInt(1000),
+	}
+	deregistration := &Event{
+		Kind:     KindNodeExpired,
+		Height:   1,
+		EntityID: entityID,
+	}
+
+	for _, tc := range []struct {
+		name         string
+		subscription Subscription
+		event        *Event
+		matches      bool
+	}{
+		{"kind", Subscription{Kind: KindTransfer}, transfer, true},
+		{"other kind", Subscription{Kind: KindProposalSubmitted}, transfer, false},
+		{"account", Subscription{Kind: KindTransfer, Account: &account}, transfer, true},
+		{"other account", Subscription{Kind: KindTransfer, Account: &entityID}, transfer, false},
+		{"amount", Subscription{Kind: KindTransfer, MinAmount: big.NewInt(1000)}, transfer, true},
+		{"small amount", Subscription{Kind: KindTransfer, MinAmount: big.NewInt(1001)}, transfer, false},
+		{"no amount", Subscription{Kind: KindNodeExpired, MinAmount: big.NewInt(1)}, deregistration, false},
+		{"entity", Subscription{Kind: KindNodeExpired, EntityID: &entityID}, deregistration, true},
+		{"other entity", Subscription{Kind: KindNodeExpired, EntityID: &account}, deregistration, false},
+	} {
+		require.Equal(t, tc.matches, tc.subscription.Matches(tc.event), tc.name)
+	}
+}
+
+func TestSign(t *testing.T) {
+	secret := "secret"
+	body := []byte(`{"kind":"transfer"}`)
+
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(`1660000000.{"kind":"transfer"}`))
+	expected := "sha256=" + hex.EncodeToString(mac.Sum(nil))
+
+	require.Equal(t, expected, Sign(secret, 1660000000, body))
+	require.NotEqual(t, expected, Sign(secret, 1660000001, body))
+	require.NotEqual(t, expected, Sign("other", 1660000000, body))
+}
+
+func TestValidateURL(t *testing.T) {
+	ctx := context.Background()
+
+	require.Nil(t, ValidateURL(ctx, "https://203.0.113.10/hooks/oasis"))
+	require.Nil(t, ValidateURL(ctx, "https://[2001:db8::1]:8443/hooks"))
+
+	for _, u := range []string{
+		"http://203.0.113.10/hooks/oasis",
+		"https:///hooks/oasis",
+		"https://127.0.0.1/hooks/oasis",
+		"https://[::1]/hooks/oasis",
+		"https://10.0.0.1/hooks/oasis",
+		"https://172.16.0.1/hooks/oasis",
+		"https://192.168.1.1/hooks/oasis",
+		"https://169.254.169.254/latest/meta-data",
+		"https://[fe80::1]/hooks/oasis",
+		"https://100.64.0.1/hooks/oasis",
+		"https://0.0.0.0/hooks/oasis",
+		"https://[::ffff:127.0.0.1]/hooks/oasis",
+	} {
+		require.ErrorIs(t, ValidateURL(ctx, u), ErrURLNotAllowed, u)
+	}
+}
+
+// deadLetterStorage records the dead letters sent to it.
+type deadLetterStorage struct {
+	storage.TargetStorage
+	deadLetters int
+}
+
+func (s *deadLetterStorage) SendBatch(ctx context.Context, batch *storage.QueryBatch) error {
+	s.deadLetters += batch.Len()
+	return nil
+}
+
+func (s *deadLetterStorage) Name() string {
+	return "dead_letters"
+}
+
+func TestStartDrainsDeliveries(t *testing.T) {
+	target := &deadLetterStorage{}
+	n, err := NewNotifier("oasis_3", &config.NotifierConfig{
+		MaxAttempts: 1,
+		Timeout:     "1s",
+	}, target, log.NewDefaultLogger("notifier"))
+	require.Nil(t, err)
+
+	s := &Subscription{ID: 1, Kind: KindTransfer}
+	for i := 0; i < 3; i++ {
+		n.deliveries <- &delivery{s, int64(i), []byte("{}")}
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	n.Start(ctx)
+
+	require.Equal(t, 3, target.deadLetters)
+	require.Len(t, n.deliveries, 0)
+}
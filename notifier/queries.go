@@ -0,0 +1,28 @@
+package notifier
+
+import (
+	"fmt"
+)
+
+// QueryFactory is a convenience type for creating notifier queries.
+type QueryFactory struct {
+	chainID string
+}
+
+func NewQueryFactory(chainID string) QueryFactory {
+	return QueryFactory{chainID}
+}
+
+func (qf QueryFactory) SubscriptionsQuery() string {
+	return fmt.Sprintf(`
+		SELECT %[1]s.webhook_subscriptions.id, kind, url, secret, account, entity_id, min_amount::text
+			FROM %[1]s.webhook_subscriptions
+			JOIN api_keys ON %[1]s.webhook_subscriptions.api_key_id = api_keys.id
+			WHERE api_keys.revoked_at IS NULL`, qf.chainID)
+}
+
+func (qf QueryFactory) DeadLetterInsertQuery() string {
+	return fmt.Sprintf(`
+		INSERT INTO %s.webhook_dead_letters (subscription_id, height, payload, attempts, last_error)
+			VALUES ($1, $2, $3, $4, $5)`, qf.chainID)
+}
@@ -0,0 +1,69 @@
+package notifier
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"syscall"
+)
+
+// ErrURLNotAllowed is returned if a webhook URL may not be delivered to.
+var ErrURLNotAllowed = errors.New("webhook url not allowed")
+
+// ValidateURL returns an error unless deliveries may be made to the URL,
+// which must be an https URL of a host that only resolves to public
+// addresses. Deliveries check the addresses they connect to again, since
+// the host may resolve differently by then.
+func ValidateURL(ctx context.Context, rawURL string) error {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return err
+	}
+	if u.Scheme != "https" || u.Hostname() == "" {
+		return fmt.Errorf("%w: '%s' is not an https url", ErrURLNotAllowed, rawURL)
+	}
+
+	addrs, err := net.DefaultResolver.LookupIPAddr(ctx, u.Hostname())
+	if err != nil {
+		return err
+	}
+	for _, addr := range addrs {
+		if err := checkIP(addr.IP); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// checkIP returns an error if the IP is not a public address, e.g. if it is
+// a loopback, private or link-local address of the network of the indexer.
+func checkIP(ip net.IP) error {
+	if ip == nil ||
+		ip.IsLoopback() ||
+		ip.IsPrivate() ||
+		ip.IsLinkLocalUnicast() ||
+		ip.IsLinkLocalMulticast() ||
+		ip.IsInterfaceLocalMulticast() ||
+		ip.IsMulticast() ||
+		ip.IsUnspecified() ||
+		sharedAddressSpace.Contains(ip) {
+		return fmt.Errorf("%w: %s is not a public address", ErrURLNotAllowed, ip)
+	}
+	return nil
+}
+
+// sharedAddressSpace is the carrier-grade NAT range of RFC 6598, which is
+// not public either.
+var sharedAddressSpace = &net.IPNet{IP: net.IPv4(100, 64, 0, 0), Mask: net.CIDRMask(10, 32)}
+
+// dialControl rejects connections to addresses that are not public, once
+// the host of a delivery has been resolved.
+func dialControl(network string, address string, _ syscall.RawConn) error {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return err
+	}
+	return checkIP(net.ParseIP(host))
+}
@@ -3,10 +3,13 @@ package storage
 
 import (
 	"context"
+	"time"
 
 	"github.com/jackc/pgx/v4"
 	beacon "github.com/oasisprotocol/oasis-core/go/beacon/api"
 	"github.com/oasisprotocol/oasis-core/go/common"
+	"github.com/oasisprotocol/oasis-core/go/common/crypto/signature"
+	"github.com/oasisprotocol/oasis-core/go/common/quantity"
 	consensus "github.com/oasisprotocol/oasis-core/go/consensus/api"
 	"github.com/oasisprotocol/oasis-core/go/consensus/api/transaction"
 	"github.com/oasisprotocol/oasis-core/go/consensus/api/transaction/results"
@@ -34,6 +37,10 @@ type QueryResult = pgx.Row
 // ConsensusSourceStorage defines an interface for retrieving raw block data
 // from the consensus layer.
 type ConsensusSourceStorage interface {
+	// LatestHeight gets the height of the latest block available from the
+	// consensus layer.
+	LatestHeight(ctx context.Context) (int64, error)
+
 	// BlockData gets block data at the specified height. This includes all
 	// block header information, as well as transactions and events included
 	// within that block.
@@ -47,6 +54,12 @@ type ConsensusSourceStorage interface {
 	// all registered entities and their controlled nodes and statuses.
 	RegistryData(ctx context.Context, height int64) (*RegistryData, error)
 
+	// NodeFreezes gets the nodes frozen at the specified height. Nodes are
+	// frozen when their entity is slashed, or for liveness failures when
+	// the epoch changes, so only the nodes of the provided slashed entities
+	// are checked, unless the epoch changed at the height.
+	NodeFreezes(ctx context.Context, height int64, slashed []staking.Address, epochChanged bool) ([]*NodeFreeze, error)
+
 	// StakingData gets staking data at the specified height. This includes
 	// staking backend events to be applied to indexed state.
 	StakingData(ctx context.Context, height int64) (*StakingData, error)
@@ -63,6 +76,10 @@ type ConsensusSourceStorage interface {
 	// runtime blocks. This is only relevant when we begin to build runtime
 	// analyzers.
 
+	// ChainContext gets the chain domain separation context of the
+	// network served by the source, which identifies the network.
+	ChainContext(ctx context.Context) (string, error)
+
 	// Name returns the name of the source storage.
 	Name() string
 }
@@ -97,10 +114,30 @@ type RegistryData struct {
 	NodeEvents         []*registry.NodeEvent
 	NodeUnfrozenEvents []*registry.NodeUnfrozenEvent
 
+	// NodeFreezes are the nodes frozen at this height. Unlike unfreezes,
+	// freezes are not signaled by events, so they are not retrieved with
+	// registry data, but with ConsensusSourceStorage.NodeFreezes once the
+	// slashed entities and the epoch of the height are known.
+	NodeFreezes []*NodeFreeze
+
+	// UnfrozenNodeEntities are the entities of the nodes unfrozen by
+	// NodeUnfrozenEvents, by node ID.
+	UnfrozenNodeEntities map[signature.PublicKey]signature.PublicKey
+
 	RuntimeSuspensions   []string
 	RuntimeUnsuspensions []string
 }
 
+// NodeFreeze is the freeze of a node, which is excluded from committees
+// until it is unfrozen.
+type NodeFreeze struct {
+	NodeID   signature.PublicKey
+	EntityID signature.PublicKey
+
+	// FreezeEndTime is the epoch after which the node may be unfrozen.
+	FreezeEndTime beacon.EpochTime
+}
+
 // StakingData represents data for accounts at a given height.
 //
 // Note: The staking backend supports getting events directly. We support
@@ -118,6 +155,7 @@ type StakingData struct {
 // SchedulerData represents data for elected committees and validators at a given height.
 type SchedulerData struct {
 	Height int64
+	Epoch  beacon.EpochTime
 
 	Validators []*scheduler.Validator
 	Committees map[common.Namespace][]*scheduler.Committee
@@ -134,11 +172,28 @@ type GovernanceData struct {
 	ProposalExecutions    []*governance.ProposalExecutedEvent
 	ProposalFinalizations []*governance.Proposal
 	Votes                 []*governance.VoteEvent
+
+	// TalliedVotes are the votes of each finalized proposal, by proposal ID.
+	TalliedVotes map[uint64][]*TalliedVote
+}
+
+// TalliedVote is a vote of a closed proposal, along with its weight in the
+// tally of the proposal.
+type TalliedVote struct {
+	Voter staking.Address
+	Vote  governance.Vote
+
+	// Weight is the escrow of the voter when the proposal closed, or nil if
+	// the vote is invalid since the voter was not in the validator set.
+	Weight *quantity.Quantity
 }
 
 // RuntimeSourceStorage defines an interface for retrieving raw block data
 // from the runtime layer.
 type RuntimeSourceStorage interface {
+	// LatestRound gets the latest round available from the runtime layer.
+	LatestRound(ctx context.Context) (uint64, error)
+
 	// BlockData gets block data in the specified round. This includes all
 	// block header information, as well as transactions and events included
 	// within that block.
@@ -153,6 +208,10 @@ type RuntimeSourceStorage interface {
 	// ConsensusAccountsData gets data in the specified round emitted by the `consensusaccounts` module.
 	ConsensusAccountsData(ctx context.Context, round uint64) (*ConsensusAccountsData, error)
 
+	// ChainContext gets the chain domain separation context of the
+	// consensus layer of the network served by the source.
+	ChainContext(ctx context.Context) (string, error)
+
 	// Name returns the name of the source storage.
 	Name() string
 }
@@ -199,16 +258,56 @@ type ConsensusAccountsData struct {
 	Withdraws []*consensusaccounts.WithdrawEvent
 }
 
+// primaryContextKey is the key of contexts in which queries are sent
+// to the primary.
+type primaryContextKey struct{}
+
+// WithPrimary returns a context in which target storage sends reads to
+// its primary rather than to read replicas. Queries that write, or that
+// must read preceding writes, must be sent with such a context.
+func WithPrimary(ctx context.Context) context.Context {
+	return context.WithValue(ctx, primaryContextKey{}, true)
+}
+
+// UsePrimary returns true if reads of a context must be sent to the primary.
+func UsePrimary(ctx context.Context) bool {
+	primary, _ := ctx.Value(primaryContextKey{}).(bool)
+	return primary
+}
+
+// statementTimeoutContextKey is the key of contexts in which queries
+// have a statement timeout of their own.
+type statementTimeoutContextKey struct{}
+
+// WithStatementTimeout returns a context in which target storage has reads
+// cancelled by the database once they run for longer than the provided
+// timeout, rather than after the statement timeout of its connections.
+// Reads that may take long, such as exports, must be sent with such a context.
+func WithStatementTimeout(ctx context.Context, timeout time.Duration) context.Context {
+	return context.WithValue(ctx, statementTimeoutContextKey{}, timeout)
+}
+
+// StatementTimeout returns the statement timeout of reads of a context,
+// which is zero if they have none of their own.
+func StatementTimeout(ctx context.Context) time.Duration {
+	timeout, _ := ctx.Value(statementTimeoutContextKey{}).(time.Duration)
+	return timeout
+}
+
 // TargetStorage defines an interface for reading and writing
 // processed block data.
 type TargetStorage interface {
 	// SendBatch sends a batch of queries to be applied to target storage.
+	// Batches are always sent to the primary.
 	SendBatch(ctx context.Context, batch *QueryBatch) error
 
-	// Query submits a query to fetch data from target storage.
+	// Query submits a query to fetch data from target storage. Queries
+	// are sent to read replicas, if any, unless the context is WithPrimary,
+	// and run with the statement timeout of the context, if any.
 	Query(ctx context.Context, sql string, args ...interface{}) (QueryResults, error)
 
-	// QueryRow submits a query to fetch a single row of data from target storage.
+	// QueryRow submits a query to fetch a single row of data from target
+	// storage. Queries are sent to the same pools as those of Query.
 	QueryRow(ctx context.Context, sql string, args ...interface{}) QueryResult
 
 	// Shutdown shuts down the target storage client.
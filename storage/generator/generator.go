@@ -10,7 +10,6 @@ import (
 
 	"github.com/iancoleman/strcase"
 	"github.com/oasisprotocol/oasis-core/go/common/entity"
-	"github.com/oasisprotocol/oasis-core/go/common/node"
 	genesis "github.com/oasisprotocol/oasis-core/go/genesis/api"
 	registry "github.com/oasisprotocol/oasis-core/go/registry/api"
 	staking "github.com/oasisprotocol/oasis-core/go/staking/api"
@@ -44,6 +43,8 @@ func (mg *MigrationGenerator) WriteGenesisDocumentMigrationOasis3(w io.Writer, d
 		mg.addRegistryBackendMigrations,
 		mg.addStakingBackendMigrations,
 		mg.addGovernanceBackendMigrations,
+		mg.addSchedulerBackendMigrations,
+		mg.addBeaconBackendMigrations,
 	} {
 		if err := f(w, document); err != nil {
 			return err
@@ -92,25 +93,32 @@ VALUES
 		return err
 	}
 
-	// Populate nodes.
+	// Populate nodes, along with the voting power of validators.
+	nodes, err := genesisNodes(document)
+	if err != nil {
+		return err
+	}
+	validators, err := genesisValidators(document, nodes)
+	if err != nil {
+		return err
+	}
 	if _, err := io.WriteString(w, fmt.Sprintf(`
 TRUNCATE %s.nodes CASCADE;`, chainID)); err != nil {
 		return err
 	}
 	if _, err := io.WriteString(w, fmt.Sprintf(`
-INSERT INTO %s.nodes (id, entity_id, expiration, tls_pubkey, tls_next_pubkey, p2p_pubkey, consensus_pubkey, roles)
+INSERT INTO %s.nodes (id, entity_id, expiration, tls_pubkey, tls_next_pubkey, p2p_pubkey, consensus_pubkey, vrf_pubkey, roles, software_version, voting_power)
 VALUES
 `, chainID)); err != nil {
 		return err
 	}
-	for i, signedNode := range document.Registry.Nodes {
-		var node node.Node
-		if err := signedNode.Open(registry.RegisterNodeSignatureContext, &node); err != nil {
-			return err
+	for i, node := range nodes {
+		vrfPubkey := ""
+		if node.VRF != nil {
+			vrfPubkey = node.VRF.ID.String()
 		}
-
 		if _, err := io.WriteString(w, fmt.Sprintf(
-			"\t('%s', '%s', %d, '%s', '%s', '%s', '%s', '%s')",
+			"\t('%s', '%s', %d, '%s', '%s', '%s', '%s', '%s', '%s', '%s', %d)",
 			node.ID.String(),
 			node.EntityID.String(),
 			node.Expiration,
@@ -118,12 +126,15 @@ VALUES
 			node.TLS.NextPubKey.String(),
 			node.P2P.ID.String(),
 			node.Consensus.ID.String(),
+			vrfPubkey,
 			node.Roles.String(),
+			node.SoftwareVersion,
+			validators[node.ID],
 		)); err != nil {
 			return err
 		}
 
-		if i != len(document.Registry.Nodes)-1 {
+		if i != len(nodes)-1 {
 			if _, err := io.WriteString(w, ",\n"); err != nil {
 				return err
 			}
@@ -133,7 +144,8 @@ VALUES
 		return err
 	}
 
-	// Populate runtimes.
+	// Populate runtimes, along with their genesis state.
+	states := genesisRuntimeStates(document)
 	if _, err := io.WriteString(w, fmt.Sprintf(`
 TRUNCATE %s.runtimes CASCADE;`, chainID)); err != nil {
 		return err
@@ -141,7 +153,7 @@ TRUNCATE %s.runtimes CASCADE;`, chainID)); err != nil {
 
 	if len(document.Registry.Runtimes) > 0 {
 		if _, err := io.WriteString(w, fmt.Sprintf(`
-INSERT INTO %s.runtimes (id, suspended, kind, tee_hardware, key_manager)
+INSERT INTO %s.runtimes (id, suspended, kind, tee_hardware, key_manager, genesis_round, genesis_state_root)
 VALUES
 `, chainID)); err != nil {
 			return err
@@ -151,13 +163,16 @@ VALUES
 			if runtime.KeyManager != nil {
 				keyManager = runtime.KeyManager.String()
 			}
+			state := states[runtime.ID]
 			if _, err := io.WriteString(w, fmt.Sprintf(
-				"\t('%s', %t, '%s', '%s', '%s')",
+				"\t('%s', %t, '%s', '%s', '%s', %d, '%s')",
 				runtime.ID.String(),
 				false,
 				runtime.Kind.String(),
 				runtime.TEEHardware.String(),
 				keyManager,
+				state.Round,
+				state.StateRoot.String(),
 
 				// TODO(ennsharma): Add extra_data.
 			)); err != nil {
@@ -177,7 +192,7 @@ VALUES
 
 	if len(document.Registry.SuspendedRuntimes) > 0 {
 		if _, err := io.WriteString(w, fmt.Sprintf(`
-INSERT INTO %s.runtimes (id, suspended, kind, tee_hardware, key_manager)
+INSERT INTO %s.runtimes (id, suspended, kind, tee_hardware, key_manager, genesis_round, genesis_state_root)
 VALUES
 `, chainID)); err != nil {
 			return err
@@ -188,13 +203,16 @@ VALUES
 			if runtime.KeyManager != nil {
 				keyManager = runtime.KeyManager.Hex()
 			}
+			state := states[runtime.ID]
 			if _, err := io.WriteString(w, fmt.Sprintf(
-				"\t('%s', %t, '%s', '%s', '%s')",
+				"\t('%s', %t, '%s', '%s', '%s', %d, '%s')",
 				runtime.ID.String(),
 				true,
 				runtime.Kind.String(),
 				runtime.TEEHardware.String(),
 				keyManager,
+				state.Round,
+				state.StateRoot.String(),
 
 				// TODO(ennsharma): Add extra_data.
 			)); err != nil {
@@ -530,16 +548,25 @@ TRUNCATE %s.proposals CASCADE;`, chainID)); err != nil {
 
 		// TODO(ennsharma): Extract `executed` for proposal.
 		if _, err := io.WriteString(w, fmt.Sprintf(`
-INSERT INTO %s.proposals (id, submitter, state, deposit, handler, cp_target_version, rhp_target_version, rcp_target_version, upgrade_epoch, cancels, created_at, closes_at, invalid_votes)
+INSERT INTO %s.proposals (id, submitter, state, deposit, handler, cp_target_version, rhp_target_version, rcp_target_version, upgrade_epoch, cancels, created_at, closes_at, invalid_votes, content, yes_stake, no_stake, abstain_stake)
 VALUES
 `, chainID)); err != nil {
 			return err
 		}
 
 		for i, proposal := range document.Governance.Proposals {
+			content, err := json.Marshal(proposal.Content)
+			if err != nil {
+				return err
+			}
+			results := []string{"null", "null", "null"}
+			for j, stake := range proposalResults(proposal) {
+				results[j] = stake.String()
+			}
+
 			if proposal.Content.Upgrade != nil {
 				if _, err := io.WriteString(w, fmt.Sprintf(
-					"\t(%d, '%s', '%s', %d, '%s', '%s', '%s', '%s', %d, %s, %d, %d, %d)",
+					"\t(%d, '%s', '%s', %d, '%s', '%s', '%s', '%s', %d, %s, %d, %d, %d, '%s', %s)",
 					proposal.ID,
 					proposal.Submitter.String(),
 					proposal.State.String(),
@@ -553,12 +580,14 @@ VALUES
 					proposal.CreatedAt,
 					proposal.ClosesAt,
 					proposal.InvalidVotes,
+					strings.ReplaceAll(string(content), "'", "''"),
+					strings.Join(results, ", "),
 				)); err != nil {
 					return err
 				}
 			} else if proposal.Content.CancelUpgrade != nil {
 				if _, err := io.WriteString(w, fmt.Sprintf(
-					"\t(%d, '%s', '%s', %d, '%s', '%s', '%s', '%s', '%s', %d, %d, %d, %d)",
+					"\t(%d, '%s', '%s', %d, '%s', '%s', '%s', '%s', '%s', %d, %d, %d, %d, '%s', %s)",
 					proposal.ID,
 					proposal.Submitter.String(),
 					proposal.State.String(),
@@ -572,6 +601,8 @@ VALUES
 					proposal.CreatedAt,
 					proposal.ClosesAt,
 					proposal.InvalidVotes,
+					strings.ReplaceAll(string(content), "'", "''"),
+					strings.Join(results, ", "),
 				)); err != nil {
 					return err
 				}
@@ -635,3 +666,35 @@ VALUES
 
 	return nil
 }
+
+func (mg *MigrationGenerator) addSchedulerBackendMigrations(w io.Writer, document *genesis.Document) error {
+	chainID := strcase.ToSnake(document.ChainID)
+
+	// Committees are not elected in the genesis epoch, so
+	// there are no committee members.
+	if _, err := io.WriteString(w, fmt.Sprintf(`
+-- Scheduler Backend Data
+TRUNCATE %s.committee_members CASCADE;
+`, chainID)); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (mg *MigrationGenerator) addBeaconBackendMigrations(w io.Writer, document *genesis.Document) error {
+	chainID := strcase.ToSnake(document.ChainID)
+
+	// Populate the genesis epoch, which starts at the genesis height.
+	if _, err := io.WriteString(w, fmt.Sprintf(`
+-- Beacon Backend Data
+TRUNCATE %s.epochs CASCADE;
+INSERT INTO %s.epochs (id, start_height)
+VALUES
+	(%d, %d);
+`, chainID, chainID, document.Beacon.Base, document.Height)); err != nil {
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,121 @@
+package generator
+
+import (
+	"bytes"
+	"sort"
+
+	"github.com/oasisprotocol/oasis-core/go/common"
+	"github.com/oasisprotocol/oasis-core/go/common/crypto/signature"
+	"github.com/oasisprotocol/oasis-core/go/common/node"
+	"github.com/oasisprotocol/oasis-core/go/common/quantity"
+	genesis "github.com/oasisprotocol/oasis-core/go/genesis/api"
+	governance "github.com/oasisprotocol/oasis-core/go/governance/api"
+	registry "github.com/oasisprotocol/oasis-core/go/registry/api"
+	scheduler "github.com/oasisprotocol/oasis-core/go/scheduler/api"
+	staking "github.com/oasisprotocol/oasis-core/go/staking/api"
+)
+
+// genesisNodes returns the nodes registered at genesis.
+func genesisNodes(document *genesis.Document) ([]*node.Node, error) {
+	nodes := make([]*node.Node, 0, len(document.Registry.Nodes))
+	for _, signedNode := range document.Registry.Nodes {
+		var n node.Node
+		if err := signedNode.Open(registry.RegisterGenesisNodeSignatureContext, &n); err != nil {
+			return nil, err
+		}
+		nodes = append(nodes, &n)
+	}
+	return nodes, nil
+}
+
+// genesisValidators returns the voting power of the validators at genesis
+// by node ID, which are elected from the nodes like the scheduler does when
+// the consensus layer is initialized from the genesis document: nodes are
+// ordered by voting power, each entity has at most MaxValidatorsPerEntity
+// validators, and there are at most MaxValidators validators.
+func genesisValidators(document *genesis.Document, nodes []*node.Node) (map[signature.PublicKey]int64, error) {
+	params := document.Scheduler.Parameters
+
+	type candidate struct {
+		node  *node.Node
+		power int64
+	}
+	var candidates []candidate
+	for _, n := range nodes {
+		if !n.HasRoles(node.RoleValidator) || n.IsExpired(uint64(document.Beacon.Base)) {
+			continue
+		}
+
+		power := int64(1)
+		if !params.DebugBypassStake {
+			var stake quantity.Quantity
+			if account, ok := document.Staking.Ledger[staking.NewAddress(n.EntityID)]; ok {
+				stake = account.Escrow.Active.Balance
+			}
+			var err error
+			if power, err = scheduler.VotingPowerFromStake(&stake); err != nil {
+				return nil, err
+			}
+		}
+		candidates = append(candidates, candidate{n, power})
+	}
+
+	// Ties are broken by entity and node ID, so that the election
+	// does not depend on the order of nodes in the genesis document.
+	sort.Slice(candidates, func(i, j int) bool {
+		a, b := candidates[i], candidates[j]
+		switch {
+		case a.power != b.power:
+			return a.power > b.power
+		case a.node.EntityID != b.node.EntityID:
+			return bytes.Compare(a.node.EntityID[:], b.node.EntityID[:]) < 0
+		default:
+			return bytes.Compare(a.node.ID[:], b.node.ID[:]) < 0
+		}
+	})
+
+	validators := make(map[signature.PublicKey]int64)
+	perEntity := make(map[signature.PublicKey]int)
+	for _, c := range candidates {
+		if len(validators) >= params.MaxValidators {
+			break
+		}
+		if perEntity[c.node.EntityID] >= params.MaxValidatorsPerEntity {
+			continue
+		}
+		perEntity[c.node.EntityID]++
+		validators[c.node.ID] = c.power
+	}
+	return validators, nil
+}
+
+// genesisRuntimeStates returns the genesis round and state root of
+// runtimes by runtime ID. The roothash states of the genesis document
+// take precedence over those of runtime descriptors.
+func genesisRuntimeStates(document *genesis.Document) map[common.Namespace]registry.RuntimeGenesis {
+	states := make(map[common.Namespace]registry.RuntimeGenesis)
+	for _, rts := range [][]*registry.Runtime{document.Registry.Runtimes, document.Registry.SuspendedRuntimes} {
+		for _, runtime := range rts {
+			states[runtime.ID] = runtime.Genesis
+		}
+	}
+	for id, state := range document.RootHash.RuntimeStates {
+		states[id] = state.RuntimeGenesis
+	}
+	return states
+}
+
+// proposalResults returns the stake that voted yes, no and abstain in the
+// tally of a proposal, or nil if the proposal is still active and has not
+// been tallied.
+func proposalResults(proposal *governance.Proposal) []*quantity.Quantity {
+	if proposal.State == governance.StateActive {
+		return nil
+	}
+	results := make([]*quantity.Quantity, 0, 3)
+	for _, vote := range []governance.Vote{governance.VoteYes, governance.VoteNo, governance.VoteAbstain} {
+		stake := proposal.Results[vote]
+		results = append(results, &stake)
+	}
+	return results
+}
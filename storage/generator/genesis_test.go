@@ -0,0 +1,122 @@
+package generator
+
+import (
+	"testing"
+
+	"github.com/oasisprotocol/oasis-core/go/common"
+	"github.com/oasisprotocol/oasis-core/go/common/crypto/hash"
+	"github.com/oasisprotocol/oasis-core/go/common/crypto/signature"
+	"github.com/oasisprotocol/oasis-core/go/common/node"
+	"github.com/oasisprotocol/oasis-core/go/common/quantity"
+	genesis "github.com/oasisprotocol/oasis-core/go/genesis/api"
+	governance "github.com/oasisprotocol/oasis-core/go/governance/api"
+	registry "github.com/oasisprotocol/oasis-core/go/registry/api"
+	roothash "github.com/oasisprotocol/oasis-core/go/roothash/api"
+	scheduler "github.com/oasisprotocol/oasis-core/go/scheduler/api"
+	staking "github.com/oasisprotocol/oasis-core/go/staking/api"
+	"github.com/stretchr/testify/require"
+)
+
+func publicKey(b byte) signature.PublicKey {
+	var pk signature.PublicKey
+	pk[0] = b
+	return pk
+}
+
+func TestGenesisValidators(t *testing.T) {
+	entity := publicKey(1)
+	var stake quantity.Quantity
+	require.Nil(t, stake.FromUint64(5_000_000_000_000_000))
+
+	document := &genesis.Document{
+		Scheduler: scheduler.Genesis{
+			Parameters: scheduler.ConsensusParameters{MaxValidators: 3, MaxValidatorsPerEntity: 1},
+		},
+		Staking: staking.Genesis{
+			Ledger: map[staking.Address]*staking.Account{
+				staking.NewAddress(entity): {Escrow: staking.EscrowAccount{Active: staking.SharePool{Balance: stake}}},
+			},
+		},
+	}
+	document.Beacon.Base = 10
+	nodes := []*node.Node{
+		{ID: publicKey(2), EntityID: entity, Expiration: 11, Roles: node.RoleValidator},
+		// Entities have at most MaxValidatorsPerEntity validators.
+		{ID: publicKey(3), EntityID: entity, Expiration: 11, Roles: node.RoleValidator},
+		// Expired nodes are not validators.
+		{ID: publicKey(4), EntityID: publicKey(5), Expiration: 9, Roles: node.RoleValidator},
+		{ID: publicKey(6), EntityID: publicKey(5), Expiration: 11, Roles: node.RoleComputeWorker},
+		// Entities without stake have a voting power of one.
+		{ID: publicKey(7), EntityID: publicKey(5), Expiration: 11, Roles: node.RoleValidator},
+	}
+
+	power, err := scheduler.VotingPowerFromStake(&stake)
+	require.Nil(t, err)
+	validators, err := genesisValidators(document, nodes)
+	require.Nil(t, err)
+	require.Equal(t, map[signature.PublicKey]int64{
+		publicKey(2): power,
+		publicKey(7): 1,
+	}, validators)
+
+	// There are at most MaxValidators validators, which are the nodes
+	// with the most voting power, regardless of their order.
+	nodes = append([]*node.Node{
+		{ID: publicKey(8), EntityID: publicKey(9), Expiration: 11, Roles: node.RoleValidator},
+		{ID: publicKey(10), EntityID: publicKey(11), Expiration: 11, Roles: node.RoleValidator},
+	}, nodes...)
+	document.Staking.Ledger[staking.NewAddress(publicKey(11))] = &staking.Account{
+		Escrow: staking.EscrowAccount{Active: staking.SharePool{Balance: stake}},
+	}
+	validators, err = genesisValidators(document, nodes)
+	require.Nil(t, err)
+	require.Equal(t, map[signature.PublicKey]int64{
+		publicKey(2):  power,
+		publicKey(10): power,
+		publicKey(7):  1,
+	}, validators)
+}
+
+func TestGenesisRuntimeStates(t *testing.T) {
+	var a, b common.Namespace
+	a[0], b[0] = 1, 2
+	var root hash.Hash
+	root.FromBytes([]byte("state"))
+
+	document := &genesis.Document{
+		Registry: registry.Genesis{
+			Runtimes:          []*registry.Runtime{{ID: a, Genesis: registry.RuntimeGenesis{Round: 1}}},
+			SuspendedRuntimes: []*registry.Runtime{{ID: b, Genesis: registry.RuntimeGenesis{Round: 2}}},
+		},
+		RootHash: roothash.Genesis{
+			RuntimeStates: map[common.Namespace]*roothash.GenesisRuntimeState{
+				a: {RuntimeGenesis: registry.RuntimeGenesis{Round: 3, StateRoot: root}},
+			},
+		},
+	}
+
+	// Roothash states take precedence over those of runtime descriptors.
+	require.Equal(t, map[common.Namespace]registry.RuntimeGenesis{
+		a: {Round: 3, StateRoot: root},
+		b: {Round: 2},
+	}, genesisRuntimeStates(document))
+}
+
+func TestProposalResults(t *testing.T) {
+	// Active proposals have not been tallied.
+	require.Nil(t, proposalResults(&governance.Proposal{State: governance.StateActive}))
+
+	// Choices that no one voted for have no stake.
+	results := proposalResults(&governance.Proposal{
+		State: governance.StatePassed,
+		Results: map[governance.Vote]quantity.Quantity{
+			governance.VoteYes:     *quantity.NewFromUint64(30),
+			governance.VoteAbstain: *quantity.NewFromUint64(5),
+		},
+	})
+	require.Equal(t, []*quantity.Quantity{
+		quantity.NewFromUint64(30),
+		quantity.NewQuantity(),
+		quantity.NewFromUint64(5),
+	}, results)
+}
@@ -0,0 +1,460 @@
+package generator
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/iancoleman/strcase"
+	"github.com/oasisprotocol/oasis-core/go/common/entity"
+	genesis "github.com/oasisprotocol/oasis-core/go/genesis/api"
+	registry "github.com/oasisprotocol/oasis-core/go/registry/api"
+	staking "github.com/oasisprotocol/oasis-core/go/staking/api"
+
+	"github.com/oasisprotocol/oasis-indexer/analyzer"
+	"github.com/oasisprotocol/oasis-indexer/analyzer/util"
+	"github.com/oasisprotocol/oasis-indexer/log"
+	"github.com/oasisprotocol/oasis-indexer/storage"
+)
+
+// GenesisLoader loads genesis state directly into Oasis Indexer
+// target storage.
+type GenesisLoader struct {
+	target storage.TargetStorage
+	logger *log.Logger
+}
+
+// NewGenesisLoader creates a new genesis loader.
+func NewGenesisLoader(target storage.TargetStorage, logger *log.Logger) *GenesisLoader {
+	return &GenesisLoader{target, logger}
+}
+
+// LoadGenesisDocumentOasis3 re-initializes all height-dependent state as per
+// the provided genesis document, in a single transaction. The genesis height
+// is checkpointed and recorded as the latest height processed by the named
+// analyzer, which then resumes from the height that follows it.
+func (gl *GenesisLoader) LoadGenesisDocumentOasis3(ctx context.Context, document *genesis.Document, analyzerName string) error {
+	batch := &storage.QueryBatch{}
+	for _, f := range []func(*storage.QueryBatch, *genesis.Document) error{
+		gl.queueRegistryBackendState,
+		gl.queueStakingBackendState,
+		gl.queueGovernanceBackendState,
+		gl.queueSchedulerBackendState,
+		gl.queueBeaconBackendState,
+	} {
+		if err := f(batch, document); err != nil {
+			return err
+		}
+	}
+
+	chainID := strcase.ToSnake(document.ChainID)
+	batch.Queue(fmt.Sprintf(`
+		DELETE FROM %s.processed_blocks
+			WHERE analyzer = $1`, chainID),
+		analyzerName,
+	)
+	batch.Queue(fmt.Sprintf(`
+		INSERT INTO %s.processed_blocks (height, analyzer, processed_time)
+			VALUES ($1, $2, CURRENT_TIMESTAMP)`, chainID),
+		document.Height,
+		analyzerName,
+	)
+
+	// The checkpoint is queued last, so that it holds the loaded state.
+	qf := analyzer.NewQueryFactory(chainID, "")
+	batch.Queue(qf.ConsensusCheckpointDeleteQuery(),
+		document.Height,
+	)
+	batch.Queue(qf.ConsensusCheckpointInsertQuery(),
+		document.Height,
+		document.Beacon.Base,
+	)
+	for _, query := range qf.ConsensusCheckpointQueries() {
+		batch.Queue(query, document.Height)
+	}
+
+	gl.logger.Info("loading genesis state",
+		"chain_id", document.ChainID,
+		"height", document.Height,
+		"queries", batch.Len(),
+	)
+	return gl.target.SendBatch(ctx, batch)
+}
+
+func (gl *GenesisLoader) queueRegistryBackendState(batch *storage.QueryBatch, document *genesis.Document) error {
+	chainID := strcase.ToSnake(document.ChainID)
+
+	// Populate entities.
+	truncate(batch, chainID, "entities")
+	entities := newBulkInsert(batch, chainID+".entities", "id", "address")
+	for _, signedEntity := range document.Registry.Entities {
+		var entity entity.Entity
+		if err := signedEntity.Open(registry.RegisterEntitySignatureContext, &entity); err != nil {
+			return err
+		}
+		entities.add(
+			entity.ID.String(),
+			staking.NewAddress(entity.ID).String(),
+		)
+	}
+	entities.flush()
+
+	// Populate nodes, along with the voting power of validators.
+	registeredNodes, err := genesisNodes(document)
+	if err != nil {
+		return err
+	}
+	validators, err := genesisValidators(document, registeredNodes)
+	if err != nil {
+		return err
+	}
+	truncate(batch, chainID, "nodes")
+	truncate(batch, chainID, "node_history")
+	nodes := newBulkInsert(batch, chainID+".nodes", "id", "entity_id", "expiration", "tls_pubkey", "tls_next_pubkey", "p2p_pubkey", "consensus_pubkey", "vrf_pubkey", "roles", "software_version", "voting_power", "freeze_end", "runtimes")
+	nodeHistory := newBulkInsert(batch, chainID+".node_history", "node_id", "entity_id", "height", "event", "expiration", "software_version", "roles", "freeze_end")
+	for _, node := range registeredNodes {
+		vrfPubkey := ""
+		if node.VRF != nil {
+			vrfPubkey = node.VRF.ID.String()
+		}
+		runtimes, err := json.Marshal(util.NodeRuntimes(node))
+		if err != nil {
+			return err
+		}
+		var freezeEnd *uint64
+		if status, ok := document.Registry.NodeStatuses[node.ID]; ok && status.IsFrozen() {
+			end := uint64(status.FreezeEndTime)
+			freezeEnd = &end
+		}
+		nodes.add(
+			node.ID.String(),
+			node.EntityID.String(),
+			node.Expiration,
+			node.TLS.PubKey.String(),
+			node.TLS.NextPubKey.String(),
+			node.P2P.ID.String(),
+			node.Consensus.ID.String(),
+			vrfPubkey,
+			node.Roles.String(),
+			node.SoftwareVersion,
+			validators[node.ID],
+			freezeEnd,
+			string(runtimes),
+		)
+
+		// Nodes registered at genesis start their history at the genesis
+		// height.
+		nodeHistory.add(
+			node.ID.String(),
+			node.EntityID.String(),
+			document.Height,
+			"registered",
+			node.Expiration,
+			node.SoftwareVersion,
+			node.Roles.String(),
+			freezeEnd,
+		)
+	}
+	nodes.flush()
+	nodeHistory.flush()
+
+	// Populate runtimes, along with their genesis state.
+	states := genesisRuntimeStates(document)
+	truncate(batch, chainID, "runtimes")
+	truncate(batch, chainID, "runtime_history")
+	runtimes := newBulkInsert(batch, chainID+".runtimes", "id", "suspended", "kind", "tee_hardware", "key_manager", "genesis_round", "genesis_state_root")
+	runtimeHistory := newBulkInsert(batch, chainID+".runtime_history", "runtime", "height", "event")
+	for suspended, rts := range map[bool][]*registry.Runtime{
+		false: document.Registry.Runtimes,
+		true:  document.Registry.SuspendedRuntimes,
+	} {
+		for _, runtime := range rts {
+			keyManager := "none"
+			if runtime.KeyManager != nil {
+				keyManager = runtime.KeyManager.String()
+			}
+			state := states[runtime.ID]
+			runtimes.add(
+				runtime.ID.String(),
+				suspended,
+				runtime.Kind.String(),
+				runtime.TEEHardware.String(),
+				keyManager,
+				state.Round,
+				state.StateRoot.String(),
+			)
+
+			// Runtimes registered at genesis start their history at the
+			// genesis height.
+			runtimeHistory.add(runtime.ID.String(), document.Height, "registered")
+			if suspended {
+				runtimeHistory.add(runtime.ID.String(), document.Height, "suspended")
+			}
+		}
+	}
+	runtimes.flush()
+	runtimeHistory.flush()
+
+	return nil
+}
+
+func (gl *GenesisLoader) queueStakingBackendState(batch *storage.QueryBatch, document *genesis.Document) error {
+	chainID := strcase.ToSnake(document.ChainID)
+
+	// Populate accounts, including special accounts with reserved addresses.
+	truncate(batch, chainID, "accounts")
+	accounts := newBulkInsert(batch, chainID+".accounts", "address", "general_balance", "nonce", "escrow_balance_active", "escrow_total_shares_active", "escrow_balance_debonding", "escrow_total_shares_debonding")
+	for address, account := range map[staking.Address]*staking.Account{
+		staking.CommonPoolAddress:         {General: staking.GeneralAccount{Balance: document.Staking.CommonPool}},
+		staking.FeeAccumulatorAddress:     {General: staking.GeneralAccount{Balance: document.Staking.LastBlockFees}},
+		staking.GovernanceDepositsAddress: {General: staking.GeneralAccount{Balance: document.Staking.GovernanceDeposits}},
+	} {
+		queueAccount(accounts, address, account)
+	}
+	for address, account := range document.Staking.Ledger {
+		queueAccount(accounts, address, account)
+	}
+	accounts.flush()
+
+	// Populate commissions.
+	truncate(batch, chainID, "commissions")
+	commissions := newBulkInsert(batch, chainID+".commissions", "address", "schedule")
+	for address, account := range document.Staking.Ledger {
+		if len(account.Escrow.CommissionSchedule.Rates) == 0 && len(account.Escrow.CommissionSchedule.Bounds) == 0 {
+			continue
+		}
+		schedule, err := json.Marshal(account.Escrow.CommissionSchedule)
+		if err != nil {
+			return err
+		}
+		commissions.add(address.String(), string(schedule))
+	}
+	commissions.flush()
+
+	// Populate allowances.
+	truncate(batch, chainID, "allowances")
+	allowances := newBulkInsert(batch, chainID+".allowances", "owner", "beneficiary", "allowance")
+	for owner, account := range document.Staking.Ledger {
+		for beneficiary, allowance := range account.General.Allowances {
+			allowances.add(owner.String(), beneficiary.String(), allowance.String())
+		}
+	}
+	allowances.flush()
+
+	// Populate delegations.
+	truncate(batch, chainID, "delegations")
+	delegations := newBulkInsert(batch, chainID+".delegations", "delegatee", "delegator", "shares")
+	for delegatee, escrows := range document.Staking.Delegations {
+		for delegator, delegation := range escrows {
+			delegations.add(delegatee.String(), delegator.String(), delegation.Shares.String())
+		}
+	}
+	delegations.flush()
+
+	// Populate debonding delegations.
+	truncate(batch, chainID, "debonding_delegations")
+	debondingDelegations := newBulkInsert(batch, chainID+".debonding_delegations", "delegatee", "delegator", "shares", "debond_end")
+	for delegatee, escrows := range document.Staking.DebondingDelegations {
+		for delegator, dds := range escrows {
+			for _, debondingDelegation := range dds {
+				debondingDelegations.add(
+					delegatee.String(),
+					delegator.String(),
+					debondingDelegation.Shares.String(),
+					debondingDelegation.DebondEndTime,
+				)
+			}
+		}
+	}
+	debondingDelegations.flush()
+
+	return nil
+}
+
+func queueAccount(accounts *bulkInsert, address staking.Address, account *staking.Account) {
+	accounts.add(
+		address.String(),
+		account.General.Balance.String(),
+		account.General.Nonce,
+		account.Escrow.Active.Balance.String(),
+		account.Escrow.Active.TotalShares.String(),
+		account.Escrow.Debonding.Balance.String(),
+		account.Escrow.Debonding.TotalShares.String(),
+	)
+}
+
+func (gl *GenesisLoader) queueGovernanceBackendState(batch *storage.QueryBatch, document *genesis.Document) error {
+	chainID := strcase.ToSnake(document.ChainID)
+
+	// Populate proposals.
+	truncate(batch, chainID, "proposals")
+	proposals := newBulkInsert(batch, chainID+".proposals", "id", "submitter", "state", "deposit", "handler", "cp_target_version", "rhp_target_version", "rcp_target_version", "upgrade_epoch", "cancels", "created_at", "closes_at", "invalid_votes", "content", "yes_stake", "no_stake", "abstain_stake")
+	for _, proposal := range document.Governance.Proposals {
+		content, err := json.Marshal(proposal.Content)
+		if err != nil {
+			return err
+		}
+		results := []interface{}{nil, nil, nil}
+		for i, stake := range proposalResults(proposal) {
+			results[i] = stake.String()
+		}
+
+		switch {
+		case proposal.Content.Upgrade != nil:
+			proposals.add(append([]interface{}{
+				proposal.ID,
+				proposal.Submitter.String(),
+				proposal.State.String(),
+				proposal.Deposit.String(),
+				string(proposal.Content.Upgrade.Handler),
+				proposal.Content.Upgrade.Target.ConsensusProtocol.String(),
+				proposal.Content.Upgrade.Target.RuntimeHostProtocol.String(),
+				proposal.Content.Upgrade.Target.RuntimeCommitteeProtocol.String(),
+				proposal.Content.Upgrade.Epoch,
+				nil,
+				proposal.CreatedAt,
+				proposal.ClosesAt,
+				proposal.InvalidVotes,
+				string(content),
+			}, results...)...)
+		case proposal.Content.CancelUpgrade != nil:
+			proposals.add(append([]interface{}{
+				proposal.ID,
+				proposal.Submitter.String(),
+				proposal.State.String(),
+				proposal.Deposit.String(),
+				nil,
+				nil,
+				nil,
+				nil,
+				nil,
+				proposal.Content.CancelUpgrade.ProposalID,
+				proposal.CreatedAt,
+				proposal.ClosesAt,
+				proposal.InvalidVotes,
+				string(content),
+			}, results...)...)
+		}
+	}
+	proposals.flush()
+
+	// Populate votes.
+	truncate(batch, chainID, "votes")
+	votes := newBulkInsert(batch, chainID+".votes", "proposal", "voter", "vote")
+	for proposalID, voteEntries := range document.Governance.VoteEntries {
+		for _, voteEntry := range voteEntries {
+			votes.add(proposalID, voteEntry.Voter.String(), voteEntry.Vote.String())
+		}
+	}
+	votes.flush()
+
+	return nil
+}
+
+func (gl *GenesisLoader) queueSchedulerBackendState(batch *storage.QueryBatch, document *genesis.Document) error {
+	chainID := strcase.ToSnake(document.ChainID)
+
+	// Committees are not elected in the genesis epoch, so
+	// there are no committee members.
+	truncate(batch, chainID, "committee_members")
+	truncate(batch, chainID, "epoch_committee_members")
+
+	// Populate the validators of the genesis epoch.
+	registeredNodes, err := genesisNodes(document)
+	if err != nil {
+		return err
+	}
+	validators, err := genesisValidators(document, registeredNodes)
+	if err != nil {
+		return err
+	}
+	truncate(batch, chainID, "epoch_validators")
+	epochValidators := newBulkInsert(batch, chainID+".epoch_validators", "epoch", "node_id", "voting_power")
+	for _, node := range registeredNodes {
+		if power, ok := validators[node.ID]; ok {
+			epochValidators.add(document.Beacon.Base, node.ID.String(), power)
+		}
+	}
+	epochValidators.flush()
+
+	return nil
+}
+
+func (gl *GenesisLoader) queueBeaconBackendState(batch *storage.QueryBatch, document *genesis.Document) error {
+	chainID := strcase.ToSnake(document.ChainID)
+
+	// Populate the genesis epoch, which starts at the genesis height.
+	truncate(batch, chainID, "epochs")
+	epochs := newBulkInsert(batch, chainID+".epochs", "id", "start_height")
+	epochs.add(document.Beacon.Base, document.Height)
+	epochs.flush()
+
+	return nil
+}
+
+// truncate queues the truncation of a table, along with the tables
+// that reference it.
+func truncate(batch *storage.QueryBatch, chainID string, table string) {
+	batch.Queue(fmt.Sprintf(`TRUNCATE %s.%s CASCADE`, chainID, table))
+}
+
+// bulkInsert queues parameterized inserts of rows into a table, of
+// up to bulkInsertBatchSize rows each.
+type bulkInsert struct {
+	batch   *storage.QueryBatch
+	table   string
+	columns []string
+
+	rows int
+	args []interface{}
+}
+
+func newBulkInsert(batch *storage.QueryBatch, table string, columns ...string) *bulkInsert {
+	return &bulkInsert{
+		batch:   batch,
+		table:   table,
+		columns: columns,
+	}
+}
+
+// add adds a row with a value for each column, queueing an insert
+// once enough rows are added.
+func (b *bulkInsert) add(values ...interface{}) {
+	b.args = append(b.args, values...)
+	b.rows++
+	if b.rows == bulkInsertBatchSize {
+		b.flush()
+	}
+}
+
+// flush queues an insert of the rows that are not yet queued, if any.
+func (b *bulkInsert) flush() {
+	if b.rows == 0 {
+		return
+	}
+	b.batch.Queue(insertQuery(b.table, b.columns, b.rows), b.args...)
+	b.rows = 0
+	b.args = nil
+}
+
+// insertQuery returns a query inserting rows into the columns of a table,
+// with a parameter for each value.
+func insertQuery(table string, columns []string, rows int) string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "INSERT INTO %s (%s)\nVALUES", table, strings.Join(columns, ", "))
+	for i := 0; i < rows; i++ {
+		if i > 0 {
+			sb.WriteString(",")
+		}
+		sb.WriteString("\n\t(")
+		for j := range columns {
+			if j > 0 {
+				sb.WriteString(", ")
+			}
+			fmt.Fprintf(&sb, "$%d", i*len(columns)+j+1)
+		}
+		sb.WriteString(")")
+	}
+	return sb.String()
+}
@@ -0,0 +1,78 @@
+package generator
+
+import (
+	"context"
+	"testing"
+
+	"github.com/oasisprotocol/oasis-core/go/common/quantity"
+	genesis "github.com/oasisprotocol/oasis-core/go/genesis/api"
+	governance "github.com/oasisprotocol/oasis-core/go/governance/api"
+	staking "github.com/oasisprotocol/oasis-core/go/staking/api"
+	"github.com/stretchr/testify/require"
+
+	"github.com/oasisprotocol/oasis-indexer/log"
+	"github.com/oasisprotocol/oasis-indexer/storage"
+)
+
+// batchStorage is a target storage that records the batches sent to it.
+type batchStorage struct {
+	storage.TargetStorage
+	batches []*storage.QueryBatch
+}
+
+func (s *batchStorage) SendBatch(ctx context.Context, batch *storage.QueryBatch) error {
+	s.batches = append(s.batches, batch)
+	return nil
+}
+
+func TestInsertQuery(t *testing.T) {
+	require.Equal(t, "INSERT INTO oasis_3.votes (proposal, voter, vote)\nVALUES\n\t($1, $2, $3),\n\t($4, $5, $6)",
+		insertQuery("oasis_3.votes", []string{"proposal", "voter", "vote"}, 2))
+}
+
+func TestBulkInsert(t *testing.T) {
+	batch := &storage.QueryBatch{}
+	b := newBulkInsert(batch, "oasis_3.votes", "proposal", "voter", "vote")
+
+	// Rows are queued in inserts of up to bulkInsertBatchSize rows.
+	for i := 0; i <= bulkInsertBatchSize; i++ {
+		b.add(1, "voter", "yes")
+	}
+	require.Equal(t, 1, batch.Len())
+	b.flush()
+	require.Equal(t, 2, batch.Len())
+
+	// Flushing without rows queues nothing.
+	b.flush()
+	require.Equal(t, 2, batch.Len())
+}
+
+func TestLoadGenesisDocument(t *testing.T) {
+	var address staking.Address
+	require.Nil(t, address.UnmarshalText([]byte("oasis1qrvsa8ukfw3p6kw2vcs0fk9t59mceqq7fyttwqgx")))
+
+	document := &genesis.Document{
+		Height:  8048956,
+		ChainID: "oasis-3",
+		Staking: staking.Genesis{
+			Ledger: map[staking.Address]*staking.Account{
+				address: {General: staking.GeneralAccount{Balance: *quantity.NewFromUint64(100)}},
+			},
+		},
+		Governance: governance.Genesis{
+			Proposals: []*governance.Proposal{
+				{ID: 1, Content: governance.ProposalContent{CancelUpgrade: &governance.CancelUpgradeProposal{ProposalID: 0}}},
+			},
+		},
+	}
+
+	target := &batchStorage{}
+	loader := NewGenesisLoader(target, log.NewDefaultLogger("generator"))
+	require.Nil(t, loader.LoadGenesisDocumentOasis3(context.Background(), document, "consensus_main_damask"))
+
+	// The state is loaded in a single batch of truncations of each table,
+	// inserts of accounts, proposals and the genesis epoch, the indexing
+	// progress, and the checkpoint of each verified table.
+	require.Len(t, target.batches, 1)
+	require.Equal(t, 16+3+2+2+10, target.batches[0].Len())
+}
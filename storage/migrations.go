@@ -0,0 +1,6 @@
+package storage
+
+// MigrationVersion is the version of the latest migration of target
+// storage, which services require to be applied. It must be bumped
+// along with each new migration.
+const MigrationVersion = 24
@@ -0,0 +1,20 @@
+-- API keys identifying clients of the API, for rate limiting.
+
+BEGIN;
+
+-- api_keys stores the keys issued to API clients. Keys are not tied to
+-- a chain, so they are not stored in a chain schema. Only the SHA-256
+-- hash of each key is stored; the key itself is shown once on creation.
+CREATE TABLE IF NOT EXISTS api_keys
+(
+  id         BIGSERIAL PRIMARY KEY,
+  name       TEXT NOT NULL,
+  tier       TEXT NOT NULL,
+  key_hash   TEXT NOT NULL UNIQUE,
+  key_prefix TEXT NOT NULL,
+
+  created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  revoked_at TIMESTAMP WITH TIME ZONE
+);
+
+COMMIT;
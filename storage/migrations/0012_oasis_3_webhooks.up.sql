@@ -0,0 +1,42 @@
+-- Webhook subscriptions and deliveries for notifications on indexed events.
+
+BEGIN;
+
+-- webhook_subscriptions stores user registered subscriptions to indexed events.
+-- Optional filters restrict which events of the given kind are delivered.
+-- Subscriptions are managed by, and only visible to, the API key that
+-- created them.
+CREATE TABLE IF NOT EXISTS oasis_3.webhook_subscriptions
+(
+  id         BIGSERIAL PRIMARY KEY,
+  api_key_id BIGINT NOT NULL REFERENCES api_keys(id),
+  kind       TEXT NOT NULL,
+  url        TEXT NOT NULL,
+  secret     TEXT NOT NULL,
+
+  account    TEXT,
+  entity_id  TEXT,
+  min_amount NUMERIC,
+
+  created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS ix_webhook_subscriptions_api_key_id ON oasis_3.webhook_subscriptions(api_key_id);
+
+-- webhook_dead_letters stores deliveries that failed after all retries,
+-- so that they can be inspected and replayed.
+CREATE TABLE IF NOT EXISTS oasis_3.webhook_dead_letters
+(
+  id              BIGSERIAL PRIMARY KEY,
+  subscription_id BIGINT NOT NULL REFERENCES oasis_3.webhook_subscriptions(id) ON DELETE CASCADE,
+  height          BIGINT NOT NULL,
+  payload         JSON NOT NULL,
+  attempts        INTEGER NOT NULL,
+  last_error      TEXT NOT NULL,
+
+  created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS ix_webhook_dead_letters_subscription_id ON oasis_3.webhook_dead_letters(subscription_id);
+
+COMMIT;
@@ -0,0 +1,8 @@
+-- Index on the processing time of blocks, used to look up the latest
+-- processed block without scanning all processed blocks.
+
+BEGIN;
+
+CREATE INDEX IF NOT EXISTS ix_processed_blocks_processed_time ON oasis_3.processed_blocks(processed_time);
+
+COMMIT;
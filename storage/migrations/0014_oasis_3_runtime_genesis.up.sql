@@ -0,0 +1,9 @@
+-- Genesis state of runtimes, from their descriptors and the roothash
+-- section of the genesis document.
+
+BEGIN;
+
+ALTER TABLE oasis_3.runtimes ADD COLUMN genesis_round BIGINT;
+ALTER TABLE oasis_3.runtimes ADD COLUMN genesis_state_root TEXT;
+
+COMMIT;
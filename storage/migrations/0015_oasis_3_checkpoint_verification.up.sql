@@ -0,0 +1,9 @@
+-- Results of verifications of checkpoints against node state.
+
+BEGIN;
+
+ALTER TABLE oasis_3.checkpointed_heights ADD COLUMN verified_time TIMESTAMP WITH TIME ZONE;
+ALTER TABLE oasis_3.checkpointed_heights ADD COLUMN verify_mismatches BIGINT;
+ALTER TABLE oasis_3.checkpointed_heights ADD COLUMN verify_report JSON;
+
+COMMIT;
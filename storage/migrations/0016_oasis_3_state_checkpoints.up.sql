@@ -0,0 +1,73 @@
+-- Periodic checkpoints of consensus state, stamped with the height at which
+-- they were taken. Deleting a checkpointed height deletes its checkpoint.
+
+BEGIN;
+
+-- The epoch of a periodic checkpoint, if there is one at the height.
+ALTER TABLE oasis_3.checkpointed_heights ADD COLUMN epoch BIGINT;
+
+CREATE TABLE IF NOT EXISTS oasis_3.accounts_checkpoints
+(
+  height  BIGINT NOT NULL REFERENCES oasis_3.checkpointed_heights(height) ON DELETE CASCADE,
+  address TEXT NOT NULL,
+
+  general_balance NUMERIC,
+  nonce           BIGINT,
+
+  escrow_balance_active         NUMERIC,
+  escrow_total_shares_active    NUMERIC,
+  escrow_balance_debonding      NUMERIC,
+  escrow_total_shares_debonding NUMERIC,
+
+  PRIMARY KEY (height, address)
+);
+
+CREATE TABLE IF NOT EXISTS oasis_3.delegations_checkpoints
+(
+  height    BIGINT NOT NULL REFERENCES oasis_3.checkpointed_heights(height) ON DELETE CASCADE,
+  delegatee TEXT NOT NULL,
+  delegator TEXT NOT NULL,
+  shares    NUMERIC NOT NULL,
+
+  PRIMARY KEY (height, delegatee, delegator)
+);
+
+CREATE TABLE IF NOT EXISTS oasis_3.debonding_delegations_checkpoints
+(
+  height     BIGINT NOT NULL REFERENCES oasis_3.checkpointed_heights(height) ON DELETE CASCADE,
+  id         BIGINT NOT NULL,
+  delegatee  TEXT NOT NULL,
+  delegator  TEXT NOT NULL,
+  shares     NUMERIC NOT NULL,
+  debond_end BIGINT NOT NULL,
+
+  PRIMARY KEY (height, id)
+);
+
+CREATE TABLE IF NOT EXISTS oasis_3.nodes_checkpoints
+(
+  height     BIGINT NOT NULL REFERENCES oasis_3.checkpointed_heights(height) ON DELETE CASCADE,
+  id         TEXT NOT NULL,
+  entity_id  TEXT NOT NULL,
+  expiration BIGINT NOT NULL,
+
+  tls_pubkey      TEXT NOT NULL,
+  tls_next_pubkey TEXT,
+  tls_addresses   TEXT ARRAY,
+
+  p2p_pubkey    TEXT NOT NULL,
+  p2p_addresses TEXT ARRAY,
+
+  consensus_pubkey  TEXT NOT NULL,
+  consensus_address TEXT,
+
+  vrf_pubkey TEXT,
+
+  roles            TEXT,
+  software_version TEXT,
+  voting_power     BIGINT,
+
+  PRIMARY KEY (height, id)
+);
+
+COMMIT;
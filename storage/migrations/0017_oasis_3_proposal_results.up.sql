@@ -0,0 +1,18 @@
+-- Contents and results of governance proposals, and the weights of votes
+-- in their tallies.
+
+BEGIN;
+
+-- The content of the proposal, being either an upgrade descriptor or the
+-- cancellation of an upgrade proposal.
+ALTER TABLE oasis_3.proposals ADD COLUMN content JSON;
+
+-- The stake that voted for each choice, once the proposal is closed.
+ALTER TABLE oasis_3.proposals ADD COLUMN yes_stake NUMERIC;
+ALTER TABLE oasis_3.proposals ADD COLUMN no_stake NUMERIC;
+ALTER TABLE oasis_3.proposals ADD COLUMN abstain_stake NUMERIC;
+
+-- The escrow of the voter when the proposal closed, if the vote was tallied.
+ALTER TABLE oasis_3.votes ADD COLUMN weight NUMERIC;
+
+COMMIT;
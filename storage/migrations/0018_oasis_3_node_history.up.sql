@@ -0,0 +1,33 @@
+-- The lifecycle of nodes, from their registrations until they expire,
+-- including the periods in which they are frozen.
+
+BEGIN;
+
+-- The epoch after which a frozen node may be unfrozen, or NULL if the node
+-- is not frozen.
+ALTER TABLE oasis_3.nodes ADD COLUMN freeze_end BIGINT;
+ALTER TABLE oasis_3.nodes_checkpoints ADD COLUMN freeze_end BIGINT;
+
+CREATE TABLE IF NOT EXISTS oasis_3.node_history
+(
+  id        BIGSERIAL PRIMARY KEY,
+  node_id   TEXT NOT NULL,
+  entity_id TEXT NOT NULL,
+  height    BIGINT NOT NULL,
+
+  -- One of 'registered', 'expired', 'frozen' or 'unfrozen'.
+  event TEXT NOT NULL,
+
+  -- The node descriptor as of the event.
+  expiration        BIGINT NOT NULL,
+  software_version  TEXT,
+  roles             TEXT,
+  tls_addresses     TEXT ARRAY,
+  p2p_addresses     TEXT ARRAY,
+  consensus_address TEXT,
+  freeze_end        BIGINT
+);
+
+CREATE INDEX ix_node_history_node_id ON oasis_3.node_history (node_id, height);
+
+COMMIT;
@@ -0,0 +1,28 @@
+-- The runtime committees and the consensus validators of each epoch, which
+-- are kept along with the current ones.
+
+BEGIN;
+
+CREATE TABLE IF NOT EXISTS oasis_3.epoch_committee_members
+(
+  epoch   BIGINT NOT NULL,
+  runtime TEXT NOT NULL,
+  kind    TEXT NOT NULL,
+  node    TEXT NOT NULL,
+  role    TEXT NOT NULL,
+
+  PRIMARY KEY (epoch, runtime, kind, node, role)
+);
+
+-- The validator set of an epoch is the one as of the last block of the
+-- epoch, or of the latest block if the epoch has not ended.
+CREATE TABLE IF NOT EXISTS oasis_3.epoch_validators
+(
+  epoch        BIGINT NOT NULL,
+  node_id      TEXT NOT NULL,
+  voting_power BIGINT NOT NULL,
+
+  PRIMARY KEY (epoch, node_id)
+);
+
+COMMIT;
@@ -0,0 +1,18 @@
+-- The registrations, updates, suspensions and unsuspensions of runtimes.
+
+BEGIN;
+
+CREATE TABLE IF NOT EXISTS oasis_3.runtime_history
+(
+  id      BIGSERIAL PRIMARY KEY,
+  runtime TEXT NOT NULL,
+  height  BIGINT NOT NULL,
+
+  -- One of 'registered', 'updated', 'suspended' or 'unsuspended'. A runtime
+  -- is only registered once; later registrations update its descriptor.
+  event TEXT NOT NULL
+);
+
+CREATE INDEX ix_runtime_history_runtime ON oasis_3.runtime_history (runtime, height);
+
+COMMIT;
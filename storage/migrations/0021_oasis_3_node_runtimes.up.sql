@@ -0,0 +1,9 @@
+-- The runtimes that nodes are registered for.
+
+BEGIN;
+
+-- The ID and version of each runtime in the node descriptor.
+ALTER TABLE oasis_3.nodes ADD COLUMN runtimes JSON;
+ALTER TABLE oasis_3.nodes_checkpoints ADD COLUMN runtimes JSON;
+
+COMMIT;
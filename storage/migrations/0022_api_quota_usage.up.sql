@@ -0,0 +1,20 @@
+-- Daily quota usage of API clients, shared by API servers.
+
+BEGIN;
+
+-- api_quota_usage stores the number of requests each client made on a UTC
+-- day, in days since the Unix epoch. Clients are identified by API key or
+-- IP, e.g. "key:1" or "ip:192.0.2.1". API servers add their usage to it
+-- periodically, and delete the usage of previous days.
+CREATE TABLE IF NOT EXISTS api_quota_usage
+(
+  client TEXT NOT NULL,
+  day    BIGINT NOT NULL,
+  used   BIGINT NOT NULL,
+
+  PRIMARY KEY (client, day)
+);
+
+CREATE INDEX IF NOT EXISTS ix_api_quota_usage_day ON api_quota_usage(day);
+
+COMMIT;
@@ -0,0 +1,81 @@
+-- Periodic checkpoints of the remaining verified consensus state, so that
+-- indexed state can be verified at any checkpointed height.
+
+BEGIN;
+
+CREATE TABLE IF NOT EXISTS oasis_3.entities_checkpoints
+(
+  height  BIGINT NOT NULL REFERENCES oasis_3.checkpointed_heights(height) ON DELETE CASCADE,
+  id      TEXT NOT NULL,
+  address TEXT,
+
+  PRIMARY KEY (height, id)
+);
+
+CREATE TABLE IF NOT EXISTS oasis_3.claimed_nodes_checkpoints
+(
+  height    BIGINT NOT NULL REFERENCES oasis_3.checkpointed_heights(height) ON DELETE CASCADE,
+  entity_id TEXT NOT NULL,
+  node_id   TEXT NOT NULL,
+
+  PRIMARY KEY (height, entity_id, node_id)
+);
+
+CREATE TABLE IF NOT EXISTS oasis_3.runtimes_checkpoints
+(
+  height       BIGINT NOT NULL REFERENCES oasis_3.checkpointed_heights(height) ON DELETE CASCADE,
+  id           TEXT NOT NULL,
+  suspended    BOOLEAN NOT NULL,
+  kind         TEXT NOT NULL,
+  tee_hardware TEXT NOT NULL,
+  key_manager  TEXT,
+
+  PRIMARY KEY (height, id)
+);
+
+CREATE TABLE IF NOT EXISTS oasis_3.allowances_checkpoints
+(
+  height      BIGINT NOT NULL REFERENCES oasis_3.checkpointed_heights(height) ON DELETE CASCADE,
+  owner       TEXT NOT NULL,
+  beneficiary TEXT NOT NULL,
+  allowance   NUMERIC,
+
+  PRIMARY KEY (height, owner, beneficiary)
+);
+
+CREATE TABLE IF NOT EXISTS oasis_3.proposals_checkpoints
+(
+  height    BIGINT NOT NULL REFERENCES oasis_3.checkpointed_heights(height) ON DELETE CASCADE,
+  id        BIGINT NOT NULL,
+  submitter TEXT NOT NULL,
+  state     TEXT NOT NULL,
+  executed  BOOLEAN NOT NULL,
+  deposit   NUMERIC NOT NULL,
+
+  handler            TEXT,
+  cp_target_version  TEXT,
+  rhp_target_version TEXT,
+  rcp_target_version TEXT,
+  upgrade_epoch      BIGINT,
+
+  cancels BIGINT,
+
+  created_at    BIGINT NOT NULL,
+  closes_at     BIGINT NOT NULL,
+  invalid_votes NUMERIC NOT NULL,
+
+  PRIMARY KEY (height, id)
+);
+
+CREATE TABLE IF NOT EXISTS oasis_3.votes_checkpoints
+(
+  height   BIGINT NOT NULL REFERENCES oasis_3.checkpointed_heights(height) ON DELETE CASCADE,
+  proposal BIGINT NOT NULL,
+  voter    TEXT NOT NULL,
+  vote     TEXT,
+  weight   NUMERIC,
+
+  PRIMARY KEY (height, proposal, voter)
+);
+
+COMMIT;
@@ -0,0 +1,12 @@
+-- Index of the accounts related to staking events, by which account activity
+-- is exported.
+
+BEGIN;
+
+-- The expression must match that of the account activity export query for
+-- the index to be used.
+CREATE INDEX IF NOT EXISTS ix_events_staking_accounts ON oasis_3.events
+  USING GIN ((ARRAY[body->>'from', body->>'to', body->>'owner', body->>'escrow', body->>'beneficiary']))
+  WHERE backend = 'staking';
+
+COMMIT;
@@ -0,0 +1,28 @@
+package storage
+
+import (
+	"os"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestMigrationVersion(t *testing.T) {
+	entries, err := os.ReadDir("migrations")
+	require.Nil(t, err)
+
+	var latest uint64
+	for _, e := range entries {
+		if !strings.HasSuffix(e.Name(), ".up.sql") {
+			continue
+		}
+		version, err := strconv.ParseUint(strings.SplitN(e.Name(), "_", 2)[0], 10, 64)
+		require.Nil(t, err, e.Name())
+		if version > latest {
+			latest = version
+		}
+	}
+	require.Equal(t, uint64(MigrationVersion), latest, "MigrationVersion is not the version of the latest migration")
+}
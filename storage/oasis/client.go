@@ -71,7 +71,8 @@ func (cf *ClientFactory) Runtime(runtimeID string) (*RuntimeClient, error) {
 		ID: runtimeID,
 	})
 
-	consensusChainContext, err := connection.Consensus().GetChainContext(ctx)
+	consensus := connection.Consensus()
+	consensusChainContext, err := consensus.GetChainContext(ctx)
 	if err != nil {
 		return nil, err
 	}
@@ -86,9 +87,10 @@ func (cf *ClientFactory) Runtime(runtimeID string) (*RuntimeClient, error) {
 
 	rtCtx := runtimeSignature.DeriveChainContext(info.ID, consensusChainContext)
 	return &RuntimeClient{
-		client:  client,
-		network: cf.network,
-		info:    info,
-		rtCtx:   rtCtx,
+		client:    client,
+		consensus: consensus,
+		network:   cf.network,
+		info:      info,
+		rtCtx:     rtCtx,
 	}, nil
 }
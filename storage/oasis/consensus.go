@@ -2,10 +2,13 @@ package oasis
 
 import (
 	"context"
+	"errors"
 	"fmt"
 
 	"github.com/oasisprotocol/oasis-core/go/common"
 	"github.com/oasisprotocol/oasis-core/go/common/cbor"
+	"github.com/oasisprotocol/oasis-core/go/common/crypto/signature"
+	"github.com/oasisprotocol/oasis-core/go/common/quantity"
 	consensus "github.com/oasisprotocol/oasis-core/go/consensus/api"
 	"github.com/oasisprotocol/oasis-core/go/consensus/api/transaction"
 	genesisAPI "github.com/oasisprotocol/oasis-core/go/genesis/api"
@@ -90,6 +93,21 @@ func (cc *ConsensusClient) Name() string {
 	return fmt.Sprintf("%s_consensus", moduleName)
 }
 
+// ChainContext returns the chain domain separation context of the network.
+func (cc *ConsensusClient) ChainContext(ctx context.Context) (string, error) {
+	return cc.client.GetChainContext(ctx)
+}
+
+// LatestHeight returns the height of the latest consensus block.
+func (cc *ConsensusClient) LatestHeight(ctx context.Context) (int64, error) {
+	block, err := cc.client.GetBlock(ctx, consensus.HeightLatest)
+	if err != nil {
+		return 0, err
+	}
+
+	return block.Height, nil
+}
+
 // BlockData retrieves data about a consensus block at the provided block height.
 func (cc *ConsensusClient) BlockData(ctx context.Context, height int64) (*storage.ConsensusBlockData, error) {
 	block, err := cc.client.GetBlock(ctx, height)
@@ -182,16 +200,90 @@ func (cc *ConsensusClient) RegistryData(ctx context.Context, height int64) (*sto
 		}
 	}
 
+	unfrozenEntities := make(map[signature.PublicKey]signature.PublicKey)
+	for _, e := range nodeUnfrozenEvents {
+		node, err := cc.client.Registry().GetNode(ctx, &registryAPI.IDQuery{
+			Height: height,
+			ID:     e.NodeID,
+		})
+		if err != nil {
+			return nil, err
+		}
+		unfrozenEntities[e.NodeID] = node.EntityID
+	}
+
 	return &storage.RegistryData{
+		Height:               height,
 		RuntimeEvents:        runtimeEvents,
 		EntityEvents:         entityEvents,
 		NodeEvents:           nodeEvents,
 		NodeUnfrozenEvents:   nodeUnfrozenEvents,
+		UnfrozenNodeEntities: unfrozenEntities,
 		RuntimeSuspensions:   suspensions,
 		RuntimeUnsuspensions: unsuspensions,
 	}, nil
 }
 
+// NodeFreezes retrieves nodes that have been frozen since the previous block.
+//
+// Validators are frozen when their entity is slashed, and runtime nodes
+// are frozen for liveness failures on epoch transitions, so only the nodes
+// of slashed entities, or all nodes on epoch transitions, are checked.
+func (cc *ConsensusClient) NodeFreezes(ctx context.Context, height int64, slashed []stakingAPI.Address, epochChanged bool) ([]*storage.NodeFreeze, error) {
+	if height == cc.genesisHeight || (len(slashed) == 0 && !epochChanged) {
+		return nil, nil
+	}
+	isSlashed := make(map[stakingAPI.Address]bool, len(slashed))
+	for _, address := range slashed {
+		isSlashed[address] = true
+	}
+
+	nodes, err := cc.client.Registry().GetNodes(ctx, height)
+	if err != nil {
+		return nil, err
+	}
+
+	var freezes []*storage.NodeFreeze
+	for _, node := range nodes {
+		if !epochChanged && !isSlashed[stakingAPI.NewAddress(node.EntityID)] {
+			continue
+		}
+
+		status, err := cc.client.Registry().GetNodeStatus(ctx, &registryAPI.IDQuery{
+			Height: height,
+			ID:     node.ID,
+		})
+		if err != nil {
+			return nil, err
+		}
+		if !status.IsFrozen() {
+			continue
+		}
+
+		// Nodes registered at this height have no previous status.
+		prevStatus, err := cc.client.Registry().GetNodeStatus(ctx, &registryAPI.IDQuery{
+			Height: height - 1,
+			ID:     node.ID,
+		})
+		switch {
+		case errors.Is(err, registryAPI.ErrNoSuchNode):
+			prevStatus = &registryAPI.NodeStatus{}
+		case err != nil:
+			return nil, err
+		}
+		if prevStatus.FreezeEndTime == status.FreezeEndTime {
+			continue
+		}
+
+		freezes = append(freezes, &storage.NodeFreeze{
+			NodeID:        node.ID,
+			EntityID:      node.EntityID,
+			FreezeEndTime: status.FreezeEndTime,
+		})
+	}
+	return freezes, nil
+}
+
 // runtimeUpdates gets runtimes that have seen status changes since the previous block.
 func (cc *ConsensusClient) runtimeUpdates(ctx context.Context, height int64) (map[string]bool, error) {
 	rtsCurr, err := cc.runtimes(ctx, height)
@@ -277,6 +369,11 @@ func (cc *ConsensusClient) StakingData(ctx context.Context, height int64) (*stor
 
 // SchedulerData retrieves validators and runtime committees at the provided block height.
 func (cc *ConsensusClient) SchedulerData(ctx context.Context, height int64) (*storage.SchedulerData, error) {
+	epoch, err := cc.client.Beacon().GetEpoch(ctx, height)
+	if err != nil {
+		return nil, err
+	}
+
 	validators, err := cc.client.Scheduler().GetValidators(ctx, height)
 	if err != nil {
 		return nil, err
@@ -301,6 +398,8 @@ func (cc *ConsensusClient) SchedulerData(ctx context.Context, height int64) (*st
 	}
 
 	return &storage.SchedulerData{
+		Height:     height,
+		Epoch:      epoch,
 		Validators: validators,
 		Committees: committees,
 	}, nil
@@ -317,6 +416,11 @@ func (cc *ConsensusClient) GovernanceData(ctx context.Context, height int64) (*s
 	var executions []*governanceAPI.ProposalExecutedEvent
 	var finalizations []*governanceAPI.Proposal
 	var votes []*governanceAPI.VoteEvent
+	talliedVotes := make(map[uint64][]*storage.TalliedVote)
+
+	// Votes are weighted by the escrow of validator entities, which is
+	// only needed when proposals close.
+	var escrows map[stakingAPI.Address]*quantity.Quantity
 
 	for _, event := range events {
 		switch e := event; {
@@ -340,6 +444,26 @@ func (cc *ConsensusClient) GovernanceData(ctx context.Context, height int64) (*s
 				return nil, err
 			}
 			finalizations = append(finalizations, proposal)
+
+			if escrows == nil {
+				if escrows, err = cc.validatorEscrows(ctx, height); err != nil {
+					return nil, err
+				}
+			}
+			voteEntries, err := cc.client.Governance().Votes(ctx, &governanceAPI.ProposalQuery{
+				Height:     height,
+				ProposalID: proposal.ID,
+			})
+			if err != nil {
+				return nil, err
+			}
+			for _, ve := range voteEntries {
+				talliedVotes[proposal.ID] = append(talliedVotes[proposal.ID], &storage.TalliedVote{
+					Voter:  ve.Voter,
+					Vote:   ve.Vote,
+					Weight: escrows[ve.Voter],
+				})
+			}
 		case e.Vote != nil:
 			votes = append(votes, event.Vote)
 		}
@@ -349,5 +473,41 @@ func (cc *ConsensusClient) GovernanceData(ctx context.Context, height int64) (*s
 		ProposalExecutions:    executions,
 		ProposalFinalizations: finalizations,
 		Votes:                 votes,
+		TalliedVotes:          talliedVotes,
 	}, nil
 }
+
+// validatorEscrows returns the active escrow of each entity with a node in
+// the validator set at the provided height, by entity address.
+func (cc *ConsensusClient) validatorEscrows(ctx context.Context, height int64) (map[stakingAPI.Address]*quantity.Quantity, error) {
+	validators, err := cc.client.Scheduler().GetValidators(ctx, height)
+	if err != nil {
+		return nil, err
+	}
+
+	escrows := make(map[stakingAPI.Address]*quantity.Quantity)
+	for _, validator := range validators {
+		node, err := cc.client.Registry().GetNode(ctx, &registryAPI.IDQuery{
+			Height: height,
+			ID:     validator.ID,
+		})
+		if err != nil {
+			return nil, err
+		}
+		address := stakingAPI.NewAddress(node.EntityID)
+
+		// Entities with several validator nodes are only weighted once.
+		if _, ok := escrows[address]; ok {
+			continue
+		}
+		account, err := cc.client.Staking().Account(ctx, &stakingAPI.OwnerQuery{
+			Height: height,
+			Owner:  address,
+		})
+		if err != nil {
+			return nil, err
+		}
+		escrows[address] = &account.Escrow.Active.Balance
+	}
+	return escrows, nil
+}
@@ -4,6 +4,8 @@ import (
 	"context"
 	"fmt"
 
+	consensus "github.com/oasisprotocol/oasis-core/go/consensus/api"
+	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/client"
 	config "github.com/oasisprotocol/oasis-sdk/client-sdk/go/config"
 	connection "github.com/oasisprotocol/oasis-sdk/client-sdk/go/connection"
 	runtimeSignature "github.com/oasisprotocol/oasis-sdk/client-sdk/go/crypto/signature"
@@ -17,13 +19,24 @@ import (
 
 // RuntimeClient is a client to a ParaTime.
 type RuntimeClient struct {
-	client  connection.RuntimeClient
-	network *config.Network
+	client    connection.RuntimeClient
+	consensus consensus.ClientBackend
+	network   *config.Network
 
 	info  *types.RuntimeInfo
 	rtCtx runtimeSignature.Context
 }
 
+// LatestRound gets the latest round of the runtime.
+func (rc *RuntimeClient) LatestRound(ctx context.Context) (uint64, error) {
+	block, err := rc.client.GetBlock(ctx, client.RoundLatest)
+	if err != nil {
+		return 0, err
+	}
+
+	return block.Header.Round, nil
+}
+
 // BlockData gets block data in the specified round.
 func (rc *RuntimeClient) BlockData(ctx context.Context, round uint64) (*storage.RuntimeBlockData, error) {
 	block, err := rc.client.GetBlock(ctx, round)
@@ -124,6 +137,12 @@ func (rc *RuntimeClient) ConsensusAccountsData(ctx context.Context, round uint64
 	}, nil
 }
 
+// ChainContext returns the chain domain separation context of the
+// consensus layer of the network.
+func (rc *RuntimeClient) ChainContext(ctx context.Context) (string, error) {
+	return rc.consensus.GetChainContext(ctx)
+}
+
 // Name returns the name of the client, for the RuntimeSourceStorage interface.
 func (rc *RuntimeClient) Name() string {
 	paratimeName := "unknown"
@@ -0,0 +1,225 @@
+// Package pools implements the connection pools of target storage backed
+// by PostgreSQL-compatible databases, which send writes to a primary and
+// balance reads between read replicas.
+package pools
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"sync/atomic"
+	"time"
+
+	"github.com/jackc/pgx/v4"
+	"github.com/jackc/pgx/v4/pgxpool"
+)
+
+// PrimaryName is the name of the pool of the primary.
+const PrimaryName = "primary"
+
+// Config is the configuration of connection pools. Settings that are not
+// set are left at the defaults of the connection strings.
+type Config struct {
+	// Primary is the connection string of the primary.
+	Primary string
+
+	// Replicas are the connection strings of read replicas. Reads are
+	// sent to the primary if there are none.
+	Replicas []string
+
+	// MaxConns is the maximum size of each pool.
+	MaxConns int32
+
+	// MinConns is the minimum size of each pool.
+	MinConns int32
+
+	// MaxConnLifetime is how long a connection may be used before
+	// it is closed.
+	MaxConnLifetime time.Duration
+
+	// MaxConnIdleTime is how long a connection may be idle before
+	// it is closed.
+	MaxConnIdleTime time.Duration
+
+	// HealthCheckPeriod is the interval at which idle connections
+	// are checked.
+	HealthCheckPeriod time.Duration
+
+	// StatementTimeout is how long a statement may run before it is
+	// cancelled by the database.
+	StatementTimeout time.Duration
+}
+
+// parse parses the configuration of a pool connecting to the
+// connection string.
+func (cfg *Config) parse(connString string) (*pgxpool.Config, error) {
+	config, err := pgxpool.ParseConfig(connString)
+	if err != nil {
+		return nil, err
+	}
+	if cfg.MaxConns > 0 {
+		config.MaxConns = cfg.MaxConns
+	}
+	if cfg.MinConns > 0 {
+		config.MinConns = cfg.MinConns
+	}
+	if cfg.MaxConnLifetime > 0 {
+		config.MaxConnLifetime = cfg.MaxConnLifetime
+	}
+	if cfg.MaxConnIdleTime > 0 {
+		config.MaxConnIdleTime = cfg.MaxConnIdleTime
+	}
+	if cfg.HealthCheckPeriod > 0 {
+		config.HealthCheckPeriod = cfg.HealthCheckPeriod
+	}
+	if cfg.StatementTimeout > 0 {
+		config.ConnConfig.RuntimeParams["statement_timeout"] = strconv.FormatInt(cfg.StatementTimeout.Milliseconds(), 10)
+	}
+	return config, nil
+}
+
+// Pools are the connection pools of a primary and its read replicas.
+type Pools struct {
+	primary  *pgxpool.Pool
+	replicas []*pgxpool.Pool
+
+	// next is the index of the replica the next read is sent to.
+	next uint64
+}
+
+// Connect connects the pools of a configuration.
+func Connect(ctx context.Context, cfg *Config) (*Pools, error) {
+	var p Pools
+	for i, connString := range append([]string{cfg.Primary}, cfg.Replicas...) {
+		config, err := cfg.parse(connString)
+		if err != nil {
+			p.Close()
+			return nil, err
+		}
+		pool, err := pgxpool.ConnectConfig(ctx, config)
+		if err != nil {
+			p.Close()
+			return nil, err
+		}
+		if i == 0 {
+			p.primary = pool
+		} else {
+			p.replicas = append(p.replicas, pool)
+		}
+	}
+	return &p, nil
+}
+
+// Primary returns the pool of the primary.
+func (p *Pools) Primary() *pgxpool.Pool {
+	return p.primary
+}
+
+// Read returns the pool that a read is sent to. Reads are balanced
+// between replicas in turn, and are sent to the primary if there are
+// none or if they must read preceding writes.
+func (p *Pools) Read(primary bool) *pgxpool.Pool {
+	if primary || len(p.replicas) == 0 {
+		return p.primary
+	}
+	i := atomic.AddUint64(&p.next, 1) - 1
+	return p.replicas[i%uint64(len(p.replicas))]
+}
+
+// Query submits a query to a pool. Queries with a statement timeout run in a
+// read-only transaction of their own, in which the timeout is set, and which
+// ends once their rows are closed.
+func Query(ctx context.Context, pool *pgxpool.Pool, statementTimeout time.Duration, sql string, args ...interface{}) (pgx.Rows, error) {
+	if statementTimeout <= 0 {
+		return pool.Query(ctx, sql, args...)
+	}
+
+	tx, err := pool.BeginTx(ctx, pgx.TxOptions{AccessMode: pgx.ReadOnly})
+	if err != nil {
+		return nil, err
+	}
+	if _, err = tx.Exec(ctx, fmt.Sprintf("SET LOCAL statement_timeout = %d", statementTimeout.Milliseconds())); err != nil {
+		_ = tx.Rollback(context.Background())
+		return nil, err
+	}
+	rows, err := tx.Query(ctx, sql, args...)
+	if err != nil {
+		_ = tx.Rollback(context.Background())
+		return nil, err
+	}
+	return &txRows{Rows: rows, tx: tx}, nil
+}
+
+// QueryRow submits a query for a single row to a pool. As with Query, queries
+// with a statement timeout run in a read-only transaction of their own, which
+// ends once the row is scanned.
+func QueryRow(ctx context.Context, pool *pgxpool.Pool, statementTimeout time.Duration, sql string, args ...interface{}) pgx.Row {
+	if statementTimeout <= 0 {
+		return pool.QueryRow(ctx, sql, args...)
+	}
+	rows, err := Query(ctx, pool, statementTimeout, sql, args...)
+	return &row{rows: rows, err: err}
+}
+
+// row is the first row of a query, which, like the rows returned by
+// pgx.Conn.QueryRow, must be scanned so that its rows are closed.
+type row struct {
+	rows pgx.Rows
+	err  error
+}
+
+// Scan scans the first row into dest, or returns pgx.ErrNoRows if there
+// are none, and closes the rows.
+func (r *row) Scan(dest ...interface{}) error {
+	if r.err != nil {
+		return r.err
+	}
+	defer r.rows.Close()
+
+	if !r.rows.Next() {
+		if err := r.rows.Err(); err != nil {
+			return err
+		}
+		return pgx.ErrNoRows
+	}
+	if err := r.rows.Scan(dest...); err != nil {
+		return err
+	}
+	r.rows.Close()
+	return r.rows.Err()
+}
+
+// txRows are the rows of a query in a transaction of its own.
+type txRows struct {
+	pgx.Rows
+	tx pgx.Tx
+}
+
+// Close closes the rows and ends their transaction, which only reads, so
+// there is nothing to commit. The transaction is ended even if the query
+// was cancelled, so that its connection is released.
+func (r *txRows) Close() {
+	r.Rows.Close()
+	_ = r.tx.Rollback(context.Background())
+}
+
+// Stats returns the statistics of the pools by name.
+func (p *Pools) Stats() map[string]*pgxpool.Stat {
+	stats := map[string]*pgxpool.Stat{
+		PrimaryName: p.primary.Stat(),
+	}
+	for i, replica := range p.replicas {
+		stats[fmt.Sprintf("replica_%d", i)] = replica.Stat()
+	}
+	return stats
+}
+
+// Close closes the pools.
+func (p *Pools) Close() {
+	if p.primary != nil {
+		p.primary.Close()
+	}
+	for _, replica := range p.replicas {
+		replica.Close()
+	}
+}
@@ -9,6 +9,8 @@ import (
 	"github.com/jackc/pgx/v4/pgxpool"
 
 	"github.com/oasisprotocol/oasis-indexer/log"
+	"github.com/oasisprotocol/oasis-indexer/storage"
+	"github.com/oasisprotocol/oasis-indexer/storage/pools"
 )
 
 const (
@@ -17,23 +19,25 @@ const (
 
 // Client is a client for connecting to PostgreSQL.
 type Client struct {
-	pool   *pgxpool.Pool
+	pools  *pools.Pools
 	logger *log.Logger
 }
 
-// NewClient creates a new PostgreSQL client.
+// NewClient creates a new PostgreSQL client of the database at the
+// connection string, with the pool settings of the connection string.
 func NewClient(connString string, l *log.Logger) (*Client, error) {
-	config, err := pgxpool.ParseConfig(connString)
-	if err != nil {
-		return nil, err
-	}
+	return NewPooledClient(&pools.Config{Primary: connString}, l)
+}
 
-	pool, err := pgxpool.ConnectConfig(context.Background(), config)
+// NewPooledClient creates a new PostgreSQL client of a primary and its
+// read replicas.
+func NewPooledClient(cfg *pools.Config, l *log.Logger) (*Client, error) {
+	p, err := pools.Connect(context.Background(), cfg)
 	if err != nil {
 		return nil, err
 	}
 	return &Client{
-		pool:   pool,
+		pools:  p,
 		logger: l.WithModule(moduleName),
 	}, nil
 }
@@ -44,7 +48,7 @@ func NewClient(connString string, l *log.Logger) (*Client, error) {
 // by any indexer. We only care about atomic success or failure of the batch of queries
 // corresponding to a new block.
 func (c *Client) SendBatch(ctx context.Context, batch *pgx.Batch) error {
-	if err := c.pool.BeginTxFunc(ctx, pgx.TxOptions{}, func(tx pgx.Tx) error {
+	if err := c.pools.Primary().BeginTxFunc(ctx, pgx.TxOptions{}, func(tx pgx.Tx) error {
 		batchResults := tx.SendBatch(ctx, batch)
 		defer batchResults.Close()
 		for i := 0; i < batch.Len(); i++ {
@@ -66,7 +70,7 @@ func (c *Client) SendBatch(ctx context.Context, batch *pgx.Batch) error {
 
 // Query submits a new read query to PostgreSQL.
 func (c *Client) Query(ctx context.Context, sql string, args ...interface{}) (pgx.Rows, error) {
-	rows, err := c.pool.Query(ctx, sql, args...)
+	rows, err := pools.Query(ctx, c.pools.Read(storage.UsePrimary(ctx)), storage.StatementTimeout(ctx), sql, args...)
 	if err != nil {
 		c.logger.Error("failed to query db",
 			"error", err,
@@ -78,12 +82,18 @@ func (c *Client) Query(ctx context.Context, sql string, args ...interface{}) (pg
 
 // QueryRow submits a new read query for a single row to PostgreSQL.
 func (c *Client) QueryRow(ctx context.Context, sql string, args ...interface{}) pgx.Row {
-	return c.pool.QueryRow(ctx, sql, args...)
+	return pools.QueryRow(ctx, c.pools.Read(storage.UsePrimary(ctx)), storage.StatementTimeout(ctx), sql, args...)
+}
+
+// Stats returns the statistics of the connection pools of the client
+// by name.
+func (c *Client) Stats() map[string]*pgxpool.Stat {
+	return c.pools.Stats()
 }
 
 // Shutdown implements the storage.TargetStorage interface for Client.
 func (c *Client) Shutdown() {
-	c.pool.Close()
+	c.pools.Close()
 }
 
 // Name implements the storage.TargetStorage interface for Client.
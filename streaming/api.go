@@ -0,0 +1,36 @@
+// Package streaming implements a message bus announcing indexing progress,
+// used to stream newly indexed data to API clients.
+package streaming
+
+import (
+	"context"
+)
+
+// Layer is the layer of the Oasis Network a message refers to.
+type Layer string
+
+const (
+	// LayerConsensus is the consensus layer.
+	LayerConsensus Layer = "consensus"
+	// LayerEmerald is the Emerald ParaTime.
+	LayerEmerald Layer = "emerald"
+)
+
+// Message announces that a block or round has been committed to target storage.
+type Message struct {
+	Layer  Layer `json:"layer"`
+	Height int64 `json:"height"`
+}
+
+// Bus is a message bus between analyzers and API servers.
+//
+// Delivery is best effort: subscribers that fall behind may miss messages,
+// and are expected to catch up from target storage using message heights.
+type Bus interface {
+	// Publish publishes a message to all subscribers.
+	Publish(ctx context.Context, msg *Message) error
+
+	// Subscribe returns a channel of published messages, which is closed
+	// once the provided context is cancelled.
+	Subscribe(ctx context.Context) (<-chan *Message, error)
+}
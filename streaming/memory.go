@@ -0,0 +1,66 @@
+package streaming
+
+import (
+	"context"
+	"sync"
+)
+
+// subscriberBufferSize is the number of messages buffered per subscriber.
+const subscriberBufferSize = 64
+
+var defaultMemoryBus = NewMemoryBus()
+
+// MemoryBus is an in-process message bus. It can only be used when analyzers
+// and API servers run within the same process.
+type MemoryBus struct {
+	mu          sync.Mutex
+	subscribers map[chan *Message]struct{}
+}
+
+// NewMemoryBus creates a new in-process message bus.
+func NewMemoryBus() *MemoryBus {
+	return &MemoryBus{
+		subscribers: make(map[chan *Message]struct{}),
+	}
+}
+
+// DefaultMemoryBus returns the in-process message bus shared by all services.
+func DefaultMemoryBus() *MemoryBus {
+	return defaultMemoryBus
+}
+
+// Publish implements the Bus interface.
+func (b *MemoryBus) Publish(ctx context.Context, msg *Message) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	for ch := range b.subscribers {
+		select {
+		case ch <- msg:
+		default:
+			// The subscriber is falling behind and will catch up
+			// from target storage.
+		}
+	}
+	return nil
+}
+
+// Subscribe implements the Bus interface.
+func (b *MemoryBus) Subscribe(ctx context.Context) (<-chan *Message, error) {
+	ch := make(chan *Message, subscriberBufferSize)
+
+	b.mu.Lock()
+	b.subscribers[ch] = struct{}{}
+	b.mu.Unlock()
+
+	go func() {
+		<-ctx.Done()
+
+		b.mu.Lock()
+		delete(b.subscribers, ch)
+		b.mu.Unlock()
+		close(ch)
+	}()
+
+	return ch, nil
+}
@@ -0,0 +1,45 @@
+package streaming
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestMemoryBus(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	b := NewMemoryBus()
+	ch, err := b.Subscribe(ctx)
+	require.Nil(t, err)
+
+	msg := &Message{Layer: LayerConsensus, Height: 8048956}
+	require.Nil(t, b.Publish(ctx, msg))
+	require.Equal(t, msg, <-ch)
+
+	cancel()
+	select {
+	case _, ok := <-ch:
+		require.False(t, ok)
+	case <-time.After(time.Second):
+		t.Fatal("subscription not closed on cancellation")
+	}
+}
+
+func TestMemoryBusSlowSubscriber(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	b := NewMemoryBus()
+	ch, err := b.Subscribe(ctx)
+	require.Nil(t, err)
+
+	// Publishing must not block on subscribers that are falling behind.
+	for i := 0; i < 2*subscriberBufferSize; i++ {
+		require.Nil(t, b.Publish(ctx, &Message{Layer: LayerEmerald, Height: int64(i)}))
+	}
+	require.Len(t, ch, subscriberBufferSize)
+}
@@ -0,0 +1,141 @@
+package streaming
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/jackc/pgx/v4"
+
+	"github.com/oasisprotocol/oasis-indexer/analyzer/util"
+	"github.com/oasisprotocol/oasis-indexer/log"
+	"github.com/oasisprotocol/oasis-indexer/storage"
+)
+
+const (
+	moduleName = "streaming"
+
+	// postgresChannel is the channel on which messages are published.
+	postgresChannel = "indexing_progress"
+)
+
+// PostgresBus is a message bus backed by PostgreSQL LISTEN/NOTIFY,
+// which allows analyzers and API servers to run as separate processes.
+type PostgresBus struct {
+	connString string
+	channel    string
+	target     storage.TargetStorage
+	logger     *log.Logger
+
+	// subscribers receives the notifications of the single connection
+	// listening on the channel, and fans them out to all subscribers.
+	subscribers *MemoryBus
+	listenOnce  sync.Once
+}
+
+// NewPostgresBus creates a new message bus. Messages are published through
+// target storage, and received over a single dedicated connection to the
+// provided PostgreSQL endpoint, which is opened on the first subscription.
+func NewPostgresBus(connString string, target storage.TargetStorage, logger *log.Logger) *PostgresBus {
+	return &PostgresBus{
+		connString:  connString,
+		channel:     postgresChannel,
+		target:      target,
+		logger:      logger.WithModule(moduleName),
+		subscribers: NewMemoryBus(),
+	}
+}
+
+// Publish implements the Bus interface.
+func (b *PostgresBus) Publish(ctx context.Context, msg *Message) error {
+	payload, err := json.Marshal(msg)
+	if err != nil {
+		return err
+	}
+
+	batch := &storage.QueryBatch{}
+	batch.Queue("SELECT pg_notify($1, $2)", b.channel, string(payload))
+	return b.target.SendBatch(ctx, batch)
+}
+
+// Subscribe implements the Bus interface.
+//
+// All subscribers share a single listening connection, which is kept open
+// for the lifetime of the process once the first subscriber subscribes.
+func (b *PostgresBus) Subscribe(ctx context.Context) (<-chan *Message, error) {
+	b.listenOnce.Do(func() {
+		go b.receive(context.Background())
+	})
+	return b.subscribers.Subscribe(ctx)
+}
+
+// listen opens a new connection listening on the bus channel.
+func (b *PostgresBus) listen(ctx context.Context) (*pgx.Conn, error) {
+	conn, err := pgx.Connect(ctx, b.connString)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := conn.Exec(ctx, fmt.Sprintf("LISTEN %s", pgx.Identifier{b.channel}.Sanitize())); err != nil {
+		conn.Close(ctx)
+		return nil, err
+	}
+	return conn, nil
+}
+
+// receive forwards notifications to all subscribers until the context is
+// cancelled, reconnecting on connection failures.
+func (b *PostgresBus) receive(ctx context.Context) {
+	var conn *pgx.Conn
+	backoff, err := util.NewBackoff(100*time.Millisecond, 10*time.Second)
+	if err != nil {
+		b.logger.Error("error configuring bus backoff policy",
+			"err", err,
+		)
+		return
+	}
+
+	for {
+		if conn == nil {
+			if conn, err = b.listen(ctx); err != nil {
+				b.logger.Error("failed to listen for notifications",
+					"err", err,
+				)
+				if backoff.WaitContext(ctx) != nil {
+					return
+				}
+				continue
+			}
+			backoff.Reset()
+		}
+
+		notification, err := conn.WaitForNotification(ctx)
+		if err != nil {
+			conn.Close(context.Background())
+			conn = nil
+			if ctx.Err() != nil {
+				return
+			}
+			b.logger.Error("failed to receive notification",
+				"err", err,
+			)
+			continue
+		}
+
+		var msg Message
+		if err := json.Unmarshal([]byte(notification.Payload), &msg); err != nil {
+			b.logger.Error("malformed notification",
+				"payload", notification.Payload,
+				"err", err,
+			)
+			continue
+		}
+
+		if err := b.subscribers.Publish(ctx, &msg); err != nil {
+			b.logger.Error("failed to forward notification",
+				"err", err,
+			)
+		}
+	}
+}
@@ -38,6 +38,17 @@ func makeTestNodes() []v1.Node {
 	}
 }
 
+// copyNodeDescriptor copies the fields of a node that depend on the
+// network it runs in, which are not known in advance.
+func copyNodeDescriptor(dst *v1.Node, src v1.Node) {
+	dst.TLSAddresses = src.TLSAddresses
+	dst.P2PAddresses = src.P2PAddresses
+	dst.ConsensusAddresses = src.ConsensusAddresses
+	dst.VRFPubkey = src.VRFPubkey
+	dst.SoftwareVersion = src.SoftwareVersion
+	dst.Runtimes = src.Runtimes
+}
+
 func escape(s string) string {
 	return url.PathEscape(s)
 }
@@ -111,8 +122,19 @@ func TestListEntityNodes(t *testing.T) {
 	for i, node := range list.Nodes {
 		// The expiration is dynamic, until we have oasis-net-runner with a halt epoch.
 		testNodes[i].Expiration = node.Expiration
+		copyNodeDescriptor(&testNodes[i], node)
 		require.Equal(t, testNodes[i], node)
 	}
+
+	var validators v1.NodeList
+	err = tests.GetFrom(fmt.Sprintf("/consensus/entities/%s/nodes?role=validator", escape(testNodes[0].EntityID)), &validators)
+	require.Nil(t, err)
+	require.Equal(t, len(testNodes), len(validators.Nodes))
+
+	var computeNodes v1.NodeList
+	err = tests.GetFrom(fmt.Sprintf("/consensus/entities/%s/nodes?role=compute", escape(testNodes[0].EntityID)), &computeNodes)
+	require.Nil(t, err)
+	require.Empty(t, computeNodes.Nodes)
 }
 
 func TestGetEntityNode(t *testing.T) {
@@ -131,5 +153,85 @@ func TestGetEntityNode(t *testing.T) {
 	require.Nil(t, err)
 	// The expiration is dynamic, until we have oasis-net-runner with a halt epoch.
 	testNodes[0].Expiration = node.Expiration
+	copyNodeDescriptor(&testNodes[0], node)
 	require.Equal(t, testNodes[0], node)
 }
+
+func TestGetEntityNodeHistory(t *testing.T) {
+	if _, ok := os.LookupEnv("OASIS_INDEXER_E2E"); !ok {
+		t.Skip("skipping test since e2e tests are not enabled")
+	}
+
+	tests.Init()
+
+	testNodes := makeTestNodes()
+	endHeight := tests.GenesisHeight + int64(len(testNodes)-1)
+	<-tests.After(endHeight)
+
+	var history v1.NodeHistory
+	err := tests.GetFrom(fmt.Sprintf("/consensus/entities/%s/nodes/%s/history", escape(testNodes[0].EntityID), escape(testNodes[0].ID)), &history)
+	require.Nil(t, err)
+	require.Equal(t, testNodes[0].ID, history.NodeID)
+	require.Equal(t, testNodes[0].EntityID, history.EntityID)
+
+	// Events are listed from the most recent, and the oldest event of a
+	// node is its registration.
+	require.NotEmpty(t, history.Events)
+	oldest := history.Events[len(history.Events)-1]
+	require.Equal(t, "registered", oldest.Event)
+	for i := 1; i < len(history.Events); i++ {
+		require.GreaterOrEqual(t, history.Events[i-1].Height, history.Events[i].Height)
+	}
+}
+
+func TestListRuntimes(t *testing.T) {
+	if _, ok := os.LookupEnv("OASIS_INDEXER_E2E"); !ok {
+		t.Skip("skipping test since e2e tests are not enabled")
+	}
+
+	tests.Init()
+
+	<-tests.After(tests.GenesisHeight)
+
+	var list v1.RuntimeList
+	err := tests.GetFrom("/consensus/runtimes", &list)
+	require.Nil(t, err)
+	require.NotEmpty(t, list.Runtimes)
+
+	// Runtimes include their committees and history only on their own.
+	var runtime v1.Runtime
+	err = tests.GetFrom(fmt.Sprintf("/consensus/runtimes/%s", list.Runtimes[0].ID), &runtime)
+	require.Nil(t, err)
+	require.Equal(t, list.Runtimes[0].ID, runtime.ID)
+	require.Equal(t, list.Runtimes[0].Kind, runtime.Kind)
+	require.NotEmpty(t, runtime.History)
+}
+
+func TestGetRuntimeHistory(t *testing.T) {
+	if _, ok := os.LookupEnv("OASIS_INDEXER_E2E"); !ok {
+		t.Skip("skipping test since e2e tests are not enabled")
+	}
+
+	tests.Init()
+
+	<-tests.After(tests.GenesisHeight)
+
+	var list v1.RuntimeList
+	err := tests.GetFrom("/consensus/runtimes", &list)
+	require.Nil(t, err)
+	require.NotEmpty(t, list.Runtimes)
+
+	var history v1.RuntimeHistory
+	err = tests.GetFrom(fmt.Sprintf("/consensus/runtimes/%s/history?limit=1000", list.Runtimes[0].ID), &history)
+	require.Nil(t, err)
+	require.Equal(t, list.Runtimes[0].ID, history.RuntimeID)
+
+	// Events are listed from the most recent, and the oldest event of a
+	// runtime is its only registration.
+	require.NotEmpty(t, history.Events)
+	require.Equal(t, "registered", history.Events[len(history.Events)-1].Event)
+	for i := 1; i < len(history.Events); i++ {
+		require.NotEqual(t, "registered", history.Events[i-1].Event)
+		require.GreaterOrEqual(t, history.Events[i-1].Height, history.Events[i].Height)
+	}
+}
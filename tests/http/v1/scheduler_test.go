@@ -0,0 +1,59 @@
+package v1
+
+import (
+	"fmt"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	v1 "github.com/oasisprotocol/oasis-indexer/api/v1"
+	"github.com/oasisprotocol/oasis-indexer/tests"
+)
+
+func TestGetEpochValidators(t *testing.T) {
+	if _, ok := os.LookupEnv("OASIS_INDEXER_E2E"); !ok {
+		t.Skip("skipping test since e2e tests are not enabled")
+	}
+
+	tests.Init()
+
+	<-tests.After(tests.GenesisHeight)
+
+	var epochs v1.EpochList
+	err := tests.GetFrom("/consensus/epochs?limit=1", &epochs)
+	require.Nil(t, err)
+	require.Len(t, epochs.Epochs, 1)
+
+	var validators v1.EpochValidators
+	err = tests.GetFrom(fmt.Sprintf("/consensus/epochs/%d/validators", epochs.Epochs[0].ID), &validators)
+	require.Nil(t, err)
+	require.Equal(t, epochs.Epochs[0].ID, validators.Epoch)
+	require.NotEmpty(t, validators.Validators)
+	for i := 1; i < len(validators.Validators); i++ {
+		require.GreaterOrEqual(t, validators.Validators[i-1].VotingPower, validators.Validators[i].VotingPower)
+	}
+}
+
+func TestGetEpochCommittees(t *testing.T) {
+	if _, ok := os.LookupEnv("OASIS_INDEXER_E2E"); !ok {
+		t.Skip("skipping test since e2e tests are not enabled")
+	}
+
+	tests.Init()
+
+	<-tests.After(tests.GenesisHeight)
+
+	var epochs v1.EpochList
+	err := tests.GetFrom("/consensus/epochs?limit=1", &epochs)
+	require.Nil(t, err)
+	require.Len(t, epochs.Epochs, 1)
+
+	var committees v1.EpochCommittees
+	err = tests.GetFrom(fmt.Sprintf("/consensus/epochs/%d/committees?kind=executor", epochs.Epochs[0].ID), &committees)
+	require.Nil(t, err)
+	require.Equal(t, epochs.Epochs[0].ID, committees.Epoch)
+	for _, member := range committees.Members {
+		require.Equal(t, "executor", member.Kind)
+	}
+}
@@ -67,3 +67,35 @@ func TestGetAccount(t *testing.T) {
 		require.Equal(t, testAccount, account)
 	}
 }
+
+func TestGetValidatorDelegators(t *testing.T) {
+	if _, ok := os.LookupEnv("OASIS_INDEXER_E2E"); !ok {
+		t.Skip("skipping test since e2e tests are not enabled")
+	}
+
+	tests.Init()
+
+	testEntities := makeTestEntities()
+	<-tests.After(stakingEndHeight)
+
+	var list v1.ValidatorDelegatorList
+	err := tests.GetFrom(fmt.Sprintf("/consensus/validators/%s/delegators", escape(testEntities[0].ID)), &list)
+	require.Nil(t, err)
+	require.LessOrEqual(t, uint64(len(list.Delegators)), list.TotalCount)
+
+	// Delegators are listed by amount, and account for at most the
+	// total amount delegated.
+	var amount uint64
+	for i, d := range list.Delegators {
+		if i > 0 {
+			require.GreaterOrEqual(t, list.Delegators[i-1].Amount, d.Amount)
+		}
+		amount += d.Amount
+	}
+	require.LessOrEqual(t, amount, list.TotalAmount)
+
+	var debonding v1.ValidatorDebondingDelegatorList
+	err = tests.GetFrom(fmt.Sprintf("/consensus/validators/%s/debonding_delegators", escape(testEntities[0].ID)), &debonding)
+	require.Nil(t, err)
+	require.LessOrEqual(t, uint64(len(debonding.DebondingDelegators)), debonding.TotalCount)
+}
@@ -0,0 +1,315 @@
+package verifier
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/oasisprotocol/oasis-core/go/common/entity"
+	"github.com/oasisprotocol/oasis-core/go/common/node"
+	"github.com/oasisprotocol/oasis-core/go/common/quantity"
+	genesis "github.com/oasisprotocol/oasis-core/go/genesis/api"
+	registry "github.com/oasisprotocol/oasis-core/go/registry/api"
+	staking "github.com/oasisprotocol/oasis-core/go/staking/api"
+)
+
+// row is a row of a table, as the text of each of its verified columns.
+type row []string
+
+// table is a verified table.
+type table struct {
+	// name is the name of the table.
+	name string
+
+	// columns are the verified columns of the table.
+	columns []string
+
+	// query selects the key and the verified columns of each row of the
+	// checkpoint at the height given as its argument, as text, given the
+	// chain ID.
+	query string
+
+	// expected returns the rows of node state by key.
+	expected func(document *genesis.Document) (map[string]row, error)
+}
+
+var tables = []*table{
+	{
+		name:    "entities",
+		columns: []string{"address", "nodes"},
+		query: `
+			SELECT e.id, COALESCE(e.address, ''), COALESCE((
+				SELECT string_agg(n.id, ',' ORDER BY n.id) FROM (
+					SELECT node_id AS id FROM %[1]s.claimed_nodes_checkpoints WHERE height = e.height AND entity_id = e.id
+					UNION
+					SELECT id FROM %[1]s.nodes_checkpoints WHERE height = e.height AND entity_id = e.id
+				) AS n
+			), '')
+			FROM %[1]s.entities_checkpoints AS e
+			WHERE e.height = $1`,
+		expected: expectedEntities,
+	},
+	{
+		name:    "nodes",
+		columns: []string{"entity_id", "expiration", "tls_pubkey", "tls_next_pubkey", "p2p_pubkey", "consensus_pubkey", "vrf_pubkey", "roles", "software_version"},
+		query: `
+			SELECT id, entity_id, expiration::TEXT, tls_pubkey, COALESCE(tls_next_pubkey, ''), COALESCE(p2p_pubkey, ''),
+				consensus_pubkey, COALESCE(vrf_pubkey, ''), COALESCE(roles, ''), COALESCE(software_version, '')
+			FROM %[1]s.nodes_checkpoints
+			WHERE height = $1`,
+		expected: expectedNodes,
+	},
+	{
+		name:    "runtimes",
+		columns: []string{"suspended", "kind", "tee_hardware", "key_manager"},
+		query: `
+			SELECT id, suspended::TEXT, kind, tee_hardware, COALESCE(key_manager, '')
+			FROM %[1]s.runtimes_checkpoints
+			WHERE height = $1`,
+		expected: expectedRuntimes,
+	},
+	{
+		name:    "accounts",
+		columns: []string{"general_balance", "nonce", "escrow_balance_active", "escrow_total_shares_active", "escrow_balance_debonding", "escrow_total_shares_debonding"},
+		query: `
+			SELECT address, general_balance::TEXT, nonce::TEXT,
+				escrow_balance_active::TEXT, escrow_total_shares_active::TEXT,
+				escrow_balance_debonding::TEXT, escrow_total_shares_debonding::TEXT
+			FROM %[1]s.accounts_checkpoints
+			WHERE height = $1`,
+		expected: expectedAccounts,
+	},
+	{
+		name:    "allowances",
+		columns: []string{"allowance"},
+		query: `
+			SELECT owner || '/' || beneficiary, COALESCE(allowance::TEXT, '')
+			FROM %[1]s.allowances_checkpoints
+			WHERE height = $1`,
+		expected: expectedAllowances,
+	},
+	{
+		name:    "delegations",
+		columns: []string{"shares"},
+		query: `
+			SELECT delegatee || '/' || delegator, shares::TEXT
+			FROM %[1]s.delegations_checkpoints
+			WHERE height = $1 AND shares <> 0`,
+		expected: expectedDelegations,
+	},
+	{
+		name:    "debonding_delegations",
+		columns: []string{"shares"},
+		query: `
+			SELECT delegatee || '/' || delegator || '/' || debond_end::TEXT, SUM(shares)::TEXT
+			FROM %[1]s.debonding_delegations_checkpoints
+			WHERE height = $1
+			GROUP BY delegatee, delegator, debond_end`,
+		expected: expectedDebondingDelegations,
+	},
+	{
+		name:    "proposals",
+		columns: []string{"submitter", "state", "deposit", "handler", "cp_target_version", "rhp_target_version", "rcp_target_version", "upgrade_epoch", "cancels", "created_at", "closes_at", "invalid_votes"},
+		query: `
+			SELECT id::TEXT, submitter, state, deposit::TEXT,
+				COALESCE(handler, ''), COALESCE(cp_target_version, ''), COALESCE(rhp_target_version, ''), COALESCE(rcp_target_version, ''),
+				COALESCE(upgrade_epoch::TEXT, ''), COALESCE(cancels::TEXT, ''),
+				created_at::TEXT, closes_at::TEXT, invalid_votes::TEXT
+			FROM %[1]s.proposals_checkpoints
+			WHERE height = $1`,
+		expected: expectedProposals,
+	},
+	{
+		name:    "votes",
+		columns: []string{"vote"},
+		query: `
+			SELECT proposal::TEXT || '/' || voter, COALESCE(vote, '')
+			FROM %[1]s.votes_checkpoints
+			WHERE height = $1`,
+		expected: expectedVotes,
+	},
+}
+
+func expectedEntities(document *genesis.Document) (map[string]row, error) {
+	rows := make(map[string]row)
+	for _, signedEntity := range document.Registry.Entities {
+		var e entity.Entity
+		if err := signedEntity.Open(registry.RegisterEntitySignatureContext, &e); err != nil {
+			return nil, err
+		}
+		nodes := make([]string, 0, len(e.Nodes))
+		for _, n := range e.Nodes {
+			nodes = append(nodes, n.String())
+		}
+		sort.Strings(nodes)
+		rows[e.ID.String()] = row{
+			staking.NewAddress(e.ID).String(),
+			strings.Join(nodes, ","),
+		}
+	}
+	return rows, nil
+}
+
+func expectedNodes(document *genesis.Document) (map[string]row, error) {
+	rows := make(map[string]row)
+	for _, signedNode := range document.Registry.Nodes {
+		// Nodes registered since genesis are signed in a different
+		// context than those of the genesis document.
+		var n node.Node
+		if err := signedNode.Open(registry.RegisterNodeSignatureContext, &n); err != nil {
+			if err = signedNode.Open(registry.RegisterGenesisNodeSignatureContext, &n); err != nil {
+				return nil, err
+			}
+		}
+		vrfPubkey := ""
+		if n.VRF != nil {
+			vrfPubkey = n.VRF.ID.String()
+		}
+		rows[n.ID.String()] = row{
+			n.EntityID.String(),
+			strconv.FormatUint(n.Expiration, 10),
+			n.TLS.PubKey.String(),
+			n.TLS.NextPubKey.String(),
+			n.P2P.ID.String(),
+			n.Consensus.ID.String(),
+			vrfPubkey,
+			n.Roles.String(),
+			n.SoftwareVersion,
+		}
+	}
+	return rows, nil
+}
+
+func expectedRuntimes(document *genesis.Document) (map[string]row, error) {
+	rows := make(map[string]row)
+	for suspended, rts := range map[bool][]*registry.Runtime{
+		false: document.Registry.Runtimes,
+		true:  document.Registry.SuspendedRuntimes,
+	} {
+		for _, rt := range rts {
+			keyManager := "none"
+			if rt.KeyManager != nil {
+				keyManager = rt.KeyManager.String()
+			}
+			rows[rt.ID.String()] = row{
+				strconv.FormatBool(suspended),
+				rt.Kind.String(),
+				rt.TEEHardware.String(),
+				keyManager,
+			}
+		}
+	}
+	return rows, nil
+}
+
+func expectedAccounts(document *genesis.Document) (map[string]row, error) {
+	rows := make(map[string]row)
+	add := func(address staking.Address, account *staking.Account) {
+		rows[address.String()] = row{
+			account.General.Balance.String(),
+			strconv.FormatUint(account.General.Nonce, 10),
+			account.Escrow.Active.Balance.String(),
+			account.Escrow.Active.TotalShares.String(),
+			account.Escrow.Debonding.Balance.String(),
+			account.Escrow.Debonding.TotalShares.String(),
+		}
+	}
+	// Special accounts with reserved addresses are not in the ledger.
+	add(staking.CommonPoolAddress, &staking.Account{General: staking.GeneralAccount{Balance: document.Staking.CommonPool}})
+	add(staking.FeeAccumulatorAddress, &staking.Account{General: staking.GeneralAccount{Balance: document.Staking.LastBlockFees}})
+	add(staking.GovernanceDepositsAddress, &staking.Account{General: staking.GeneralAccount{Balance: document.Staking.GovernanceDeposits}})
+	for address, account := range document.Staking.Ledger {
+		add(address, account)
+	}
+	return rows, nil
+}
+
+func expectedAllowances(document *genesis.Document) (map[string]row, error) {
+	rows := make(map[string]row)
+	for owner, account := range document.Staking.Ledger {
+		for beneficiary, allowance := range account.General.Allowances {
+			rows[owner.String()+"/"+beneficiary.String()] = row{allowance.String()}
+		}
+	}
+	return rows, nil
+}
+
+func expectedDelegations(document *genesis.Document) (map[string]row, error) {
+	rows := make(map[string]row)
+	for delegatee, escrows := range document.Staking.Delegations {
+		for delegator, delegation := range escrows {
+			if delegation.Shares.IsZero() {
+				continue
+			}
+			rows[delegatee.String()+"/"+delegator.String()] = row{delegation.Shares.String()}
+		}
+	}
+	return rows, nil
+}
+
+func expectedDebondingDelegations(document *genesis.Document) (map[string]row, error) {
+	shares := make(map[string]*quantity.Quantity)
+	for delegatee, escrows := range document.Staking.DebondingDelegations {
+		for delegator, debondingDelegations := range escrows {
+			for _, dd := range debondingDelegations {
+				key := fmt.Sprintf("%s/%s/%d", delegatee, delegator, dd.DebondEndTime)
+				if _, ok := shares[key]; !ok {
+					shares[key] = quantity.NewQuantity()
+				}
+				if err := shares[key].Add(&dd.Shares); err != nil {
+					return nil, err
+				}
+			}
+		}
+	}
+	rows := make(map[string]row)
+	for key, s := range shares {
+		rows[key] = row{s.String()}
+	}
+	return rows, nil
+}
+
+func expectedProposals(document *genesis.Document) (map[string]row, error) {
+	rows := make(map[string]row)
+	for _, p := range document.Governance.Proposals {
+		var handler, cpTargetVersion, rhpTargetVersion, rcpTargetVersion, upgradeEpoch, cancels string
+		switch {
+		case p.Content.Upgrade != nil:
+			handler = string(p.Content.Upgrade.Handler)
+			cpTargetVersion = p.Content.Upgrade.Target.ConsensusProtocol.String()
+			rhpTargetVersion = p.Content.Upgrade.Target.RuntimeHostProtocol.String()
+			rcpTargetVersion = p.Content.Upgrade.Target.RuntimeCommitteeProtocol.String()
+			upgradeEpoch = strconv.FormatUint(uint64(p.Content.Upgrade.Epoch), 10)
+		case p.Content.CancelUpgrade != nil:
+			cancels = strconv.FormatUint(p.Content.CancelUpgrade.ProposalID, 10)
+		default:
+			return nil, fmt.Errorf("malformed proposal %d", p.ID)
+		}
+		rows[strconv.FormatUint(p.ID, 10)] = row{
+			p.Submitter.String(),
+			p.State.String(),
+			p.Deposit.String(),
+			handler,
+			cpTargetVersion,
+			rhpTargetVersion,
+			rcpTargetVersion,
+			upgradeEpoch,
+			cancels,
+			strconv.FormatUint(uint64(p.CreatedAt), 10),
+			strconv.FormatUint(uint64(p.ClosesAt), 10),
+			strconv.FormatUint(p.InvalidVotes, 10),
+		}
+	}
+	return rows, nil
+}
+
+func expectedVotes(document *genesis.Document) (map[string]row, error) {
+	rows := make(map[string]row)
+	for proposalID, voteEntries := range document.Governance.VoteEntries {
+		for _, ve := range voteEntries {
+			rows[fmt.Sprintf("%d/%s", proposalID, ve.Voter)] = row{ve.Vote.String()}
+		}
+	}
+	return rows, nil
+}
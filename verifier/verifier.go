@@ -0,0 +1,348 @@
+// Package verifier verifies indexed state against the state of the
+// consensus layer, as reported by a node, at checkpointed heights.
+package verifier
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	"github.com/iancoleman/strcase"
+	genesis "github.com/oasisprotocol/oasis-core/go/genesis/api"
+
+	"github.com/oasisprotocol/oasis-indexer/analyzer"
+	"github.com/oasisprotocol/oasis-indexer/log"
+	"github.com/oasisprotocol/oasis-indexer/storage"
+)
+
+// maxReportedRows is the maximum number of rows of each kind of difference
+// that are listed in the report of a table. All of them are counted.
+const maxReportedRows = 100
+
+// maxCheckpointAttempts is the maximum number of attempts to checkpoint the
+// latest processed height, which fail if the index moves on meanwhile.
+const maxCheckpointAttempts = 5
+
+// Source is the node state that indexed state is verified against.
+type Source interface {
+	// GenesisDocumentAtHeight returns the state at the provided height,
+	// in the form of a genesis document.
+	GenesisDocumentAtHeight(ctx context.Context, height int64) (*genesis.Document, error)
+}
+
+// Report is the report of a verification of indexed state.
+type Report struct {
+	// Height is the height at which indexed state was verified.
+	Height int64 `json:"height"`
+
+	// Tables are the reports of each verified table.
+	Tables []*TableReport `json:"tables"`
+
+	// Mismatches is how many rows differ across all tables.
+	Mismatches int `json:"mismatches"`
+}
+
+// TableReport is the report of a verification of an indexed table.
+type TableReport struct {
+	// Table is the name of the table.
+	Table string `json:"table"`
+
+	// Expected is how many rows node state has.
+	Expected int `json:"expected"`
+
+	// Actual is how many rows are indexed.
+	Actual int `json:"actual"`
+
+	// Mismatches is how many rows are missing, unexpected or mismatched.
+	Mismatches int `json:"mismatches"`
+
+	// Missing are the keys of rows of node state that are not indexed.
+	Missing []string `json:"missing,omitempty"`
+
+	// Unexpected are the keys of indexed rows that node state does not have.
+	Unexpected []string `json:"unexpected,omitempty"`
+
+	// Mismatched are the rows whose indexed columns differ from node state.
+	Mismatched []*RowDiff `json:"mismatched,omitempty"`
+}
+
+// RowDiff is the difference between an indexed row and node state.
+type RowDiff struct {
+	// Key is the key of the row.
+	Key string `json:"key"`
+
+	// Columns are the columns that differ.
+	Columns []*ColumnDiff `json:"columns"`
+}
+
+// ColumnDiff is the difference between an indexed column and node state.
+type ColumnDiff struct {
+	Column   string `json:"column"`
+	Expected string `json:"expected"`
+	Actual   string `json:"actual"`
+}
+
+// Verifier verifies the indexed state of a chain.
+type Verifier struct {
+	chainID string
+	qf      analyzer.QueryFactory
+	source  Source
+	target  storage.TargetStorage
+	logger  *log.Logger
+}
+
+// NewVerifier creates a new verifier of the indexed state of a chain.
+func NewVerifier(chainID string, source Source, target storage.TargetStorage, logger *log.Logger) *Verifier {
+	return &Verifier{
+		chainID: strcase.ToSnake(chainID),
+		qf:      analyzer.NewQueryFactory(strcase.ToSnake(chainID), ""),
+		source:  source,
+		target:  target,
+		logger:  logger,
+	}
+}
+
+// Checkpoint checkpoints the indexed state at the latest height processed
+// by the named analyzer, and returns that height. Heights that are already
+// checkpointed, such as by periodic checkpoints, are not checkpointed again.
+func (v *Verifier) Checkpoint(ctx context.Context, analyzer string) (int64, error) {
+	for attempt := 1; ; attempt++ {
+		height, err := v.latestProcessed(ctx, analyzer)
+		if err != nil {
+			return 0, err
+		}
+		checkpointed, err := v.checkpointed(ctx, height)
+		if err != nil {
+			return 0, err
+		}
+		if checkpointed {
+			return height, nil
+		}
+
+		// The checkpoint is taken in a single snapshot of indexed state,
+		// and only recorded if the snapshot is that of the height. Since
+		// checkpointed rows reference their checkpoint, they are not
+		// copied either if the analyzer has moved on in the meantime.
+		batch := &storage.QueryBatch{}
+		batch.Queue(`SET TRANSACTION ISOLATION LEVEL REPEATABLE READ`)
+		batch.Queue(fmt.Sprintf(`
+			INSERT INTO %[1]s.checkpointed_heights (height)
+				SELECT height FROM (
+					SELECT height FROM %[1]s.processed_blocks
+						WHERE analyzer = $2
+					ORDER BY height DESC
+					LIMIT 1
+				) AS latest
+				WHERE height = $1
+			ON CONFLICT (height) DO NOTHING`, v.chainID),
+			height,
+			analyzer,
+		)
+		for _, query := range v.qf.ConsensusCheckpointQueries() {
+			batch.Queue(query, height)
+		}
+		err = v.target.SendBatch(ctx, batch)
+		if err == nil {
+			if checkpointed, err = v.checkpointed(ctx, height); err != nil {
+				return 0, err
+			}
+			if checkpointed {
+				return height, nil
+			}
+		}
+		if attempt == maxCheckpointAttempts {
+			return 0, fmt.Errorf("indexed state moved on from height %d while it was checkpointed: %v", height, err)
+		}
+		v.logger.Info("indexed state moved on while it was checkpointed, retrying",
+			"height", height,
+			"error", err,
+		)
+	}
+}
+
+// LatestCheckpoint returns the height of the latest checkpoint.
+func (v *Verifier) LatestCheckpoint(ctx context.Context) (int64, error) {
+	var height int64
+	if err := v.target.QueryRow(
+		storage.WithPrimary(ctx),
+		fmt.Sprintf(`
+			SELECT height FROM %s.checkpointed_heights
+			ORDER BY height DESC
+			LIMIT 1`, v.chainID),
+	).Scan(&height); err != nil {
+		return 0, fmt.Errorf("no checkpoint: %w", err)
+	}
+	return height, nil
+}
+
+// Verify verifies the checkpoint at the provided height, table by table,
+// against node state at that height.
+func (v *Verifier) Verify(ctx context.Context, height int64) (*Report, error) {
+	checkpointed, err := v.checkpointed(ctx, height)
+	if err != nil {
+		return nil, err
+	}
+	if !checkpointed {
+		return nil, fmt.Errorf("no checkpoint at height %d", height)
+	}
+
+	document, err := v.source.GenesisDocumentAtHeight(ctx, height)
+	if err != nil {
+		return nil, fmt.Errorf("node state at height %d unavailable: %w", height, err)
+	}
+
+	report := Report{Height: height}
+	for _, t := range tables {
+		v.logger.Info("verifying table",
+			"table", t.name,
+			"height", height,
+		)
+		expected, err := t.expected(document)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", t.name, err)
+		}
+		actual, err := v.indexed(ctx, t, height)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", t.name, err)
+		}
+		tr := diff(t, expected, actual)
+		report.Tables = append(report.Tables, tr)
+		report.Mismatches += tr.Mismatches
+	}
+	return &report, nil
+}
+
+// Record records the result of a verification with its checkpoint.
+func (v *Verifier) Record(ctx context.Context, report *Report) error {
+	raw, err := json.Marshal(report)
+	if err != nil {
+		return err
+	}
+	batch := &storage.QueryBatch{}
+	batch.Queue(fmt.Sprintf(`
+		UPDATE %s.checkpointed_heights
+		SET
+			verified_time = CURRENT_TIMESTAMP,
+			verify_mismatches = $2,
+			verify_report = $3
+		WHERE height = $1`, v.chainID),
+		report.Height,
+		report.Mismatches,
+		string(raw),
+	)
+	return v.target.SendBatch(ctx, batch)
+}
+
+// latestProcessed returns the latest height processed by the named analyzer.
+func (v *Verifier) latestProcessed(ctx context.Context, analyzer string) (int64, error) {
+	var height int64
+	if err := v.target.QueryRow(
+		storage.WithPrimary(ctx),
+		fmt.Sprintf(`
+			SELECT height FROM %s.processed_blocks
+				WHERE analyzer = $1
+			ORDER BY height DESC
+			LIMIT 1`, v.chainID),
+		analyzer,
+	).Scan(&height); err != nil {
+		return 0, fmt.Errorf("no processed height: %w", err)
+	}
+	return height, nil
+}
+
+// checkpointed returns whether the provided height is checkpointed.
+func (v *Verifier) checkpointed(ctx context.Context, height int64) (bool, error) {
+	var checkpointed bool
+	if err := v.target.QueryRow(
+		storage.WithPrimary(ctx),
+		fmt.Sprintf(`
+			SELECT EXISTS (
+				SELECT 1 FROM %s.checkpointed_heights
+					WHERE height = $1
+			)`, v.chainID),
+		height,
+	).Scan(&checkpointed); err != nil {
+		return false, err
+	}
+	return checkpointed, nil
+}
+
+// indexed returns the rows of a table in the checkpoint at the provided
+// height by key.
+func (v *Verifier) indexed(ctx context.Context, t *table, height int64) (map[string]row, error) {
+	rows, err := v.target.Query(storage.WithPrimary(ctx), fmt.Sprintf(t.query, v.chainID), height)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	indexed := make(map[string]row)
+	for rows.Next() {
+		var key string
+		r := make(row, len(t.columns))
+		dest := []interface{}{&key}
+		for i := range r {
+			dest = append(dest, &r[i])
+		}
+		if err := rows.Scan(dest...); err != nil {
+			return nil, err
+		}
+		indexed[key] = r
+	}
+	return indexed, rows.Err()
+}
+
+// diff reports the differences between the rows of node state and the
+// indexed rows of a table.
+func diff(t *table, expected map[string]row, actual map[string]row) *TableReport {
+	tr := TableReport{
+		Table:    t.name,
+		Expected: len(expected),
+		Actual:   len(actual),
+	}
+	for _, key := range sortedKeys(expected) {
+		a, ok := actual[key]
+		if !ok {
+			tr.Mismatches++
+			if len(tr.Missing) < maxReportedRows {
+				tr.Missing = append(tr.Missing, key)
+			}
+			continue
+		}
+		var columns []*ColumnDiff
+		for i, column := range t.columns {
+			if expected[key][i] != a[i] {
+				columns = append(columns, &ColumnDiff{
+					Column:   column,
+					Expected: expected[key][i],
+					Actual:   a[i],
+				})
+			}
+		}
+		if len(columns) > 0 {
+			tr.Mismatches++
+			if len(tr.Mismatched) < maxReportedRows {
+				tr.Mismatched = append(tr.Mismatched, &RowDiff{Key: key, Columns: columns})
+			}
+		}
+	}
+	for _, key := range sortedKeys(actual) {
+		if _, ok := expected[key]; !ok {
+			tr.Mismatches++
+			if len(tr.Unexpected) < maxReportedRows {
+				tr.Unexpected = append(tr.Unexpected, key)
+			}
+		}
+	}
+	return &tr
+}
+
+func sortedKeys(rows map[string]row) []string {
+	keys := make([]string, 0, len(rows))
+	for key := range rows {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
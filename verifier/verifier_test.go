@@ -0,0 +1,96 @@
+package verifier
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/oasisprotocol/oasis-core/go/common/quantity"
+	genesis "github.com/oasisprotocol/oasis-core/go/genesis/api"
+	governance "github.com/oasisprotocol/oasis-core/go/governance/api"
+	staking "github.com/oasisprotocol/oasis-core/go/staking/api"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDiff(t *testing.T) {
+	tbl := &table{name: "votes", columns: []string{"vote"}}
+
+	tr := diff(tbl,
+		map[string]row{
+			"1/a": {"yes"},
+			"1/b": {"no"},
+			"1/c": {"abstain"},
+		},
+		map[string]row{
+			"1/a": {"yes"},
+			"1/b": {"yes"},
+			"1/d": {"no"},
+		},
+	)
+	require.Equal(t, &TableReport{
+		Table:      "votes",
+		Expected:   3,
+		Actual:     3,
+		Mismatches: 3,
+		Missing:    []string{"1/c"},
+		Unexpected: []string{"1/d"},
+		Mismatched: []*RowDiff{
+			{Key: "1/b", Columns: []*ColumnDiff{{Column: "vote", Expected: "no", Actual: "yes"}}},
+		},
+	}, tr)
+
+	// All differences are counted, but only some are listed.
+	expected := make(map[string]row)
+	for i := 0; i < 2*maxReportedRows; i++ {
+		expected[fmt.Sprintf("%d/a", i)] = row{"yes"}
+	}
+	tr = diff(tbl, expected, map[string]row{})
+	require.Equal(t, 2*maxReportedRows, tr.Mismatches)
+	require.Len(t, tr.Missing, maxReportedRows)
+}
+
+func TestExpectedRows(t *testing.T) {
+	var delegatee, delegator staking.Address
+	require.Nil(t, delegatee.UnmarshalText([]byte("oasis1qrvsa8ukfw3p6kw2vcs0fk9t59mceqq7fyttwqgx")))
+	require.Nil(t, delegator.UnmarshalText([]byte("oasis1qqnv3peudzvekhulf8v3ht29z4cthkhy7gkxmph5")))
+
+	document := &genesis.Document{
+		Staking: staking.Genesis{
+			DebondingDelegations: map[staking.Address]map[staking.Address][]*staking.DebondingDelegation{
+				delegatee: {
+					delegator: {
+						{Shares: *quantity.NewFromUint64(10), DebondEndTime: 5},
+						{Shares: *quantity.NewFromUint64(20), DebondEndTime: 5},
+						{Shares: *quantity.NewFromUint64(30), DebondEndTime: 6},
+					},
+				},
+			},
+		},
+		Governance: governance.Genesis{
+			Proposals: []*governance.Proposal{
+				{
+					ID:        2,
+					Submitter: delegator,
+					State:     governance.StateActive,
+					Deposit:   *quantity.NewFromUint64(100),
+					Content:   governance.ProposalContent{CancelUpgrade: &governance.CancelUpgradeProposal{ProposalID: 1}},
+					CreatedAt: 7,
+					ClosesAt:  8,
+				},
+			},
+		},
+	}
+
+	// Debonding delegations that end in the same epoch are summed.
+	rows, err := expectedDebondingDelegations(document)
+	require.Nil(t, err)
+	require.Equal(t, map[string]row{
+		fmt.Sprintf("%s/%s/5", delegatee, delegator): {"30"},
+		fmt.Sprintf("%s/%s/6", delegatee, delegator): {"30"},
+	}, rows)
+
+	rows, err = expectedProposals(document)
+	require.Nil(t, err)
+	require.Equal(t, map[string]row{
+		"2": {delegator.String(), "active", "100", "", "", "", "", "", "1", "7", "8", "0"},
+	}, rows)
+}